@@ -12,6 +12,7 @@ import (
 	httpDelivery "github.com/yourusername/sotalk/internal/delivery/http"
 	"github.com/yourusername/sotalk/internal/delivery/http/handler"
 	"github.com/yourusername/sotalk/internal/delivery/websocket"
+	"github.com/yourusername/sotalk/internal/infrastructure/linkpreview"
 	"github.com/yourusername/sotalk/internal/infrastructure/solana"
 	"github.com/yourusername/sotalk/internal/infrastructure/storage"
 	"github.com/yourusername/sotalk/internal/repository/postgres"
@@ -113,13 +114,11 @@ func main() {
 
 	// Caches are available for service integration
 	// - sessionCache: Use in auth service for session management
-	// - messageCache: Use in message service for fast message retrieval
-	// - presenceCache: Available for presence tracking integration
+	// - messageCache: Unread counts of the message service, also available for fast message retrieval
+	// - presenceCache: Online, away and offline statuses of the WebSocket hub
 	// - conversationCache: Use in conversation service for conversation lists
 	// See internal/repository/redis/README.md for integration examples
 	_ = sessionCache      // Available for auth service integration
-	_ = messageCache      // Available for message service integration
-	_ = presenceCache     // Available for presence tracking integration
 	_ = conversationCache // Available for conversation service integration
 
 	// Initialize repositories
@@ -133,12 +132,14 @@ func main() {
 	mediaRepo := postgres.NewMediaRepository(db)
 	walletRepo := postgres.NewWalletRepository(db)
 	paymentRepo := postgres.NewPaymentRequestRepository(db)
-	privacyRepo := postgres.NewPrivacyRepository(db)
+	// Privacy settings are checked on every typing event and read receipt, so they are served from Redis
+	privacyRepo := redisRepo.NewPrivacyCache(postgres.NewPrivacyRepository(db), redisClient.GetClient(), 10*time.Minute)
 	statusRepo := postgres.NewStatusRepository(db)         // Day 13
 	contactRepo := postgres.NewContactRepository(db)       // Day 13
 	notificationRepo := postgres.NewNotificationRepository(db) // Notifications
 	referralRepo := postgres.NewReferralRepository(db)     // Referral system
 	passkeyRepo := postgres.NewPasskeyRepository(db)       // Passkey credentials
+	changeLogRepo := postgres.NewChangeLogRepository(db)
 
 	// Initialize JWT manager
 	jwtManager := middleware.NewJWTManager(
@@ -188,12 +189,6 @@ func main() {
 		referralService,
 	)
 
-	channelService := channel.NewService(
-		channelRepo,
-		conversationRepo,
-		userRepo,
-	)
-
 	// Initialize storage service (Azure Blob or Local)
 	var storageService storage.Service
 
@@ -245,7 +240,28 @@ func main() {
 	)
 
 	// Initialize WebSocket hub and start it
-	wsHub := websocket.NewHub(conversationRepo, userRepo)
+	// With clustering on, every node relays its broadcasts and presence through Redis
+	var wsCluster *websocket.Cluster
+	if cfg.WebSocket.ClusterEnabled {
+		wsCluster = websocket.NewCluster(redisClient.GetClient(), websocket.ClusterConfig{
+			NodeID:      cfg.WebSocket.NodeID,
+			PresenceTTL: cfg.WebSocket.PresenceTTL,
+		})
+	}
+	// Events are numbered per user and buffered, so dropped sockets can resume where they left off
+	wsReplay := websocket.NewReplayBuffer(redisClient.GetClient(), websocket.ReplayConfig{
+		Size:      cfg.WebSocket.ReplaySize,
+		Retention: cfg.WebSocket.ReplayTTL,
+	})
+	// Initialize privacy service (Day 12)
+	// It also decides who is sent a user's presence, so the hub needs it first
+	privacyService := privacy.NewService(privacyRepo, conversationRepo, contactRepo)
+	logger.Info("✅ Privacy service initialized")
+
+	wsPresence := websocket.NewPresence(privacyService, presenceCache, websocket.PresenceConfig{
+		AwayAfter: cfg.WebSocket.AwayAfter,
+	})
+	wsHub := websocket.NewHub(conversationRepo, userRepo, wsPresence, wsCluster, wsReplay)
 	go wsHub.Run()
 	logger.Info("✅ WebSocket Hub started")
 
@@ -263,39 +279,110 @@ func main() {
 		groupRepo,
 		conversationRepo,
 		userRepo,
+		privacyRepo,
+		changeLogRepo,
 		wsBroadcaster,
 	)
 	logger.Info("✅ Group service initialized with WebSocket support")
 
+	// Initialize channel service with WebSocket support
+	channelService := channel.NewService(
+		channelRepo,
+		conversationRepo,
+		userRepo,
+		changeLogRepo,
+		wsBroadcaster,
+	)
+	logger.Info("✅ Channel service initialized with WebSocket support")
+
+	// Link previews are fetched in the background and cached in Redis
+	var linkPreviewer message.LinkPreviewer
+	if cfg.LinkPreview.Enabled {
+		linkPreviewer = linkpreview.NewUnfurler(linkpreview.Config{
+			Timeout:              cfg.LinkPreview.Timeout,
+			MaxBodyBytes:         int64(cfg.LinkPreview.MaxBodyBytes),
+			CacheTTL:             cfg.LinkPreview.CacheTTL,
+			AllowPrivateNetworks: cfg.LinkPreview.AllowPrivateNetworks,
+		}, redisClient.GetClient())
+		logger.Info("✅ Link previews enabled",
+			zap.Duration("timeout", cfg.LinkPreview.Timeout),
+			zap.Bool("allow_private_networks", cfg.LinkPreview.AllowPrivateNetworks),
+		)
+	}
+
 	// Initialize message service with WebSocket support
 	messageService := message.NewService(
 		messageRepo,
 		conversationRepo,
 		userRepo,
+		groupRepo,
+		channelRepo,
+		privacyRepo,
+		contactRepo,
+		changeLogRepo,
 		wsBroadcaster,
+		linkPreviewer,
+		messageCache,
 	)
 	logger.Info("✅ Message service initialized with WebSocket support")
 
+	// Start disappearing messages worker
+	expiryWorker := message.NewExpiryWorker(
+		messageRepo,
+		mediaRepo,
+		changeLogRepo,
+		storageService,
+		wsBroadcaster,
+		messageCache,
+		cfg.Workers.MessageExpiryInterval,
+		cfg.Workers.MessageExpiryBatchSize,
+	)
+	expiryWorker.Start()
+	logger.Info("✅ Message expiry worker started",
+		zap.Duration("interval", cfg.Workers.MessageExpiryInterval),
+	)
+
+	// Start scheduled messages dispatcher
+	scheduledWorker := message.NewScheduledWorker(
+		messageRepo,
+		messageService,
+		cfg.Workers.ScheduledMessageInterval,
+		cfg.Workers.ScheduledMessageBatchSize,
+	)
+	scheduledWorker.Start()
+	logger.Info("✅ Scheduled message dispatcher started",
+		zap.Duration("interval", cfg.Workers.ScheduledMessageInterval),
+	)
+
+	// Start change log pruning, delta sync can catch up within the retention
+	changeLogWorker := message.NewChangeLogWorker(
+		changeLogRepo,
+		cfg.Workers.ChangeLogRetention,
+		cfg.Workers.ChangeLogPruneInterval,
+		cfg.Workers.ChangeLogPruneBatchSize,
+	)
+	changeLogWorker.Start()
+	logger.Info("✅ Change log pruning started",
+		zap.Duration("retention", cfg.Workers.ChangeLogRetention),
+	)
+
 	paymentService := payment.NewService(
 		paymentRepo,
 		userRepo,
 		walletRepo,
+		privacyRepo,
 		solanaClient,
 		wsBroadcaster,
 	)
 	logger.Info("✅ Payment service initialized with WebSocket support")
 
 
-	// Initialize privacy service (Day 12)
-	privacyService := privacy.NewService(privacyRepo, conversationRepo)
-	logger.Info("✅ Privacy service initialized")
-
 	// Initialize status service (Day 13)
-	statusService := status.NewService(statusRepo, contactRepo, userRepo)
+	statusService := status.NewService(statusRepo, contactRepo, userRepo, privacyRepo)
 	logger.Info("✅ Status service initialized")
 
 	// Initialize contact service (Day 13)
-	contactService := contact.NewService(contactRepo)
+	contactService := contact.NewService(contactRepo, privacyRepo)
 	logger.Info("✅ Contact service initialized")
 
 	// Initialize user service
@@ -374,6 +461,14 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
+	// Stop background workers
+	expiryWorker.Stop()
+	scheduledWorker.Stop()
+	changeLogWorker.Stop()
+
+	// Leave the WebSocket cluster before Redis is closed
+	wsHub.Stop()
+
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -387,6 +387,462 @@ func (h *ChannelHandler) DeleteChannel(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "channel deleted"})
 }
 
+// UpdateChannel handles PUT /api/v1/channels/:id
+func (h *ChannelHandler) UpdateChannel(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_channel_id",
+			Message: "Invalid channel ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.UpdateChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.channelService.UpdateChannel(c.Request.Context(), userID, channelID, &dto.UpdateChannelRequest{
+		Name:        req.Name,
+		Description: req.Description,
+		Avatar:      req.Avatar,
+	})
+	if err != nil {
+		logger.Error("Failed to update channel", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "update_channel_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "channel updated"})
+}
+
+// UpdateChannelSettings handles PUT /api/v1/channels/:id/settings
+func (h *ChannelHandler) UpdateChannelSettings(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_channel_id",
+			Message: "Invalid channel ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.UpdateChannelSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.channelService.UpdateChannelSettings(c.Request.Context(), userID, channelID, &dto.UpdateChannelSettingsRequest{
+		AdminsCanPost:     req.AdminsCanPost,
+		LinkPreview:       req.LinkPreview,
+		ForwardingAllowed: req.ForwardingAllowed,
+		EditWindowSeconds: req.EditWindowSeconds,
+		SubscriberVotes:   req.SubscriberVotes,
+	})
+	if err != nil {
+		logger.Error("Failed to update channel settings", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "update_settings_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "channel settings updated"})
+}
+
+// ToggleNotifications handles POST /api/v1/channels/:username/notifications
+func (h *ChannelHandler) ToggleNotifications(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := h.resolveChannelID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "channel_not_found",
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	err = h.channelService.ToggleNotifications(c.Request.Context(), userID, channelID)
+	if err != nil {
+		logger.Error("Failed to toggle notifications", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "toggle_notifications_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "notifications toggled"})
+}
+
+// GetChannelAdmins handles GET /api/v1/channels/:username/admins
+func (h *ChannelHandler) GetChannelAdmins(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := h.resolveChannelID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "channel_not_found",
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	result, err := h.channelService.GetChannelAdmins(c.Request.Context(), userID, channelID)
+	if err != nil {
+		logger.Error("Failed to get channel admins", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "get_admins_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	admins := make([]response.ChannelAdminDTO, len(result.Admins))
+	for i, admin := range result.Admins {
+		admins[i] = mapChannelAdminDTO(admin)
+	}
+
+	c.JSON(http.StatusOK, response.GetChannelAdminsResponse{
+		Admins: admins,
+	})
+}
+
+// AddAdmin handles POST /api/v1/channels/:username/admins
+func (h *ChannelHandler) AddAdmin(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := h.resolveChannelID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "channel_not_found",
+			Message: err.Error(),
+			Code:    http.StatusNotFound,
+		})
+		return
+	}
+
+	var req request.AddChannelAdminRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.channelService.AddAdmin(c.Request.Context(), userID, channelID, &dto.AddAdminRequest{
+		UserID: req.UserID,
+	})
+	if err != nil {
+		logger.Error("Failed to add channel admin", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "add_admin_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "admin added"})
+}
+
+// UpdateAdminPermissions handles PUT /api/v1/channels/:id/admins/:userId
+func (h *ChannelHandler) UpdateAdminPermissions(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_channel_id",
+			Message: "Invalid channel ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_target_user_id",
+			Message: "Invalid target user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.UpdateChannelAdminPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.channelService.UpdateAdminPermissions(c.Request.Context(), userID, channelID, targetUserID, &dto.UpdateAdminPermissionsRequest{
+		CanPostMessages:   req.CanPostMessages,
+		CanEditMessages:   req.CanEditMessages,
+		CanDeleteMessages: req.CanDeleteMessages,
+		CanManageAdmins:   req.CanManageAdmins,
+	})
+	if err != nil {
+		logger.Error("Failed to update admin permissions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "update_permissions_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "admin permissions updated"})
+}
+
+// RemoveAdmin handles DELETE /api/v1/channels/:id/admins/:userId
+func (h *ChannelHandler) RemoveAdmin(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	channelID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_channel_id",
+			Message: "Invalid channel ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	targetUserID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_target_user_id",
+			Message: "Invalid target user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = h.channelService.RemoveAdmin(c.Request.Context(), userID, channelID, targetUserID)
+	if err != nil {
+		logger.Error("Failed to remove channel admin", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "remove_admin_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "admin removed"})
+}
+
+// resolveChannelID resolves the :username route param, which accepts a channel ID or username
+func (h *ChannelHandler) resolveChannelID(c *gin.Context, userID uuid.UUID) (uuid.UUID, error) {
+	ref := c.Param("username")
+	if channelID, err := uuid.Parse(ref); err == nil {
+		return channelID, nil
+	}
+
+	result, err := h.channelService.GetChannel(c.Request.Context(), userID, ref)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return uuid.Parse(result.Channel.ID)
+}
+
+// Helper function to map channel admin DTO
+func mapChannelAdminDTO(admin dto.ChannelAdminDTO) response.ChannelAdminDTO {
+	var user *response.UserDTO
+	if admin.User != nil {
+		user = &response.UserDTO{
+			ID:            admin.User.ID,
+			WalletAddress: admin.User.WalletAddress,
+			Username:      admin.User.Username,
+			Avatar:        admin.User.Avatar,
+			Status:        admin.User.Status,
+		}
+	}
+
+	var permissions *response.ChannelAdminPermissions
+	if admin.Permissions != nil {
+		permissions = &response.ChannelAdminPermissions{
+			CanPostMessages:   admin.Permissions.CanPostMessages,
+			CanEditMessages:   admin.Permissions.CanEditMessages,
+			CanDeleteMessages: admin.Permissions.CanDeleteMessages,
+			CanManageAdmins:   admin.Permissions.CanManageAdmins,
+		}
+	}
+
+	return response.ChannelAdminDTO{
+		UserID:      admin.UserID,
+		User:        user,
+		Permissions: permissions,
+		AddedAt:     admin.AddedAt,
+	}
+}
+
 // Helper function to map channel DTO
 func mapChannelDTO(ch dto.ChannelDTO) response.ChannelDTO {
 	var settings *response.ChannelSettings
@@ -395,6 +851,8 @@ func mapChannelDTO(ch dto.ChannelDTO) response.ChannelDTO {
 			AdminsCanPost:     ch.Settings.AdminsCanPost,
 			LinkPreview:       ch.Settings.LinkPreview,
 			ForwardingAllowed: ch.Settings.ForwardingAllowed,
+			EditWindowSeconds: ch.Settings.EditWindowSeconds,
+			SubscriberVotes:   ch.Settings.SubscriberVotes,
 		}
 	}
 
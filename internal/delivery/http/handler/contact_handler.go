@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/delivery/http/response"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/contact"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/pkg/logger"
@@ -293,6 +295,10 @@ func (h *ContactHandler) SendInvite(c *gin.Context) {
 	result, err := h.contactService.SendInvite(c.Request.Context(), senderID, &req)
 	if err != nil {
 		logger.Error("Failed to send invite", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "internal_error",
 			Message: "Failed to send invite",
@@ -374,6 +380,10 @@ func (h *ContactHandler) AcceptInvite(c *gin.Context) {
 
 	if err := h.contactService.AcceptInvite(c.Request.Context(), recipientID, inviteID); err != nil {
 		logger.Error("Failed to accept invite", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "internal_error",
 			Message: "Failed to accept invite",
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/delivery/http/request"
 	"github.com/yourusername/sotalk/internal/delivery/http/response"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/internal/usecase/group"
 	"github.com/yourusername/sotalk/pkg/logger"
@@ -356,6 +358,10 @@ func (h *GroupHandler) AddMember(c *gin.Context) {
 
 	if err != nil {
 		logger.Error("Failed to add member", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "add_member_failed",
 			Message: err.Error(),
@@ -597,9 +603,11 @@ func (h *GroupHandler) UpdateGroupSettings(c *gin.Context) {
 
 	// Call service
 	err = h.groupService.UpdateGroupSettings(c.Request.Context(), userID, groupID, &dto.UpdateGroupSettingsRequest{
-		WhoCanMessage:    req.WhoCanMessage,
-		WhoCanAddMembers: req.WhoCanAddMembers,
-		WhoCanEditInfo:   req.WhoCanEditInfo,
+		WhoCanMessage:     req.WhoCanMessage,
+		WhoCanAddMembers:  req.WhoCanAddMembers,
+		WhoCanEditInfo:    req.WhoCanEditInfo,
+		EditWindowSeconds: req.EditWindowSeconds,
+		LinkPreview:       req.LinkPreview,
 	})
 
 	if err != nil {
@@ -620,9 +628,11 @@ func mapGroupDTO(grp dto.GroupDTO) response.GroupDTO {
 	var settings *response.GroupSettings
 	if grp.Settings != nil {
 		settings = &response.GroupSettings{
-			WhoCanMessage:    grp.Settings.WhoCanMessage,
-			WhoCanAddMembers: grp.Settings.WhoCanAddMembers,
-			WhoCanEditInfo:   grp.Settings.WhoCanEditInfo,
+			WhoCanMessage:     grp.Settings.WhoCanMessage,
+			WhoCanAddMembers:  grp.Settings.WhoCanAddMembers,
+			WhoCanEditInfo:    grp.Settings.WhoCanEditInfo,
+			EditWindowSeconds: grp.Settings.EditWindowSeconds,
+			LinkPreview:       grp.Settings.LinkPreview,
 		}
 	}
 
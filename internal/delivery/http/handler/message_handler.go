@@ -2,6 +2,8 @@ package handler
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -9,6 +11,8 @@ import (
 	"github.com/yourusername/sotalk/internal/delivery/http/request"
 	"github.com/yourusername/sotalk/internal/delivery/http/response"
 	"github.com/yourusername/sotalk/internal/domain/contact"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	domainMessage "github.com/yourusername/sotalk/internal/domain/message"
 	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/internal/usecase/message"
@@ -88,17 +92,40 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 		return
 	}
 
+	clientMessageID, ok := resolveClientMessageID(c, req.ClientMessageID)
+	if !ok {
+		return
+	}
+
 	// Call use case
 	result, err := h.messageService.SendMessage(c.Request.Context(), senderID, &dto.SendMessageRequest{
-		RecipientID: recipientID,
-		Content:     req.Content, // Plain text, no encryption
-		ContentType: req.ContentType,
-		Signature:   req.Signature,
-		ReplyToID:   replyToID,
+		RecipientID:     recipientID,
+		Content:         req.Content, // Plain text, no encryption
+		ContentType:     req.ContentType,
+		Signature:       req.Signature,
+		ReplyToID:       replyToID,
+		ClientMessageID: clientMessageID,
+		Poll:            toCreatePollDTO(req.Poll),
 	})
 
 	if err != nil {
 		logger.Error("Failed to send message", zap.Error(err))
+		if errors.Is(err, domainMessage.ErrClientMessageIDReused) {
+			respondClientMessageIDReused(c)
+			return
+		}
+		if errors.Is(err, domainMessage.ErrInvalidPoll) {
+			respondInvalidPoll(c)
+			return
+		}
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
+		if errors.Is(err, privacy.ErrMessagingRestricted) {
+			respondMessagingRestricted(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "send_message_failed",
 			Message: err.Error(),
@@ -113,6 +140,173 @@ func (h *MessageHandler) SendMessage(c *gin.Context) {
 	})
 }
 
+// SendConversationMessage handles POST /api/v1/conversations/:id/messages
+func (h *MessageHandler) SendConversationMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	senderID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.SendConversationMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	replyToID, err := req.ParseReplyToID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_reply_to_id",
+			Message: "Invalid reply to ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	clientMessageID, ok := resolveClientMessageID(c, req.ClientMessageID)
+	if !ok {
+		return
+	}
+
+	result, err := h.messageService.SendToConversation(c.Request.Context(), senderID, &dto.SendConversationMessageRequest{
+		ConversationID:  conversationID,
+		Content:         req.Content,
+		ContentType:     req.ContentType,
+		Signature:       req.Signature,
+		ReplyToID:       replyToID,
+		ClientMessageID: clientMessageID,
+		Poll:            toCreatePollDTO(req.Poll),
+	})
+	if err != nil {
+		if errors.Is(err, domainMessage.ErrClientMessageIDReused) {
+			respondClientMessageIDReused(c)
+			return
+		}
+		if errors.Is(err, domainMessage.ErrInvalidPoll) {
+			respondInvalidPoll(c)
+			return
+		}
+
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
+		if errors.Is(err, conversation.ErrNotParticipant) || errors.Is(err, domainMessage.ErrSendNotAllowed) {
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "send_not_allowed",
+				Message: err.Error(),
+				Code:    http.StatusForbidden,
+			})
+			return
+		}
+
+		logger.Error("Failed to send message", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "send_message_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, response.SendMessageResponse{
+		Message: mapMessageDTO(result.Message),
+	})
+}
+
+// resolveClientMessageID returns the client message ID from the body, falling back to the Idempotency-Key header.
+// It writes a 400 response and returns false if the header value is too long.
+func resolveClientMessageID(c *gin.Context, fromBody string) (string, bool) {
+	if fromBody != "" {
+		return fromBody, true
+	}
+
+	key := c.GetHeader("Idempotency-Key")
+	if len(key) > 64 {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_idempotency_key",
+			Message: "Idempotency-Key must be at most 64 characters",
+			Code:    http.StatusBadRequest,
+		})
+		return "", false
+	}
+
+	return key, true
+}
+
+// respondClientMessageIDReused responds to a client message ID reused for another conversation
+func respondClientMessageIDReused(c *gin.Context) {
+	c.JSON(http.StatusConflict, response.ErrorResponse{
+		Error:   "client_message_id_reused",
+		Message: "client_message_id was already used for a message in another conversation",
+		Code:    http.StatusConflict,
+	})
+}
+
+// toCreatePollDTO maps the poll part of a send request, nil when the message is not a poll
+func toCreatePollDTO(req *request.CreatePollRequest) *dto.CreatePollRequest {
+	if req == nil {
+		return nil
+	}
+	return &dto.CreatePollRequest{
+		Options:        req.Options,
+		MultipleChoice: req.MultipleChoice,
+		Anonymous:      req.Anonymous,
+		Quiz:           req.Quiz,
+		CorrectOption:  req.CorrectOption,
+		ClosesAt:       req.ClosesAt,
+	}
+}
+
+func respondInvalidPoll(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, response.ErrorResponse{
+		Error: "invalid_poll",
+		Message: fmt.Sprintf(
+			"A poll needs a question of up to %d characters, %d to %d distinct options of up to %d characters and one correct option for quizzes",
+			domainMessage.MaxPollQuestionLength, domainMessage.MinPollOptions, domainMessage.MaxPollOptions, domainMessage.MaxPollOptionLength,
+		),
+		Code: http.StatusBadRequest,
+	})
+}
+
+func respondPollUnsupported(c *gin.Context) {
+	c.JSON(http.StatusBadRequest, response.ErrorResponse{
+		Error:   "poll_unsupported",
+		Message: "Polls cannot be edited, forwarded or scheduled",
+		Code:    http.StatusBadRequest,
+	})
+}
+
 // GetMessages handles GET /api/v1/messages
 func (h *MessageHandler) GetMessages(c *gin.Context) {
 	// Get user ID from context
@@ -158,15 +352,60 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 		return
 	}
 
+	aroundID, err := req.ParseAroundID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_around_id",
+			Message: "Invalid around message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Only one pagination mode can be used at a time
+	modes := 0
+	for _, set := range []bool{req.Before != "", req.After != "", aroundID != nil} {
+		if set {
+			modes++
+		}
+	}
+	if modes > 1 {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Only one of before, after or around can be specified",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
 	// Call use case
 	result, err := h.messageService.GetMessages(c.Request.Context(), userID, &dto.GetMessagesRequest{
 		ConversationID: conversationID,
 		Limit:          req.Limit,
 		Offset:         req.Offset,
+		Before:         req.Before,
+		After:          req.After,
+		AroundID:       aroundID,
 	})
 
 	if err != nil {
 		logger.Error("Failed to get messages", zap.Error(err))
+		if errors.Is(err, domainMessage.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "Invalid pagination cursor",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if errors.Is(err, domainMessage.ErrMessageNotFound) {
+			c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "message_not_found",
+				Message: "Message not found in this conversation",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "get_messages_failed",
 			Message: err.Error(),
@@ -181,8 +420,12 @@ func (h *MessageHandler) GetMessages(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, response.GetMessagesResponse{
-		Messages: messages,
-		Total:    result.Total,
+		Messages:   messages,
+		Total:      result.Total,
+		NextCursor: result.NextCursor,
+		PrevCursor: result.PrevCursor,
+		HasOlder:   result.HasOlder,
+		HasNewer:   result.HasNewer,
 	})
 }
 
@@ -220,19 +463,31 @@ func (h *MessageHandler) GetConversations(c *gin.Context) {
 		return
 	}
 
-	// Call use case
-	result, err := h.messageService.GetConversations(c.Request.Context(), userID, &dto.GetConversationsRequest{
+	getReq := &dto.GetConversationsRequest{
 		Limit:  req.Limit,
 		Offset: req.Offset,
-	})
+		Pinned: req.Pinned,
+		Muted:  req.Muted,
+	}
+	if req.FolderID != "" {
+		folderID, err := uuid.Parse(req.FolderID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_folder_id",
+				Message: "Invalid folder ID",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		getReq.FolderID = &folderID
+	}
+
+	// Call use case
+	result, err := h.messageService.GetConversations(c.Request.Context(), userID, getReq)
 
 	if err != nil {
 		logger.Error("Failed to get conversations", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "get_conversations_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		respondConversationStateError(c, err, "get_conversations_failed")
 		return
 	}
 
@@ -304,6 +559,14 @@ func (h *MessageHandler) CreateConversation(c *gin.Context) {
 
 	if err != nil {
 		logger.Error("Failed to create conversation", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
+		if errors.Is(err, privacy.ErrMessagingRestricted) {
+			respondMessagingRestricted(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "create_conversation_failed",
 			Message: err.Error(),
@@ -362,29 +625,143 @@ func (h *MessageHandler) MarkAsRead(c *gin.Context) {
 		return
 	}
 
+	messageID, err := req.ParseMessageID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
 	// Call use case
-	err = h.messageService.MarkAsRead(c.Request.Context(), userID, &dto.MarkAsReadRequest{
+	state, err := h.messageService.MarkAsRead(c.Request.Context(), userID, &dto.MarkAsReadRequest{
 		ConversationID: conversationID,
+		MessageID:      messageID,
 	})
 
 	if err != nil {
 		logger.Error("Failed to mark as read", zap.Error(err))
+		respondReadStateError(c, err, "mark_as_read_failed")
+		return
+	}
+
+	// NOTE: message.read receipts go out from the WebSocket client handler when the client
+	// sends message IDs. The user's own devices get a conversation.read event from the use case.
+
+	c.JSON(http.StatusOK, mapReadState(*state))
+}
+
+// MarkAsUnread handles POST /api/v1/conversations/:id/unread
+func (h *MessageHandler) MarkAsUnread(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// The body is optional, without one the latest message from others becomes unread
+	var req request.MarkAsUnreadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	messageID, err := req.ParseMessageID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	state, err := h.messageService.MarkAsUnread(c.Request.Context(), userID, &dto.MarkAsUnreadRequest{
+		ConversationID: conversationID,
+		MessageID:      messageID,
+	})
+	if err != nil {
+		logger.Error("Failed to mark as unread", zap.Error(err))
+		respondReadStateError(c, err, "mark_as_unread_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, mapReadState(*state))
+}
+
+// respondReadStateError maps read marker errors to HTTP responses
+func respondReadStateError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, conversation.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "not_participant",
+			Message: "You are not a participant in this conversation",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, conversation.ErrConversationNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "conversation_not_found",
+			Message: "Conversation not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "message_not_found",
+			Message: "Message not found",
+			Code:    http.StatusNotFound,
+		})
+	default:
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "mark_as_read_failed",
+			Error:   code,
 			Message: err.Error(),
 			Code:    http.StatusInternalServerError,
 		})
-		return
 	}
+}
 
-	// NOTE: WebSocket message.read events are handled by the WebSocket client handler
-	// when the client explicitly sends message IDs. The HTTP endpoint doesn't broadcast
-	// because it doesn't know which specific messages were marked as read.
-
-	c.JSON(http.StatusOK, gin.H{"message": "marked as read"})
+func mapReadState(state dto.ReadStateDTO) response.ReadStateResponse {
+	return response.ReadStateResponse{
+		ConversationID:     state.ConversationID,
+		LastReadMessageID:  state.LastReadMessageID,
+		LastReadAt:         state.LastReadAt,
+		UnreadCount:        state.UnreadCount,
+		UnreadMentionCount: state.UnreadMentionCount,
+	}
 }
 
-// DeleteMessage handles DELETE /api/v1/messages/:id
+// DeleteMessage handles DELETE /api/v1/messages/:id?scope=me|everyone
 func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 	// Get user ID from context
 	userIDStr, exists := c.Get("user_id")
@@ -418,15 +795,47 @@ func (h *MessageHandler) DeleteMessage(c *gin.Context) {
 		return
 	}
 
+	var req request.DeleteMessageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "scope must be 'me' or 'everyone'",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	scope := domainMessage.DeleteForEveryone
+	if req.Scope != "" {
+		scope = domainMessage.DeleteScope(req.Scope)
+	}
+
 	// Call use case
-	err = h.messageService.DeleteMessage(c.Request.Context(), userID, messageID)
+	err = h.messageService.DeleteMessage(c.Request.Context(), userID, messageID, scope)
 	if err != nil {
 		logger.Error("Failed to delete message", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "delete_message_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		switch {
+		case errors.Is(err, domainMessage.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You are not allowed to delete this message for everyone",
+				Code:    http.StatusForbidden,
+			})
+		case errors.Is(err, domainMessage.ErrMessageDeleted):
+			c.JSON(http.StatusGone, response.ErrorResponse{
+				Error:   "message_deleted",
+				Message: "Message has already been deleted",
+				Code:    http.StatusGone,
+			})
+		case errors.Is(err, domainMessage.ErrInvalidDeleteScope):
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_scope",
+				Message: "scope must be 'me' or 'everyone'",
+				Code:    http.StatusBadRequest,
+			})
+		default:
+			respondThreadError(c, err, "delete_message_failed")
+		}
 		return
 	}
 
@@ -483,11 +892,46 @@ func (h *MessageHandler) EditMessage(c *gin.Context) {
 	result, err := h.messageService.EditMessage(c.Request.Context(), userID, messageID, req.Content)
 	if err != nil {
 		logger.Error("Failed to edit message", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "edit_message_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+		switch {
+		case errors.Is(err, domainMessage.ErrUnauthorized):
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "forbidden",
+				Message: "You can only edit your own messages",
+				Code:    http.StatusForbidden,
+			})
+		case errors.Is(err, domainMessage.ErrEditWindowExpired):
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "edit_window_expired",
+				Message: "This message can no longer be edited",
+				Code:    http.StatusForbidden,
+			})
+		case errors.Is(err, domainMessage.ErrMessageDeleted):
+			c.JSON(http.StatusGone, response.ErrorResponse{
+				Error:   "message_deleted",
+				Message: "Message has been deleted",
+				Code:    http.StatusGone,
+			})
+		case errors.Is(err, domainMessage.ErrPollUnsupported):
+			respondPollUnsupported(c)
+		case errors.Is(err, domainMessage.ErrEditConflict):
+			c.JSON(http.StatusConflict, response.ErrorResponse{
+				Error:   "edit_conflict",
+				Message: "Message was edited by another request, reload and try again",
+				Code:    http.StatusConflict,
+			})
+		case errors.Is(err, domainMessage.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, response.ErrorResponse{
+				Error:   "message_not_found",
+				Message: "Message not found",
+				Code:    http.StatusNotFound,
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+				Error:   "edit_message_failed",
+				Message: err.Error(),
+				Code:    http.StatusInternalServerError,
+			})
+		}
 		return
 	}
 
@@ -496,109 +940,144 @@ func (h *MessageHandler) EditMessage(c *gin.Context) {
 	})
 }
 
-// Helper functions to map DTOs
-func mapMessageDTO(msg dto.MessageDTO) response.MessageDTO {
-	var sender *response.UserDTO
-	if msg.Sender != nil {
-		sender = &response.UserDTO{
-			ID:            msg.Sender.ID,
-			WalletAddress: msg.Sender.WalletAddress,
-			Username:      msg.Sender.Username,
-			Avatar:        msg.Sender.Avatar,
-			Status:        msg.Sender.Status,
-		}
+// GetMessageRevisions handles GET /api/v1/messages/:id/revisions
+func (h *MessageHandler) GetMessageRevisions(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
 	}
 
-	return response.MessageDTO{
-		ID:             msg.ID,
-		ConversationID: msg.ConversationID,
-		SenderID:       msg.SenderID,
-		Content:        msg.Content,
-		ContentType:    msg.ContentType,
-		Signature:      msg.Signature,
-		ReplyToID:      msg.ReplyToID,
-		Status:         msg.Status,
-		CreatedAt:      msg.CreatedAt,
-		UpdatedAt:      msg.UpdatedAt,
-		Sender:         sender,
-		Reactions:      msg.Reactions,
-		IsPinned:       msg.IsPinned,
-		PinnedBy:       msg.PinnedBy,
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
 	}
-}
 
-// mapConversationDTOWithPrivacy maps conversation DTO with privacy checking
-func (h *MessageHandler) mapConversationDTOWithPrivacy(ctx context.Context, conv dto.ConversationDTO, currentUserID uuid.UUID) response.ConversationDTO {
-	participants := make([]response.UserDTO, len(conv.Participants))
-	for i, p := range conv.Participants {
-		// Default to hiding everything
-		canSeeAvatar := false
-		canSeeLastSeen := false
-		canSeeStatus := false
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
 
-		// Parse target user ID
-		targetUserID, err := uuid.Parse(p.ID)
-		if err == nil && targetUserID != currentUserID {
-			// Get target user's privacy settings
-			privacySettings, err := h.privacyRepo.GetPrivacySettings(ctx, targetUserID)
-			if err == nil && privacySettings != nil {
-				// Determine relationship between current user and target user
-				relationship := "stranger"
-				isContact, err := h.contactRepo.IsContact(ctx, currentUserID, targetUserID)
-				if err == nil && isContact {
-					relationship = "contact"
-				}
+	result, err := h.messageService.GetMessageRevisions(c.Request.Context(), userID, messageID)
+	if err != nil {
+		logger.Error("Failed to get message revisions", zap.Error(err))
+		respondThreadError(c, err, "get_revisions_failed")
+		return
+	}
 
-				// Calculate privacy flags based on target user's settings
-				canSeeAvatar = privacySettings.CanSeeProfilePhoto(relationship)
-				canSeeLastSeen = privacySettings.CanSeeLastSeen(relationship)
-				canSeeStatus = privacySettings.CanSeeStatus(relationship)
-			} else {
-				// If no privacy settings found, default to everyone can see (for backwards compatibility)
-				canSeeAvatar = true
-				canSeeLastSeen = true
-				canSeeStatus = true
-			}
-		} else if targetUserID == currentUserID {
-			// User can always see their own information
-			canSeeAvatar = true
-			canSeeLastSeen = true
-			canSeeStatus = true
+	revisions := make([]response.MessageRevisionDTO, len(result.Revisions))
+	for i, rev := range result.Revisions {
+		revisions[i] = response.MessageRevisionDTO{
+			ID:          rev.ID,
+			Revision:    rev.Revision,
+			Content:     rev.Content,
+			ContentType: rev.ContentType,
+			EditedBy:    rev.EditedBy,
+			EditedAt:    rev.EditedAt,
 		}
+	}
 
-		participants[i] = response.UserDTO{
-			ID:             p.ID,
-			WalletAddress:  p.WalletAddress,
-			Username:       p.Username,
-			Avatar:         p.Avatar,
-			Status:         p.Status,
-			IsOnline:       p.IsOnline,
-			LastSeen:       &p.LastSeen,
-			CanSeeAvatar:   &canSeeAvatar,
-			CanSeeLastSeen: &canSeeLastSeen,
-			CanSeeStatus:   &canSeeStatus,
-		}
+	c.JSON(http.StatusOK, response.GetMessageRevisionsResponse{
+		MessageID:      result.MessageID,
+		CurrentContent: result.CurrentContent,
+		EditCount:      result.EditCount,
+		EditedAt:       result.EditedAt,
+		Revisions:      revisions,
+	})
+}
+
+// GetMessageReceipts handles GET /api/v1/messages/:id/receipts
+func (h *MessageHandler) GetMessageReceipts(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
 	}
 
-	var lastMessage *response.MessageDTO
-	if conv.LastMessage != nil {
-		msg := mapMessageDTO(*conv.LastMessage)
-		lastMessage = &msg
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
 	}
 
-	return response.ConversationDTO{
-		ID:           conv.ID,
-		Type:         conv.Type,
-		Participants: participants,
-		LastMessage:  lastMessage,
-		UnreadCount:  conv.UnreadCount,
-		CreatedAt:    conv.CreatedAt,
-		UpdatedAt:    conv.UpdatedAt,
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.GetMessageReceipts(c.Request.Context(), userID, messageID)
+	if err != nil {
+		logger.Error("Failed to get message receipts", zap.Error(err))
+		if errors.Is(err, domainMessage.ErrUnauthorized) {
+			c.JSON(http.StatusForbidden, response.ErrorResponse{
+				Error:   "forbidden",
+				Message: "Only the sender can see who received a message",
+				Code:    http.StatusForbidden,
+			})
+			return
+		}
+		respondThreadError(c, err, "get_receipts_failed")
+		return
+	}
+
+	receipts := make([]response.MessageReceiptDTO, len(result.Receipts))
+	for i, receipt := range result.Receipts {
+		var u *response.UserDTO
+		if receipt.User != nil {
+			u = &response.UserDTO{
+				ID:            receipt.User.ID,
+				WalletAddress: receipt.User.WalletAddress,
+				Username:      receipt.User.Username,
+				Avatar:        receipt.User.Avatar,
+				Status:        receipt.User.Status,
+			}
+		}
+		receipts[i] = response.MessageReceiptDTO{
+			UserID:      receipt.UserID,
+			User:        u,
+			DeliveredAt: receipt.DeliveredAt,
+			ReadAt:      receipt.ReadAt,
+		}
 	}
+
+	c.JSON(http.StatusOK, response.GetMessageReceiptsResponse{
+		MessageID:  result.MessageID,
+		Recipients: result.Recipients,
+		Delivered:  result.Delivered,
+		Read:       result.Read,
+		Receipts:   receipts,
+	})
 }
 
-// AddReaction handles POST /api/v1/messages/:id/react (Day 13)
-func (h *MessageHandler) AddReaction(c *gin.Context) {
+// GetThread handles GET /api/v1/messages/:id/thread
+func (h *MessageHandler) GetThread(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -629,37 +1108,43 @@ func (h *MessageHandler) AddReaction(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		Emoji string `json:"emoji" binding:"required"`
-	}
-
-	if err := c.ShouldBindJSON(&req); err != nil {
+	var req request.GetThreadRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "invalid_request",
-			Message: err.Error(),
+			Message: "Invalid query parameters",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	if err := h.messageService.AddReaction(c.Request.Context(), userID, messageID, req.Emoji); err != nil {
-		logger.Error("Failed to add reaction", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "add_reaction_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+	result, err := h.messageService.GetThread(c.Request.Context(), userID, &dto.GetThreadRequest{
+		MessageID: messageID,
+		After:     req.After,
+		Limit:     req.Limit,
+	})
+	if err != nil {
+		logger.Error("Failed to get thread", zap.Error(err))
+		respondThreadError(c, err, "get_thread_failed")
 		return
 	}
 
-	// WebSocket broadcast is handled by the service layer
-	// Removed duplicate broadcast to prevent double events
+	replies := make([]response.MessageDTO, len(result.Replies))
+	for i, reply := range result.Replies {
+		replies[i] = mapMessageDTO(reply)
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "reaction added"})
+	c.JSON(http.StatusOK, response.GetThreadResponse{
+		Root:        mapMessageDTO(result.Root),
+		Replies:     replies,
+		IsFollowing: result.IsFollowing,
+		NextCursor:  result.NextCursor,
+		HasMore:     result.HasMore,
+	})
 }
 
-// RemoveReaction handles DELETE /api/v1/messages/:id/react (Day 13)
-func (h *MessageHandler) RemoveReaction(c *gin.Context) {
+// FollowThread handles POST /api/v1/messages/:id/thread/follow
+func (h *MessageHandler) FollowThread(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -690,40 +1175,110 @@ func (h *MessageHandler) RemoveReaction(c *gin.Context) {
 		return
 	}
 
-	emoji := c.Query("emoji")
-	if emoji == "" {
+	if err := h.messageService.FollowThread(c.Request.Context(), userID, messageID); err != nil {
+		logger.Error("Failed to follow thread", zap.Error(err))
+		respondThreadError(c, err, "follow_thread_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "thread followed"})
+}
+
+// UnfollowThread handles DELETE /api/v1/messages/:id/thread/follow
+func (h *MessageHandler) UnfollowThread(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "missing_emoji",
-			Message: "Emoji parameter is required",
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	if err := h.messageService.RemoveReaction(c.Request.Context(), userID, messageID, emoji); err != nil {
-		logger.Error("Failed to remove reaction", zap.Error(err))
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.UnfollowThread(c.Request.Context(), userID, messageID); err != nil {
+		logger.Error("Failed to unfollow thread", zap.Error(err))
+		respondThreadError(c, err, "unfollow_thread_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "thread unfollowed"})
+}
+
+// respondThreadError maps thread and message lookup errors to HTTP responses
+func respondThreadError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, conversation.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "not_participant",
+			Message: "You are not a participant in this conversation",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, domainMessage.ErrMessageNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "message_not_found",
+			Message: "Message not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrInvalidCursor):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_cursor",
+			Message: "Invalid pagination cursor",
+			Code:    http.StatusBadRequest,
+		})
+	default:
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "remove_reaction_failed",
+			Error:   code,
 			Message: err.Error(),
 			Code:    http.StatusInternalServerError,
 		})
-		return
 	}
+}
 
-	logger.Info("Reaction removed successfully",
-		zap.String("message_id", messageID.String()),
-		zap.String("emoji", emoji),
-		zap.String("user_id", userID.String()),
-	)
+// Polls
 
-	// WebSocket broadcast is handled by the service layer
-	// Removed duplicate broadcast to prevent double events
+// VotePoll handles POST /api/v1/messages/:id/poll/vote
+func (h *MessageHandler) VotePoll(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "reaction removed"})
-}
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
 
-// GetMessageReactions handles GET /api/v1/messages/:id/reactions (Day 13)
-func (h *MessageHandler) GetMessageReactions(c *gin.Context) {
 	messageID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
@@ -734,22 +1289,30 @@ func (h *MessageHandler) GetMessageReactions(c *gin.Context) {
 		return
 	}
 
-	reactions, err := h.messageService.GetMessageReactions(c.Request.Context(), messageID)
-	if err != nil {
-		logger.Error("Failed to get reactions", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "get_reactions_failed",
+	var req request.VotePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, reactions)
+	poll, err := h.messageService.VotePoll(c.Request.Context(), userID, messageID, req.Options)
+	if err != nil {
+		logger.Error("Failed to vote in poll", zap.Error(err))
+		respondPollError(c, err, "vote_poll_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.PollResponse{
+		Poll: mapPollDTO(*poll),
+	})
 }
 
-// PinMessage handles POST /api/v1/messages/:id/pin (Day 13)
-func (h *MessageHandler) PinMessage(c *gin.Context) {
+// RetractPollVote handles DELETE /api/v1/messages/:id/poll/vote
+func (h *MessageHandler) RetractPollVote(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -780,20 +1343,1606 @@ func (h *MessageHandler) PinMessage(c *gin.Context) {
 		return
 	}
 
-	var req struct {
-		ConversationID string `json:"conversation_id" binding:"required"`
+	poll, err := h.messageService.RetractPollVote(c.Request.Context(), userID, messageID)
+	if err != nil {
+		logger.Error("Failed to retract poll vote", zap.Error(err))
+		respondPollError(c, err, "retract_poll_vote_failed")
+		return
 	}
 
-	if err := c.ShouldBindJSON(&req); err != nil {
+	c.JSON(http.StatusOK, response.PollResponse{
+		Poll: mapPollDTO(*poll),
+	})
+}
+
+// ClosePoll handles POST /api/v1/messages/:id/poll/close
+func (h *MessageHandler) ClosePoll(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	poll, err := h.messageService.ClosePoll(c.Request.Context(), userID, messageID)
+	if err != nil {
+		logger.Error("Failed to close poll", zap.Error(err))
+		respondPollError(c, err, "close_poll_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.PollResponse{
+		Poll: mapPollDTO(*poll),
+	})
+}
+
+// respondPollError maps poll voting errors to HTTP responses
+func respondPollError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, privacy.ErrUserUnavailable):
+		respondUserUnavailable(c)
+	case errors.Is(err, conversation.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "not_participant",
+			Message: "You are not a participant in this conversation",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, domainMessage.ErrPollVoteNotAllowed):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "poll_vote_not_allowed",
+			Message: "Only the channel owner and admins can vote in this channel's polls",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, domainMessage.ErrUnauthorized):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "Only the sender can close a poll",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, domainMessage.ErrMessageNotFound), errors.Is(err, domainMessage.ErrNotAPoll):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "poll_not_found",
+			Message: "Poll not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrPollVoteNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "poll_vote_not_found",
+			Message: "You have not voted in this poll",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrMessageDeleted):
+		c.JSON(http.StatusGone, response.ErrorResponse{
+			Error:   "message_deleted",
+			Message: "Message was deleted",
+			Code:    http.StatusGone,
+		})
+	case errors.Is(err, domainMessage.ErrInvalidPollVote):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_poll_vote",
+			Message: "Choose existing options, only one unless the poll allows multiple answers",
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, domainMessage.ErrPollClosed):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "poll_closed",
+			Message: "Poll is closed",
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, domainMessage.ErrPollVoteFinal):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "poll_vote_final",
+			Message: "Quiz answers cannot be changed",
+			Code:    http.StatusConflict,
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   code,
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+// GetMentions handles GET /api/v1/mentions
+func (h *MessageHandler) GetMentions(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.GetMentionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid query parameters",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := req.ParseConversationID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.GetMentions(c.Request.Context(), userID, &dto.GetMentionsRequest{
+		ConversationID: conversationID,
+		UnreadOnly:     req.UnreadOnly,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		logger.Error("Failed to get mentions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "get_mentions_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	mentions := make([]response.MentionDTO, len(result.Mentions))
+	for i, m := range result.Mentions {
+		var msg *response.MessageDTO
+		if m.Message != nil {
+			mapped := mapMessageDTO(*m.Message)
+			msg = &mapped
+		}
+		mentions[i] = response.MentionDTO{
+			ID:             m.ID,
+			MessageID:      m.MessageID,
+			ConversationID: m.ConversationID,
+			Position:       m.Position,
+			Length:         m.Length,
+			Read:           m.Read,
+			CreatedAt:      m.CreatedAt,
+			Message:        msg,
+		}
+	}
+
+	c.JSON(http.StatusOK, response.GetMentionsResponse{
+		Mentions:     mentions,
+		UnreadCounts: result.UnreadCounts,
+		TotalUnread:  result.TotalUnread,
+	})
+}
+
+// Sync handles GET /api/v1/sync
+func (h *MessageHandler) Sync(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.SyncRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid query parameters",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.Sync(c.Request.Context(), userID, &dto.SyncRequest{
+		Since: req.Since,
+		Limit: req.Limit,
+	})
+	if err != nil {
+		logger.Error("Failed to sync", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "sync_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	conversations := make([]response.ConversationDTO, len(result.Conversations))
+	for i, conv := range result.Conversations {
+		conversations[i] = h.mapConversationDTOWithPrivacy(c.Request.Context(), conv, userID)
+	}
+
+	messages := make([]response.MessageDTO, len(result.Messages))
+	for i, msg := range result.Messages {
+		messages[i] = mapMessageDTO(msg)
+	}
+
+	deleted := make([]response.DeletedMessageDTO, len(result.DeletedMessages))
+	for i, d := range result.DeletedMessages {
+		deleted[i] = response.DeletedMessageDTO{
+			ID:             d.ID,
+			ConversationID: d.ConversationID,
+		}
+	}
+
+	c.JSON(http.StatusOK, response.SyncResponse{
+		Seq:                    result.Seq,
+		HasMore:                result.HasMore,
+		FullResync:             result.FullResync,
+		Conversations:          conversations,
+		RemovedConversationIDs: result.RemovedConversationIDs,
+		Messages:               messages,
+		DeletedMessages:        deleted,
+	})
+}
+
+// ScheduleMessage handles POST /api/v1/scheduled-messages
+func (h *MessageHandler) ScheduleMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.ScheduleMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := req.ParseConversationID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	replyToID, err := req.ParseReplyToID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_reply_to_id",
+			Message: "Invalid reply to ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.ScheduleMessage(c.Request.Context(), userID, &dto.ScheduleMessageRequest{
+		ConversationID: conversationID,
+		Content:        req.Content,
+		ContentType:    req.ContentType,
+		ReplyToID:      replyToID,
+		SendAt:         req.SendAt,
+	})
+	if err != nil {
+		logger.Error("Failed to schedule message", zap.Error(err))
+		respondScheduledError(c, err, "schedule_message_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.ScheduledMessageResponse{
+		ScheduledMessage: mapScheduledMessageDTO(*result),
+	})
+}
+
+// GetScheduledMessages handles GET /api/v1/scheduled-messages
+func (h *MessageHandler) GetScheduledMessages(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.GetScheduledMessagesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid query parameters",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := req.ParseConversationID()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.GetScheduledMessages(c.Request.Context(), userID, &dto.GetScheduledMessagesRequest{
+		ConversationID: conversationID,
+		Status:         req.Status,
+		Limit:          req.Limit,
+		Offset:         req.Offset,
+	})
+	if err != nil {
+		logger.Error("Failed to get scheduled messages", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "get_scheduled_messages_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	scheduled := make([]response.ScheduledMessageDTO, len(result.ScheduledMessages))
+	for i, sm := range result.ScheduledMessages {
+		scheduled[i] = mapScheduledMessageDTO(sm)
+	}
+
+	c.JSON(http.StatusOK, response.GetScheduledMessagesResponse{
+		ScheduledMessages: scheduled,
+	})
+}
+
+// UpdateScheduledMessage handles PUT /api/v1/scheduled-messages/:id
+func (h *MessageHandler) UpdateScheduledMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	scheduledID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_scheduled_message_id",
+			Message: "Invalid scheduled message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.UpdateScheduledMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logger.Error("Failed to bind request", zap.Error(err))
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid request body",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if (req.Content != nil && *req.Content == "") || (req.ContentType != nil && *req.ContentType == "") {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "content and content_type cannot be empty",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.UpdateScheduledMessage(c.Request.Context(), userID, scheduledID, &dto.UpdateScheduledMessageRequest{
+		Content:     req.Content,
+		ContentType: req.ContentType,
+		SendAt:      req.SendAt,
+	})
+	if err != nil {
+		logger.Error("Failed to update scheduled message", zap.Error(err))
+		respondScheduledError(c, err, "update_scheduled_message_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.ScheduledMessageResponse{
+		ScheduledMessage: mapScheduledMessageDTO(*result),
+	})
+}
+
+// CancelScheduledMessage handles DELETE /api/v1/scheduled-messages/:id
+func (h *MessageHandler) CancelScheduledMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	scheduledID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_scheduled_message_id",
+			Message: "Invalid scheduled message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.CancelScheduledMessage(c.Request.Context(), userID, scheduledID); err != nil {
+		logger.Error("Failed to cancel scheduled message", zap.Error(err))
+		respondScheduledError(c, err, "cancel_scheduled_message_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "scheduled message canceled"})
+}
+
+// respondScheduledError maps scheduled message errors to HTTP responses
+func respondScheduledError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, privacy.ErrUserUnavailable):
+		respondUserUnavailable(c)
+	case errors.Is(err, conversation.ErrNotParticipant), errors.Is(err, domainMessage.ErrSendNotAllowed):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "send_not_allowed",
+			Message: err.Error(),
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, conversation.ErrConversationNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "conversation_not_found",
+			Message: "Conversation not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrScheduledMessageNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "scheduled_message_not_found",
+			Message: "Scheduled message not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, domainMessage.ErrScheduledNotPending):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "scheduled_message_not_pending",
+			Message: "Scheduled message was already sent or canceled",
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, domainMessage.ErrInvalidSendTime):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_send_time",
+			Message: "send_at must be in the future",
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, domainMessage.ErrPollUnsupported):
+		respondPollUnsupported(c)
+	default:
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   code,
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+	}
+}
+
+// mapScheduledMessageDTO maps a scheduled message DTO to its response
+func mapScheduledMessageDTO(sm dto.ScheduledMessageDTO) response.ScheduledMessageDTO {
+	return response.ScheduledMessageDTO{
+		ID:             sm.ID,
+		ConversationID: sm.ConversationID,
+		SenderID:       sm.SenderID,
+		Content:        sm.Content,
+		ContentType:    sm.ContentType,
+		ReplyToID:      sm.ReplyToID,
+		SendAt:         sm.SendAt,
+		Status:         sm.Status,
+		SentAt:         sm.SentAt,
+		LastError:      sm.LastError,
+		CreatedAt:      sm.CreatedAt,
+		UpdatedAt:      sm.UpdatedAt,
+	}
+}
+
+// Helper functions to map DTOs
+func mapMessageDTO(msg dto.MessageDTO) response.MessageDTO {
+	var sender *response.UserDTO
+	if msg.Sender != nil {
+		sender = &response.UserDTO{
+			ID:            msg.Sender.ID,
+			WalletAddress: msg.Sender.WalletAddress,
+			Username:      msg.Sender.Username,
+			Avatar:        msg.Sender.Avatar,
+			Status:        msg.Sender.Status,
+		}
+	}
+
+	var thread *response.ThreadSummaryDTO
+	if msg.Thread != nil {
+		thread = &response.ThreadSummaryDTO{
+			ReplyCount:    msg.Thread.ReplyCount,
+			LastReplyID:   msg.Thread.LastReplyID,
+			LastReplyAt:   msg.Thread.LastReplyAt,
+			LastReplierID: msg.Thread.LastReplierID,
+		}
+	}
+
+	var receipts *response.ReceiptCountsDTO
+	if msg.Receipts != nil {
+		receipts = &response.ReceiptCountsDTO{
+			Delivered: msg.Receipts.Delivered,
+			Read:      msg.Receipts.Read,
+		}
+	}
+
+	var poll *response.PollDTO
+	if msg.Poll != nil {
+		mapped := mapPollDTO(*msg.Poll)
+		poll = &mapped
+	}
+
+	var linkPreviews []response.LinkPreviewDTO
+	for _, preview := range msg.LinkPreviews {
+		linkPreviews = append(linkPreviews, response.LinkPreviewDTO{
+			URL:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageURL:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		})
+	}
+
+	return response.MessageDTO{
+		ID:              msg.ID,
+		ConversationID:  msg.ConversationID,
+		SenderID:        msg.SenderID,
+		Content:         msg.Content,
+		ContentType:     msg.ContentType,
+		Signature:       msg.Signature,
+		ReplyToID:       msg.ReplyToID,
+		Status:          msg.Status,
+		CreatedAt:       msg.CreatedAt,
+		UpdatedAt:       msg.UpdatedAt,
+		Sender:          sender,
+		Reactions:       msg.Reactions,
+		IsPinned:        msg.IsPinned,
+		PinnedBy:        msg.PinnedBy,
+		ExpiresAt:       msg.ExpiresAt,
+		ThreadID:        msg.ThreadID,
+		Thread:          thread,
+		Receipts:        receipts,
+		EditedAt:        msg.EditedAt,
+		EditCount:       msg.EditCount,
+		DeletedAt:       msg.DeletedAt,
+		DeletedBy:       msg.DeletedBy,
+		ClientMessageID: msg.ClientMessageID,
+		LinkPreviews:    linkPreviews,
+		Poll:            poll,
+	}
+}
+
+// mapPollDTO maps a poll DTO to its response
+func mapPollDTO(poll dto.PollDTO) response.PollDTO {
+	options := make([]response.PollOptionDTO, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = response.PollOptionDTO{
+			Position:  option.Position,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+			Voters:    option.Voters,
+		}
+	}
+
+	return response.PollDTO{
+		MessageID:      poll.MessageID,
+		Question:       poll.Question,
+		Options:        options,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Quiz:           poll.Quiz,
+		CorrectOption:  poll.CorrectOption,
+		ClosesAt:       poll.ClosesAt,
+		IsClosed:       poll.IsClosed,
+		TotalVoters:    poll.TotalVoters,
+		Voted:          poll.Voted,
+	}
+}
+
+// mapConversationDTOWithPrivacy maps conversation DTO with privacy checking
+func (h *MessageHandler) mapConversationDTOWithPrivacy(ctx context.Context, conv dto.ConversationDTO, currentUserID uuid.UUID) response.ConversationDTO {
+	participants := make([]response.UserDTO, len(conv.Participants))
+	for i, p := range conv.Participants {
+		// Default to hiding everything
+		canSeeAvatar := false
+		canSeeLastSeen := false
+		canSeeStatus := false
+
+		// Parse target user ID
+		targetUserID, err := uuid.Parse(p.ID)
+		if err == nil && targetUserID != currentUserID {
+			// Get target user's privacy settings
+			privacySettings, err := h.privacyRepo.GetPrivacySettings(ctx, targetUserID)
+			if err == nil && privacySettings != nil {
+				// Determine relationship between current user and target user
+				relationship := "stranger"
+				isContact, err := h.contactRepo.IsContact(ctx, currentUserID, targetUserID)
+				if err == nil && isContact {
+					relationship = "contact"
+				}
+
+				// Calculate privacy flags based on target user's settings
+				canSeeAvatar = privacySettings.CanSeeProfilePhoto(relationship)
+				canSeeLastSeen = privacySettings.CanSeeLastSeen(relationship)
+				canSeeStatus = privacySettings.CanSeeStatus(relationship)
+			} else {
+				// If no privacy settings found, default to everyone can see (for backwards compatibility)
+				canSeeAvatar = true
+				canSeeLastSeen = true
+				canSeeStatus = true
+			}
+		} else if targetUserID == currentUserID {
+			// User can always see their own information
+			canSeeAvatar = true
+			canSeeLastSeen = true
+			canSeeStatus = true
+		}
+
+		// Nothing is shared across a block, whichever side blocked
+		isOnline := p.IsOnline
+		if targetUserID != currentUserID {
+			if conv.RequestPending {
+				// Presence is shared once the message request is accepted
+				canSeeLastSeen = false
+				isOnline = false
+			}
+			if blocked, err := h.privacyRepo.IsBlockedBetween(ctx, currentUserID, targetUserID); err == nil && blocked {
+				canSeeAvatar = false
+				canSeeLastSeen = false
+				canSeeStatus = false
+				isOnline = false
+			}
+		}
+
+		participants[i] = response.UserDTO{
+			ID:             p.ID,
+			WalletAddress:  p.WalletAddress,
+			Username:       p.Username,
+			Avatar:         p.Avatar,
+			Status:         p.Status,
+			IsOnline:       isOnline,
+			LastSeen:       &p.LastSeen,
+			CanSeeAvatar:   &canSeeAvatar,
+			CanSeeLastSeen: &canSeeLastSeen,
+			CanSeeStatus:   &canSeeStatus,
+		}
+	}
+
+	var lastMessage *response.MessageDTO
+	if conv.LastMessage != nil {
+		msg := mapMessageDTO(*conv.LastMessage)
+		lastMessage = &msg
+	}
+
+	return response.ConversationDTO{
+		ID:                 conv.ID,
+		Type:               conv.Type,
+		Participants:       participants,
+		LastMessage:        lastMessage,
+		UnreadCount:        conv.UnreadCount,
+		UnreadMentionCount: conv.UnreadMentionCount,
+		IsRequest:          conv.IsRequest,
+		RequestPending:     conv.RequestPending,
+		LastReadMessageID:  conv.LastReadMessageID,
+		IsArchived:         conv.IsArchived,
+		IsPinned:           conv.IsPinned,
+		IsMuted:            conv.IsMuted,
+		MutedUntil:         conv.MutedUntil,
+		FolderIDs:          conv.FolderIDs,
+		CreatedAt:          conv.CreatedAt,
+		UpdatedAt:          conv.UpdatedAt,
+	}
+}
+
+// AddReaction handles POST /api/v1/messages/:id/react (Day 13)
+func (h *MessageHandler) AddReaction(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req struct {
+		Emoji string `json:"emoji" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.AddReaction(c.Request.Context(), userID, messageID, req.Emoji); err != nil {
+		logger.Error("Failed to add reaction", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "add_reaction_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// WebSocket broadcast is handled by the service layer
+	// Removed duplicate broadcast to prevent double events
+
+	c.JSON(http.StatusOK, gin.H{"message": "reaction added"})
+}
+
+// RemoveReaction handles DELETE /api/v1/messages/:id/react (Day 13)
+func (h *MessageHandler) RemoveReaction(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	emoji := c.Query("emoji")
+	if emoji == "" {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "missing_emoji",
+			Message: "Emoji parameter is required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.RemoveReaction(c.Request.Context(), userID, messageID, emoji); err != nil {
+		logger.Error("Failed to remove reaction", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "remove_reaction_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logger.Info("Reaction removed successfully",
+		zap.String("message_id", messageID.String()),
+		zap.String("emoji", emoji),
+		zap.String("user_id", userID.String()),
+	)
+
+	// WebSocket broadcast is handled by the service layer
+	// Removed duplicate broadcast to prevent double events
+
+	c.JSON(http.StatusOK, gin.H{"message": "reaction removed"})
+}
+
+// GetMessageReactions handles GET /api/v1/messages/:id/reactions (Day 13)
+func (h *MessageHandler) GetMessageReactions(c *gin.Context) {
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	reactions, err := h.messageService.GetMessageReactions(c.Request.Context(), messageID)
+	if err != nil {
+		logger.Error("Failed to get reactions", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "get_reactions_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, reactions)
+}
+
+// PinMessage handles POST /api/v1/messages/:id/pin (Day 13)
+func (h *MessageHandler) PinMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req struct {
+		ConversationID string `json:"conversation_id" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(req.ConversationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.PinMessage(c.Request.Context(), userID, conversationID, messageID); err != nil {
+		logger.Error("Failed to pin message", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "pin_message_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "message pinned"})
+}
+
+// UnpinMessage handles DELETE /api/v1/messages/:id/pin (Day 13)
+func (h *MessageHandler) UnpinMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Query("conversation_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "conversation_id query parameter required",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.UnpinMessage(c.Request.Context(), userID, conversationID, messageID); err != nil {
+		logger.Error("Failed to unpin message", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "unpin_message_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "message unpinned"})
+}
+
+// GetPinnedMessages handles GET /api/v1/conversations/:id/pinned (Day 13)
+func (h *MessageHandler) GetPinnedMessages(c *gin.Context) {
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	pinnedMessages, err := h.messageService.GetPinnedMessages(c.Request.Context(), conversationID)
+	if err != nil {
+		logger.Error("Failed to get pinned messages", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "get_pinned_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pinnedMessages)
+}
+
+// ForwardMessage handles POST /api/v1/messages/:id/forward (Day 13)
+func (h *MessageHandler) ForwardMessage(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	messageID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_message_id",
+			Message: "Invalid message ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req dto.ForwardMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	targetConversationID, err := uuid.Parse(req.ConversationID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_target_conversation_id",
+			Message: "Invalid target conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.ForwardMessage(c.Request.Context(), userID, messageID, targetConversationID)
+	if err != nil {
+		logger.Error("Failed to forward message", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
+		if errors.Is(err, domainMessage.ErrPollUnsupported) {
+			respondPollUnsupported(c)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "forward_message_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// SearchMessages handles POST /api/v1/messages/search (Day 13)
+func (h *MessageHandler) SearchMessages(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req dto.SearchMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	result, err := h.messageService.SearchMessages(c.Request.Context(), userID, &req)
+	if err != nil {
+		logger.Error("Failed to search messages", zap.Error(err))
+		if errors.Is(err, domainMessage.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: "Invalid search cursor",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "search_messages_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ArchiveConversation handles PUT /api/v1/conversations/:id/archive
+func (h *MessageHandler) ArchiveConversation(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Get conversation ID from URL parameter
+	conversationIDStr := c.Param("id")
+	conversationID, err := uuid.Parse(conversationIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Archive the conversation
+	if err := h.messageService.ArchiveConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to archive conversation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "archive_conversation_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation archived successfully",
+	})
+}
+
+// UnarchiveConversation handles PUT /api/v1/conversations/:id/unarchive
+func (h *MessageHandler) UnarchiveConversation(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Get conversation ID from URL parameter
+	conversationIDStr := c.Param("id")
+	conversationID, err := uuid.Parse(conversationIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Unarchive the conversation
+	if err := h.messageService.UnarchiveConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to unarchive conversation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "unarchive_conversation_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation unarchived successfully",
+	})
+}
+
+// DeleteConversation handles DELETE /api/v1/conversations/:id
+func (h *MessageHandler) DeleteConversation(c *gin.Context) {
+	// Get user ID from context
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Get conversation ID from URL parameter
+	conversationIDStr := c.Param("id")
+	conversationID, err := uuid.Parse(conversationIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// Delete the conversation
+	if err := h.messageService.DeleteConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to delete conversation", zap.Error(err))
+		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+			Error:   "delete_conversation_failed",
+			Message: err.Error(),
+			Code:    http.StatusInternalServerError,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation deleted successfully",
+	})
+}
+
+// PinConversation handles PUT /api/v1/conversations/:id/pin
+func (h *MessageHandler) PinConversation(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.PinConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to pin conversation", zap.Error(err))
+		respondConversationStateError(c, err, "pin_conversation_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation pinned successfully",
+	})
+}
+
+// UnpinConversation handles PUT /api/v1/conversations/:id/unpin
+func (h *MessageHandler) UnpinConversation(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := h.messageService.UnpinConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to unpin conversation", zap.Error(err))
+		respondConversationStateError(c, err, "unpin_conversation_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation unpinned successfully",
+	})
+}
+
+// ReorderPinnedConversations handles PUT /api/v1/conversations/pins
+func (h *MessageHandler) ReorderPinnedConversations(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	var req request.ReorderPinnedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
+			Message: err.Error(),
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationIDs := make([]uuid.UUID, len(req.ConversationIDs))
+	for i, idStr := range req.ConversationIDs {
+		conversationIDs[i], err = uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_conversation_id",
+				Message: "Invalid conversation ID: " + idStr,
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	if err := h.messageService.ReorderPinnedConversations(c.Request.Context(), userID, &dto.ReorderPinnedRequest{
+		ConversationIDs: conversationIDs,
+	}); err != nil {
+		logger.Error("Failed to reorder pinned conversations", zap.Error(err))
+		respondConversationStateError(c, err, "reorder_pinned_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Pinned conversations reordered successfully",
+	})
+}
+
+// MuteConversation handles PUT /api/v1/conversations/:id/mute
+func (h *MessageHandler) MuteConversation(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	// The body is optional, without one the conversation is muted until unmuted
+	var req request.MuteConversationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_request",
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+	}
+
+	if err := h.messageService.MuteConversation(c.Request.Context(), userID, conversationID, &dto.MuteConversationRequest{
+		Until: req.Until,
+	}); err != nil {
+		logger.Error("Failed to mute conversation", zap.Error(err))
+		respondConversationStateError(c, err, "mute_conversation_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation muted successfully",
+	})
+}
+
+// UnmuteConversation handles PUT /api/v1/conversations/:id/unmute
+func (h *MessageHandler) UnmuteConversation(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_request",
-			Message: err.Error(),
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	conversationID, err := uuid.Parse(req.ConversationID)
+	conversationID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "invalid_conversation_id",
@@ -803,21 +2952,19 @@ func (h *MessageHandler) PinMessage(c *gin.Context) {
 		return
 	}
 
-	if err := h.messageService.PinMessage(c.Request.Context(), userID, conversationID, messageID); err != nil {
-		logger.Error("Failed to pin message", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "pin_message_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
-		})
+	if err := h.messageService.UnmuteConversation(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to unmute conversation", zap.Error(err))
+		respondConversationStateError(c, err, "unmute_conversation_failed")
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "message pinned"})
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation unmuted successfully",
+	})
 }
 
-// UnpinMessage handles DELETE /api/v1/messages/:id/pin (Day 13)
-func (h *MessageHandler) UnpinMessage(c *gin.Context) {
+// GetFolders handles GET /api/v1/folders
+func (h *MessageHandler) GetFolders(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -838,67 +2985,69 @@ func (h *MessageHandler) UnpinMessage(c *gin.Context) {
 		return
 	}
 
-	messageID, err := uuid.Parse(c.Param("id"))
+	result, err := h.messageService.GetFolders(c.Request.Context(), userID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_message_id",
-			Message: "Invalid message ID",
-			Code:    http.StatusBadRequest,
-		})
+		logger.Error("Failed to get folders", zap.Error(err))
+		respondConversationStateError(c, err, "get_folders_failed")
 		return
 	}
 
-	conversationID, err := uuid.Parse(c.Query("conversation_id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_conversation_id",
-			Message: "conversation_id query parameter required",
-			Code:    http.StatusBadRequest,
-		})
-		return
+	folders := make([]response.FolderDTO, len(result.Folders))
+	for i, folder := range result.Folders {
+		folders[i] = mapFolderDTO(folder)
 	}
 
-	if err := h.messageService.UnpinMessage(c.Request.Context(), userID, conversationID, messageID); err != nil {
-		logger.Error("Failed to unpin message", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "unpin_message_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+	c.JSON(http.StatusOK, response.GetFoldersResponse{
+		Folders: folders,
+	})
+}
+
+// CreateFolder handles POST /api/v1/folders
+func (h *MessageHandler) CreateFolder(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "message unpinned"})
-}
-
-// GetPinnedMessages handles GET /api/v1/conversations/:id/pinned (Day 13)
-func (h *MessageHandler) GetPinnedMessages(c *gin.Context) {
-	conversationID, err := uuid.Parse(c.Param("id"))
+	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_conversation_id",
-			Message: "Invalid conversation ID",
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	pinnedMessages, err := h.messageService.GetPinnedMessages(c.Request.Context(), conversationID)
-	if err != nil {
-		logger.Error("Failed to get pinned messages", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "get_pinned_failed",
+	var req request.FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_request",
 			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, pinnedMessages)
+	folder, err := h.messageService.CreateFolder(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		logger.Error("Failed to create folder", zap.Error(err))
+		respondConversationStateError(c, err, "create_folder_failed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, response.FolderResponse{
+		Folder: mapFolderDTO(*folder),
+	})
 }
 
-// ForwardMessage handles POST /api/v1/messages/:id/forward (Day 13)
-func (h *MessageHandler) ForwardMessage(c *gin.Context) {
+// RenameFolder handles PATCH /api/v1/folders/:id
+func (h *MessageHandler) RenameFolder(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -919,17 +3068,17 @@ func (h *MessageHandler) ForwardMessage(c *gin.Context) {
 		return
 	}
 
-	messageID, err := uuid.Parse(c.Param("id"))
+	folderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_message_id",
-			Message: "Invalid message ID",
+			Error:   "invalid_folder_id",
+			Message: "Invalid folder ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	var req dto.ForwardMessageRequest
+	var req request.FolderRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "invalid_request",
@@ -939,32 +3088,63 @@ func (h *MessageHandler) ForwardMessage(c *gin.Context) {
 		return
 	}
 
-	targetConversationID, err := uuid.Parse(req.ConversationID)
+	folder, err := h.messageService.RenameFolder(c.Request.Context(), userID, folderID, req.Name)
+	if err != nil {
+		logger.Error("Failed to rename folder", zap.Error(err))
+		respondConversationStateError(c, err, "rename_folder_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.FolderResponse{
+		Folder: mapFolderDTO(*folder),
+	})
+}
+
+// DeleteFolder handles DELETE /api/v1/folders/:id
+func (h *MessageHandler) DeleteFolder(c *gin.Context) {
+	userIDStr, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+			Code:    http.StatusUnauthorized,
+		})
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_target_conversation_id",
-			Message: "Invalid target conversation ID",
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	result, err := h.messageService.ForwardMessage(c.Request.Context(), userID, messageID, targetConversationID)
+	folderID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
-		logger.Error("Failed to forward message", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "forward_message_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_folder_id",
+			Message: "Invalid folder ID",
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if err := h.messageService.DeleteFolder(c.Request.Context(), userID, folderID); err != nil {
+		logger.Error("Failed to delete folder", zap.Error(err))
+		respondConversationStateError(c, err, "delete_folder_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Folder deleted successfully",
+	})
 }
 
-// SearchMessages handles POST /api/v1/messages/search (Day 13)
-func (h *MessageHandler) SearchMessages(c *gin.Context) {
+// AddConversationToFolder handles PUT /api/v1/folders/:id/conversations/:conversation_id
+func (h *MessageHandler) AddConversationToFolder(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -985,33 +3165,39 @@ func (h *MessageHandler) SearchMessages(c *gin.Context) {
 		return
 	}
 
-	var req dto.SearchMessagesRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	folderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_request",
-			Message: err.Error(),
+			Error:   "invalid_folder_id",
+			Message: "Invalid folder ID",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	result, err := h.messageService.SearchMessages(c.Request.Context(), userID, &req)
+	conversationID, err := uuid.Parse(c.Param("conversation_id"))
 	if err != nil {
-		logger.Error("Failed to search messages", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "search_messages_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_conversation_id",
+			Message: "Invalid conversation ID",
+			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, result)
+	if err := h.messageService.AddConversationToFolder(c.Request.Context(), userID, folderID, conversationID); err != nil {
+		logger.Error("Failed to add conversation to folder", zap.Error(err))
+		respondConversationStateError(c, err, "add_to_folder_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation added to folder successfully",
+	})
 }
 
-// ArchiveConversation handles PUT /api/v1/conversations/:id/archive
-func (h *MessageHandler) ArchiveConversation(c *gin.Context) {
-	// Get user ID from context
+// RemoveConversationFromFolder handles DELETE /api/v1/folders/:id/conversations/:conversation_id
+func (h *MessageHandler) RemoveConversationFromFolder(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -1032,9 +3218,17 @@ func (h *MessageHandler) ArchiveConversation(c *gin.Context) {
 		return
 	}
 
-	// Get conversation ID from URL parameter
-	conversationIDStr := c.Param("id")
-	conversationID, err := uuid.Parse(conversationIDStr)
+	folderID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_folder_id",
+			Message: "Invalid folder ID",
+			Code:    http.StatusBadRequest,
+		})
+		return
+	}
+
+	conversationID, err := uuid.Parse(c.Param("conversation_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "invalid_conversation_id",
@@ -1044,25 +3238,89 @@ func (h *MessageHandler) ArchiveConversation(c *gin.Context) {
 		return
 	}
 
-	// Archive the conversation
-	if err := h.messageService.ArchiveConversation(c.Request.Context(), userID, conversationID); err != nil {
-		logger.Error("Failed to archive conversation", zap.Error(err))
+	if err := h.messageService.RemoveConversationFromFolder(c.Request.Context(), userID, folderID, conversationID); err != nil {
+		logger.Error("Failed to remove conversation from folder", zap.Error(err))
+		respondConversationStateError(c, err, "remove_from_folder_failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Conversation removed from folder successfully",
+	})
+}
+
+// respondConversationStateError maps pin, mute and folder errors to HTTP responses
+func respondConversationStateError(c *gin.Context, err error, code string) {
+	switch {
+	case errors.Is(err, conversation.ErrNotParticipant):
+		c.JSON(http.StatusForbidden, response.ErrorResponse{
+			Error:   "not_participant",
+			Message: "You are not a participant in this conversation",
+			Code:    http.StatusForbidden,
+		})
+	case errors.Is(err, conversation.ErrFolderNotFound):
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "folder_not_found",
+			Message: "Folder not found",
+			Code:    http.StatusNotFound,
+		})
+	case errors.Is(err, conversation.ErrPinLimitReached):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "pin_limit_reached",
+			Message: fmt.Sprintf("At most %d conversations can be pinned", conversation.MaxPinnedConversations),
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, conversation.ErrFolderNameTaken):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "folder_name_taken",
+			Message: "You already have a folder with this name",
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, conversation.ErrFolderLimitReached):
+		c.JSON(http.StatusConflict, response.ErrorResponse{
+			Error:   "folder_limit_reached",
+			Message: fmt.Sprintf("At most %d folders can be created", conversation.MaxFolders),
+			Code:    http.StatusConflict,
+		})
+	case errors.Is(err, conversation.ErrInvalidPinOrder):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_pin_order",
+			Message: "List every pinned conversation exactly once",
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, conversation.ErrInvalidMuteUntil):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_mute_until",
+			Message: "Mute end must be in the future",
+			Code:    http.StatusBadRequest,
+		})
+	case errors.Is(err, conversation.ErrInvalidFolderName):
+		c.JSON(http.StatusBadRequest, response.ErrorResponse{
+			Error:   "invalid_folder_name",
+			Message: fmt.Sprintf("Folder name must be 1 to %d characters", conversation.MaxFolderNameLength),
+			Code:    http.StatusBadRequest,
+		})
+	default:
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "archive_conversation_failed",
+			Error:   code,
 			Message: err.Error(),
 			Code:    http.StatusInternalServerError,
 		})
-		return
 	}
+}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Conversation archived successfully",
-	})
+func mapFolderDTO(folder dto.FolderDTO) response.FolderDTO {
+	return response.FolderDTO{
+		ID:                folder.ID,
+		Name:              folder.Name,
+		ConversationCount: folder.ConversationCount,
+		CreatedAt:         folder.CreatedAt,
+		UpdatedAt:         folder.UpdatedAt,
+	}
 }
 
-// UnarchiveConversation handles PUT /api/v1/conversations/:id/unarchive
-func (h *MessageHandler) UnarchiveConversation(c *gin.Context) {
-	// Get user ID from context
+// GetMessageRequests handles GET /api/v1/message-requests
+func (h *MessageHandler) GetMessageRequests(c *gin.Context) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -1083,37 +3341,93 @@ func (h *MessageHandler) UnarchiveConversation(c *gin.Context) {
 		return
 	}
 
-	// Get conversation ID from URL parameter
-	conversationIDStr := c.Param("id")
-	conversationID, err := uuid.Parse(conversationIDStr)
-	if err != nil {
+	var req request.GetConversationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
-			Error:   "invalid_conversation_id",
-			Message: "Invalid conversation ID",
+			Error:   "invalid_request",
+			Message: "Invalid query parameters",
 			Code:    http.StatusBadRequest,
 		})
 		return
 	}
 
-	// Unarchive the conversation
-	if err := h.messageService.UnarchiveConversation(c.Request.Context(), userID, conversationID); err != nil {
-		logger.Error("Failed to unarchive conversation", zap.Error(err))
+	result, err := h.messageService.GetMessageRequests(c.Request.Context(), userID, &dto.GetConversationsRequest{
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	})
+	if err != nil {
+		logger.Error("Failed to get message requests", zap.Error(err))
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "unarchive_conversation_failed",
+			Error:   "get_message_requests_failed",
 			Message: err.Error(),
 			Code:    http.StatusInternalServerError,
 		})
 		return
 	}
 
+	conversations := make([]response.ConversationDTO, len(result.Conversations))
+	for i, conv := range result.Conversations {
+		conversations[i] = h.mapConversationDTOWithPrivacy(c.Request.Context(), conv, userID)
+	}
+
+	c.JSON(http.StatusOK, response.GetConversationsResponse{
+		Conversations: conversations,
+	})
+}
+
+// AcceptMessageRequest handles POST /api/v1/message-requests/:id/accept
+func (h *MessageHandler) AcceptMessageRequest(c *gin.Context) {
+	userID, conversationID, ok := parseMessageRequestParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.AcceptMessageRequest(c.Request.Context(), userID, conversationID); err != nil {
+		logger.Error("Failed to accept message request", zap.Error(err))
+		respondMessageRequestError(c, err, "accept_message_request_failed")
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Conversation unarchived successfully",
+		"message": "Message request accepted",
 	})
 }
 
-// DeleteConversation handles DELETE /api/v1/conversations/:id
-func (h *MessageHandler) DeleteConversation(c *gin.Context) {
-	// Get user ID from context
+// DeleteMessageRequest handles DELETE /api/v1/message-requests/:id
+func (h *MessageHandler) DeleteMessageRequest(c *gin.Context) {
+	h.declineMessageRequest(c, false)
+}
+
+// BlockMessageRequest handles POST /api/v1/message-requests/:id/block
+func (h *MessageHandler) BlockMessageRequest(c *gin.Context) {
+	h.declineMessageRequest(c, true)
+}
+
+// declineMessageRequest deletes a message request, blocking the requester if asked to
+func (h *MessageHandler) declineMessageRequest(c *gin.Context, block bool) {
+	userID, conversationID, ok := parseMessageRequestParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.messageService.DeclineMessageRequest(c.Request.Context(), userID, conversationID, block); err != nil {
+		logger.Error("Failed to decline message request", zap.Error(err))
+		respondMessageRequestError(c, err, "decline_message_request_failed")
+		return
+	}
+
+	result := "Message request deleted"
+	if block {
+		result = "Message request deleted and user blocked"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": result,
+	})
+}
+
+// parseMessageRequestParams reads the user and the message request conversation ID.
+// It writes an error response and returns false if either is invalid.
+func parseMessageRequestParams(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, response.ErrorResponse{
@@ -1121,7 +3435,7 @@ func (h *MessageHandler) DeleteConversation(c *gin.Context) {
 			Message: "User not authenticated",
 			Code:    http.StatusUnauthorized,
 		})
-		return
+		return uuid.Nil, uuid.Nil, false
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
@@ -1131,33 +3445,44 @@ func (h *MessageHandler) DeleteConversation(c *gin.Context) {
 			Message: "Invalid user ID",
 			Code:    http.StatusBadRequest,
 		})
-		return
+		return uuid.Nil, uuid.Nil, false
 	}
 
-	// Get conversation ID from URL parameter
-	conversationIDStr := c.Param("id")
-	conversationID, err := uuid.Parse(conversationIDStr)
+	conversationID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.ErrorResponse{
 			Error:   "invalid_conversation_id",
 			Message: "Invalid conversation ID",
 			Code:    http.StatusBadRequest,
 		})
-		return
+		return uuid.Nil, uuid.Nil, false
 	}
 
-	// Delete the conversation
-	if err := h.messageService.DeleteConversation(c.Request.Context(), userID, conversationID); err != nil {
-		logger.Error("Failed to delete conversation", zap.Error(err))
-		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
-			Error:   "delete_conversation_failed",
-			Message: err.Error(),
-			Code:    http.StatusInternalServerError,
+	return userID, conversationID, true
+}
+
+func respondMessageRequestError(c *gin.Context, err error, code string) {
+	if errors.Is(err, conversation.ErrMessageRequestNotFound) {
+		c.JSON(http.StatusNotFound, response.ErrorResponse{
+			Error:   "message_request_not_found",
+			Message: "Message request not found",
+			Code:    http.StatusNotFound,
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message": "Conversation deleted successfully",
+	c.JSON(http.StatusInternalServerError, response.ErrorResponse{
+		Error:   code,
+		Message: err.Error(),
+		Code:    http.StatusInternalServerError,
+	})
+}
+
+// respondMessagingRestricted responds to a new chat with a user who does not accept new chats
+func respondMessagingRestricted(c *gin.Context) {
+	c.JSON(http.StatusForbidden, response.ErrorResponse{
+		Error:   "messaging_restricted",
+		Message: "This user does not accept new chats",
+		Code:    http.StatusForbidden,
 	})
 }
@@ -1,12 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/delivery/http/request"
 	"github.com/yourusername/sotalk/internal/delivery/http/response"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/internal/usecase/payment"
 	"github.com/yourusername/sotalk/pkg/logger"
@@ -71,6 +73,10 @@ func (h *PaymentHandler) CreatePaymentRequest(c *gin.Context) {
 	result, err := h.paymentService.CreatePaymentRequest(c.Request.Context(), userID, createDTO)
 	if err != nil {
 		logger.Error("Failed to create payment request", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "create_payment_failed",
 			Message: err.Error(),
@@ -467,6 +473,10 @@ func (h *PaymentHandler) SendPayment(c *gin.Context) {
 	result, err := h.paymentService.SendDirectPayment(c.Request.Context(), userID, sendDTO)
 	if err != nil {
 		logger.Error("Failed to send payment", zap.Error(err))
+		if errors.Is(err, privacy.ErrUserUnavailable) {
+			respondUserUnavailable(c)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "send_payment_failed",
 			Message: err.Error(),
@@ -1,11 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/delivery/http/response"
+	domainPrivacy "github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/internal/usecase/privacy"
 	"github.com/yourusername/sotalk/pkg/logger"
@@ -95,6 +97,14 @@ func (h *PrivacyHandler) UpdatePrivacySettings(c *gin.Context) {
 	settings, err := h.privacyService.UpdatePrivacySettings(c.Request.Context(), userID, &req)
 	if err != nil {
 		logger.Error("Failed to update privacy settings", zap.Error(err))
+		if errors.Is(err, domainPrivacy.ErrInvalidVisibility) {
+			c.JSON(http.StatusBadRequest, response.ErrorResponse{
+				Error:   "invalid_visibility",
+				Message: "Visibility must be everyone, contacts or nobody",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, response.ErrorResponse{
 			Error:   "update_settings_failed",
 			Message: err.Error(),
@@ -520,3 +530,13 @@ func (h *PrivacyHandler) GetTwoFactorStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, status)
 }
+
+// respondUserUnavailable responds to an action blocked in either direction.
+// It reads the same for both sides so the response does not reveal the block
+func respondUserUnavailable(c *gin.Context) {
+	c.JSON(http.StatusForbidden, response.ErrorResponse{
+		Error:   "user_unavailable",
+		Message: "This user is unavailable",
+		Code:    http.StatusForbidden,
+	})
+}
@@ -15,6 +15,15 @@ type UpdateChannelRequest struct {
 	Avatar      string `json:"avatar"`
 }
 
+// UpdateChannelSettingsRequest is the HTTP request for updating channel settings
+type UpdateChannelSettingsRequest struct {
+	AdminsCanPost     *bool `json:"admins_can_post"`
+	LinkPreview       *bool `json:"link_preview"`
+	ForwardingAllowed *bool `json:"forwarding_allowed"`
+	EditWindowSeconds *int  `json:"edit_window_seconds" binding:"omitempty,min=0"` // 0 = no limit
+	SubscriberVotes   *bool `json:"subscriber_votes"`
+}
+
 // AddChannelAdminRequest is the HTTP request for adding a channel admin
 type AddChannelAdminRequest struct {
 	UserID string `json:"user_id" binding:"required"`
@@ -30,6 +30,8 @@ type UpdateGroupSettingsRequest struct {
 	WhoCanMessage    string `json:"who_can_message"`    // all, admins_only
 	WhoCanAddMembers string `json:"who_can_add_members"` // all, admins_only
 	WhoCanEditInfo   string `json:"who_can_edit_info"`   // all, admins_only
+	EditWindowSeconds *int `json:"edit_window_seconds" binding:"omitempty,min=0"` // 0 = no limit
+	LinkPreview       *bool `json:"link_preview"`
 }
 
 // GetGroupsRequest is the HTTP request for getting user's groups
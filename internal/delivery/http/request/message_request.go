@@ -1,14 +1,45 @@
 package request
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 // SendMessageRequest is the HTTP request for sending a message
 type SendMessageRequest struct {
-	RecipientID string `json:"recipient_id" binding:"required"`
-	Content     string `json:"content" binding:"required"` // Accept as string, convert to []byte in handler
-	ContentType string `json:"content_type" binding:"required"`
-	Signature   string `json:"signature"`
-	ReplyToID   *string `json:"reply_to_id,omitempty"`
+	RecipientID     string             `json:"recipient_id" binding:"required"`
+	Content         string             `json:"content" binding:"required"` // Accept as string, convert to []byte in handler
+	ContentType     string             `json:"content_type" binding:"required"`
+	Signature       string             `json:"signature"`
+	ReplyToID       *string            `json:"reply_to_id,omitempty"`
+	ClientMessageID string             `json:"client_message_id" binding:"omitempty,max=64"` // Or the Idempotency-Key header
+	Poll            *CreatePollRequest `json:"poll,omitempty"`                               // Required when content_type is poll, the content is the question
+}
+
+// SendConversationMessageRequest is the HTTP request for sending a message into a conversation
+type SendConversationMessageRequest struct {
+	Content         string             `json:"content" binding:"required"`
+	ContentType     string             `json:"content_type" binding:"required"`
+	Signature       string             `json:"signature"`
+	ReplyToID       *string            `json:"reply_to_id,omitempty"`
+	ClientMessageID string             `json:"client_message_id" binding:"omitempty,max=64"` // Or the Idempotency-Key header
+	Poll            *CreatePollRequest `json:"poll,omitempty"`                               // Required when content_type is poll, the content is the question
+}
+
+// CreatePollRequest is the HTTP request part holding the options and settings of a new poll
+type CreatePollRequest struct {
+	Options        []string   `json:"options" binding:"required"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	Anonymous      bool       `json:"anonymous"`
+	Quiz           bool       `json:"quiz"`
+	CorrectOption  *int       `json:"correct_option,omitempty"` // Position of the right answer, required for quizzes
+	ClosesAt       *time.Time `json:"closes_at,omitempty"`      // RFC 3339
+}
+
+// VotePollRequest is the HTTP request for voting in a poll
+type VotePollRequest struct {
+	Options []int `json:"options" binding:"required"` // Positions of the chosen options
 }
 
 // GetMessagesRequest is the HTTP request for getting messages
@@ -16,17 +47,105 @@ type GetMessagesRequest struct {
 	ConversationID string `form:"conversation_id" binding:"required"`
 	Limit          int    `form:"limit"`
 	Offset         int    `form:"offset"`
+	Before         string `form:"before"` // Message ID or cursor
+	After          string `form:"after"`  // Message ID or cursor
+	Around         string `form:"around"` // Message ID to center the page on
+}
+
+// DeleteMessageRequest is the HTTP request for deleting a message
+type DeleteMessageRequest struct {
+	Scope string `form:"scope" binding:"omitempty,oneof=me everyone"` // Defaults to everyone
+}
+
+// ScheduleMessageRequest is the HTTP request for scheduling a message
+type ScheduleMessageRequest struct {
+	ConversationID string    `json:"conversation_id" binding:"required"`
+	Content        string    `json:"content" binding:"required"`
+	ContentType    string    `json:"content_type" binding:"required"`
+	ReplyToID      *string   `json:"reply_to_id,omitempty"`
+	SendAt         time.Time `json:"send_at" binding:"required"` // RFC 3339
+}
+
+// UpdateScheduledMessageRequest is the HTTP request for editing a scheduled message
+type UpdateScheduledMessageRequest struct {
+	Content     *string    `json:"content,omitempty"`
+	ContentType *string    `json:"content_type,omitempty"`
+	SendAt      *time.Time `json:"send_at,omitempty"`
+}
+
+// GetScheduledMessagesRequest is the HTTP request for listing scheduled messages
+type GetScheduledMessagesRequest struct {
+	ConversationID string `form:"conversation_id"`
+	Status         string `form:"status" binding:"omitempty,oneof=pending sending sent canceled failed all"` // Defaults to pending
+	Limit          int    `form:"limit"`
+	Offset         int    `form:"offset"`
+}
+
+// GetThreadRequest is the HTTP request for getting thread replies
+type GetThreadRequest struct {
+	After string `form:"after"` // Reply ID or cursor
+	Limit int    `form:"limit"`
+}
+
+// GetMentionsRequest is the HTTP request for getting the mentions feed
+type GetMentionsRequest struct {
+	ConversationID string `form:"conversation_id"`
+	UnreadOnly     bool   `form:"unread_only"`
+	Limit          int    `form:"limit"`
+	Offset         int    `form:"offset"`
+}
+
+// ParseConversationID parses conversation_id from string to UUID (optional)
+func (r *GetMentionsRequest) ParseConversationID() (*uuid.UUID, error) {
+	if r.ConversationID == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(r.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// SyncRequest is the HTTP request for the changes after a change sequence number
+type SyncRequest struct {
+	Since int64 `form:"since"`
+	Limit int   `form:"limit"`
 }
 
 // GetConversationsRequest is the HTTP request for getting conversations
 type GetConversationsRequest struct {
-	Limit  int `form:"limit"`
-	Offset int `form:"offset"`
+	Limit    int    `form:"limit"`
+	Offset   int    `form:"offset"`
+	FolderID string `form:"folder_id"`
+	Pinned   *bool  `form:"pinned"`
+	Muted    *bool  `form:"muted"`
+}
+
+// MuteConversationRequest is the HTTP request for muting a conversation
+type MuteConversationRequest struct {
+	Until *time.Time `json:"until,omitempty"` // Omit to mute until unmuted
+}
+
+// ReorderPinnedRequest is the HTTP request for ordering the pinned conversations
+type ReorderPinnedRequest struct {
+	ConversationIDs []string `json:"conversation_ids" binding:"required"`
+}
+
+// FolderRequest is the HTTP request for creating or renaming a conversation folder
+type FolderRequest struct {
+	Name string `json:"name" binding:"required"`
 }
 
 // MarkAsReadRequest is the HTTP request for marking messages as read
 type MarkAsReadRequest struct {
-	ConversationID string `json:"conversation_id" binding:"required"`
+	ConversationID string  `json:"conversation_id" binding:"required"`
+	MessageID      *string `json:"message_id,omitempty"` // Read up to this message, omit to read everything
+}
+
+// MarkAsUnreadRequest is the HTTP request for marking messages as unread
+type MarkAsUnreadRequest struct {
+	MessageID *string `json:"message_id,omitempty"` // First unread message, omit for the latest message from others
 }
 
 // EditMessageRequest is the HTTP request for editing a message
@@ -51,16 +170,93 @@ func (r *SendMessageRequest) ParseReplyToID() (*uuid.UUID, error) {
 	return &id, nil
 }
 
+// ParseReplyToID parses reply_to_id from string to UUID
+func (r *SendConversationMessageRequest) ParseReplyToID() (*uuid.UUID, error) {
+	if r.ReplyToID == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*r.ReplyToID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// ParseConversationID parses conversation_id from string to UUID
+func (r *ScheduleMessageRequest) ParseConversationID() (uuid.UUID, error) {
+	return uuid.Parse(r.ConversationID)
+}
+
+// ParseReplyToID parses reply_to_id from string to UUID
+func (r *ScheduleMessageRequest) ParseReplyToID() (*uuid.UUID, error) {
+	if r.ReplyToID == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*r.ReplyToID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// ParseConversationID parses conversation_id from string to UUID (optional)
+func (r *GetScheduledMessagesRequest) ParseConversationID() (*uuid.UUID, error) {
+	if r.ConversationID == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(r.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
 // ParseConversationID parses conversation_id from string to UUID
 func (r *GetMessagesRequest) ParseConversationID() (uuid.UUID, error) {
 	return uuid.Parse(r.ConversationID)
 }
 
+// ParseAroundID parses around from string to UUID (optional)
+func (r *GetMessagesRequest) ParseAroundID() (*uuid.UUID, error) {
+	if r.Around == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(r.Around)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
 // ParseConversationID parses conversation_id from string to UUID
 func (r *MarkAsReadRequest) ParseConversationID() (uuid.UUID, error) {
 	return uuid.Parse(r.ConversationID)
 }
 
+// ParseMessageID parses the optional message_id from string to UUID
+func (r *MarkAsReadRequest) ParseMessageID() (*uuid.UUID, error) {
+	if r.MessageID == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*r.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// ParseMessageID parses the optional message_id from string to UUID
+func (r *MarkAsUnreadRequest) ParseMessageID() (*uuid.UUID, error) {
+	if r.MessageID == nil {
+		return nil, nil
+	}
+	id, err := uuid.Parse(*r.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
 // CreateConversationRequest is the HTTP request for creating a conversation
 type CreateConversationRequest struct {
 	ParticipantID string  `json:"participant_id" binding:"required"`
@@ -41,4 +41,27 @@ type ChannelSettings struct {
 	AdminsCanPost     bool `json:"admins_can_post"`
 	LinkPreview       bool `json:"link_preview"`
 	ForwardingAllowed bool `json:"forwarding_allowed"`
+	EditWindowSeconds int  `json:"edit_window_seconds"`
+	SubscriberVotes   bool `json:"subscriber_votes"`
+}
+
+// GetChannelAdminsResponse is the HTTP response for getting channel admins
+type GetChannelAdminsResponse struct {
+	Admins []ChannelAdminDTO `json:"admins"`
+}
+
+// ChannelAdminDTO is the channel admin data in response
+type ChannelAdminDTO struct {
+	UserID      string                   `json:"user_id"`
+	User        *UserDTO                 `json:"user,omitempty"`
+	Permissions *ChannelAdminPermissions `json:"permissions,omitempty"`
+	AddedAt     time.Time                `json:"added_at"`
+}
+
+// ChannelAdminPermissions represents admin permissions in response
+type ChannelAdminPermissions struct {
+	CanPostMessages   bool `json:"can_post_messages"`
+	CanEditMessages   bool `json:"can_edit_messages"`
+	CanDeleteMessages bool `json:"can_delete_messages"`
+	CanManageAdmins   bool `json:"can_manage_admins"`
 }
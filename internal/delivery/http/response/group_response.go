@@ -35,9 +35,11 @@ type GroupDTO struct {
 
 // GroupSettings represents group settings in response
 type GroupSettings struct {
-	WhoCanMessage    string `json:"who_can_message"`
-	WhoCanAddMembers string `json:"who_can_add_members"`
-	WhoCanEditInfo   string `json:"who_can_edit_info"`
+	WhoCanMessage     string `json:"who_can_message"`
+	WhoCanAddMembers  string `json:"who_can_add_members"`
+	WhoCanEditInfo    string `json:"who_can_edit_info"`
+	EditWindowSeconds int    `json:"edit_window_seconds"`
+	LinkPreview       bool   `json:"link_preview"`
 }
 
 // MemberDTO is the member data in response
@@ -9,8 +9,12 @@ type SendMessageResponse struct {
 
 // GetMessagesResponse is the HTTP response for getting messages
 type GetMessagesResponse struct {
-	Messages []MessageDTO `json:"messages"`
-	Total    int64        `json:"total"`
+	Messages   []MessageDTO `json:"messages"`
+	Total      int64        `json:"total"`
+	NextCursor string       `json:"next_cursor,omitempty"` // Pass as before to load older messages
+	PrevCursor string       `json:"prev_cursor,omitempty"` // Pass as after to load newer messages
+	HasOlder   bool         `json:"has_older"`
+	HasNewer   bool         `json:"has_newer"`
 }
 
 // GetConversationsResponse is the HTTP response for getting conversations
@@ -25,29 +29,235 @@ type ConversationResponse struct {
 
 // MessageDTO is the message data in response
 type MessageDTO struct {
-	ID             string              `json:"id"`
-	ConversationID string              `json:"conversation_id"`
-	SenderID       string              `json:"sender_id"`
-	Content        string              `json:"content"`
-	ContentType    string              `json:"content_type"`
-	Signature      string              `json:"signature,omitempty"`
-	ReplyToID      *string             `json:"reply_to_id,omitempty"`
-	Status         string              `json:"status"`
-	CreatedAt      time.Time           `json:"created_at"`
-	UpdatedAt      time.Time           `json:"updated_at"`
-	Sender         *UserDTO            `json:"sender,omitempty"`
-	Reactions      map[string][]string `json:"reactions,omitempty"`
-	IsPinned       bool                `json:"is_pinned"`           // Deprecated: use PinnedBy instead
-	PinnedBy       []string            `json:"pinned_by,omitempty"` // List of user IDs who pinned this message
+	ID              string              `json:"id"`
+	ConversationID  string              `json:"conversation_id"`
+	SenderID        string              `json:"sender_id"`
+	Content         string              `json:"content"`
+	ContentType     string              `json:"content_type"`
+	Signature       string              `json:"signature,omitempty"`
+	ReplyToID       *string             `json:"reply_to_id,omitempty"`
+	Status          string              `json:"status"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	Sender          *UserDTO            `json:"sender,omitempty"`
+	Reactions       map[string][]string `json:"reactions,omitempty"`
+	IsPinned        bool                `json:"is_pinned"`           // Deprecated: use PinnedBy instead
+	PinnedBy        []string            `json:"pinned_by,omitempty"` // List of user IDs who pinned this message
+	ExpiresAt       *time.Time          `json:"expires_at,omitempty"`
+	ThreadID        *string             `json:"thread_id,omitempty"`
+	Thread          *ThreadSummaryDTO   `json:"thread,omitempty"`
+	Receipts        *ReceiptCountsDTO   `json:"receipts,omitempty"`
+	EditedAt        *time.Time          `json:"edited_at,omitempty"`
+	EditCount       int                 `json:"edit_count"`
+	DeletedAt       *time.Time          `json:"deleted_at,omitempty"` // Set on tombstones of messages deleted for everyone
+	DeletedBy       *string             `json:"deleted_by,omitempty"`
+	ClientMessageID string              `json:"client_message_id,omitempty"`
+	LinkPreviews    []LinkPreviewDTO    `json:"link_previews,omitempty"`
+	Poll            *PollDTO            `json:"poll,omitempty"`
+}
+
+// LinkPreviewDTO is the preview of a link in a message in response
+type LinkPreviewDTO struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// PollDTO is the poll of a poll message with its results in response
+type PollDTO struct {
+	MessageID      string          `json:"message_id"`
+	Question       string          `json:"question"`
+	Options        []PollOptionDTO `json:"options"`
+	MultipleChoice bool            `json:"multiple_choice"`
+	Anonymous      bool            `json:"anonymous"`
+	Quiz           bool            `json:"quiz"`
+	CorrectOption  *int            `json:"correct_option,omitempty"` // Only shown once the user answered or the quiz closed
+	ClosesAt       *time.Time      `json:"closes_at,omitempty"`
+	IsClosed       bool            `json:"is_closed"`
+	TotalVoters    int             `json:"total_voters"`
+	Voted          []int           `json:"voted,omitempty"` // Options chosen by the current user
+}
+
+// PollOptionDTO is one answer option of a poll in response
+type PollOptionDTO struct {
+	Position  int      `json:"position"`
+	Text      string   `json:"text"`
+	VoteCount int      `json:"vote_count"`
+	Voters    []string `json:"voters,omitempty"` // Only set on public polls
+}
+
+// PollResponse is the response for voting in or closing a poll
+type PollResponse struct {
+	Poll PollDTO `json:"poll"`
+}
+
+// ThreadSummaryDTO is the reply information of a thread root message in response
+type ThreadSummaryDTO struct {
+	ReplyCount    int       `json:"reply_count"`
+	LastReplyID   string    `json:"last_reply_id"`
+	LastReplyAt   time.Time `json:"last_reply_at"`
+	LastReplierID string    `json:"last_replier_id"`
 }
 
 // ConversationDTO is the conversation data in response
 type ConversationDTO struct {
-	ID            string        `json:"id"`
-	Type          string        `json:"type"`
-	Participants  []UserDTO     `json:"participants"`
-	LastMessage   *MessageDTO   `json:"last_message,omitempty"`
-	UnreadCount   int           `json:"unread_count"`
-	CreatedAt     time.Time     `json:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at"`
+	ID                 string      `json:"id"`
+	Type               string      `json:"type"`
+	Participants       []UserDTO   `json:"participants"`
+	LastMessage        *MessageDTO `json:"last_message,omitempty"`
+	UnreadCount        int         `json:"unread_count"`
+	UnreadMentionCount int         `json:"unread_mention_count"`
+	IsRequest          bool        `json:"is_request"`      // In the current user's message requests
+	RequestPending     bool        `json:"request_pending"` // A message request not accepted yet, by either side
+	LastReadMessageID  *string     `json:"last_read_message_id,omitempty"`
+	IsArchived         bool        `json:"is_archived"`
+	IsPinned           bool        `json:"is_pinned"`
+	IsMuted            bool        `json:"is_muted"`
+	MutedUntil         *time.Time  `json:"muted_until,omitempty"` // Not set when muted until unmuted
+	FolderIDs          []string    `json:"folder_ids,omitempty"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// ReadStateResponse is the HTTP response for the user's read marker in a conversation
+type ReadStateResponse struct {
+	ConversationID     string     `json:"conversation_id"`
+	LastReadMessageID  *string    `json:"last_read_message_id,omitempty"`
+	LastReadAt         *time.Time `json:"last_read_at,omitempty"` // Messages created up to this time are read
+	UnreadCount        int        `json:"unread_count"`
+	UnreadMentionCount int        `json:"unread_mention_count"`
+}
+
+// FolderDTO is a conversation folder in response
+type FolderDTO struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	ConversationCount int       `json:"conversation_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GetFoldersResponse is the HTTP response for listing conversation folders
+type GetFoldersResponse struct {
+	Folders []FolderDTO `json:"folders"`
+}
+
+// FolderResponse is the HTTP response for a single conversation folder
+type FolderResponse struct {
+	Folder FolderDTO `json:"folder"`
+}
+
+// MentionDTO is a mention of the current user in response
+type MentionDTO struct {
+	ID             string      `json:"id"`
+	MessageID      string      `json:"message_id"`
+	ConversationID string      `json:"conversation_id"`
+	Position       int         `json:"position"`
+	Length         int         `json:"length"`
+	Read           bool        `json:"read"`
+	CreatedAt      time.Time   `json:"created_at"`
+	Message        *MessageDTO `json:"message,omitempty"`
+}
+
+// GetMentionsResponse is the HTTP response for the mentions feed
+type GetMentionsResponse struct {
+	Mentions     []MentionDTO     `json:"mentions"`
+	UnreadCounts map[string]int64 `json:"unread_counts"`
+	TotalUnread  int64            `json:"total_unread"`
+}
+
+// DeletedMessageDTO identifies a message that is gone for the user
+type DeletedMessageDTO struct {
+	ID             string `json:"id"`
+	ConversationID string `json:"conversation_id"`
+}
+
+// SyncResponse is the HTTP response for a delta sync
+type SyncResponse struct {
+	Seq                    int64               `json:"seq"` // Pass as since on the next sync
+	HasMore                bool                `json:"has_more"`
+	FullResync             bool                `json:"full_resync"` // Reload conversations and history, then sync from seq
+	Conversations          []ConversationDTO   `json:"conversations"`
+	RemovedConversationIDs []string            `json:"removed_conversation_ids"`
+	Messages               []MessageDTO        `json:"messages"`
+	DeletedMessages        []DeletedMessageDTO `json:"deleted_messages"`
+}
+
+// GetThreadResponse is the HTTP response for getting a thread
+type GetThreadResponse struct {
+	Root        MessageDTO   `json:"root"`
+	Replies     []MessageDTO `json:"replies"`
+	IsFollowing bool         `json:"is_following"`
+	NextCursor  string       `json:"next_cursor,omitempty"`
+	HasMore     bool         `json:"has_more"`
+}
+
+// ReceiptCountsDTO is the number of recipients who received and read a message in response
+type ReceiptCountsDTO struct {
+	Delivered int `json:"delivered"`
+	Read      int `json:"read"`
+}
+
+// MessageReceiptDTO is the delivery and read time of a message for one recipient in response
+type MessageReceiptDTO struct {
+	UserID      string     `json:"user_id"`
+	User        *UserDTO   `json:"user,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// GetMessageReceiptsResponse is the HTTP response for getting who received and read a message
+type GetMessageReceiptsResponse struct {
+	MessageID  string              `json:"message_id"`
+	Recipients int                 `json:"recipients"`
+	Delivered  int                 `json:"delivered"`
+	Read       int                 `json:"read"`
+	Receipts   []MessageReceiptDTO `json:"receipts"`
+}
+
+// MessageRevisionDTO is a prior version of a message's content in response
+type MessageRevisionDTO struct {
+	ID          string    `json:"id"`
+	Revision    int       `json:"revision"` // 1 is the original content
+	Content     string    `json:"content"`
+	ContentType string    `json:"content_type"`
+	EditedBy    string    `json:"edited_by"`
+	EditedAt    time.Time `json:"edited_at"` // When this revision was replaced
+}
+
+// GetMessageRevisionsResponse is the HTTP response for getting the edit history of a message
+type GetMessageRevisionsResponse struct {
+	MessageID      string               `json:"message_id"`
+	CurrentContent string               `json:"current_content"`
+	EditCount      int                  `json:"edit_count"`
+	EditedAt       *time.Time           `json:"edited_at,omitempty"`
+	Revisions      []MessageRevisionDTO `json:"revisions"`
+}
+
+// ScheduledMessageDTO is the scheduled message data in response
+type ScheduledMessageDTO struct {
+	ID             string     `json:"id"` // Also the ID of the delivered message once sent
+	ConversationID string     `json:"conversation_id"`
+	SenderID       string     `json:"sender_id"`
+	Content        string     `json:"content"`
+	ContentType    string     `json:"content_type"`
+	ReplyToID      *string    `json:"reply_to_id,omitempty"`
+	SendAt         time.Time  `json:"send_at"`
+	Status         string     `json:"status"`
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// ScheduledMessageResponse is the HTTP response for a single scheduled message
+type ScheduledMessageResponse struct {
+	ScheduledMessage ScheduledMessageDTO `json:"scheduled_message"`
+}
+
+// GetScheduledMessagesResponse is the HTTP response for listing scheduled messages
+type GetScheduledMessagesResponse struct {
+	ScheduledMessages []ScheduledMessageDTO `json:"scheduled_messages"`
 }
@@ -89,6 +89,14 @@ func (r *Router) Setup(mode string) *gin.Engine {
 			auth.POST("/refresh", r.authHandler.RefreshToken)
 		}
 
+		// Server-Sent Events stream, for networks that break WebSocket upgrades.
+		// EventSource cannot set headers, so the token may come as a query parameter
+		eventStream := v1.Group("/events")
+		eventStream.Use(httpMiddleware.WebSocketAuthMiddleware(r.jwtManager))
+		{
+			eventStream.GET("/stream", r.wsHandler.HandleEventStream)
+		}
+
 		// Protected routes (require authentication via header)
 		protected := v1.Group("")
 		protected.Use(httpMiddleware.AuthMiddleware(r.jwtManager))
@@ -101,6 +109,8 @@ func (r *Router) Setup(mode string) *gin.Engine {
 			// WebSocket routes (Day 4)
 			protected.GET("/ws", r.wsHandler.HandleWebSocket)
 			protected.GET("/ws/stats", r.wsHandler.Stats)
+			protected.GET("/events/poll", r.wsHandler.HandleLongPoll)
+			protected.POST("/events/messages", r.wsHandler.HandleClientMessage)
 
 			// Message routes (Day 3)
 			messages := protected.Group("/messages")
@@ -112,17 +122,53 @@ func (r *Router) Setup(mode string) *gin.Engine {
 				messages.DELETE("/:id", r.messageHandler.DeleteMessage)
 			}
 
+			// Scheduled message routes
+			scheduledMessages := protected.Group("/scheduled-messages")
+			{
+				scheduledMessages.POST("", r.messageHandler.ScheduleMessage)
+				scheduledMessages.GET("", r.messageHandler.GetScheduledMessages)
+				scheduledMessages.PUT("/:id", r.messageHandler.UpdateScheduledMessage)
+				scheduledMessages.DELETE("/:id", r.messageHandler.CancelScheduledMessage)
+			}
+
+			// Message request routes: direct chats from non-contacts when the user only accepts contacts
+			messageRequests := protected.Group("/message-requests")
+			{
+				messageRequests.GET("", r.messageHandler.GetMessageRequests)
+				messageRequests.POST("/:id/accept", r.messageHandler.AcceptMessageRequest)
+				messageRequests.POST("/:id/block", r.messageHandler.BlockMessageRequest)
+				messageRequests.DELETE("/:id", r.messageHandler.DeleteMessageRequest)
+			}
+
 			// Conversation routes (Day 3, Day 8)
 			conversations := protected.Group("/conversations")
 			{
 				conversations.GET("", r.messageHandler.GetConversations)
 				conversations.POST("", r.messageHandler.CreateConversation)
+				conversations.PUT("/pins", r.messageHandler.ReorderPinnedConversations)
+				conversations.POST("/:id/messages", r.messageHandler.SendConversationMessage)
 				conversations.GET("/:id/media", r.mediaHandler.GetConversationMedia) // Day 8: Shared media
 				conversations.PUT("/:id/archive", r.messageHandler.ArchiveConversation)
 				conversations.PUT("/:id/unarchive", r.messageHandler.UnarchiveConversation)
+				conversations.POST("/:id/unread", r.messageHandler.MarkAsUnread)
+				conversations.PUT("/:id/pin", r.messageHandler.PinConversation)
+				conversations.PUT("/:id/unpin", r.messageHandler.UnpinConversation)
+				conversations.PUT("/:id/mute", r.messageHandler.MuteConversation)
+				conversations.PUT("/:id/unmute", r.messageHandler.UnmuteConversation)
 				conversations.DELETE("/:id", r.messageHandler.DeleteConversation)
 			}
 
+			// Conversation folder routes: user-defined labels such as "Work" or "Payments"
+			folders := protected.Group("/folders")
+			{
+				folders.GET("", r.messageHandler.GetFolders)
+				folders.POST("", r.messageHandler.CreateFolder)
+				folders.PATCH("/:id", r.messageHandler.RenameFolder)
+				folders.DELETE("/:id", r.messageHandler.DeleteFolder)
+				folders.PUT("/:id/conversations/:conversation_id", r.messageHandler.AddConversationToFolder)
+				folders.DELETE("/:id/conversations/:conversation_id", r.messageHandler.RemoveConversationFromFolder)
+			}
+
 			// Group routes (Day 5)
 			groups := protected.Group("/groups")
 			{
@@ -148,7 +194,16 @@ func (r *Router) Setup(mode string) *gin.Engine {
 				channels.GET("/:username", r.channelHandler.GetChannel)
 				channels.POST("/:username/subscribe", r.channelHandler.Subscribe)
 				channels.POST("/:username/unsubscribe", r.channelHandler.Unsubscribe)
+				channels.POST("/:username/notifications", r.channelHandler.ToggleNotifications)
+				channels.PUT("/:id", r.channelHandler.UpdateChannel)
+				channels.PUT("/:id/settings", r.channelHandler.UpdateChannelSettings)
 				channels.DELETE("/:id", r.channelHandler.DeleteChannel)
+
+				// Channel admins (:username also accepts a channel ID)
+				channels.GET("/:username/admins", r.channelHandler.GetChannelAdmins)
+				channels.POST("/:username/admins", r.channelHandler.AddAdmin)
+				channels.PUT("/:id/admins/:userId", r.channelHandler.UpdateAdminPermissions)
+				channels.DELETE("/:id/admins/:userId", r.channelHandler.RemoveAdmin)
 			}
 
 			// Media routes (Day 7-8)
@@ -226,7 +281,17 @@ func (r *Router) Setup(mode string) *gin.Engine {
 			protected.DELETE("/messages/:id/pin", r.messageHandler.UnpinMessage)
 			protected.GET("/conversations/:id/pinned", r.messageHandler.GetPinnedMessages)
 			protected.POST("/messages/:id/forward", r.messageHandler.ForwardMessage)
+			protected.GET("/messages/:id/revisions", r.messageHandler.GetMessageRevisions)
+			protected.GET("/messages/:id/receipts", r.messageHandler.GetMessageReceipts)
+			protected.GET("/messages/:id/thread", r.messageHandler.GetThread)
+			protected.POST("/messages/:id/thread/follow", r.messageHandler.FollowThread)
+			protected.DELETE("/messages/:id/thread/follow", r.messageHandler.UnfollowThread)
+			protected.POST("/messages/:id/poll/vote", r.messageHandler.VotePoll)
+			protected.DELETE("/messages/:id/poll/vote", r.messageHandler.RetractPollVote)
+			protected.POST("/messages/:id/poll/close", r.messageHandler.ClosePoll)
 			protected.POST("/messages/search", r.messageHandler.SearchMessages)
+			protected.GET("/mentions", r.messageHandler.GetMentions)
+			protected.GET("/sync", r.messageHandler.Sync)
 
 			// Status/Stories routes (Day 13)
 			statuses := protected.Group("/statuses")
@@ -348,7 +413,7 @@ func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, Last-Event-ID")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE, PATCH")
 
 		if c.Request.Method == "OPTIONS" {
@@ -25,16 +25,23 @@ func NewBroadcaster(hub *Hub) *Broadcaster {
 // BroadcastNewMessage broadcasts a new message event to conversation participants
 func (b *Broadcaster) BroadcastNewMessage(ctx context.Context, conversationID uuid.UUID, msg dto.MessageDTO) error {
 	event, err := NewEvent(EventMessageNew, MessagePayload{
-		ID:             msg.ID,
-		ConversationID: msg.ConversationID,
-		SenderID:       msg.SenderID,
-		Content:        msg.Content,
-		ContentType:    msg.ContentType,
-		Status:         msg.Status,
-		CreatedAt:      msg.CreatedAt,
-		Reactions:      msg.Reactions,
-		IsPinned:       msg.IsPinned,
-		ReplyToID:      msg.ReplyToID,
+		ID:              msg.ID,
+		ConversationID:  msg.ConversationID,
+		SenderID:        msg.SenderID,
+		Content:         msg.Content,
+		ContentType:     msg.ContentType,
+		Status:          msg.Status,
+		CreatedAt:       msg.CreatedAt,
+		Reactions:       msg.Reactions,
+		IsPinned:        msg.IsPinned,
+		ReplyToID:       msg.ReplyToID,
+		ExpiresAt:       msg.ExpiresAt,
+		ThreadID:        msg.ThreadID,
+		EditedAt:        msg.EditedAt,
+		EditCount:       msg.EditCount,
+		ClientMessageID: msg.ClientMessageID,
+		LinkPreviews:    toLinkPreviewPayloads(msg.LinkPreviews),
+		Poll:            toPollPayload(msg.Poll),
 	})
 	if err != nil {
 		logger.Error("Failed to create new message event", zap.Error(err))
@@ -44,11 +51,15 @@ func (b *Broadcaster) BroadcastNewMessage(ctx context.Context, conversationID uu
 	return b.hub.BroadcastToConversation(ctx, conversationID, event)
 }
 
-// BroadcastMessageDeleted broadcasts a message deleted event
-func (b *Broadcaster) BroadcastMessageDeleted(ctx context.Context, conversationID uuid.UUID, messageID string) error {
-	event, err := NewEvent(EventMessageDeleted, map[string]interface{}{
-		"message_id":      messageID,
-		"conversation_id": conversationID.String(),
+// BroadcastMessageDeleted broadcasts a message deleted for everyone.
+// deletedBy is empty when the system removed the message, e.g. on expiry
+func (b *Broadcaster) BroadcastMessageDeleted(ctx context.Context, conversationID uuid.UUID, messageID, deletedBy string) error {
+	event, err := NewEvent(EventMessageDeleted, MessageDeletedPayload{
+		MessageID:      messageID,
+		ConversationID: conversationID.String(),
+		Scope:          "everyone",
+		DeletedBy:      deletedBy,
+		DeletedAt:      time.Now(),
 	})
 	if err != nil {
 		logger.Error("Failed to create deleted message event", zap.Error(err))
@@ -58,6 +69,23 @@ func (b *Broadcaster) BroadcastMessageDeleted(ctx context.Context, conversationI
 	return b.hub.BroadcastToConversation(ctx, conversationID, event)
 }
 
+// BroadcastMessageHidden tells a user's own connections that they deleted a message for themselves
+func (b *Broadcaster) BroadcastMessageHidden(ctx context.Context, userID, conversationID uuid.UUID, messageID string) error {
+	event, err := NewEvent(EventMessageDeleted, MessageDeletedPayload{
+		MessageID:      messageID,
+		ConversationID: conversationID.String(),
+		Scope:          "me",
+		DeletedBy:      userID.String(),
+		DeletedAt:      time.Now(),
+	})
+	if err != nil {
+		logger.Error("Failed to create hidden message event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToUser(userID, event)
+}
+
 // BroadcastMessageUpdated broadcasts a message updated event
 func (b *Broadcaster) BroadcastMessageUpdated(ctx context.Context, conversationID uuid.UUID, msg dto.MessageDTO) error {
 	event, err := NewEvent(EventMessageUpdated, MessagePayload{
@@ -73,6 +101,12 @@ func (b *Broadcaster) BroadcastMessageUpdated(ctx context.Context, conversationI
 		IsPinned:       msg.IsPinned,
 		PinnedBy:       msg.PinnedBy,
 		ReplyToID:      msg.ReplyToID,
+		ExpiresAt:      msg.ExpiresAt,
+		ThreadID:       msg.ThreadID,
+		EditedAt:       msg.EditedAt,
+		EditCount:      msg.EditCount,
+		LinkPreviews:   toLinkPreviewPayloads(msg.LinkPreviews),
+		Poll:           toPollPayload(msg.Poll),
 	})
 	if err != nil {
 		logger.Error("Failed to create message updated event", zap.Error(err))
@@ -82,6 +116,86 @@ func (b *Broadcaster) BroadcastMessageUpdated(ctx context.Context, conversationI
 	return b.hub.BroadcastToConversation(ctx, conversationID, event)
 }
 
+// toLinkPreviewPayloads maps the link previews of a message, nil when there are none
+func toLinkPreviewPayloads(previews []dto.LinkPreviewDTO) []LinkPreviewPayload {
+	if len(previews) == 0 {
+		return nil
+	}
+
+	payloads := make([]LinkPreviewPayload, len(previews))
+	for i, preview := range previews {
+		payloads[i] = LinkPreviewPayload{
+			URL:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageURL:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		}
+	}
+	return payloads
+}
+
+// BroadcastPollUpdated broadcasts new poll results to conversation participants
+func (b *Broadcaster) BroadcastPollUpdated(ctx context.Context, conversationID uuid.UUID, poll dto.PollDTO) error {
+	event, err := NewEvent(EventPollUpdated, PollUpdatedPayload{
+		ConversationID: conversationID.String(),
+		Poll:           *toPollPayload(&poll),
+	})
+	if err != nil {
+		logger.Error("Failed to create poll updated event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastReadState tells a user's own connections that their read marker moved, so every device clears its badge
+func (b *Broadcaster) BroadcastReadState(ctx context.Context, userID uuid.UUID, state dto.ReadStateDTO) error {
+	event, err := NewEvent(EventConversationRead, ConversationReadPayload{
+		ConversationID:     state.ConversationID,
+		LastReadMessageID:  state.LastReadMessageID,
+		LastReadAt:         state.LastReadAt,
+		UnreadCount:        state.UnreadCount,
+		UnreadMentionCount: state.UnreadMentionCount,
+	})
+	if err != nil {
+		logger.Error("Failed to create conversation read event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToUser(userID, event)
+}
+
+// toPollPayload maps the poll of a message, nil for other messages
+func toPollPayload(poll *dto.PollDTO) *PollPayload {
+	if poll == nil {
+		return nil
+	}
+
+	options := make([]PollOptionPayload, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = PollOptionPayload{
+			Position:  option.Position,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+			Voters:    option.Voters,
+		}
+	}
+
+	return &PollPayload{
+		MessageID:      poll.MessageID,
+		Question:       poll.Question,
+		Options:        options,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Quiz:           poll.Quiz,
+		CorrectOption:  poll.CorrectOption,
+		ClosesAt:       poll.ClosesAt,
+		IsClosed:       poll.IsClosed,
+		TotalVoters:    poll.TotalVoters,
+	}
+}
+
 // BroadcastReactionAdded broadcasts a reaction added event
 func (b *Broadcaster) BroadcastReactionAdded(ctx context.Context, conversationID uuid.UUID, messageID, userID, emoji string) error {
 	event, err := NewEvent(EventReactionAdded, ReactionPayload{
@@ -146,6 +260,75 @@ func (b *Broadcaster) BroadcastMessageUnpinned(ctx context.Context, conversation
 	return b.hub.BroadcastToConversation(ctx, conversationID, event)
 }
 
+// BroadcastMention sends a mention event to the mentioned user only
+func (b *Broadcaster) BroadcastMention(ctx context.Context, userID uuid.UUID, msg dto.MessageDTO, unreadMentions int64) error {
+	event, err := NewEvent(EventMentionNew, MentionPayload{
+		MessageID:      msg.ID,
+		ConversationID: msg.ConversationID,
+		MentionedBy:    msg.SenderID,
+		Message: MessagePayload{
+			ID:             msg.ID,
+			ConversationID: msg.ConversationID,
+			SenderID:       msg.SenderID,
+			Content:        msg.Content,
+			ContentType:    msg.ContentType,
+			Status:         msg.Status,
+			CreatedAt:      msg.CreatedAt,
+			UpdatedAt:      msg.UpdatedAt,
+			ReplyToID:      msg.ReplyToID,
+			ExpiresAt:      msg.ExpiresAt,
+			ThreadID:       msg.ThreadID,
+			EditedAt:       msg.EditedAt,
+			EditCount:      msg.EditCount,
+		},
+		UnreadMentions: unreadMentions,
+	})
+	if err != nil {
+		logger.Error("Failed to create mention event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToUser(userID, event)
+}
+
+// BroadcastThreadUpdated sends a thread update to the thread's followers
+func (b *Broadcaster) BroadcastThreadUpdated(ctx context.Context, userIDs []uuid.UUID, thread dto.ThreadSummaryDTO, reply dto.MessageDTO) error {
+	rootMessageID := ""
+	if reply.ThreadID != nil {
+		rootMessageID = *reply.ThreadID
+	}
+
+	event, err := NewEvent(EventThreadUpdated, ThreadPayload{
+		RootMessageID:  rootMessageID,
+		ConversationID: reply.ConversationID,
+		ReplyCount:     thread.ReplyCount,
+		LastReplyID:    thread.LastReplyID,
+		LastReplyAt:    thread.LastReplyAt,
+		LastReplierID:  thread.LastReplierID,
+		Reply: MessagePayload{
+			ID:             reply.ID,
+			ConversationID: reply.ConversationID,
+			SenderID:       reply.SenderID,
+			Content:        reply.Content,
+			ContentType:    reply.ContentType,
+			Status:         reply.Status,
+			CreatedAt:      reply.CreatedAt,
+			UpdatedAt:      reply.UpdatedAt,
+			ReplyToID:      reply.ReplyToID,
+			ExpiresAt:      reply.ExpiresAt,
+			ThreadID:       reply.ThreadID,
+			EditedAt:       reply.EditedAt,
+			EditCount:      reply.EditCount,
+		},
+	})
+	if err != nil {
+		logger.Error("Failed to create thread updated event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToUsers(userIDs, event)
+}
+
 // BroadcastPaymentRequest broadcasts a payment request event
 func (b *Broadcaster) BroadcastPaymentRequest(ctx context.Context, toUserID uuid.UUID, payment dto.PaymentRequestDTO) error {
 	event, err := NewEvent(EventPaymentRequest, PaymentPayload{
@@ -238,6 +421,8 @@ func (b *Broadcaster) BroadcastGroupCreated(ctx context.Context, conversationID
 			"who_can_message":     group.Settings.WhoCanMessage,
 			"who_can_add_members": group.Settings.WhoCanAddMembers,
 			"who_can_edit_info":   group.Settings.WhoCanEditInfo,
+			"edit_window_seconds": group.Settings.EditWindowSeconds,
+			"link_preview":        group.Settings.LinkPreview,
 		},
 		CreatedAt: group.CreatedAt,
 		UpdatedAt: group.UpdatedAt,
@@ -264,6 +449,8 @@ func (b *Broadcaster) BroadcastGroupUpdated(ctx context.Context, conversationID
 			"who_can_message":     group.Settings.WhoCanMessage,
 			"who_can_add_members": group.Settings.WhoCanAddMembers,
 			"who_can_edit_info":   group.Settings.WhoCanEditInfo,
+			"edit_window_seconds": group.Settings.EditWindowSeconds,
+			"link_preview":        group.Settings.LinkPreview,
 		},
 		CreatedAt: group.CreatedAt,
 		UpdatedAt: group.UpdatedAt,
@@ -300,6 +487,8 @@ func (b *Broadcaster) BroadcastGroupSettingsUpdated(ctx context.Context, convers
 			"who_can_message":     settings.WhoCanMessage,
 			"who_can_add_members": settings.WhoCanAddMembers,
 			"who_can_edit_info":   settings.WhoCanEditInfo,
+			"edit_window_seconds": settings.EditWindowSeconds,
+			"link_preview":        settings.LinkPreview,
 		},
 		UpdatedBy: updatedBy,
 	})
@@ -382,3 +571,131 @@ func (b *Broadcaster) BroadcastGroupMemberRoleChanged(ctx context.Context, conve
 
 	return b.hub.BroadcastToConversation(ctx, conversationID, event)
 }
+
+// BroadcastChannelUpdated broadcasts a channel updated event to all subscribers
+func (b *Broadcaster) BroadcastChannelUpdated(ctx context.Context, conversationID uuid.UUID, channel dto.ChannelDTO) error {
+	var settings map[string]interface{}
+	if channel.Settings != nil {
+		settings = map[string]interface{}{
+			"admins_can_post":     channel.Settings.AdminsCanPost,
+			"link_preview":        channel.Settings.LinkPreview,
+			"forwarding_allowed":  channel.Settings.ForwardingAllowed,
+			"edit_window_seconds": channel.Settings.EditWindowSeconds,
+			"subscriber_votes":    channel.Settings.SubscriberVotes,
+		}
+	}
+
+	event, err := NewEvent(EventChannelUpdated, ChannelPayload{
+		ID:              channel.ID,
+		ConversationID:  channel.ConversationID,
+		Name:            channel.Name,
+		Username:        channel.Username,
+		Description:     channel.Description,
+		Avatar:          channel.Avatar,
+		OwnerID:         channel.OwnerID,
+		IsPublic:        channel.IsPublic,
+		SubscriberCount: channel.SubscriberCount,
+		Settings:        settings,
+		CreatedAt:       channel.CreatedAt,
+		UpdatedAt:       channel.UpdatedAt,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel updated event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastChannelDeleted broadcasts a channel deleted event to all subscribers
+func (b *Broadcaster) BroadcastChannelDeleted(ctx context.Context, conversationID uuid.UUID, channelID, deletedBy string) error {
+	event, err := NewEvent(EventChannelDeleted, map[string]interface{}{
+		"channel_id":      channelID,
+		"conversation_id": conversationID.String(),
+		"deleted_by":      deletedBy,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel deleted event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastChannelSettingsUpdated broadcasts a channel settings updated event
+func (b *Broadcaster) BroadcastChannelSettingsUpdated(ctx context.Context, conversationID uuid.UUID, channelID, updatedBy string, settings dto.ChannelSettings) error {
+	event, err := NewEvent(EventChannelSettingsUpdated, ChannelSettingsPayload{
+		ChannelID:      channelID,
+		ConversationID: conversationID.String(),
+		Settings: map[string]interface{}{
+			"admins_can_post":     settings.AdminsCanPost,
+			"link_preview":        settings.LinkPreview,
+			"forwarding_allowed":  settings.ForwardingAllowed,
+			"edit_window_seconds": settings.EditWindowSeconds,
+			"subscriber_votes":    settings.SubscriberVotes,
+		},
+		UpdatedBy: updatedBy,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel settings updated event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastChannelAdminAdded broadcasts an admin added event
+func (b *Broadcaster) BroadcastChannelAdminAdded(ctx context.Context, conversationID uuid.UUID, channelID, userID, username, addedBy string) error {
+	event, err := NewEvent(EventChannelAdminAdded, ChannelAdminPayload{
+		ChannelID:      channelID,
+		ConversationID: conversationID.String(),
+		UserID:         userID,
+		Username:       username,
+		ActionBy:       addedBy,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel admin added event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastChannelAdminRemoved broadcasts an admin removed event
+func (b *Broadcaster) BroadcastChannelAdminRemoved(ctx context.Context, conversationID uuid.UUID, channelID, userID, username, removedBy string) error {
+	event, err := NewEvent(EventChannelAdminRemoved, ChannelAdminPayload{
+		ChannelID:      channelID,
+		ConversationID: conversationID.String(),
+		UserID:         userID,
+		Username:       username,
+		ActionBy:       removedBy,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel admin removed event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
+
+// BroadcastChannelAdminPermissionsUpdated broadcasts an admin permissions updated event
+func (b *Broadcaster) BroadcastChannelAdminPermissionsUpdated(ctx context.Context, conversationID uuid.UUID, channelID, userID, updatedBy string, permissions dto.ChannelAdminPermissions) error {
+	event, err := NewEvent(EventChannelAdminPermissionsUpdated, ChannelAdminPayload{
+		ChannelID:      channelID,
+		ConversationID: conversationID.String(),
+		UserID:         userID,
+		Permissions: map[string]interface{}{
+			"can_post_messages":   permissions.CanPostMessages,
+			"can_edit_messages":   permissions.CanEditMessages,
+			"can_delete_messages": permissions.CanDeleteMessages,
+			"can_manage_admins":   permissions.CanManageAdmins,
+		},
+		ActionBy: updatedBy,
+	})
+	if err != nil {
+		logger.Error("Failed to create channel admin permissions updated event", zap.Error(err))
+		return err
+	}
+
+	return b.hub.BroadcastToConversation(ctx, conversationID, event)
+}
@@ -3,6 +3,10 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -23,6 +27,19 @@ const (
 
 	// Maximum message size allowed from peer
 	maxMessageSize = 512 * 1024 // 512 KB
+
+	// Live events remembered before the client resumes, past this a resume asks for a resync
+	maxTrackedLiveSeqs = 1024
+
+	// Live events held while a resume replays the missed ones, past this the client is asked to resync
+	maxHeldLiveEvents = 1024
+)
+
+// Transports a client can receive events over
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+	TransportLongPoll  = "long_poll"
 )
 
 // Client represents a WebSocket client connection
@@ -36,7 +53,10 @@ type Client struct {
 	// Username for display purposes
 	Username string
 
-	// The websocket connection
+	// Transport the client receives events over, the hub treats them all the same
+	Transport string
+
+	// The websocket connection, nil for the other transports
 	conn *websocket.Conn
 
 	// Hub reference
@@ -45,12 +65,33 @@ type Client struct {
 	// Buffered channel of outbound messages
 	send chan []byte
 
+	// Closed once the hub delivers events to the client
+	registered chan struct{}
+
 	// Context for cancellation
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	// Client message handler
 	messageHandler ClientMessageHandler
+
+	// Rate limit of the commands sent over this connection
+	commands *commandLimiter
+
+	// Resume state, so a replay neither repeats nor misses events sent live meanwhile,
+	// and the client gets them all in sequence order
+	resumeMu      sync.Mutex
+	resumeStarted bool
+	resumed       bool
+	holding       bool           // Live events are held until the replay was sent
+	held          []heldEvent    // Live events waiting for the replay
+	heldDropped   bool           // Live events were dropped for lack of room while holding
+	liveSeqs      map[int64]bool // Sent live before resuming, nil once there were too many
+	replayedUpTo  int64          // After resuming, events up to here were replayed or already seen
+
+	// Activity, telling when the user went away
+	lastActivity atomic.Int64 // Unix nanoseconds of the last thing the user did
+	lastReported atomic.Int64 // When activity was last written to the presence cache
 }
 
 // ClientMessageHandler handles incoming client messages
@@ -59,23 +100,35 @@ type ClientMessageHandler interface {
 	HandleStopTyping(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID)
 	HandleMessageDelivered(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, conversationID uuid.UUID)
 	HandleMessageRead(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, conversationID uuid.UUID)
+	HandleCommand(ctx context.Context, userID uuid.UUID, requestID, commandType string, payload json.RawMessage) (*AckPayload, error)
 }
 
 // NewClient creates a new Client instance
 func NewClient(userID uuid.UUID, username string, conn *websocket.Conn, hub *Hub, messageHandler ClientMessageHandler) *Client {
+	return newClient(userID, username, TransportWebSocket, conn, hub, messageHandler)
+}
+
+func newClient(userID uuid.UUID, username, transport string, conn *websocket.Conn, hub *Hub, messageHandler ClientMessageHandler) *Client {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &Client{
+	client := &Client{
 		ID:             uuid.New().String(),
 		UserID:         userID,
 		Username:       username,
+		Transport:      transport,
 		conn:           conn,
 		hub:            hub,
 		send:           make(chan []byte, 256),
+		registered:     make(chan struct{}),
 		ctx:            ctx,
 		cancel:         cancel,
 		messageHandler: messageHandler,
+		commands:       newCommandLimiter(),
+		liveSeqs:       make(map[int64]bool),
 	}
+	client.lastActivity.Store(time.Now().UnixNano())
+
+	return client
 }
 
 // ReadPump pumps messages from the WebSocket connection to the hub
@@ -176,6 +229,16 @@ func (c *Client) handleClientMessage(message []byte) {
 		return
 	}
 
+	if isActivity(clientMsg.Type) {
+		c.hub.presence.touch(c)
+	}
+
+	// Commands run in order, one at a time, so a client sees its sends acknowledged in the order it made them
+	if isCommand(clientMsg.Type) {
+		c.handleCommand(&clientMsg)
+		return
+	}
+
 	// Process the message
 	switch clientMsg.Type {
 	case ClientMessageTyping:
@@ -193,6 +256,12 @@ func (c *Client) handleClientMessage(message []byte) {
 	case ClientMessagePing:
 		c.handlePing()
 
+	case ClientMessageResume:
+		c.handleResume(clientMsg.Payload)
+
+	case ClientMessagePresenceSubscribe, ClientMessagePresenceUnsubscribe:
+		c.handlePresenceSubscription(clientMsg.Type, clientMsg.Payload)
+
 	default:
 		logger.Warn("Unknown client message type",
 			zap.String("type", clientMsg.Type),
@@ -289,6 +358,178 @@ func (c *Client) handleMessageRead(payload json.RawMessage) {
 	}
 }
 
+// handleCommand runs a command and answers it with an ack or an error carrying its request ID
+func (c *Client) handleCommand(clientMsg *ClientMessage) {
+	if clientMsg.RequestID == "" || len(clientMsg.RequestID) > maxRequestIDLength {
+		c.sendEvent(EventError, commandErrorPayload(clientMsg.RequestID, clientMsg.Type,
+			invalidCommand("invalid_request_id", "request_id is required and must be at most 64 characters")))
+		return
+	}
+	if !c.commands.allow() {
+		c.sendEvent(EventError, commandErrorPayload(clientMsg.RequestID, clientMsg.Type,
+			invalidCommand("rate_limited", "Too many commands, slow down")))
+		return
+	}
+	if c.messageHandler == nil {
+		return
+	}
+
+	ack, err := c.messageHandler.HandleCommand(c.ctx, c.UserID, clientMsg.RequestID, clientMsg.Type, clientMsg.Payload)
+	if err != nil {
+		var cmdErr *CommandError
+		if !errors.As(err, &cmdErr) {
+			logger.Error("WebSocket command failed",
+				zap.String("user_id", c.UserID.String()),
+				zap.String("command", clientMsg.Type),
+				zap.String("request_id", clientMsg.RequestID),
+				zap.Error(err),
+			)
+		}
+		c.sendEvent(EventError, commandErrorPayload(clientMsg.RequestID, clientMsg.Type, err))
+		return
+	}
+
+	c.sendEvent(EventAck, ack)
+}
+
+// handleResume replays the events missed since the last sequence number the client saw
+func (c *Client) handleResume(payload json.RawMessage) {
+	var resumePayload ClientResumePayload
+	if err := json.Unmarshal(payload, &resumePayload); err != nil {
+		logger.Error("Failed to unmarshal resume payload", zap.Error(err))
+		c.sendError("invalid_message", "Failed to parse resume")
+		return
+	}
+
+	c.hub.resume(c, resumePayload.LastSeq)
+}
+
+// handlePresenceSubscription follows or stops following the presence of users outside the
+// ones the client is sent presence of anyway
+func (c *Client) handlePresenceSubscription(messageType string, payload json.RawMessage) {
+	var presencePayload ClientPresencePayload
+	if err := json.Unmarshal(payload, &presencePayload); err != nil {
+		logger.Error("Failed to unmarshal presence payload", zap.Error(err))
+		c.sendError("invalid_message", "Failed to parse presence subscription")
+		return
+	}
+	if len(presencePayload.UserIDs) > maxPresenceSubscriptions {
+		c.sendError("too_many_subscriptions", "A connection can follow the presence of at most 100 users")
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(presencePayload.UserIDs))
+	for _, id := range presencePayload.UserIDs {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			c.sendError("invalid_user_id", "Invalid user ID")
+			return
+		}
+		if userID != c.UserID {
+			userIDs = append(userIDs, userID)
+		}
+	}
+
+	if messageType == ClientMessagePresenceSubscribe {
+		c.hub.presence.subscribe(c, userIDs)
+	} else {
+		c.hub.presence.unsubscribe(c, userIDs)
+	}
+}
+
+// heldEvent is a live event held back while the client resumes
+type heldEvent struct {
+	seq  int64
+	data []byte
+}
+
+// admitLive reports if a live event should be sent now. It is held instead while the client
+// resumes, and remembered until then so the replay can skip it.
+// Called with the hub lock held
+func (c *Client) admitLive(seq int64, data []byte) bool {
+	c.resumeMu.Lock()
+	defer c.resumeMu.Unlock()
+
+	if c.holding {
+		if len(c.held) < maxHeldLiveEvents {
+			c.held = append(c.held, heldEvent{seq: seq, data: data})
+		} else {
+			c.heldDropped = true
+		}
+		return false
+	}
+	if c.resumed {
+		return seq > c.replayedUpTo
+	}
+	if c.liveSeqs != nil {
+		if len(c.liveSeqs) >= maxTrackedLiveSeqs {
+			c.liveSeqs = nil
+		} else {
+			c.liveSeqs[seq] = true
+		}
+	}
+	return true
+}
+
+// holdLive holds live events from now on, for a client that resumes as soon as it is registered
+func (c *Client) holdLive() {
+	c.resumeMu.Lock()
+	defer c.resumeMu.Unlock()
+
+	if !c.resumeStarted {
+		c.holding = true
+	}
+}
+
+// startReplay holds live events until the replay was sent and returns the ones the client
+// was already sent live, nil when too many went by to tell. first is false when the client resumed before.
+func (c *Client) startReplay() (sentLive map[int64]bool, first bool) {
+	c.resumeMu.Lock()
+	defer c.resumeMu.Unlock()
+
+	if c.resumeStarted {
+		return nil, false
+	}
+	c.resumeStarted = true
+	c.holding = true
+	sentLive, c.liveSeqs = c.liveSeqs, nil
+
+	return sentLive, true
+}
+
+// releaseHeld takes the live events held during the replay, in sequence order. Once none are left,
+// the client is marked resumed up to seq and later events are sent right away.
+// dropped reports that some did not fit.
+func (c *Client) releaseHeld(seq int64) (held []heldEvent, dropped bool) {
+	c.resumeMu.Lock()
+	defer c.resumeMu.Unlock()
+
+	held, c.held = c.held, nil
+	dropped, c.heldDropped = c.heldDropped, false
+	if len(held) == 0 {
+		c.holding = false
+		c.resumed = true
+		c.replayedUpTo = seq
+		return nil, dropped
+	}
+
+	// Concurrent broadcasts may have been numbered in one order and delivered in the other
+	sort.Slice(held, func(i, j int) bool { return held[i].seq < held[j].seq })
+	return held, dropped
+}
+
+// sendBlocking queues an event, waiting for room instead of dropping it
+func (c *Client) sendBlocking(data []byte) bool {
+	select {
+	case c.send <- data:
+		return true
+	case <-time.After(writeWait):
+		return false
+	case <-c.ctx.Done():
+		return false
+	}
+}
+
 // handlePing responds with pong
 func (c *Client) handlePing() {
 	event, err := NewEvent(EventPong, map[string]interface{}{
@@ -312,6 +553,28 @@ func (c *Client) handlePing() {
 	}
 }
 
+// sendEvent sends an event to this client only, waiting for room in the buffer
+func (c *Client) sendEvent(eventType EventType, payload interface{}) {
+	event, err := NewEvent(eventType, payload)
+	if err != nil {
+		logger.Error("Failed to create client event", zap.Error(err))
+		return
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("Failed to marshal client event", zap.Error(err))
+		return
+	}
+
+	if !c.sendBlocking(data) {
+		logger.Warn("Failed to send client event, buffer full",
+			zap.String("event_type", string(eventType)),
+			zap.String("client_id", c.ID),
+		)
+	}
+}
+
 // sendError sends an error event to the client
 func (c *Client) sendError(code, message string) {
 	event, err := NewEvent(EventError, ErrorPayload{
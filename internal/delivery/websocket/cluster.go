@@ -0,0 +1,283 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// clusterChannel is the pub/sub channel all nodes relay events over
+	clusterChannel = "ws:events"
+
+	defaultPresenceTTL = 30 * time.Second
+
+	// presenceLookupTimeout bounds the Redis round trip of an online check
+	presenceLookupTimeout = 500 * time.Millisecond
+)
+
+// ClusterConfig controls how a node joins the cluster
+type ClusterConfig struct {
+	NodeID      string        // Unique per node, generated when empty
+	PresenceTTL time.Duration // How long a node's users stay online after its last heartbeat
+}
+
+// Cluster relays events between the hubs of API nodes that share one Redis.
+// Every broadcast is delivered to the local clients and published, each other node
+// delivers it to its own clients. Presence is kept per user as a sorted set of the
+// nodes they are connected to, scored by when each entry expires, so a user is
+// online while any live node has them and the entries of a crashed node lapse.
+//
+// Hubs only tell themselves apart by node ID, so several can share one Redis in a process.
+type Cluster struct {
+	client      *redis.Client
+	nodeID      string
+	presenceTTL time.Duration
+
+	hub    *Hub
+	pubsub *redis.PubSub
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// clusterEnvelope is an event published to the other nodes.
+// Recipients are resolved by the publishing node, so receivers never query the database.
+type clusterEnvelope struct {
+	Node       string          `json:"node"`
+	UserIDs    []uuid.UUID     `json:"user_ids,omitempty"`
+	Seqs       []int64         `json:"seqs,omitempty"`        // Each user's sequence number for the event
+	PresenceOf *uuid.UUID      `json:"presence_of,omitempty"` // Whose status the event is, for delivering it to subscribers
+	Data       json.RawMessage `json:"data"`
+}
+
+// NewCluster creates a new cluster transport, pass it to NewHub to enable it
+func NewCluster(client *redis.Client, cfg ClusterConfig) *Cluster {
+	if cfg.NodeID == "" {
+		cfg.NodeID = uuid.New().String()
+	}
+	if cfg.PresenceTTL <= 0 {
+		cfg.PresenceTTL = defaultPresenceTTL
+	}
+
+	return &Cluster{
+		client:      client,
+		nodeID:      cfg.NodeID,
+		presenceTTL: cfg.PresenceTTL,
+		stop:        make(chan struct{}),
+	}
+}
+
+// NodeID returns the ID this node publishes under
+func (c *Cluster) NodeID() string {
+	return c.nodeID
+}
+
+// Start subscribes to relayed events and starts refreshing this node's presence entries
+func (c *Cluster) Start() error {
+	ctx := context.Background()
+
+	c.pubsub = c.client.Subscribe(ctx, clusterChannel)
+	// Wait for the subscription, so no event published after Start is missed
+	if _, err := c.pubsub.Receive(ctx); err != nil {
+		c.pubsub.Close()
+		return fmt.Errorf("failed to subscribe to cluster events: %w", err)
+	}
+
+	c.wg.Add(2)
+	go c.receive()
+	go c.heartbeat()
+
+	logger.Info("WebSocket cluster transport started", zap.String("node_id", c.nodeID))
+	return nil
+}
+
+// Stop unsubscribes and takes this node's users out of presence.
+// Their clients reconnect to another node, which announces them again.
+func (c *Cluster) Stop() {
+	close(c.stop)
+	if c.pubsub != nil {
+		c.pubsub.Close()
+	}
+	c.wg.Wait()
+
+	for _, userID := range c.hub.GetOnlineUsers() {
+		c.leave(userID)
+	}
+}
+
+// publish relays an event to the other nodes. Local clients already have it,
+// so a failure is only logged
+func (c *Cluster) publish(env clusterEnvelope) {
+	env.Node = c.nodeID
+
+	payload, err := json.Marshal(env)
+	if err != nil {
+		logger.Error("Failed to marshal cluster event", zap.Error(err))
+		return
+	}
+
+	if err := c.client.Publish(context.Background(), clusterChannel, payload).Err(); err != nil {
+		logger.Error("Failed to publish cluster event", zap.Error(err))
+	}
+}
+
+// receive delivers the events of other nodes to local clients
+func (c *Cluster) receive() {
+	defer c.wg.Done()
+
+	ch := c.pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var env clusterEnvelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				logger.Error("Failed to unmarshal cluster event", zap.Error(err))
+				continue
+			}
+			// Our own events were delivered when they were published
+			if env.Node == c.nodeID {
+				continue
+			}
+
+			c.hub.deliverRelayed(&env)
+
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// heartbeat keeps the presence entries of this node's users from expiring
+func (c *Cluster) heartbeat() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.presenceTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.refresh(c.hub.GetOnlineUsers())
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// refresh extends the presence entries of this node's users. Entries are only updated,
+// never created, so a heartbeat racing a disconnect cannot bring a user back online.
+func (c *Cluster) refresh(userIDs []uuid.UUID) {
+	if len(userIDs) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	expiresAt := float64(time.Now().Add(c.presenceTTL).UnixMilli())
+
+	pipe := c.client.Pipeline()
+	for _, userID := range userIDs {
+		key := presenceNodesKey(userID)
+		pipe.ZAddXX(ctx, key, redis.Z{Score: expiresAt, Member: c.nodeID})
+		pipe.Expire(ctx, key, c.presenceTTL)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Error("Failed to refresh cluster presence", zap.Int("users", len(userIDs)), zap.Error(err))
+	}
+}
+
+// join adds this node to the user's presence and reports if no other node had them.
+// When Redis cannot tell, the user is treated as newly online.
+func (c *Cluster) join(userID uuid.UUID) bool {
+	ctx := context.Background()
+	key := presenceNodesKey(userID)
+	now := time.Now()
+
+	var count *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(now.UnixMilli(), 10))
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(now.Add(c.presenceTTL).UnixMilli()), Member: c.nodeID})
+		pipe.Expire(ctx, key, c.presenceTTL)
+		count = pipe.ZCard(ctx, key)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to join cluster presence", zap.String("user_id", userID.String()), zap.Error(err))
+		return true
+	}
+
+	return count.Val() == 1
+}
+
+// leave removes this node from the user's presence and reports if no other node has them.
+// When Redis cannot tell, the user is treated as gone offline.
+func (c *Cluster) leave(userID uuid.UUID) bool {
+	ctx := context.Background()
+	key := presenceNodesKey(userID)
+
+	var count *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, key, c.nodeID)
+		pipe.ZRemRangeByScore(ctx, key, "-inf", strconv.FormatInt(time.Now().UnixMilli(), 10))
+		count = pipe.ZCard(ctx, key)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to leave cluster presence", zap.String("user_id", userID.String()), zap.Error(err))
+		return true
+	}
+
+	return count.Val() == 0
+}
+
+// isOnline checks if any live node has the user
+func (c *Cluster) isOnline(userID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+	defer cancel()
+
+	count, err := c.client.ZCount(ctx, presenceNodesKey(userID), strconv.FormatInt(time.Now().UnixMilli(), 10), "+inf").Result()
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// onlineUsers returns which of the users any live node has
+func (c *Cluster) onlineUsers(userIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+	defer cancel()
+
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	pipe := c.client.Pipeline()
+	cmds := make([]*redis.IntCmd, len(userIDs))
+	for i, userID := range userIDs {
+		cmds[i] = pipe.ZCount(ctx, presenceNodesKey(userID), now, "+inf")
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	online := make(map[uuid.UUID]bool)
+	for i, cmd := range cmds {
+		if cmd.Val() > 0 {
+			online[userIDs[i]] = true
+		}
+	}
+	return online, nil
+}
+
+func presenceNodesKey(userID uuid.UUID) string {
+	return fmt.Sprintf("ws:presence:%s", userID.String())
+}
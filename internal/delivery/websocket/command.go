@@ -0,0 +1,327 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	domainMessage "github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+const (
+	// Commands a connection may run per second once its burst is used up
+	commandRate  = 5
+	commandBurst = 20
+
+	maxRequestIDLength = 64
+)
+
+// CommandError is a command failure with the code sent to the client
+type CommandError struct {
+	Code    string
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	return e.Message
+}
+
+func invalidCommand(code, message string) *CommandError {
+	return &CommandError{Code: code, Message: message}
+}
+
+// commandLimiter is a token bucket limiting how fast one connection runs commands
+type commandLimiter struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newCommandLimiter() *commandLimiter {
+	return &commandLimiter{
+		tokens: commandBurst,
+		last:   time.Now(),
+	}
+}
+
+// allow takes a token if there is one
+func (l *commandLimiter) allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * commandRate
+	if l.tokens > commandBurst {
+		l.tokens = commandBurst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// isCommand reports if a client message type is a command
+func isCommand(messageType string) bool {
+	switch messageType {
+	case CommandMessageSend, CommandMessageEdit, CommandMessageDelete,
+		CommandReactionAdd, CommandReactionRemove, CommandMarkRead:
+		return true
+	}
+	return false
+}
+
+// HandleCommand runs a client command through the message service and returns its ack.
+// The service broadcasts the resulting events, the ack only goes to the connection that sent the command.
+func (m *MessageHandler) HandleCommand(ctx context.Context, userID uuid.UUID, requestID, commandType string, payload json.RawMessage) (*AckPayload, error) {
+	ack := &AckPayload{RequestID: requestID, Command: commandType}
+
+	switch commandType {
+	case CommandMessageSend:
+		var p ClientSendMessagePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, invalidCommand("invalid_request", "Invalid message payload")
+		}
+		msg, err := m.sendMessage(ctx, userID, requestID, &p)
+		if err != nil {
+			return nil, err
+		}
+		ack.Message = toMessagePayload(msg)
+
+	case CommandMessageEdit:
+		var p ClientEditMessagePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, invalidCommand("invalid_request", "Invalid edit payload")
+		}
+		messageID, err := uuid.Parse(p.MessageID)
+		if err != nil {
+			return nil, invalidCommand("invalid_message_id", "Invalid message ID")
+		}
+		if p.Content == "" {
+			return nil, invalidCommand("invalid_request", "content is required")
+		}
+		msg, err := m.messageService.EditMessage(ctx, userID, messageID, p.Content)
+		if err != nil {
+			return nil, err
+		}
+		ack.Message = toMessagePayload(msg)
+
+	case CommandMessageDelete:
+		var p ClientDeleteMessagePayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, invalidCommand("invalid_request", "Invalid delete payload")
+		}
+		messageID, err := uuid.Parse(p.MessageID)
+		if err != nil {
+			return nil, invalidCommand("invalid_message_id", "Invalid message ID")
+		}
+		scope := domainMessage.DeleteForEveryone
+		if p.Scope != "" {
+			scope = domainMessage.DeleteScope(p.Scope)
+		}
+		if err := m.messageService.DeleteMessage(ctx, userID, messageID, scope); err != nil {
+			return nil, err
+		}
+
+	case CommandReactionAdd, CommandReactionRemove:
+		var p ClientReactionPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, invalidCommand("invalid_request", "Invalid reaction payload")
+		}
+		messageID, err := uuid.Parse(p.MessageID)
+		if err != nil {
+			return nil, invalidCommand("invalid_message_id", "Invalid message ID")
+		}
+		if p.Emoji == "" {
+			return nil, invalidCommand("missing_emoji", "emoji is required")
+		}
+		if commandType == CommandReactionAdd {
+			err = m.messageService.AddReaction(ctx, userID, messageID, p.Emoji)
+		} else {
+			err = m.messageService.RemoveReaction(ctx, userID, messageID, p.Emoji)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+	case CommandMarkRead:
+		var p ClientMarkReadPayload
+		if err := json.Unmarshal(payload, &p); err != nil {
+			return nil, invalidCommand("invalid_request", "Invalid mark read payload")
+		}
+		req := &dto.MarkAsReadRequest{}
+		var err error
+		if req.ConversationID, err = uuid.Parse(p.ConversationID); err != nil {
+			return nil, invalidCommand("invalid_conversation_id", "Invalid conversation ID")
+		}
+		if p.MessageID != "" {
+			messageID, err := uuid.Parse(p.MessageID)
+			if err != nil {
+				return nil, invalidCommand("invalid_message_id", "Invalid message ID")
+			}
+			req.MessageID = &messageID
+		}
+		state, err := m.messageService.MarkAsRead(ctx, userID, req)
+		if err != nil {
+			return nil, err
+		}
+		ack.ReadState = &ConversationReadPayload{
+			ConversationID:     state.ConversationID,
+			LastReadMessageID:  state.LastReadMessageID,
+			LastReadAt:         state.LastReadAt,
+			UnreadCount:        state.UnreadCount,
+			UnreadMentionCount: state.UnreadMentionCount,
+		}
+
+	default:
+		return nil, invalidCommand("unknown_command", "Unknown command")
+	}
+
+	return ack, nil
+}
+
+// sendMessage sends a message to a conversation, or to a user when no conversation is given.
+// The request ID doubles as the idempotency key, so a command retried after a reconnect is not sent twice.
+func (m *MessageHandler) sendMessage(ctx context.Context, userID uuid.UUID, requestID string, p *ClientSendMessagePayload) (*dto.MessageDTO, error) {
+	// Required like on the HTTP endpoints
+	if p.Content == "" {
+		return nil, invalidCommand("invalid_request", "content is required")
+	}
+	if p.ContentType == "" {
+		return nil, invalidCommand("invalid_request", "content_type is required")
+	}
+
+	var replyToID *uuid.UUID
+	if p.ReplyToID != "" {
+		id, err := uuid.Parse(p.ReplyToID)
+		if err != nil {
+			return nil, invalidCommand("invalid_reply_to_id", "Invalid reply to ID")
+		}
+		replyToID = &id
+	}
+
+	clientMessageID := p.ClientMessageID
+	if clientMessageID == "" {
+		clientMessageID = requestID
+	}
+	if len(clientMessageID) > maxRequestIDLength {
+		return nil, invalidCommand("invalid_client_message_id", "client_message_id must be at most 64 characters")
+	}
+
+	if p.ConversationID != "" {
+		conversationID, err := uuid.Parse(p.ConversationID)
+		if err != nil {
+			return nil, invalidCommand("invalid_conversation_id", "Invalid conversation ID")
+		}
+		result, err := m.messageService.SendToConversation(ctx, userID, &dto.SendConversationMessageRequest{
+			ConversationID:  conversationID,
+			Content:         p.Content,
+			ContentType:     p.ContentType,
+			Signature:       p.Signature,
+			ReplyToID:       replyToID,
+			ClientMessageID: clientMessageID,
+			Poll:            p.Poll,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return &result.Message, nil
+	}
+
+	recipientID, err := uuid.Parse(p.RecipientID)
+	if err != nil {
+		return nil, invalidCommand("invalid_recipient_id", "conversation_id or recipient_id is required")
+	}
+	result, err := m.messageService.SendMessage(ctx, userID, &dto.SendMessageRequest{
+		RecipientID:     recipientID,
+		Content:         p.Content,
+		ContentType:     p.ContentType,
+		Signature:       p.Signature,
+		ReplyToID:       replyToID,
+		ClientMessageID: clientMessageID,
+		Poll:            p.Poll,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result.Message, nil
+}
+
+// commandErrorPayload maps a command failure to the error sent back, using the codes of the HTTP API
+func commandErrorPayload(requestID, commandType string, err error) ErrorPayload {
+	payload := ErrorPayload{RequestID: requestID, Command: commandType}
+
+	var cmdErr *CommandError
+	switch {
+	case errors.As(err, &cmdErr):
+		payload.Code, payload.Message = cmdErr.Code, cmdErr.Message
+	case errors.Is(err, domainMessage.ErrClientMessageIDReused):
+		payload.Code, payload.Message = "client_message_id_reused", "client_message_id was already used for a message in another conversation"
+	case errors.Is(err, domainMessage.ErrInvalidPoll):
+		payload.Code, payload.Message = "invalid_poll", err.Error()
+	case errors.Is(err, privacy.ErrUserUnavailable):
+		payload.Code, payload.Message = "user_unavailable", "This user is unavailable"
+	case errors.Is(err, privacy.ErrMessagingRestricted):
+		payload.Code, payload.Message = "messaging_restricted", "This user does not accept new chats"
+	case errors.Is(err, conversation.ErrNotParticipant):
+		payload.Code, payload.Message = "not_participant", "You are not a participant in this conversation"
+	case errors.Is(err, domainMessage.ErrSendNotAllowed):
+		payload.Code, payload.Message = "send_not_allowed", err.Error()
+	case errors.Is(err, conversation.ErrConversationNotFound):
+		payload.Code, payload.Message = "conversation_not_found", "Conversation not found"
+	case errors.Is(err, domainMessage.ErrUnauthorized):
+		payload.Code, payload.Message = "forbidden", err.Error()
+	case errors.Is(err, domainMessage.ErrEditWindowExpired):
+		payload.Code, payload.Message = "edit_window_expired", "This message can no longer be edited"
+	case errors.Is(err, domainMessage.ErrEditConflict):
+		payload.Code, payload.Message = "edit_conflict", "Message was edited by another request, reload and try again"
+	case errors.Is(err, domainMessage.ErrPollUnsupported):
+		payload.Code, payload.Message = "poll_unsupported", "Polls cannot be edited, forwarded or scheduled"
+	case errors.Is(err, domainMessage.ErrMessageDeleted):
+		payload.Code, payload.Message = "message_deleted", "This message has been deleted"
+	case errors.Is(err, domainMessage.ErrInvalidDeleteScope):
+		payload.Code, payload.Message = "invalid_scope", "scope must be 'me' or 'everyone'"
+	case errors.Is(err, domainMessage.ErrMessageNotFound):
+		payload.Code, payload.Message = "message_not_found", "Message not found"
+	default:
+		payload.Code, payload.Message = "command_failed", err.Error()
+	}
+
+	return payload
+}
+
+// toMessagePayload maps a message for an ack
+func toMessagePayload(msg *dto.MessageDTO) *MessagePayload {
+	if msg == nil {
+		return nil
+	}
+	return &MessagePayload{
+		ID:              msg.ID,
+		ConversationID:  msg.ConversationID,
+		SenderID:        msg.SenderID,
+		Content:         msg.Content,
+		ContentType:     msg.ContentType,
+		Status:          msg.Status,
+		CreatedAt:       msg.CreatedAt,
+		UpdatedAt:       msg.UpdatedAt,
+		Reactions:       msg.Reactions,
+		IsPinned:        msg.IsPinned,
+		PinnedBy:        msg.PinnedBy,
+		ReplyToID:       msg.ReplyToID,
+		ExpiresAt:       msg.ExpiresAt,
+		ThreadID:        msg.ThreadID,
+		EditedAt:        msg.EditedAt,
+		EditCount:       msg.EditCount,
+		ClientMessageID: msg.ClientMessageID,
+		LinkPreviews:    toLinkPreviewPayloads(msg.LinkPreviews),
+		Poll:            toPollPayload(msg.Poll),
+	}
+}
@@ -0,0 +1,254 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	domainMessage "github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+	"github.com/yourusername/sotalk/internal/usecase/message"
+)
+
+// commandService records the requests commands make, failing them with err when set
+type commandService struct {
+	message.Service
+	err           error
+	sent          *dto.SendConversationMessageRequest
+	sentDirect    *dto.SendMessageRequest
+	deletedScope  domainMessage.DeleteScope
+	removedEmojis []string
+	read          *dto.MarkAsReadRequest
+}
+
+func (s *commandService) SendToConversation(ctx context.Context, senderID uuid.UUID, req *dto.SendConversationMessageRequest) (*dto.SendMessageResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = req
+	return &dto.SendMessageResponse{Message: dto.MessageDTO{ID: uuid.NewString(), ConversationID: req.ConversationID.String(), ClientMessageID: req.ClientMessageID}}, nil
+}
+
+func (s *commandService) SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.SendMessageRequest) (*dto.SendMessageResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sentDirect = req
+	return &dto.SendMessageResponse{Message: dto.MessageDTO{ID: uuid.NewString(), ClientMessageID: req.ClientMessageID}}, nil
+}
+
+func (s *commandService) DeleteMessage(ctx context.Context, userID, messageID uuid.UUID, scope domainMessage.DeleteScope) error {
+	s.deletedScope = scope
+	return s.err
+}
+
+func (s *commandService) RemoveReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error {
+	s.removedEmojis = append(s.removedEmojis, emoji)
+	return s.err
+}
+
+func (s *commandService) MarkAsRead(ctx context.Context, userID uuid.UUID, req *dto.MarkAsReadRequest) (*dto.ReadStateDTO, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.read = req
+	return &dto.ReadStateDTO{ConversationID: req.ConversationID.String(), UnreadCount: 3}, nil
+}
+
+func newCommandHandler(service *commandService) *MessageHandler {
+	return NewMessageHandler(nil, service)
+}
+
+func TestHandleCommandRejectsInvalidPayloads(t *testing.T) {
+	// A valid message, so sends fail on what each case gets wrong
+	text := `"content":"hi","content_type":"text"`
+	tests := []struct {
+		name     string
+		command  string
+		payload  string
+		wantCode string
+	}{
+		{name: "unknown command", command: "message.forward", payload: `{}`, wantCode: "unknown_command"},
+		{name: "malformed send", command: CommandMessageSend, payload: `[]`, wantCode: "invalid_request"},
+		{name: "send without content", command: CommandMessageSend, payload: `{"conversation_id":"` + uuid.NewString() + `","content_type":"text"}`, wantCode: "invalid_request"},
+		{name: "send without a content type", command: CommandMessageSend, payload: `{"conversation_id":"` + uuid.NewString() + `","content":"hi"}`, wantCode: "invalid_request"},
+		{name: "send without a target", command: CommandMessageSend, payload: `{` + text + `}`, wantCode: "invalid_recipient_id"},
+		{name: "send to a bad conversation", command: CommandMessageSend, payload: `{` + text + `,"conversation_id":"nope"}`, wantCode: "invalid_conversation_id"},
+		{name: "send replying to a bad message", command: CommandMessageSend, payload: `{` + text + `,"conversation_id":"` + uuid.NewString() + `","reply_to_id":"nope"}`, wantCode: "invalid_reply_to_id"},
+		{name: "send with a long client message ID", command: CommandMessageSend, payload: `{` + text + `,"conversation_id":"` + uuid.NewString() + `","client_message_id":"` + strings.Repeat("a", maxRequestIDLength+1) + `"}`, wantCode: "invalid_client_message_id"},
+		{name: "edit a bad message", command: CommandMessageEdit, payload: `{"message_id":"nope","content":"hi"}`, wantCode: "invalid_message_id"},
+		{name: "edit to nothing", command: CommandMessageEdit, payload: `{"message_id":"` + uuid.NewString() + `"}`, wantCode: "invalid_request"},
+		{name: "delete a bad message", command: CommandMessageDelete, payload: `{"message_id":""}`, wantCode: "invalid_message_id"},
+		{name: "react without an emoji", command: CommandReactionAdd, payload: `{"message_id":"` + uuid.NewString() + `"}`, wantCode: "missing_emoji"},
+		{name: "mark a bad conversation read", command: CommandMarkRead, payload: `{"conversation_id":"nope"}`, wantCode: "invalid_conversation_id"},
+		{name: "mark read up to a bad message", command: CommandMarkRead, payload: `{"conversation_id":"` + uuid.NewString() + `","message_id":"nope"}`, wantCode: "invalid_message_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The service fails any call, so these must be turned down before reaching it
+			handler := newCommandHandler(&commandService{err: errors.New("service called")})
+
+			_, err := handler.HandleCommand(context.Background(), uuid.New(), "req-1", tt.command, json.RawMessage(tt.payload))
+			var cmdErr *CommandError
+			require.ErrorAs(t, err, &cmdErr)
+			assert.Equal(t, tt.wantCode, cmdErr.Code)
+		})
+	}
+}
+
+func TestHandleCommandSend(t *testing.T) {
+	service := &commandService{}
+	handler := newCommandHandler(service)
+	ctx := context.Background()
+	conversationID := uuid.New()
+
+	ack, err := handler.HandleCommand(ctx, uuid.New(), "req-1", CommandMessageSend,
+		json.RawMessage(`{"conversation_id":"`+conversationID.String()+`","content":"hi","content_type":"text"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", ack.RequestID)
+	assert.Equal(t, CommandMessageSend, ack.Command)
+	require.NotNil(t, ack.Message)
+	assert.Equal(t, conversationID, service.sent.ConversationID)
+	assert.Equal(t, "req-1", service.sent.ClientMessageID, "the request ID is the idempotency key")
+
+	recipientID := uuid.New()
+	_, err = handler.HandleCommand(ctx, uuid.New(), "req-2", CommandMessageSend,
+		json.RawMessage(`{"recipient_id":"`+recipientID.String()+`","content":"hi","content_type":"text","client_message_id":"local-7"}`))
+	require.NoError(t, err)
+	assert.Equal(t, recipientID, service.sentDirect.RecipientID)
+	assert.Equal(t, "local-7", service.sentDirect.ClientMessageID)
+}
+
+func TestHandleCommandDefaults(t *testing.T) {
+	service := &commandService{}
+	handler := newCommandHandler(service)
+	ctx := context.Background()
+	messageID, conversationID := uuid.New(), uuid.New()
+
+	_, err := handler.HandleCommand(ctx, uuid.New(), "req-1", CommandMessageDelete, json.RawMessage(`{"message_id":"`+messageID.String()+`"}`))
+	require.NoError(t, err)
+	assert.Equal(t, domainMessage.DeleteForEveryone, service.deletedScope)
+
+	_, err = handler.HandleCommand(ctx, uuid.New(), "req-2", CommandReactionRemove, json.RawMessage(`{"message_id":"`+messageID.String()+`","emoji":"👍"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"👍"}, service.removedEmojis)
+
+	ack, err := handler.HandleCommand(ctx, uuid.New(), "req-3", CommandMarkRead, json.RawMessage(`{"conversation_id":"`+conversationID.String()+`"}`))
+	require.NoError(t, err)
+	assert.Nil(t, service.read.MessageID, "reads up to the latest message")
+	require.NotNil(t, ack.ReadState)
+	assert.Equal(t, 3, ack.ReadState.UnreadCount)
+}
+
+func TestCommandErrorPayload(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode string
+	}{
+		{name: "command error", err: invalidCommand("missing_emoji", "emoji is required"), wantCode: "missing_emoji"},
+		{name: "reused client message ID", err: domainMessage.ErrClientMessageIDReused, wantCode: "client_message_id_reused"},
+		{name: "invalid poll", err: fmt.Errorf("%w: too few options", domainMessage.ErrInvalidPoll), wantCode: "invalid_poll"},
+		{name: "blocked", err: privacy.ErrUserUnavailable, wantCode: "user_unavailable"},
+		{name: "restricted", err: privacy.ErrMessagingRestricted, wantCode: "messaging_restricted"},
+		{name: "not a participant", err: conversation.ErrNotParticipant, wantCode: "not_participant"},
+		{name: "send not allowed", err: domainMessage.ErrSendNotAllowed, wantCode: "send_not_allowed"},
+		{name: "conversation not found", err: conversation.ErrConversationNotFound, wantCode: "conversation_not_found"},
+		{name: "not the sender", err: domainMessage.ErrUnauthorized, wantCode: "forbidden"},
+		{name: "edit window expired", err: domainMessage.ErrEditWindowExpired, wantCode: "edit_window_expired"},
+		{name: "edit conflict", err: domainMessage.ErrEditConflict, wantCode: "edit_conflict"},
+		{name: "poll unsupported", err: domainMessage.ErrPollUnsupported, wantCode: "poll_unsupported"},
+		{name: "message deleted", err: domainMessage.ErrMessageDeleted, wantCode: "message_deleted"},
+		{name: "invalid delete scope", err: domainMessage.ErrInvalidDeleteScope, wantCode: "invalid_scope"},
+		{name: "wrapped message not found", err: fmt.Errorf("failed to edit: %w", domainMessage.ErrMessageNotFound), wantCode: "message_not_found"},
+		{name: "anything else", err: errors.New("database unavailable"), wantCode: "command_failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := commandErrorPayload("req-1", CommandMessageEdit, tt.err)
+			assert.Equal(t, tt.wantCode, payload.Code)
+			assert.NotEmpty(t, payload.Message)
+			assert.Equal(t, "req-1", payload.RequestID)
+			assert.Equal(t, CommandMessageEdit, payload.Command)
+		})
+	}
+}
+
+func TestCommandLimiter(t *testing.T) {
+	limiter := newCommandLimiter()
+	for i := 0; i < commandBurst; i++ {
+		require.True(t, limiter.allow())
+	}
+	assert.False(t, limiter.allow(), "burst used up")
+
+	// A second later the bucket holds commandRate tokens again
+	limiter.last = limiter.last.Add(-time.Second)
+	for i := 0; i < commandRate; i++ {
+		assert.True(t, limiter.allow())
+	}
+	assert.False(t, limiter.allow())
+}
+
+// sendCommand runs a command the way it arrives over the connection
+func sendCommand(t *testing.T, client *Client, requestID, command, payload string) {
+	t.Helper()
+	data, err := json.Marshal(ClientMessage{Type: command, RequestID: requestID, Payload: json.RawMessage(payload)})
+	require.NoError(t, err)
+	client.handleClientMessage(data)
+}
+
+func TestClientAnswersCommands(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	service := &commandService{}
+	client := newClient(uuid.New(), "", TransportWebSocket, nil, hub, newCommandHandler(service))
+	conversationID := uuid.NewString()
+
+	sendCommand(t, client, "req-1", CommandMarkRead, `{"conversation_id":"`+conversationID+`"}`)
+	event := nextEvent(t, client)
+	assert.Equal(t, EventAck, event.Type)
+	var ack AckPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &ack))
+	assert.Equal(t, "req-1", ack.RequestID)
+
+	service.err = domainMessage.ErrMessageNotFound
+	sendCommand(t, client, "req-2", CommandMarkRead, `{"conversation_id":"`+conversationID+`"}`)
+	event = nextEvent(t, client)
+	assert.Equal(t, EventError, event.Type)
+	var failure ErrorPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &failure))
+	assert.Equal(t, ErrorPayload{RequestID: "req-2", Command: CommandMarkRead, Code: "message_not_found", Message: "Message not found"}, failure)
+
+	sendCommand(t, client, "", CommandMarkRead, `{"conversation_id":"`+conversationID+`"}`)
+	event = nextEvent(t, client)
+	require.NoError(t, json.Unmarshal(event.Payload, &failure))
+	assert.Equal(t, "invalid_request_id", failure.Code)
+}
+
+func TestClientRateLimitsCommands(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	service := &commandService{}
+	client := newClient(uuid.New(), "", TransportWebSocket, nil, hub, newCommandHandler(service))
+	client.commands.tokens = 1
+
+	sendCommand(t, client, "req-1", CommandMarkRead, `{"conversation_id":"`+uuid.NewString()+`"}`)
+	assert.Equal(t, EventAck, nextEvent(t, client).Type)
+
+	service.read = nil
+	sendCommand(t, client, "req-2", CommandMarkRead, `{"conversation_id":"`+uuid.NewString()+`"}`)
+	event := nextEvent(t, client)
+	var failure ErrorPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &failure))
+	assert.Equal(t, "rate_limited", failure.Code)
+	assert.Nil(t, service.read, "the command did not run")
+}
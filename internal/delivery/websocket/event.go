@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"time"
 
+	"github.com/yourusername/sotalk/internal/usecase/dto"
 )
 
 // EventType represents the type of WebSocket event
@@ -25,12 +26,22 @@ const (
 	EventMessagePinned   EventType = "message.pinned"
 	EventMessageUnpinned EventType = "message.unpinned"
 
+	// Mention events (sent only to the mentioned user)
+	EventMentionNew EventType = "mention.new"
+
+	// Thread events (sent to thread followers)
+	EventThreadUpdated EventType = "thread.updated"
+
+	// Poll events
+	EventPollUpdated EventType = "poll.updated"
+
 	// Typing events
 	EventTypingStart EventType = "typing.start"
 	EventTypingStop  EventType = "typing.stop"
 
 	// Conversation events
 	EventConversationUpdated EventType = "conversation.updated"
+	EventConversationRead    EventType = "conversation.read" // The user's read marker moved, sent to their own devices
 
 	// Group events
 	EventGroupCreated            EventType = "group.created"
@@ -42,6 +53,14 @@ const (
 	EventGroupMemberRemoved      EventType = "group.member_removed"
 	EventGroupMemberRoleChanged  EventType = "group.member_role_changed"
 
+	// Channel events
+	EventChannelUpdated                 EventType = "channel.updated"
+	EventChannelDeleted                 EventType = "channel.deleted"
+	EventChannelSettingsUpdated         EventType = "channel.settings_updated"
+	EventChannelAdminAdded              EventType = "channel.admin_added"
+	EventChannelAdminRemoved            EventType = "channel.admin_removed"
+	EventChannelAdminPermissionsUpdated EventType = "channel.admin_permissions_updated"
+
 	// Payment events
 	EventPaymentRequest  EventType = "payment.request"
 	EventPaymentAccepted EventType = "payment.accepted"
@@ -50,8 +69,18 @@ const (
 	EventPaymentConfirmed EventType = "payment.confirmed"
 
 	// Presence events
-	EventUserOnline  EventType = "user.online"
-	EventUserOffline EventType = "user.offline"
+	EventUserOnline    EventType = "user.online"
+	EventUserOffline   EventType = "user.offline"
+	EventUserAway      EventType = "user.away"      // Connected but inactive
+	EventPresenceState EventType = "presence.state" // Answers a presence subscription
+
+	// Session events
+	EventSessionOpened         EventType = "session.opened" // First event of an event stream
+	EventSessionResumed        EventType = "session.resumed"
+	EventSessionResyncRequired EventType = "session.resync_required"
+
+	// Command events, answering a client command by its request ID
+	EventAck EventType = "ack"
 
 	// System events
 	EventError EventType = "error"
@@ -59,8 +88,26 @@ const (
 	EventPong  EventType = "pong"
 )
 
+// ephemeralEvents only matter while they happen, so they get no sequence number and are never replayed
+var ephemeralEvents = map[EventType]bool{
+	EventTypingStart:           true,
+	EventTypingStop:            true,
+	EventUserOnline:            true,
+	EventUserOffline:           true,
+	EventUserAway:              true,
+	EventPresenceState:         true,
+	EventSessionOpened:         true,
+	EventSessionResumed:        true,
+	EventSessionResyncRequired: true,
+	EventAck:                   true,
+	EventError:                 true,
+	EventPing:                  true,
+	EventPong:                  true,
+}
+
 // Event represents a WebSocket event
 type Event struct {
+	Seq       int64           `json:"seq,omitempty"` // Per user, set on events that can be replayed after a reconnect
 	Type      EventType       `json:"type"`
 	Payload   json.RawMessage `json:"payload"`
 	Timestamp time.Time       `json:"timestamp"`
@@ -84,18 +131,71 @@ func NewEvent(eventType EventType, payload interface{}) (*Event, error) {
 
 // MessagePayload for message events
 type MessagePayload struct {
-	ID             string                       `json:"id"`
-	ConversationID string                       `json:"conversation_id"`
-	SenderID       string                       `json:"sender_id"`
-	Content        string                       `json:"content"`
-	ContentType    string                       `json:"content_type"`
-	Status         string                       `json:"status"`
-	CreatedAt      time.Time                    `json:"created_at"`
-	UpdatedAt      time.Time                    `json:"updated_at"`
-	Reactions      map[string][]string          `json:"reactions,omitempty"`
-	IsPinned       bool                         `json:"is_pinned"`
-	PinnedBy       []string                     `json:"pinned_by,omitempty"`
-	ReplyToID      *string                      `json:"reply_to_id,omitempty"`
+	ID              string               `json:"id"`
+	ConversationID  string               `json:"conversation_id"`
+	SenderID        string               `json:"sender_id"`
+	Content         string               `json:"content"`
+	ContentType     string               `json:"content_type"`
+	Status          string               `json:"status"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	Reactions       map[string][]string  `json:"reactions,omitempty"`
+	IsPinned        bool                 `json:"is_pinned"`
+	PinnedBy        []string             `json:"pinned_by,omitempty"`
+	ReplyToID       *string              `json:"reply_to_id,omitempty"`
+	ExpiresAt       *time.Time           `json:"expires_at,omitempty"`
+	ThreadID        *string              `json:"thread_id,omitempty"`
+	EditedAt        *time.Time           `json:"edited_at,omitempty"`
+	EditCount       int                  `json:"edit_count"`
+	ClientMessageID string               `json:"client_message_id,omitempty"` // Sender's client-generated ID, for matching optimistic messages
+	LinkPreviews    []LinkPreviewPayload `json:"link_previews,omitempty"`
+	Poll            *PollPayload         `json:"poll,omitempty"`
+}
+
+// LinkPreviewPayload is the preview of a link in a message
+type LinkPreviewPayload struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// PollPayload is the poll of a poll message with its results
+type PollPayload struct {
+	MessageID      string              `json:"message_id"`
+	Question       string              `json:"question"`
+	Options        []PollOptionPayload `json:"options"`
+	MultipleChoice bool                `json:"multiple_choice"`
+	Anonymous      bool                `json:"anonymous"`
+	Quiz           bool                `json:"quiz"`
+	CorrectOption  *int                `json:"correct_option,omitempty"` // Only sent once the quiz closed
+	ClosesAt       *time.Time          `json:"closes_at,omitempty"`
+	IsClosed       bool                `json:"is_closed"`
+	TotalVoters    int                 `json:"total_voters"`
+}
+
+// PollOptionPayload is one answer option of a poll
+type PollOptionPayload struct {
+	Position  int      `json:"position"`
+	Text      string   `json:"text"`
+	VoteCount int      `json:"vote_count"`
+	Voters    []string `json:"voters,omitempty"`
+}
+
+// PollUpdatedPayload for poll updated events, sent when votes change or the poll closes
+type PollUpdatedPayload struct {
+	ConversationID string      `json:"conversation_id"`
+	Poll           PollPayload `json:"poll"`
+}
+
+// MessageDeletedPayload for message deleted events
+type MessageDeletedPayload struct {
+	MessageID      string    `json:"message_id"`
+	ConversationID string    `json:"conversation_id"`
+	Scope          string    `json:"scope"`                // "everyone" or "me"
+	DeletedBy      string    `json:"deleted_by,omitempty"` // Empty when removed by the system
+	DeletedAt      time.Time `json:"deleted_at"`
 }
 
 // MessageStatusPayload for delivery/read receipts
@@ -123,6 +223,26 @@ type PinPayload struct {
 	IsPinned       bool   `json:"is_pinned"`
 }
 
+// MentionPayload for mention events
+type MentionPayload struct {
+	MessageID      string         `json:"message_id"`
+	ConversationID string         `json:"conversation_id"`
+	MentionedBy    string         `json:"mentioned_by"`
+	Message        MessagePayload `json:"message"`
+	UnreadMentions int64          `json:"unread_mentions"` // Unread mentions in this conversation
+}
+
+// ThreadPayload for thread events
+type ThreadPayload struct {
+	RootMessageID  string         `json:"root_message_id"`
+	ConversationID string         `json:"conversation_id"`
+	ReplyCount     int            `json:"reply_count"`
+	LastReplyID    string         `json:"last_reply_id"`
+	LastReplyAt    time.Time      `json:"last_reply_at"`
+	LastReplierID  string         `json:"last_replier_id"`
+	Reply          MessagePayload `json:"reply"`
+}
+
 // TypingPayload for typing indicator events
 type TypingPayload struct {
 	ConversationID string `json:"conversation_id"`
@@ -139,6 +259,15 @@ type ConversationPayload struct {
 	LastMessageAt  time.Time `json:"last_message_at"`
 }
 
+// ConversationReadPayload for conversation read events, sent when the user reads or marks messages unread
+type ConversationReadPayload struct {
+	ConversationID     string     `json:"conversation_id"`
+	LastReadMessageID  *string    `json:"last_read_message_id,omitempty"`
+	LastReadAt         *time.Time `json:"last_read_at,omitempty"`
+	UnreadCount        int        `json:"unread_count"`
+	UnreadMentionCount int        `json:"unread_mention_count"`
+}
+
 // PaymentPayload for payment events
 type PaymentPayload struct {
 	ID             string    `json:"id"`
@@ -154,16 +283,62 @@ type PaymentPayload struct {
 	UpdatedAt      time.Time `json:"updated_at"`
 }
 
+// UserStatusPayload for user.online, user.offline and user.away events
+type UserStatusPayload struct {
+	UserID   string     `json:"user_id"`
+	IsOnline bool       `json:"is_online"` // Also true while away
+	Status   string     `json:"status"`    // "online", "away" or "offline"
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+}
+
+// PresenceStatePayload for presence state events, the current presence of the users a client
+// subscribed to. Users whose presence it may not see are left out.
+type PresenceStatePayload struct {
+	Users []UserStatusPayload `json:"users"`
+}
+
+// SessionOpenedPayload for session opened events, telling an event stream client the session
+// to post its client messages to
+type SessionOpenedPayload struct {
+	SessionID string `json:"session_id"`
+	Transport string `json:"transport"`
+}
+
+// SessionResumedPayload for session resumed events, sent once the missed events were replayed
+type SessionResumedPayload struct {
+	LastSeq  int64 `json:"last_seq"` // The sequence number the client resumed from
+	Seq      int64 `json:"seq"`      // The last sequence number replayed
+	Replayed int   `json:"replayed"`
+}
+
+// SessionResyncPayload for resync required events, sent when missed events can no longer be replayed.
+// The client should catch up through the sync endpoint instead.
+type SessionResyncPayload struct {
+	LastSeq int64  `json:"last_seq"`
+	Reason  string `json:"reason"`
+}
+
 // ErrorPayload for error events
 type ErrorPayload struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	RequestID string `json:"request_id,omitempty"` // Set when a command failed
+	Command   string `json:"command,omitempty"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+// AckPayload for ack events, sent when a command succeeded
+type AckPayload struct {
+	RequestID string                   `json:"request_id"`
+	Command   string                   `json:"command"`
+	Message   *MessagePayload          `json:"message,omitempty"`    // The sent or edited message
+	ReadState *ConversationReadPayload `json:"read_state,omitempty"` // The read marker after mark_read
 }
 
 // ClientMessage represents messages sent from client to server
 type ClientMessage struct {
-	Type    string          `json:"type"`
-	Payload json.RawMessage `json:"payload"`
+	Type      string          `json:"type"`
+	RequestID string          `json:"request_id,omitempty"` // Required for commands, echoed in their ack or error
+	Payload   json.RawMessage `json:"payload"`
 }
 
 // Client message types
@@ -173,13 +348,73 @@ const (
 	ClientMessageDelivered      = "message_delivered"
 	ClientMessageRead           = "message_read"
 	ClientMessagePing           = "ping"
+	ClientMessageResume         = "resume"
+	ClientMessagePresenceSubscribe   = "presence.subscribe"
+	ClientMessagePresenceUnsubscribe = "presence.unsubscribe"
 )
 
+// Client commands, each answered with an ack or an error carrying its request ID
+const (
+	CommandMessageSend    = "message.send"
+	CommandMessageEdit    = "message.edit"
+	CommandMessageDelete  = "message.delete"
+	CommandReactionAdd    = "reaction.add"
+	CommandReactionRemove = "reaction.remove"
+	CommandMarkRead       = "mark_read"
+)
+
+// ClientSendMessagePayload for sending a message, to a conversation or directly to a user
+type ClientSendMessagePayload struct {
+	ConversationID  string                 `json:"conversation_id,omitempty"`
+	RecipientID     string                 `json:"recipient_id,omitempty"` // Used when conversation_id is empty
+	Content         string                 `json:"content"`
+	ContentType     string                 `json:"content_type"`
+	Signature       string                 `json:"signature"`
+	ReplyToID       string                 `json:"reply_to_id,omitempty"`
+	ClientMessageID string                 `json:"client_message_id,omitempty"` // Defaults to the request ID
+	Poll            *dto.CreatePollRequest `json:"poll,omitempty"`
+}
+
+// ClientEditMessagePayload for editing a message
+type ClientEditMessagePayload struct {
+	MessageID string `json:"message_id"`
+	Content   string `json:"content"`
+}
+
+// ClientDeleteMessagePayload for deleting a message
+type ClientDeleteMessagePayload struct {
+	MessageID string `json:"message_id"`
+	Scope     string `json:"scope,omitempty"` // "me" or "everyone", the default
+}
+
+// ClientReactionPayload for adding or removing a reaction
+type ClientReactionPayload struct {
+	MessageID string `json:"message_id"`
+	Emoji     string `json:"emoji"`
+}
+
+// ClientMarkReadPayload for moving the read marker of a conversation
+type ClientMarkReadPayload struct {
+	ConversationID string `json:"conversation_id"`
+	MessageID      string `json:"message_id,omitempty"` // Read up to this message, empty reads up to the latest one
+}
+
 // ClientTypingPayload for client typing events
 type ClientTypingPayload struct {
 	ConversationID string `json:"conversation_id"`
 }
 
+// ClientResumePayload for resuming a session after a reconnect
+type ClientResumePayload struct {
+	LastSeq int64 `json:"last_seq"` // Highest sequence number the client received
+}
+
+// ClientPresencePayload for subscribing to the presence of users outside the ones
+// a client is sent presence of anyway
+type ClientPresencePayload struct {
+	UserIDs []string `json:"user_ids"`
+}
+
 // ClientMessageStatusPayload for client delivery/read receipts
 type ClientMessageStatusPayload struct {
 	MessageID      string `json:"message_id"`
@@ -220,3 +455,37 @@ type GroupSettingsPayload struct {
 	Settings       map[string]interface{} `json:"settings"`
 	UpdatedBy      string                 `json:"updated_by"`
 }
+
+// ChannelPayload for channel events
+type ChannelPayload struct {
+	ID              string                 `json:"id"`
+	ConversationID  string                 `json:"conversation_id"`
+	Name            string                 `json:"name"`
+	Username        string                 `json:"username"`
+	Description     string                 `json:"description"`
+	Avatar          string                 `json:"avatar,omitempty"`
+	OwnerID         string                 `json:"owner_id"`
+	IsPublic        bool                   `json:"is_public"`
+	SubscriberCount int                    `json:"subscriber_count"`
+	Settings        map[string]interface{} `json:"settings,omitempty"`
+	CreatedAt       time.Time              `json:"created_at"`
+	UpdatedAt       time.Time              `json:"updated_at"`
+}
+
+// ChannelAdminPayload for channel admin events
+type ChannelAdminPayload struct {
+	ChannelID      string                 `json:"channel_id"`
+	ConversationID string                 `json:"conversation_id"`
+	UserID         string                 `json:"user_id"`
+	Username       string                 `json:"username,omitempty"`
+	Permissions    map[string]interface{} `json:"permissions,omitempty"`
+	ActionBy       string                 `json:"action_by,omitempty"` // Who performed the action
+}
+
+// ChannelSettingsPayload for channel settings update events
+type ChannelSettingsPayload struct {
+	ChannelID      string                 `json:"channel_id"`
+	ConversationID string                 `json:"conversation_id"`
+	Settings       map[string]interface{} `json:"settings"`
+	UpdatedBy      string                 `json:"updated_by"`
+}
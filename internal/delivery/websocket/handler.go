@@ -74,10 +74,11 @@ var upgrader = websocket.Upgrader{
 	CheckOrigin:     checkOrigin,
 }
 
-// Handler handles WebSocket connections
+// Handler handles WebSocket connections, and the event streams and long polls standing in for them
 type Handler struct {
 	hub            *Hub
 	messageService message.Service
+	sessions       *streamSessions
 }
 
 // NewHandler creates a new WebSocket handler
@@ -85,22 +86,23 @@ func NewHandler(hub *Hub, messageService message.Service) *Handler {
 	return &Handler{
 		hub:            hub,
 		messageService: messageService,
+		sessions:       newStreamSessions(),
 	}
 }
 
-// HandleWebSocket handles WebSocket upgrade requests
-func (h *Handler) HandleWebSocket(c *gin.Context) {
+// requestUser returns the authenticated user of a request, answering it when there is none
+func requestUser(c *gin.Context) (uuid.UUID, string, bool) {
 	// Get user ID from auth middleware
 	userIDStr, exists := c.Get("user_id")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
-		return
+		return uuid.Nil, "", false
 	}
 
 	userID, err := uuid.Parse(userIDStr.(string))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid user ID"})
-		return
+		return uuid.Nil, "", false
 	}
 
 	// Get username (optional, for logging)
@@ -110,6 +112,16 @@ func (h *Handler) HandleWebSocket(c *gin.Context) {
 		usernameStr = username.(string)
 	}
 
+	return userID, usernameStr, true
+}
+
+// HandleWebSocket handles WebSocket upgrade requests
+func (h *Handler) HandleWebSocket(c *gin.Context) {
+	userID, usernameStr, ok := requestUser(c)
+	if !ok {
+		return
+	}
+
 	// Upgrade HTTP connection to WebSocket
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -154,8 +166,18 @@ func NewMessageHandler(hub *Hub, messageService message.Service) *MessageHandler
 	}
 }
 
-// HandleTyping broadcasts typing indicator to conversation participants
+// HandleTyping broadcasts typing indicator to conversation participants unless the user turned it off
 func (m *MessageHandler) HandleTyping(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID) {
+	// Users who turned typing indicators off never share them, and blocked users never see them
+	recipients, err := m.messageService.TypingAudience(ctx, userID, conversationID)
+	if err != nil {
+		logger.Error("Failed to get typing audience", zap.String("conversation_id", conversationID.String()), zap.Error(err))
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
 	event, err := NewEvent(EventTypingStart, TypingPayload{
 		ConversationID: conversationID.String(),
 		UserID:         userID.String(),
@@ -167,7 +189,7 @@ func (m *MessageHandler) HandleTyping(ctx context.Context, userID uuid.UUID, con
 	}
 
 	// Broadcast to conversation (excluding self is optional)
-	if err := m.hub.BroadcastToConversation(ctx, conversationID, event); err != nil {
+	if err := m.hub.BroadcastToUsers(recipients, event); err != nil {
 		logger.Error("Failed to broadcast typing event",
 			zap.String("conversation_id", conversationID.String()),
 			zap.Error(err),
@@ -182,6 +204,15 @@ func (m *MessageHandler) HandleTyping(ctx context.Context, userID uuid.UUID, con
 
 // HandleStopTyping broadcasts stop typing indicator to conversation participants
 func (m *MessageHandler) HandleStopTyping(ctx context.Context, userID uuid.UUID, conversationID uuid.UUID) {
+	recipients, err := m.messageService.TypingAudience(ctx, userID, conversationID)
+	if err != nil {
+		logger.Error("Failed to get typing audience", zap.String("conversation_id", conversationID.String()), zap.Error(err))
+		return
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
 	event, err := NewEvent(EventTypingStop, TypingPayload{
 		ConversationID: conversationID.String(),
 		UserID:         userID.String(),
@@ -193,7 +224,7 @@ func (m *MessageHandler) HandleStopTyping(ctx context.Context, userID uuid.UUID,
 	}
 
 	// Broadcast to conversation
-	if err := m.hub.BroadcastToConversation(ctx, conversationID, event); err != nil {
+	if err := m.hub.BroadcastToUsers(recipients, event); err != nil {
 		logger.Error("Failed to broadcast stop typing event",
 			zap.String("conversation_id", conversationID.String()),
 			zap.Error(err),
@@ -206,18 +237,22 @@ func (m *MessageHandler) HandleStopTyping(ctx context.Context, userID uuid.UUID,
 	)
 }
 
-// HandleMessageDelivered marks message as delivered and broadcasts to sender
+// HandleMessageDelivered records a delivery receipt and sends it to the sender
 func (m *MessageHandler) HandleMessageDelivered(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, conversationID uuid.UUID) {
-	// Update message status in database
-	if err := m.messageService.UpdateMessageStatus(ctx, messageID, "delivered"); err != nil {
-		logger.Error("Failed to update message status to delivered",
+	// Record this user's receipt; group messages keep a receipt per member
+	recipients, err := m.messageService.RecordReceipt(ctx, userID, conversationID, messageID, "delivered")
+	if err != nil {
+		logger.Error("Failed to record delivered receipt",
 			zap.String("message_id", messageID.String()),
 			zap.Error(err),
 		)
 		return
 	}
+	if len(recipients) == 0 {
+		return
+	}
 
-	// Broadcast delivery receipt to conversation (sender will receive it)
+	// Send the delivery receipt to the sender
 	event, err := NewEvent(EventMessageDelivered, MessageStatusPayload{
 		MessageID:      messageID.String(),
 		ConversationID: conversationID.String(),
@@ -230,7 +265,7 @@ func (m *MessageHandler) HandleMessageDelivered(ctx context.Context, userID uuid
 		return
 	}
 
-	if err := m.hub.BroadcastToConversation(ctx, conversationID, event); err != nil {
+	if err := m.hub.BroadcastToUsers(recipients, event); err != nil {
 		logger.Error("Failed to broadcast delivered event",
 			zap.String("conversation_id", conversationID.String()),
 			zap.Error(err),
@@ -243,18 +278,22 @@ func (m *MessageHandler) HandleMessageDelivered(ctx context.Context, userID uuid
 	)
 }
 
-// HandleMessageRead marks message as read and broadcasts to sender
+// HandleMessageRead records a read receipt and sends it to the sender unless either side turned read receipts off
 func (m *MessageHandler) HandleMessageRead(ctx context.Context, userID uuid.UUID, messageID uuid.UUID, conversationID uuid.UUID) {
-	// Update message status in database
-	if err := m.messageService.UpdateMessageStatus(ctx, messageID, "read"); err != nil {
-		logger.Error("Failed to update message status to read",
+	// Record this user's receipt; group messages keep a receipt per member
+	recipients, err := m.messageService.RecordReceipt(ctx, userID, conversationID, messageID, "read")
+	if err != nil {
+		logger.Error("Failed to record read receipt",
 			zap.String("message_id", messageID.String()),
 			zap.Error(err),
 		)
 		return
 	}
+	if len(recipients) == 0 {
+		return
+	}
 
-	// Broadcast read receipt to conversation (sender will receive it)
+	// Send read receipt to the sender and the reader's other devices
 	event, err := NewEvent(EventMessageRead, MessageStatusPayload{
 		MessageID:      messageID.String(),
 		ConversationID: conversationID.String(),
@@ -267,7 +306,7 @@ func (m *MessageHandler) HandleMessageRead(ctx context.Context, userID uuid.UUID
 		return
 	}
 
-	if err := m.hub.BroadcastToConversation(ctx, conversationID, event); err != nil {
+	if err := m.hub.BroadcastToUsers(recipients, event); err != nil {
 		logger.Error("Failed to broadcast read event",
 			zap.String("conversation_id", conversationID.String()),
 			zap.Error(err),
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
@@ -24,6 +25,18 @@ type Hub struct {
 	// User repository for updating online status and last seen
 	userRepo user.Repository
 
+	// Presence tracker deciding who is told when a user comes online, goes away or goes offline
+	presence *Presence
+
+	// Cluster transport relaying events to other nodes, nil when running a single node
+	cluster *Cluster
+
+	// Replay buffer numbering events for resuming sessions, nil to send events unnumbered
+	replay *ReplayBuffer
+
+	// Users coming online and going offline, announced in order off the main loop
+	transitions *userQueue
+
 	// Register requests from clients
 	register chan *Client
 
@@ -34,19 +47,36 @@ type Hub struct {
 	mu sync.RWMutex
 }
 
-// NewHub creates a new Hub instance
-func NewHub(conversationRepo conversation.Repository, userRepo user.Repository) *Hub {
-	return &Hub{
+// NewHub creates a new Hub instance, cluster may be nil when running a single node
+func NewHub(conversationRepo conversation.Repository, userRepo user.Repository, presence *Presence, cluster *Cluster, replay *ReplayBuffer) *Hub {
+	h := &Hub{
 		clients:          make(map[uuid.UUID]map[string]*Client),
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
+		presence:         presence,
+		cluster:          cluster,
+		replay:           replay,
+		transitions:      newUserQueue(),
 		register:         make(chan *Client, 256),
 		unregister:       make(chan *Client, 256),
 	}
+	presence.hub = h
+	if cluster != nil {
+		cluster.hub = h
+	}
+	return h
 }
 
 // Run starts the hub's main loop
 func (h *Hub) Run() {
+	if h.cluster != nil {
+		if err := h.cluster.Start(); err != nil {
+			// Local clients are still served, other nodes just do not reach them
+			logger.Error("Failed to start WebSocket cluster transport", zap.Error(err))
+		}
+	}
+	h.presence.Start()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -58,6 +88,16 @@ func (h *Hub) Run() {
 	}
 }
 
+// Stop takes this node out of the cluster
+func (h *Hub) Stop() {
+	// Joins still queued would otherwise land after the cluster left
+	h.transitions.wait()
+	h.presence.Stop()
+	if h.cluster != nil {
+		h.cluster.Stop()
+	}
+}
+
 // registerClient adds a client to the hub
 func (h *Hub) registerClient(client *Client) {
 	h.mu.Lock()
@@ -70,121 +110,147 @@ func (h *Hub) registerClient(client *Client) {
 
 	// Add client with unique ID
 	h.clients[client.UserID][client.ID] = client
+	close(client.registered)
 
 	logger.Info("🟢 Client connected",
 		zap.String("user_id", client.UserID.String()),
 		zap.String("client_id", client.ID),
+		zap.String("transport", client.Transport),
 		zap.Int("total_devices", len(h.clients[client.UserID])),
 		zap.Bool("is_first_device", isFirstDevice),
 	)
 
-	// Broadcast user online status IMMEDIATELY if this is their first device
-	if isFirstDevice {
-		logger.Info("📢 Broadcasting user ONLINE immediately",
+	h.mu.Unlock()
+
+	// Announced off the loop, which would otherwise wait on Redis and the database
+	if !isFirstDevice {
+		// Connecting another device brings the user back if they were away
+		h.transitions.push(client.UserID, func() { h.presence.touch(client) })
+		return
+	}
+	h.transitions.push(client.UserID, func() { h.connected(client) })
+}
+
+// connected announces a user whose first device on this node connected, unless another node has them
+func (h *Hub) connected(client *Client) {
+	// The user may already be connected to another node
+	if h.cluster != nil && !h.cluster.join(client.UserID) {
+		h.presence.touch(client)
+		return
+	}
+
+	// Broadcast user online status now that they have their first device
+	logger.Info("📢 Broadcasting user ONLINE",
+		zap.String("user_id", client.UserID.String()),
+	)
+	h.presence.online(client.UserID)
+
+	// Update user status to online in database
+	ctx := context.Background()
+	userEntity, err := h.userRepo.FindByID(ctx, client.UserID)
+	if err != nil {
+		logger.Error("❌ Failed to fetch user for status update",
 			zap.String("user_id", client.UserID.String()),
+			zap.Error(err),
 		)
-		h.broadcastUserStatusSync(client.UserID, true)
-
-		// Update user status to online in database (asynchronous to avoid blocking)
-		go func(userID uuid.UUID) {
-			ctx := context.Background()
-			userEntity, err := h.userRepo.FindByID(ctx, userID)
-			if err != nil {
-				logger.Error("❌ Failed to fetch user for status update",
-					zap.String("user_id", userID.String()),
-					zap.Error(err),
-				)
-				return
-			}
-
-			userEntity.UpdateStatus(user.StatusOnline)
-			if err := h.userRepo.Update(ctx, userEntity); err != nil {
-				logger.Error("❌ Failed to update user online status in database",
-					zap.String("user_id", userID.String()),
-					zap.Error(err),
-				)
-			} else {
-				logger.Info("✅ Updated user online status in database",
-					zap.String("user_id", userID.String()),
-				)
-			}
-		}(client.UserID)
+		return
 	}
 
-	h.mu.Unlock()
+	userEntity.UpdateStatus(user.StatusOnline)
+	if err := h.userRepo.Update(ctx, userEntity); err != nil {
+		logger.Error("❌ Failed to update user online status in database",
+			zap.String("user_id", client.UserID.String()),
+			zap.Error(err),
+		)
+	} else {
+		logger.Info("✅ Updated user online status in database",
+			zap.String("user_id", client.UserID.String()),
+		)
+	}
 }
 
 // unregisterClient removes a client from the hub
 func (h *Hub) unregisterClient(client *Client) {
 	h.mu.Lock()
-	if clients, ok := h.clients[client.UserID]; ok {
-		if _, exists := clients[client.ID]; exists {
-			delete(clients, client.ID)
-			close(client.send)
-
-			remainingDevices := len(clients)
-			isLastDevice := remainingDevices == 0
+	clients, ok := h.clients[client.UserID]
+	if !ok {
+		h.mu.Unlock()
+		return
+	}
+	if _, exists := clients[client.ID]; !exists {
+		h.mu.Unlock()
+		return
+	}
 
-			logger.Info("🔴 Client disconnected",
-				zap.String("user_id", client.UserID.String()),
-				zap.String("client_id", client.ID),
-				zap.Int("remaining_devices", remainingDevices),
-				zap.Bool("is_last_device", isLastDevice),
-			)
+	delete(clients, client.ID)
+	close(client.send)
 
-			// If user has no more connected devices, remove from map
-			if isLastDevice {
-				delete(h.clients, client.UserID)
+	remainingDevices := len(clients)
+	isLastDevice := remainingDevices == 0
 
-				logger.Info("📢 Broadcasting user OFFLINE immediately",
-					zap.String("user_id", client.UserID.String()),
-				)
-				// Broadcast user offline status IMMEDIATELY if this was their last device
-				h.broadcastUserStatusSync(client.UserID, false)
-
-				// Update user status to offline and set last seen in database (asynchronous)
-				go func(userID uuid.UUID) {
-					ctx := context.Background()
-					userEntity, err := h.userRepo.FindByID(ctx, userID)
-					if err != nil {
-						logger.Error("❌ Failed to fetch user for status update",
-							zap.String("user_id", userID.String()),
-							zap.Error(err),
-						)
-						return
-					}
-
-					userEntity.UpdateStatus(user.StatusOffline)
-					if err := h.userRepo.Update(ctx, userEntity); err != nil {
-						logger.Error("❌ Failed to update user offline status in database",
-							zap.String("user_id", userID.String()),
-							zap.Error(err),
-						)
-					} else {
-						logger.Info("✅ Updated user offline status and last seen in database",
-							zap.String("user_id", userID.String()),
-						)
-					}
-				}(client.UserID)
-			}
+	logger.Info("🔴 Client disconnected",
+		zap.String("user_id", client.UserID.String()),
+		zap.String("client_id", client.ID),
+		zap.String("transport", client.Transport),
+		zap.Int("remaining_devices", remainingDevices),
+		zap.Bool("is_last_device", isLastDevice),
+	)
 
-			h.mu.Unlock()
-			return
-		}
+	// If user has no more connected devices, remove from map
+	if isLastDevice {
+		delete(h.clients, client.UserID)
 	}
 	h.mu.Unlock()
+
+	h.presence.removeClient(client)
+
+	if !isLastDevice {
+		return
+	}
+	h.transitions.push(client.UserID, func() { h.disconnected(client.UserID) })
+}
+
+// disconnected announces a user whose last device on this node disconnected, unless another node still has them
+func (h *Hub) disconnected(userID uuid.UUID) {
+	// The user may still be connected to another node
+	if h.cluster != nil && !h.cluster.leave(userID) {
+		return
+	}
+
+	logger.Info("📢 Broadcasting user OFFLINE",
+		zap.String("user_id", userID.String()),
+	)
+	// Broadcast user offline status now that their last device is gone
+	h.presence.offline(userID)
+
+	// Update user status to offline and set last seen in database
+	ctx := context.Background()
+	userEntity, err := h.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		logger.Error("❌ Failed to fetch user for status update",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	userEntity.UpdateStatus(user.StatusOffline)
+	if err := h.userRepo.Update(ctx, userEntity); err != nil {
+		logger.Error("❌ Failed to update user offline status in database",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+	} else {
+		logger.Info("✅ Updated user offline status and last seen in database",
+			zap.String("user_id", userID.String()),
+		)
+	}
 }
 
 // BroadcastToConversation broadcasts an event to all participants in a conversation
 // This is the CORRECT implementation with participant filtering
 func (h *Hub) BroadcastToConversation(ctx context.Context, conversationID uuid.UUID, event *Event) error {
-	logger.Info("🔔 BroadcastToConversation called",
-		zap.String("conversation_id", conversationID.String()),
-		zap.String("event_type", string(event.Type)),
-	)
-
 	// Get conversation participants from database
-	logger.Info("🔍 Querying conversation participants from DB")
 	participants, err := h.conversationRepo.FindParticipants(ctx, conversationID)
 	if err != nil {
 		logger.Error("❌ Failed to get conversation participants for broadcast",
@@ -194,10 +260,6 @@ func (h *Hub) BroadcastToConversation(ctx context.Context, conversationID uuid.U
 		return err
 	}
 
-	logger.Info("✅ Got participants from DB",
-		zap.Int("count", len(participants)),
-	)
-
 	// Marshal event once
 	data, err := json.Marshal(event)
 	if err != nil {
@@ -205,53 +267,13 @@ func (h *Hub) BroadcastToConversation(ctx context.Context, conversationID uuid.U
 		return err
 	}
 
-	h.mu.RLock()
-	defer h.mu.RUnlock()
-
-	logger.Info("🔍 Checking connected clients",
-		zap.Int("total_online_users", len(h.clients)),
-	)
-
-	// Track successful sends for logging
-	successCount := 0
-	totalDevices := 0
-
-	// Broadcast to each participant's connected devices
-	for _, participant := range participants {
-		logger.Info("🔍 Checking participant",
-			zap.String("user_id", participant.UserID.String()),
-		)
-
-		if clientMap, ok := h.clients[participant.UserID]; ok {
-			totalDevices += len(clientMap)
-			logger.Info("✅ Participant is online",
-				zap.String("user_id", participant.UserID.String()),
-				zap.Int("devices", len(clientMap)),
-			)
-
-			for _, client := range clientMap {
-				// Non-blocking send with synchronous delivery
-				select {
-				case client.send <- data:
-					successCount++
-					logger.Info("✅ Sent to client",
-						zap.String("user_id", participant.UserID.String()),
-						zap.String("client_id", client.ID),
-					)
-				default:
-					logger.Warn("❌ Client send buffer full",
-						zap.String("user_id", participant.UserID.String()),
-						zap.String("client_id", client.ID),
-					)
-				}
-			}
-		} else {
-			logger.Info("⚠️ Participant is offline",
-				zap.String("user_id", participant.UserID.String()),
-			)
-		}
+	userIDs := make([]uuid.UUID, len(participants))
+	for i, participant := range participants {
+		userIDs[i] = participant.UserID
 	}
 
+	totalDevices, successCount := h.deliver(userIDs, event.Type, data)
+
 	logger.Debug("Broadcast to conversation",
 		zap.String("conversation_id", conversationID.String()),
 		zap.String("event_type", string(event.Type)),
@@ -271,14 +293,103 @@ func (h *Hub) BroadcastToUser(userID uuid.UUID, event *Event) error {
 		return err
 	}
 
+	totalDevices, successCount := h.deliver([]uuid.UUID{userID}, event.Type, data)
+
+	logger.Debug("Broadcast to user",
+		zap.String("user_id", userID.String()),
+		zap.String("event_type", string(event.Type)),
+		zap.Int("devices", totalDevices),
+		zap.Int("delivered", successCount),
+	)
+
+	return nil
+}
+
+// BroadcastToUsers broadcasts an event to multiple users
+func (h *Hub) BroadcastToUsers(userIDs []uuid.UUID, event *Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("Failed to marshal event", zap.Error(err))
+		return err
+	}
+
+	totalDevices, successCount := h.deliver(userIDs, event.Type, data)
+
+	logger.Debug("Broadcast to users",
+		zap.String("event_type", string(event.Type)),
+		zap.Int("users", len(userIDs)),
+		zap.Int("total_devices", totalDevices),
+		zap.Int("delivered", successCount),
+	)
+
+	return nil
+}
+
+// deliver sends an event to the users' devices on this node and relays it to the other nodes.
+// The counts only cover this node.
+func (h *Hub) deliver(userIDs []uuid.UUID, eventType EventType, data []byte) (totalDevices, successCount int) {
+	if len(userIDs) == 0 {
+		return 0, 0
+	}
+
+	seqs := h.sequence(userIDs, eventType, data)
+	totalDevices, successCount = h.deliverLocal(userIDs, seqs, data)
+
+	if h.cluster != nil {
+		h.cluster.publish(clusterEnvelope{UserIDs: userIDs, Seqs: seqs, Data: data})
+	}
+
+	return totalDevices, successCount
+}
+
+// sequence numbers an event for each user and buffers it for replay.
+// Returns nil when the event goes out unnumbered, and clients that miss it will have to resync.
+func (h *Hub) sequence(userIDs []uuid.UUID, eventType EventType, data []byte) []int64 {
+	if h.replay == nil || ephemeralEvents[eventType] {
+		return nil
+	}
+
+	seqs, err := h.replay.Append(context.Background(), userIDs, data)
+	if err != nil {
+		logger.Error("Failed to buffer event for replay",
+			zap.String("event_type", string(eventType)),
+			zap.Int("users", len(userIDs)),
+			zap.Error(err),
+		)
+		return nil
+	}
+	return seqs
+}
+
+// deliverLocal sends an event to the users' devices connected to this node.
+// seqs holds each user's sequence number for the event, or is nil when it has none.
+func (h *Hub) deliverLocal(userIDs []uuid.UUID, seqs []int64, data []byte) (totalDevices, successCount int) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	if clientMap, ok := h.clients[userID]; ok {
-		successCount := 0
+	for i, userID := range userIDs {
+		clientMap, ok := h.clients[userID]
+		if !ok {
+			continue
+		}
+
+		var seq int64
+		payload := data
+		if i < len(seqs) {
+			seq = seqs[i]
+			payload = withSeq(data, seq)
+		}
+
+		totalDevices += len(clientMap)
 		for _, client := range clientMap {
+			// Held until the client resumed, or already replayed to it
+			if seq > 0 && !client.admitLive(seq, payload) {
+				continue
+			}
+
+			// Non-blocking send so a slow client does not hold up the others
 			select {
-			case client.send <- data:
+			case client.send <- payload:
 				successCount++
 			default:
 				logger.Warn("Client send buffer full",
@@ -287,74 +398,241 @@ func (h *Hub) BroadcastToUser(userID uuid.UUID, event *Event) error {
 				)
 			}
 		}
+	}
 
-		logger.Debug("Broadcast to user",
-			zap.String("user_id", userID.String()),
-			zap.String("event_type", string(event.Type)),
-			zap.Int("devices", len(clientMap)),
-			zap.Int("delivered", successCount),
-		)
-	} else {
-		logger.Debug("User not connected",
-			zap.String("user_id", userID.String()),
-			zap.String("event_type", string(event.Type)),
-		)
+	return totalDevices, successCount
+}
+
+// deliverPresence sends a user's status event to their audience and to the connections
+// subscribed to them, on this node and the others
+func (h *Hub) deliverPresence(userID uuid.UUID, audience []uuid.UUID, data []byte) (totalDevices, successCount int) {
+	totalDevices, successCount = h.deliverLocal(audience, nil, data)
+	h.presence.deliverToSubscribers(userID, audience, data)
+
+	if h.cluster != nil {
+		h.cluster.publish(clusterEnvelope{UserIDs: audience, PresenceOf: &userID, Data: data})
 	}
 
-	return nil
+	return totalDevices, successCount
 }
 
-// BroadcastToUsers broadcasts an event to multiple users
-func (h *Hub) BroadcastToUsers(userIDs []uuid.UUID, event *Event) error {
-	data, err := json.Marshal(event)
-	if err != nil {
-		logger.Error("Failed to marshal event", zap.Error(err))
-		return err
+// deliverRelayed sends an event published by another node to the local clients it is meant for
+func (h *Hub) deliverRelayed(env *clusterEnvelope) {
+	h.deliverLocal(env.UserIDs, env.Seqs, env.Data)
+	if env.PresenceOf != nil {
+		h.presence.deliverToSubscribers(*env.PresenceOf, env.UserIDs, env.Data)
+	}
+}
+
+// sendToClients sends an event to the given clients that are still connected
+func (h *Hub) sendToClients(clients []*Client, data []byte) {
+	if len(clients) == 0 {
+		return
 	}
 
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	totalDevices := 0
-	successCount := 0
+	for _, client := range clients {
+		// The send channel of a client that disconnected meanwhile is closed
+		if h.clients[client.UserID][client.ID] != client {
+			continue
+		}
 
-	for _, userID := range userIDs {
-		if clientMap, ok := h.clients[userID]; ok {
-			totalDevices += len(clientMap)
-			for _, client := range clientMap {
-				select {
-				case client.send <- data:
-					successCount++
-				default:
-					logger.Warn("Client send buffer full",
-						zap.String("user_id", userID.String()),
-						zap.String("client_id", client.ID),
-					)
-				}
+		select {
+		case client.send <- data:
+		default:
+			logger.Warn("Client send buffer full",
+				zap.String("user_id", client.UserID.String()),
+				zap.String("client_id", client.ID),
+			)
+		}
+	}
+}
+
+// resume sends a reconnected client the events it missed after lastSeq, skipping the ones it was
+// already sent live. Live events are held meanwhile and sent after the replay, so the client gets
+// them all in sequence order. When they can no longer all be replayed, the client is told to resync.
+func (h *Hub) resume(client *Client, lastSeq int64) {
+	// Live delivery has to start first, or events sent before it would be neither live nor replayed
+	select {
+	case <-client.registered:
+	case <-client.ctx.Done():
+		return
+	}
+
+	sentLive, first := client.startReplay()
+	if !first {
+		client.sendError("already_resumed", "The session was already resumed")
+		return
+	}
+
+	replayedUpTo, replayed, reason := h.replayMissed(client, lastSeq, sentLive)
+	if reason == "" {
+		client.sendEvent(EventSessionResumed, SessionResumedPayload{
+			LastSeq:  lastSeq,
+			Seq:      replayedUpTo,
+			Replayed: replayed,
+		})
+
+		logger.Info("Session resumed",
+			zap.String("user_id", client.UserID.String()),
+			zap.String("client_id", client.ID),
+			zap.Int64("last_seq", lastSeq),
+			zap.Int("replayed", replayed),
+		)
+	}
+
+	// Sent even when the client has to resync, it catches up on the rest
+	if heldReason := h.sendHeld(client, replayedUpTo, reason == "replay_interrupted"); reason == "" {
+		reason = heldReason
+	}
+	if reason != "" {
+		h.requireResync(client, lastSeq, reason)
+	}
+}
+
+// replayMissed sends the buffered events after lastSeq the client was not sent live, and returns the
+// last sequence number they cover. reason is set when the client has to resync instead,
+// and then every held event is sent.
+func (h *Hub) replayMissed(client *Client, lastSeq int64, sentLive map[int64]bool) (replayedUpTo int64, replayed int, reason string) {
+	if h.replay == nil {
+		return 0, 0, "unsupported"
+	}
+
+	events, ok, err := h.replay.Since(client.ctx, client.UserID, lastSeq)
+	if err != nil {
+		logger.Error("Failed to read replay buffer",
+			zap.String("user_id", client.UserID.String()),
+			zap.Error(err),
+		)
+		ok = false
+	}
+	if !ok {
+		return 0, 0, "gap_too_large"
+	}
+	if sentLive == nil {
+		return 0, 0, "too_many_events"
+	}
+
+	replayedUpTo = lastSeq
+	for _, event := range events {
+		if !sentLive[event.Seq] {
+			if !client.sendBlocking(event.Data) {
+				return 0, replayed, "replay_interrupted"
 			}
+			replayed++
 		}
+		replayedUpTo = event.Seq
 	}
 
-	logger.Debug("Broadcast to users",
-		zap.String("event_type", string(event.Type)),
-		zap.Int("users", len(userIDs)),
-		zap.Int("total_devices", totalDevices),
-		zap.Int("delivered", successCount),
-	)
+	return replayedUpTo, replayed, ""
+}
 
-	return nil
+// sendHeld sends the live events held during the replay that it did not cover, until none are held
+// and the client goes back to getting events live. Returns why the client has to resync when some
+// could not be sent. Nothing is sent once the client stopped taking events.
+func (h *Hub) sendHeld(client *Client, replayedUpTo int64, interrupted bool) (reason string) {
+	dropped := false
+	for {
+		held, heldDropped := client.releaseHeld(replayedUpTo)
+		dropped = dropped || heldDropped
+		if held == nil {
+			break
+		}
+
+		for _, event := range held {
+			if interrupted || event.seq <= replayedUpTo {
+				continue
+			}
+			if !client.sendBlocking(event.data) {
+				interrupted = true
+				continue
+			}
+			replayedUpTo = event.seq
+		}
+	}
+
+	switch {
+	case interrupted:
+		return "replay_interrupted"
+	case dropped:
+		return "too_many_events"
+	}
+	return ""
 }
 
-// IsUserOnline checks if a user has any connected devices
+// requireResync tells a client its missed events cannot be replayed
+func (h *Hub) requireResync(client *Client, lastSeq int64, reason string) {
+	client.sendEvent(EventSessionResyncRequired, SessionResyncPayload{
+		LastSeq: lastSeq,
+		Reason:  reason,
+	})
+
+	logger.Info("Session resync required",
+		zap.String("user_id", client.UserID.String()),
+		zap.String("client_id", client.ID),
+		zap.Int64("last_seq", lastSeq),
+		zap.String("reason", reason),
+	)
+}
+
+// IsUserOnline checks if a user has any connected devices, on any node of the cluster
 func (h *Hub) IsUserOnline(userID uuid.UUID) bool {
 	h.mu.RLock()
-	defer h.mu.RUnlock()
-
 	clientMap, ok := h.clients[userID]
-	return ok && len(clientMap) > 0
+	online := ok && len(clientMap) > 0
+	h.mu.RUnlock()
+
+	if online || h.cluster == nil {
+		return online
+	}
+
+	online, err := h.cluster.isOnline(userID)
+	if err != nil {
+		logger.Warn("Failed to check cluster presence",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+	return online
 }
 
-// GetOnlineUsers returns a list of all online user IDs
+// OnlineUsers returns which of the users have connected devices, on any node of the cluster
+func (h *Hub) OnlineUsers(userIDs []uuid.UUID) map[uuid.UUID]bool {
+	online := make(map[uuid.UUID]bool, len(userIDs))
+	remote := make([]uuid.UUID, 0, len(userIDs))
+
+	h.mu.RLock()
+	for _, userID := range userIDs {
+		if len(h.clients[userID]) > 0 {
+			online[userID] = true
+		} else {
+			remote = append(remote, userID)
+		}
+	}
+	h.mu.RUnlock()
+
+	if h.cluster == nil || len(remote) == 0 {
+		return online
+	}
+
+	remoteOnline, err := h.cluster.onlineUsers(remote)
+	if err != nil {
+		logger.Warn("Failed to check cluster presence",
+			zap.Int("users", len(remote)),
+			zap.Error(err),
+		)
+		return online
+	}
+	for userID := range remoteOnline {
+		online[userID] = true
+	}
+	return online
+}
+
+// GetOnlineUsers returns a list of the user IDs connected to this node
 func (h *Hub) GetOnlineUsers() []uuid.UUID {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -367,7 +645,7 @@ func (h *Hub) GetOnlineUsers() []uuid.UUID {
 	return users
 }
 
-// GetUserDeviceCount returns the number of connected devices for a user
+// GetUserDeviceCount returns the number of a user's devices connected to this node
 func (h *Hub) GetUserDeviceCount(userID uuid.UUID) int {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
@@ -378,79 +656,51 @@ func (h *Hub) GetUserDeviceCount(userID uuid.UUID) int {
 	return 0
 }
 
-// Stats returns hub statistics
+// Stats returns hub statistics of this node
 func (h *Hub) Stats() map[string]interface{} {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
 	totalDevices := 0
+	transports := make(map[string]int)
 	for _, clientMap := range h.clients {
 		totalDevices += len(clientMap)
+		for _, client := range clientMap {
+			transports[client.Transport]++
+		}
 	}
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"online_users":  len(h.clients),
 		"total_devices": totalDevices,
+		"transports":    transports,
 	}
-}
-
-// broadcastUserStatusSync broadcasts user online/offline status synchronously
-// This is a lightweight broadcast that doesn't query the database
-// IMPORTANT: Called with h.mu already locked - does NOT lock again
-func (h *Hub) broadcastUserStatusSync(userID uuid.UUID, isOnline bool) {
-	eventType := EventUserOnline
-	if !isOnline {
-		eventType = EventUserOffline
-	}
-
-	event, err := NewEvent(eventType, map[string]interface{}{
-		"user_id":   userID.String(),
-		"is_online": isOnline,
-	})
-	if err != nil {
-		logger.Error("❌ Failed to create user status event", zap.Error(err))
-		return
+	if h.cluster != nil {
+		stats["node_id"] = h.cluster.NodeID()
 	}
+	return stats
+}
 
-	data, err := json.Marshal(event)
-	if err != nil {
-		logger.Error("❌ Failed to marshal user status event", zap.Error(err))
-		return
-	}
+// idleUsers returns the users connected to this node whose devices all did nothing since the given time
+func (h *Hub) idleUsers(since time.Time) []uuid.UUID {
+	cutoff := since.UnixNano()
 
-	successCount := 0
-	totalDevices := 0
-	status := "ONLINE"
-	if !isOnline {
-		status = "OFFLINE"
-	}
+	h.mu.RLock()
+	defer h.mu.RUnlock()
 
-	// Broadcast to ALL connected users (mutex already held by caller)
-	for recipientUserID, clientMap := range h.clients {
-		totalDevices += len(clientMap)
+	var users []uuid.UUID
+	for userID, clientMap := range h.clients {
+		idle := true
 		for _, client := range clientMap {
-			select {
-			case client.send <- data:
-				successCount++
-				logger.Info("✅ Sent presence update",
-					zap.String("status", status),
-					zap.String("changed_user", userID.String()),
-					zap.String("sent_to", recipientUserID.String()),
-					zap.String("client_id", client.ID),
-				)
-			default:
-				logger.Warn("⚠️ Client send buffer full during presence broadcast",
-					zap.String("user_id", client.UserID.String()),
-					zap.String("client_id", client.ID),
-				)
+			if client.lastActivity.Load() > cutoff {
+				idle = false
+				break
 			}
 		}
+		if idle {
+			users = append(users, userID)
+		}
 	}
 
-	logger.Info("📢 Broadcast user status complete",
-		zap.String("user_id", userID.String()),
-		zap.String("status", status),
-		zap.Int("total_devices", totalDevices),
-		zap.Int("delivered", successCount),
-	)
+	return users
 }
@@ -0,0 +1,223 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/user"
+	redisRepo "github.com/yourusername/sotalk/internal/repository/redis"
+)
+
+// eventWait bounds how long a test waits for an event, relayed ones go through pub/sub
+const eventWait = 2 * time.Second
+
+// testUsers stands in for the database the hub writes online statuses to
+type testUsers struct {
+	user.Repository
+}
+
+func (testUsers) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return &user.User{ID: id}, nil
+}
+
+func (testUsers) Update(ctx context.Context, u *user.User) error {
+	return nil
+}
+
+// audiencePolicy shows each user's presence to a fixed audience
+type audiencePolicy map[uuid.UUID][]uuid.UUID
+
+func (p audiencePolicy) PresenceAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return p[userID], nil
+}
+
+func (p audiencePolicy) CanSeePresence(ctx context.Context, viewerID, targetID uuid.UUID) (bool, error) {
+	return true, nil
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newTestHub creates a hub on the given Redis, joining the cluster under nodeID unless it is empty.
+// Clients are registered directly instead of through a running hub loop.
+func newTestHub(t *testing.T, client *redis.Client, nodeID string, policy PresencePolicy) *Hub {
+	t.Helper()
+
+	var cluster *Cluster
+	if nodeID != "" {
+		cluster = NewCluster(client, ClusterConfig{NodeID: nodeID})
+	}
+	presence := NewPresence(policy, redisRepo.NewPresenceCache(client), PresenceConfig{})
+	hub := NewHub(nil, testUsers{}, presence, cluster, NewReplayBuffer(client, ReplayConfig{}))
+
+	if cluster != nil {
+		require.NoError(t, cluster.Start())
+	}
+	t.Cleanup(hub.Stop)
+	return hub
+}
+
+// connect registers a new device of the user and waits for it to be announced
+func connect(hub *Hub, userID uuid.UUID) *Client {
+	client := newClient(userID, "", TransportWebSocket, nil, hub, nil)
+	hub.registerClient(client)
+	hub.transitions.wait()
+	return client
+}
+
+// disconnect unregisters a device and waits for the user's status to be announced
+func disconnect(hub *Hub, client *Client) {
+	hub.unregisterClient(client)
+	hub.transitions.wait()
+}
+
+// receivedEvent is an event as a client receives it
+type receivedEvent struct {
+	Seq     int64           `json:"seq"`
+	Type    EventType       `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// nextEvent waits for the next event sent to a client
+func nextEvent(t *testing.T, client *Client) receivedEvent {
+	t.Helper()
+
+	select {
+	case data, ok := <-client.send:
+		require.True(t, ok, "client was unregistered")
+		var event receivedEvent
+		require.NoError(t, json.Unmarshal(data, &event))
+		return event
+	case <-time.After(eventWait):
+		require.FailNow(t, "no event arrived")
+		return receivedEvent{}
+	}
+}
+
+// expectNoEvent checks that nothing is sent to a client for a while
+func expectNoEvent(t *testing.T, client *Client, within time.Duration) {
+	t.Helper()
+
+	select {
+	case data := <-client.send:
+		assert.Failf(t, "unexpected event", "%s", data)
+	case <-time.After(within):
+	}
+}
+
+func broadcastTo(t *testing.T, hub *Hub, userID uuid.UUID, eventType EventType) {
+	t.Helper()
+	event, err := NewEvent(eventType, map[string]string{"id": uuid.NewString()})
+	require.NoError(t, err)
+	require.NoError(t, hub.BroadcastToUser(userID, event))
+}
+
+func TestClusterDeliversAcrossNodes(t *testing.T) {
+	client := newTestRedis(t)
+	hubA := newTestHub(t, client, "node-a", audiencePolicy{})
+	hubB := newTestHub(t, client, "node-b", audiencePolicy{})
+
+	userID := uuid.New()
+	device := connect(hubB, userID)
+
+	broadcastTo(t, hubA, userID, EventMessageNew)
+
+	event := nextEvent(t, device)
+	assert.Equal(t, EventMessageNew, event.Type)
+	assert.Equal(t, int64(1), event.Seq, "the sequence number is shared by the nodes")
+}
+
+func TestClusterUserOnlineOnAnyNode(t *testing.T) {
+	client := newTestRedis(t)
+	hubA := newTestHub(t, client, "node-a", audiencePolicy{})
+	hubB := newTestHub(t, client, "node-b", audiencePolicy{})
+
+	userID := uuid.New()
+	assert.False(t, hubA.IsUserOnline(userID))
+
+	device := connect(hubB, userID)
+	assert.True(t, hubA.IsUserOnline(userID))
+	assert.Equal(t, map[uuid.UUID]bool{userID: true}, hubA.OnlineUsers([]uuid.UUID{userID, uuid.New()}))
+	assert.Equal(t, 0, hubA.GetUserDeviceCount(userID))
+
+	disconnect(hubB, device)
+	assert.False(t, hubA.IsUserOnline(userID))
+}
+
+func TestClusterStatusFollowsTheLastNode(t *testing.T) {
+	client := newTestRedis(t)
+	userID, watcherID := uuid.New(), uuid.New()
+	policy := audiencePolicy{userID: {watcherID}}
+	hubA := newTestHub(t, client, "node-a", policy)
+	hubB := newTestHub(t, client, "node-b", policy)
+
+	watcher := connect(hubA, watcherID)
+
+	onA := connect(hubA, userID)
+	assert.Equal(t, EventUserOnline, nextEvent(t, watcher).Type)
+
+	// Already online through node A
+	onB := connect(hubB, userID)
+	expectNoEvent(t, watcher, 200*time.Millisecond)
+
+	// Node B still holds a device
+	disconnect(hubA, onA)
+	expectNoEvent(t, watcher, 200*time.Millisecond)
+	assert.True(t, hubA.IsUserOnline(userID))
+
+	// Announced by node B, relayed to the watcher on node A
+	disconnect(hubB, onB)
+	event := nextEvent(t, watcher)
+	assert.Equal(t, EventUserOffline, event.Type)
+	assert.False(t, hubA.IsUserOnline(userID))
+}
+
+func TestRegisterDoesNotWaitForAnnouncement(t *testing.T) {
+	client := newTestRedis(t)
+	release := make(chan struct{})
+	hub := newTestHub(t, client, "node-a", blockingPolicy{release: release})
+	defer close(release)
+
+	done := make(chan struct{})
+	go func() {
+		hub.registerClient(newClient(uuid.New(), "", TransportWebSocket, nil, hub, nil))
+		hub.registerClient(newClient(uuid.New(), "", TransportWebSocket, nil, hub, nil))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(eventWait):
+		t.Fatal("registering waited for the presence audience")
+	}
+	assert.Len(t, hub.GetOnlineUsers(), 2)
+}
+
+// blockingPolicy answers audience lookups only once released
+type blockingPolicy struct {
+	release chan struct{}
+}
+
+func (p blockingPolicy) PresenceAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+	}
+	return nil, nil
+}
+
+func (p blockingPolicy) CanSeePresence(ctx context.Context, viewerID, targetID uuid.UUID) (bool, error) {
+	return true, nil
+}
@@ -0,0 +1,408 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	redisRepo "github.com/yourusername/sotalk/internal/repository/redis"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultAwayAfter = 5 * time.Minute
+
+	// presenceStatusTTL matches how long the cache keeps away and offline statuses
+	presenceStatusTTL = 24 * time.Hour
+
+	// activityReportInterval bounds how often a client's activity is written to the cache
+	activityReportInterval = 15 * time.Second
+
+	// policyLookupTimeout bounds the database lookups deciding who sees a user's presence
+	policyLookupTimeout = 5 * time.Second
+
+	// Users one connection may follow the presence of beyond its own audience
+	maxPresenceSubscriptions = 100
+)
+
+// PresencePolicy decides who may see a user's presence
+type PresencePolicy interface {
+	// PresenceAudience returns the users who are sent a user's presence updates
+	PresenceAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	// CanSeePresence checks if viewerID may see targetID's presence, also from outside their audience
+	CanSeePresence(ctx context.Context, viewerID, targetID uuid.UUID) (bool, error)
+}
+
+// PresenceConfig controls when connected users show as away
+type PresenceConfig struct {
+	AwayAfter time.Duration // Inactivity after which a connected user is away
+}
+
+// Presence tells users when the people they talk to come online, go away or go offline.
+// Updates go to the audience the policy allows, plus connections that subscribed to the user
+// and are still allowed to see them. Statuses live in the presence cache, so a user's devices
+// on every node share them: any node may notice that all of them went idle.
+type Presence struct {
+	policy    PresencePolicy
+	cache     *redisRepo.PresenceCache
+	awayAfter time.Duration
+
+	hub *Hub
+
+	// Subscriptions of local connections, both ways
+	mu            sync.Mutex
+	subscribers   map[uuid.UUID]map[*Client]bool // By the user followed
+	subscriptions map[*Client]map[uuid.UUID]bool
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewPresence creates a new presence tracker, pass it to NewHub
+func NewPresence(policy PresencePolicy, cache *redisRepo.PresenceCache, cfg PresenceConfig) *Presence {
+	if cfg.AwayAfter <= 0 {
+		cfg.AwayAfter = defaultAwayAfter
+	}
+
+	return &Presence{
+		policy:        policy,
+		cache:         cache,
+		awayAfter:     cfg.AwayAfter,
+		subscribers:   make(map[uuid.UUID]map[*Client]bool),
+		subscriptions: make(map[*Client]map[uuid.UUID]bool),
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start starts watching for users going idle
+func (p *Presence) Start() {
+	p.wg.Add(1)
+	go p.watchIdle()
+}
+
+// Stop stops watching for idle users
+func (p *Presence) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// online announces a user whose first device connected
+func (p *Presence) online(userID uuid.UUID) {
+	if err := p.cache.SetOnline(context.Background(), userID, presenceStatusTTL); err != nil {
+		logger.Warn("Failed to cache online status", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+	p.broadcast(userID, redisRepo.StatusOnline)
+}
+
+// offline announces a user whose last device disconnected
+func (p *Presence) offline(userID uuid.UUID) {
+	if err := p.cache.SetOffline(context.Background(), userID); err != nil {
+		logger.Warn("Failed to cache offline status", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+	p.broadcast(userID, redisRepo.StatusOffline)
+}
+
+// touch records that the user did something on a client, bringing them back if they were away.
+// The cache is written at most every activityReportInterval per client, never from the hub loop.
+func (p *Presence) touch(client *Client) {
+	now := time.Now().UnixNano()
+	client.lastActivity.Store(now)
+	if now-client.lastReported.Load() < int64(activityReportInterval) {
+		return
+	}
+	client.lastReported.Store(now)
+
+	ctx, cancel := context.WithTimeout(context.Background(), presenceLookupTimeout)
+	defer cancel()
+
+	status, err := p.cache.GetStatus(ctx, client.UserID)
+	if err != nil {
+		logger.Warn("Failed to get cached status", zap.String("user_id", client.UserID.String()), zap.Error(err))
+	}
+	// Also sets the last seen time other nodes compare against when looking for idle users
+	if err := p.cache.SetOnline(ctx, client.UserID, presenceStatusTTL); err != nil {
+		logger.Warn("Failed to cache online status", zap.String("user_id", client.UserID.String()), zap.Error(err))
+		return
+	}
+	if err == nil && status != redisRepo.StatusOnline {
+		p.broadcast(client.UserID, redisRepo.StatusOnline)
+	}
+}
+
+// watchIdle marks users away once none of their devices did anything for awayAfter
+func (p *Presence) watchIdle() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.awayAfter / 5)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.markIdleAway()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// markIdleAway marks away the users idle on this node who were not active on another one either
+func (p *Presence) markIdleAway() {
+	cutoff := time.Now().Add(-p.awayAfter)
+	idle := p.hub.idleUsers(cutoff)
+	if len(idle) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	statuses, err := p.cache.GetBulkStatus(ctx, idle)
+	if err != nil {
+		logger.Warn("Failed to get cached statuses", zap.Int("users", len(idle)), zap.Error(err))
+		return
+	}
+
+	for _, userID := range idle {
+		if statuses[userID] != redisRepo.StatusOnline {
+			continue
+		}
+		// Devices on other nodes report their activity as the last seen time
+		lastSeen, err := p.cache.GetLastSeen(ctx, userID)
+		if err != nil || (lastSeen != nil && lastSeen.After(cutoff)) {
+			continue
+		}
+
+		if err := p.cache.SetAway(ctx, userID); err != nil {
+			logger.Warn("Failed to cache away status", zap.String("user_id", userID.String()), zap.Error(err))
+			continue
+		}
+		p.broadcast(userID, redisRepo.StatusAway)
+	}
+}
+
+// broadcast sends a user's new status to their audience and subscribers, on every node.
+// It waits on the policy's database lookups, so the hub runs it from its transition queue, never its loop.
+func (p *Presence) broadcast(userID uuid.UUID, status redisRepo.PresenceStatus) {
+	ctx, cancel := context.WithTimeout(context.Background(), policyLookupTimeout)
+	defer cancel()
+
+	audience, err := p.policy.PresenceAudience(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get presence audience",
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return
+	}
+
+	payload := UserStatusPayload{
+		UserID:   userID.String(),
+		IsOnline: status != redisRepo.StatusOffline,
+		Status:   string(status),
+	}
+	eventType := EventUserOnline
+	switch status {
+	case redisRepo.StatusAway:
+		eventType = EventUserAway
+	case redisRepo.StatusOffline:
+		eventType = EventUserOffline
+		now := time.Now()
+		payload.LastSeen = &now
+	}
+
+	event, err := NewEvent(eventType, payload)
+	if err != nil {
+		logger.Error("Failed to create user status event", zap.Error(err))
+		return
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		logger.Error("Failed to marshal user status event", zap.Error(err))
+		return
+	}
+
+	totalDevices, successCount := p.hub.deliverPresence(userID, audience, data)
+
+	logger.Info("📢 Broadcast user status complete",
+		zap.String("user_id", userID.String()),
+		zap.String("status", string(status)),
+		zap.Int("audience", len(audience)),
+		zap.Int("total_devices", totalDevices),
+		zap.Int("delivered", successCount),
+	)
+}
+
+// deliverToSubscribers sends a user's status event to the local connections that subscribed to them.
+// Users in the audience already got it, the others are checked again in case they were blocked since.
+func (p *Presence) deliverToSubscribers(userID uuid.UUID, audience []uuid.UUID, data []byte) {
+	p.mu.Lock()
+	subscribers := make([]*Client, 0, len(p.subscribers[userID]))
+	for client := range p.subscribers[userID] {
+		subscribers = append(subscribers, client)
+	}
+	p.mu.Unlock()
+
+	if len(subscribers) == 0 {
+		return
+	}
+
+	sent := make(map[uuid.UUID]bool, len(audience))
+	for _, id := range audience {
+		sent[id] = true
+	}
+
+	allowed := make(map[uuid.UUID]bool)
+	recipients := make([]*Client, 0, len(subscribers))
+	for _, client := range subscribers {
+		if sent[client.UserID] {
+			continue
+		}
+		canSee, checked := allowed[client.UserID]
+		if !checked {
+			canSee = p.canSee(client.UserID, userID)
+			allowed[client.UserID] = canSee
+		}
+		if canSee {
+			recipients = append(recipients, client)
+		}
+	}
+
+	p.hub.sendToClients(recipients, data)
+}
+
+// subscribe follows the presence of users for a connection and sends their current presence.
+// Users it may not see are skipped.
+func (p *Presence) subscribe(client *Client, userIDs []uuid.UUID) {
+	allowed := make([]uuid.UUID, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if p.canSee(client.UserID, userID) {
+			allowed = append(allowed, userID)
+		}
+	}
+
+	p.mu.Lock()
+	subscriptions := p.subscriptions[client]
+	if subscriptions == nil {
+		subscriptions = make(map[uuid.UUID]bool)
+		p.subscriptions[client] = subscriptions
+	}
+	added := allowed[:0]
+	for _, userID := range allowed {
+		if !subscriptions[userID] && len(subscriptions) >= maxPresenceSubscriptions {
+			continue
+		}
+		subscriptions[userID] = true
+		if p.subscribers[userID] == nil {
+			p.subscribers[userID] = make(map[*Client]bool)
+		}
+		p.subscribers[userID][client] = true
+		added = append(added, userID)
+	}
+	p.mu.Unlock()
+
+	if len(added) < len(allowed) {
+		client.sendError("too_many_subscriptions", "A connection can follow the presence of at most 100 users")
+	}
+
+	client.sendEvent(EventPresenceState, PresenceStatePayload{Users: p.states(added)})
+}
+
+// unsubscribe stops following the presence of users for a connection
+func (p *Presence) unsubscribe(client *Client, userIDs []uuid.UUID) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, userID := range userIDs {
+		p.dropSubscription(client, userID)
+	}
+}
+
+// removeClient drops the subscriptions of a disconnected client
+func (p *Presence) removeClient(client *Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for userID := range p.subscriptions[client] {
+		p.dropSubscription(client, userID)
+	}
+	delete(p.subscriptions, client)
+}
+
+// dropSubscription is called with p.mu held
+func (p *Presence) dropSubscription(client *Client, userID uuid.UUID) {
+	delete(p.subscriptions[client], userID)
+
+	if subscribers, ok := p.subscribers[userID]; ok {
+		delete(subscribers, client)
+		if len(subscribers) == 0 {
+			delete(p.subscribers, userID)
+		}
+	}
+}
+
+// states returns the current presence of users, with the last seen time of those offline
+func (p *Presence) states(userIDs []uuid.UUID) []UserStatusPayload {
+	states := make([]UserStatusPayload, 0, len(userIDs))
+	if len(userIDs) == 0 {
+		return states
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), policyLookupTimeout)
+	defer cancel()
+
+	online := p.hub.OnlineUsers(userIDs)
+	statuses, err := p.cache.GetBulkStatus(ctx, userIDs)
+	if err != nil {
+		logger.Warn("Failed to get cached statuses", zap.Int("users", len(userIDs)), zap.Error(err))
+	}
+
+	for _, userID := range userIDs {
+		state := UserStatusPayload{
+			UserID:   userID.String(),
+			IsOnline: online[userID],
+			Status:   string(redisRepo.StatusOffline),
+		}
+		switch {
+		case online[userID] && statuses[userID] == redisRepo.StatusAway:
+			state.Status = string(redisRepo.StatusAway)
+		case online[userID]:
+			state.Status = string(redisRepo.StatusOnline)
+		default:
+			if lastSeen, err := p.cache.GetLastSeen(ctx, userID); err == nil {
+				state.LastSeen = lastSeen
+			}
+		}
+		states = append(states, state)
+	}
+
+	return states
+}
+
+// canSee checks if a viewer may see a user's presence, denying it when that cannot be told
+func (p *Presence) canSee(viewerID, userID uuid.UUID) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), policyLookupTimeout)
+	defer cancel()
+
+	allowed, err := p.policy.CanSeePresence(ctx, viewerID, userID)
+	if err != nil {
+		logger.Warn("Failed to check presence visibility",
+			zap.String("viewer_id", viewerID.String()),
+			zap.String("user_id", userID.String()),
+			zap.Error(err),
+		)
+		return false
+	}
+	return allowed
+}
+
+// isActivity reports if a client message is something the user did, as opposed to
+// something their app sends on its own
+func isActivity(messageType string) bool {
+	switch messageType {
+	case ClientMessageTyping, ClientMessageStopTyping, ClientMessageRead:
+		return true
+	}
+	return isCommand(messageType)
+}
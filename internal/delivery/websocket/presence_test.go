@@ -0,0 +1,91 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	redisRepo "github.com/yourusername/sotalk/internal/repository/redis"
+)
+
+func statusOf(t *testing.T, event receivedEvent) UserStatusPayload {
+	t.Helper()
+	var payload UserStatusPayload
+	require.NoError(t, json.Unmarshal(event.Payload, &payload))
+	return payload
+}
+
+func TestAnotherDeviceBringsUserBackFromAway(t *testing.T) {
+	client := newTestRedis(t)
+	userID, watcherID := uuid.New(), uuid.New()
+	hub := newTestHub(t, client, "", audiencePolicy{userID: {watcherID}})
+
+	watcher := connect(hub, watcherID)
+	connect(hub, userID)
+	assert.Equal(t, EventUserOnline, nextEvent(t, watcher).Type)
+
+	require.NoError(t, hub.presence.cache.SetAway(context.Background(), userID))
+	connect(hub, userID)
+
+	event := nextEvent(t, watcher)
+	assert.Equal(t, EventUserOnline, event.Type)
+	assert.Equal(t, string(redisRepo.StatusOnline), statusOf(t, event).Status)
+}
+
+func TestIdleUsersGoAway(t *testing.T) {
+	client := newTestRedis(t)
+	idleID, activeID, watcherID := uuid.New(), uuid.New(), uuid.New()
+	hub := newTestHub(t, client, "", audiencePolicy{idleID: {watcherID}, activeID: {watcherID}})
+
+	watcher := connect(hub, watcherID)
+	idle := connect(hub, idleID)
+	connect(hub, activeID)
+	nextEvent(t, watcher)
+	nextEvent(t, watcher)
+
+	// Nothing done on the idle user's device, or on any other node, since before the cutoff
+	past := time.Now().Add(-2 * hub.presence.awayAfter)
+	idle.lastActivity.Store(past.UnixNano())
+	watcher.lastActivity.Store(past.UnixNano())
+	require.NoError(t, client.Set(context.Background(), "lastseen:"+idleID.String(), past.Unix(), 0).Err())
+	require.NoError(t, client.Set(context.Background(), "lastseen:"+watcherID.String(), past.Unix(), 0).Err())
+
+	hub.presence.markIdleAway()
+
+	event := nextEvent(t, watcher)
+	assert.Equal(t, EventUserAway, event.Type)
+	payload := statusOf(t, event)
+	assert.Equal(t, idleID.String(), payload.UserID)
+	assert.True(t, payload.IsOnline)
+	expectNoEvent(t, watcher, 100*time.Millisecond)
+}
+
+func TestSubscribersFollowUsersOutsideTheirAudience(t *testing.T) {
+	client := newTestRedis(t)
+	userID, subscriberID := uuid.New(), uuid.New()
+	hub := newTestHub(t, client, "", audiencePolicy{})
+
+	device := connect(hub, userID)
+	subscriber := connect(hub, subscriberID)
+
+	hub.presence.subscribe(subscriber, []uuid.UUID{userID})
+	event := nextEvent(t, subscriber)
+	require.Equal(t, EventPresenceState, event.Type)
+	var state PresenceStatePayload
+	require.NoError(t, json.Unmarshal(event.Payload, &state))
+	require.Len(t, state.Users, 1)
+	assert.True(t, state.Users[0].IsOnline)
+
+	disconnect(hub, device)
+	event = nextEvent(t, subscriber)
+	assert.Equal(t, EventUserOffline, event.Type)
+	assert.NotNil(t, statusOf(t, event).LastSeen)
+
+	// Dropped with the connection
+	hub.unregisterClient(subscriber)
+	assert.Empty(t, hub.presence.subscribers[userID])
+}
@@ -0,0 +1,59 @@
+package websocket
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// userQueue runs tasks off the hub loop, one at a time per user and in the order they were queued,
+// so a user's connect and disconnect are announced in the order they happened.
+// Each user with queued tasks has a goroutine of its own, which exits once they all ran.
+type userQueue struct {
+	mu      sync.Mutex
+	pending map[uuid.UUID][]func()
+	wg      sync.WaitGroup
+}
+
+func newUserQueue() *userQueue {
+	return &userQueue{pending: make(map[uuid.UUID][]func())}
+}
+
+// push queues a task for a user, never blocking
+func (q *userQueue) push(userID uuid.UUID, task func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	tasks, running := q.pending[userID]
+	q.pending[userID] = append(tasks, task)
+	if !running {
+		q.wg.Add(1)
+		go q.run(userID)
+	}
+}
+
+// run runs a user's tasks until none are left
+func (q *userQueue) run(userID uuid.UUID) {
+	defer q.wg.Done()
+
+	for {
+		q.mu.Lock()
+		tasks := q.pending[userID]
+		if len(tasks) == 0 {
+			delete(q.pending, userID)
+			q.mu.Unlock()
+			return
+		}
+		task := tasks[0]
+		tasks[0] = nil
+		q.pending[userID] = tasks[1:]
+		q.mu.Unlock()
+
+		task()
+	}
+}
+
+// wait waits for the queued tasks to run
+func (q *userQueue) wait() {
+	q.wg.Wait()
+}
@@ -0,0 +1,172 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultReplaySize      = 500
+	defaultReplayRetention = 5 * time.Minute
+)
+
+// appendScript numbers an event for each user and adds it to their stream under that number.
+// KEYS are each user's counter and stream in turn, so one call covers a batch of recipients
+// and the event is sent to Redis once. The counter never expires, so numbers are not reused
+// once an idle stream is gone.
+var appendScript = redis.NewScript(`
+local seqs = {}
+for i = 1, #KEYS, 2 do
+	local seq = redis.call('INCR', KEYS[i])
+	redis.call('XADD', KEYS[i + 1], 'MAXLEN', '~', ARGV[2], seq .. '-0', 'event', ARGV[1])
+	redis.call('PEXPIRE', KEYS[i + 1], ARGV[3])
+	seqs[#seqs + 1] = seq
+end
+return seqs
+`)
+
+// appendBatch bounds the users numbered by one script call, channels can have many subscribers
+const appendBatch = 1000
+
+// ReplayConfig controls how many events are kept for resuming sessions
+type ReplayConfig struct {
+	Size      int           // Events kept per user
+	Retention time.Duration // How long a user's events are kept after the last one
+}
+
+// ReplayBuffer numbers the events sent to each user and keeps the latest ones,
+// so a client that reconnects can be sent exactly what it missed.
+// Each user has a Redis stream whose entry IDs are the sequence numbers, shared by all nodes.
+type ReplayBuffer struct {
+	client    *redis.Client
+	size      int
+	retention time.Duration
+}
+
+// replayedEvent is a buffered event with its sequence number set
+type replayedEvent struct {
+	Seq  int64
+	Data []byte
+}
+
+// NewReplayBuffer creates a new replay buffer
+func NewReplayBuffer(client *redis.Client, cfg ReplayConfig) *ReplayBuffer {
+	if cfg.Size <= 0 {
+		cfg.Size = defaultReplaySize
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = defaultReplayRetention
+	}
+
+	return &ReplayBuffer{
+		client:    client,
+		size:      cfg.Size,
+		retention: cfg.Retention,
+	}
+}
+
+// Append numbers an event for each user and buffers it, returning the sequence numbers in the order of userIDs.
+// data is the event marshaled without a sequence number. Recipients are numbered a batch per round trip.
+func (b *ReplayBuffer) Append(ctx context.Context, userIDs []uuid.UUID, data []byte) ([]int64, error) {
+	seqs := make([]int64, 0, len(userIDs))
+	for start := 0; start < len(userIDs); start += appendBatch {
+		end := start + appendBatch
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		keys := make([]string, 0, 2*(end-start))
+		for _, userID := range userIDs[start:end] {
+			keys = append(keys, replaySeqKey(userID), replayStreamKey(userID))
+		}
+		// Run loads the script again when Redis restarted or its script cache was flushed
+		batch, err := appendScript.Run(ctx, b.client, keys, data, b.size, b.retention.Milliseconds()).Int64Slice()
+		if err != nil {
+			return nil, err
+		}
+		if len(batch) != end-start {
+			return nil, fmt.Errorf("replay script numbered %d of %d users", len(batch), end-start)
+		}
+		seqs = append(seqs, batch...)
+	}
+	return seqs, nil
+}
+
+// Since returns the user's events after seq with their sequence numbers set, oldest first.
+// ok is false when some of them are no longer buffered, and the client has to resync instead.
+func (b *ReplayBuffer) Since(ctx context.Context, userID uuid.UUID, seq int64) ([]replayedEvent, bool, error) {
+	current, err := b.client.Get(ctx, replaySeqKey(userID)).Int64()
+	if err == redis.Nil {
+		current = 0
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	switch {
+	case seq > current:
+		// Not a number this user was ever sent
+		return nil, false, nil
+	case seq == current:
+		return nil, true, nil
+	case current-seq > int64(b.size):
+		return nil, false, nil
+	}
+
+	entries, err := b.client.XRangeN(ctx, replayStreamKey(userID), fmt.Sprintf("%d-0", seq+1), "+", int64(b.size)).Result()
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Sequence numbers have no gaps, so the replay is exact when it picks up right after seq
+	events := make([]replayedEvent, 0, len(entries))
+	next := seq + 1
+	for _, entry := range entries {
+		entrySeq, err := parseReplayID(entry.ID)
+		if err != nil || entrySeq != next {
+			return nil, false, nil
+		}
+		data, _ := entry.Values["event"].(string)
+		events = append(events, replayedEvent{Seq: entrySeq, Data: withSeq([]byte(data), entrySeq)})
+		next++
+	}
+	if next <= current {
+		// The stream expired or was trimmed past seq
+		return nil, false, nil
+	}
+
+	return events, true, nil
+}
+
+// withSeq adds the sequence number to a marshaled event, the same as marshaling it with Seq set
+// but without marshaling the event again for every recipient
+func withSeq(data []byte, seq int64) []byte {
+	if seq <= 0 || len(data) < 2 || data[0] != '{' {
+		return data
+	}
+
+	prefix := `{"seq":` + strconv.FormatInt(seq, 10) + `,`
+	out := make([]byte, 0, len(prefix)+len(data)-1)
+	out = append(out, prefix...)
+	return append(out, data[1:]...)
+}
+
+func parseReplayID(id string) (int64, error) {
+	seq, _, _ := strings.Cut(id, "-")
+	return strconv.ParseInt(seq, 10, 64)
+}
+
+// A user's keys share a hash tag. One script call takes the keys of many users,
+// so the buffer needs a single Redis rather than Redis Cluster.
+func replaySeqKey(userID uuid.UUID) string {
+	return fmt.Sprintf("ws:{%s}:seq", userID.String())
+}
+
+func replayStreamKey(userID uuid.UUID) string {
+	return fmt.Sprintf("ws:{%s}:events", userID.String())
+}
@@ -0,0 +1,162 @@
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func appendEvents(t *testing.T, buffer *ReplayBuffer, userID uuid.UUID, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		_, err := buffer.Append(context.Background(), []uuid.UUID{userID}, []byte(fmt.Sprintf(`{"type":"message.new","payload":%d}`, i)))
+		require.NoError(t, err)
+	}
+}
+
+func replayedSeqs(events []replayedEvent) []int64 {
+	seqs := make([]int64, len(events))
+	for i, event := range events {
+		seqs[i] = event.Seq
+	}
+	return seqs
+}
+
+func TestReplayBufferNumbersEachUser(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewReplayBuffer(newTestRedis(t), ReplayConfig{})
+	alice, bob := uuid.New(), uuid.New()
+
+	seqs, err := buffer.Append(ctx, []uuid.UUID{alice}, []byte(`{"type":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, seqs)
+
+	seqs, err = buffer.Append(ctx, []uuid.UUID{bob, alice}, []byte(`{"type":"b"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1, 2}, seqs, "in the order of the users")
+}
+
+func TestReplayBufferAppendsInBatches(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewReplayBuffer(newTestRedis(t), ReplayConfig{})
+
+	userIDs := make([]uuid.UUID, appendBatch+5)
+	for i := range userIDs {
+		userIDs[i] = uuid.New()
+	}
+	first := userIDs[0]
+	_, err := buffer.Append(ctx, []uuid.UUID{first}, []byte(`{"type":"a"}`))
+	require.NoError(t, err)
+
+	seqs, err := buffer.Append(ctx, userIDs, []byte(`{"type":"b"}`))
+	require.NoError(t, err)
+	require.Len(t, seqs, len(userIDs))
+	assert.Equal(t, int64(2), seqs[0])
+	for _, seq := range seqs[1:] {
+		assert.Equal(t, int64(1), seq)
+	}
+
+	events, ok, err := buffer.Since(ctx, userIDs[len(userIDs)-1], 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, events, 1)
+	assert.Equal(t, `{"seq":1,"type":"b"}`, string(events[0].Data))
+}
+
+func TestReplayBufferReloadsFlushedScript(t *testing.T) {
+	ctx := context.Background()
+	client := newTestRedis(t)
+	buffer := NewReplayBuffer(client, ReplayConfig{})
+	userID := uuid.New()
+
+	appendEvents(t, buffer, userID, 1)
+	require.NoError(t, client.ScriptFlush(ctx).Err())
+
+	seqs, err := buffer.Append(ctx, []uuid.UUID{userID}, []byte(`{"type":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, seqs)
+}
+
+func TestReplayBufferSince(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	tests := []struct {
+		name    string
+		size    int
+		appends int
+		prepare func(t *testing.T, buffer *ReplayBuffer)
+		since   int64
+		want    []int64
+		wantOK  bool
+	}{
+		{name: "missed events", size: 10, appends: 5, since: 2, want: []int64{3, 4, 5}, wantOK: true},
+		{name: "nothing missed", size: 10, appends: 5, since: 5, want: nil, wantOK: true},
+		{name: "never sent", size: 10, appends: 0, since: 0, want: nil, wantOK: true},
+		{name: "ahead of the counter", size: 10, appends: 5, since: 6, wantOK: false},
+		{name: "more missed than kept", size: 3, appends: 10, since: 2, wantOK: false},
+		{
+			name: "stream expired", size: 10, appends: 5, since: 2, wantOK: false,
+			prepare: func(t *testing.T, buffer *ReplayBuffer) {
+				require.NoError(t, buffer.client.Del(ctx, replayStreamKey(userID)).Err())
+			},
+		},
+		{
+			name: "entry missing in the middle", size: 10, appends: 5, since: 2, wantOK: false,
+			prepare: func(t *testing.T, buffer *ReplayBuffer) {
+				require.NoError(t, buffer.client.XDel(ctx, replayStreamKey(userID), "4-0").Err())
+			},
+		},
+		{
+			name: "newest entries missing", size: 10, appends: 5, since: 2, wantOK: false,
+			prepare: func(t *testing.T, buffer *ReplayBuffer) {
+				require.NoError(t, buffer.client.XDel(ctx, replayStreamKey(userID), "5-0").Err())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buffer := NewReplayBuffer(newTestRedis(t), ReplayConfig{Size: tt.size, Retention: time.Minute})
+			appendEvents(t, buffer, userID, tt.appends)
+			if tt.prepare != nil {
+				tt.prepare(t, buffer)
+			}
+
+			events, ok, err := buffer.Since(ctx, userID, tt.since)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				if tt.want == nil {
+					assert.Empty(t, events)
+				} else {
+					assert.Equal(t, tt.want, replayedSeqs(events))
+				}
+			}
+		})
+	}
+}
+
+func TestReplayedEventsCarryTheirSeq(t *testing.T) {
+	ctx := context.Background()
+	buffer := NewReplayBuffer(newTestRedis(t), ReplayConfig{})
+	userID := uuid.New()
+	appendEvents(t, buffer, userID, 2)
+
+	events, ok, err := buffer.Since(ctx, userID, 0)
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(2), eventSeq(events[1].Data))
+}
+
+func TestWithSeq(t *testing.T) {
+	assert.Equal(t, `{"seq":7,"type":"a"}`, string(withSeq([]byte(`{"type":"a"}`), 7)))
+	assert.Equal(t, `{"type":"a"}`, string(withSeq([]byte(`{"type":"a"}`), 0)), "unnumbered")
+	assert.Equal(t, `[]`, string(withSeq([]byte(`[]`), 3)), "not an object")
+}
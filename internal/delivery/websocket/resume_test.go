@@ -0,0 +1,194 @@
+package websocket
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openStream registers a client the way an event stream resuming after lastSeq does,
+// with room for bufferSize queued events
+func openStream(hub *Hub, userID uuid.UUID, lastSeq int64, bufferSize int) *Client {
+	client := newClient(userID, "", TransportSSE, nil, hub, nil)
+	client.send = make(chan []byte, bufferSize)
+	if lastSeq > 0 {
+		client.holdLive()
+	}
+	hub.registerClient(client)
+	return client
+}
+
+// resumeInBackground resumes a client, the returned channel is closed once the resume stopped
+func resumeInBackground(hub *Hub, client *Client, lastSeq int64) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		hub.resume(client, lastSeq)
+	}()
+	return done
+}
+
+func TestResumeReconnectingMidReplay(t *testing.T) {
+	client := newTestRedis(t)
+	hub := newTestHub(t, client, "", audiencePolicy{})
+	userID := uuid.New()
+
+	// Missed while disconnected
+	for i := 0; i < 10; i++ {
+		broadcastTo(t, hub, userID, EventMessageNew)
+	}
+
+	// The first stream resumes after 2, and has room for only a few events, so the replay blocks
+	first := openStream(hub, userID, 2, 4)
+	done := resumeInBackground(hub, first, 2)
+
+	var seen []int64
+	for i := 0; i < 3; i++ {
+		seen = append(seen, nextEvent(t, first).Seq)
+	}
+
+	// Sent while the replay is still going, they must not get ahead of it
+	broadcastTo(t, hub, userID, EventMessageNew)
+	broadcastTo(t, hub, userID, EventMessageNew)
+	for i := 0; i < 3; i++ {
+		seen = append(seen, nextEvent(t, first).Seq)
+	}
+	assert.Equal(t, []int64{3, 4, 5, 6, 7, 8}, seen)
+
+	// The stream drops, what it had not written yet is lost
+	first.cancel()
+	<-done
+	hub.unregisterClient(first)
+
+	// Reconnecting with the last event ID the stream saw, like EventSource does
+	lastSeq := seen[len(seen)-1]
+	second := openStream(hub, userID, lastSeq, 256)
+	broadcastTo(t, hub, userID, EventMessageNew) // Before the resume starts
+	done = resumeInBackground(hub, second, lastSeq)
+	<-done
+
+	var types []EventType
+	for len(second.send) > 0 {
+		event := nextEvent(t, second)
+		if event.Seq > 0 {
+			seen = append(seen, event.Seq)
+		}
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []int64{3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13}, seen, "nothing lost or repeated across the reconnect")
+	assert.Equal(t, EventSessionResumed, types[len(types)-1])
+
+	// Live again
+	broadcastTo(t, hub, userID, EventMessageNew)
+	assert.Equal(t, int64(14), nextEvent(t, second).Seq)
+}
+
+func TestResumeSendsLiveEventsAfterTheReplay(t *testing.T) {
+	client := newTestRedis(t)
+	hub := newTestHub(t, client, "", audiencePolicy{})
+	userID := uuid.New()
+
+	for i := 0; i < 6; i++ {
+		broadcastTo(t, hub, userID, EventMessageNew)
+	}
+
+	stream := openStream(hub, userID, 1, 2)
+	done := resumeInBackground(hub, stream, 1)
+	first := nextEvent(t, stream)
+
+	broadcastTo(t, hub, userID, EventMessageNew)
+	broadcastTo(t, hub, userID, EventMessageNew)
+
+	seqs := []int64{first.Seq}
+	var types []EventType
+	for len(types) < 7 {
+		event := nextEvent(t, stream)
+		types = append(types, event.Type)
+		if event.Seq > 0 {
+			seqs = append(seqs, event.Seq)
+		}
+	}
+	<-done
+
+	assert.Equal(t, []int64{2, 3, 4, 5, 6, 7, 8}, seqs)
+	// The replay covers up to 6, the live events follow the resumed event
+	assert.Equal(t, EventSessionResumed, types[4])
+}
+
+func TestResumeSkipsEventsSentLive(t *testing.T) {
+	client := newTestRedis(t)
+	hub := newTestHub(t, client, "", audiencePolicy{})
+	userID := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		broadcastTo(t, hub, userID, EventMessageNew)
+	}
+
+	// A WebSocket gets events live until it asks to resume
+	device := connect(hub, userID)
+	broadcastTo(t, hub, userID, EventMessageNew)
+	assert.Equal(t, int64(4), nextEvent(t, device).Seq)
+
+	hub.resume(device, 1)
+
+	assert.Equal(t, int64(2), nextEvent(t, device).Seq)
+	assert.Equal(t, int64(3), nextEvent(t, device).Seq)
+	event := nextEvent(t, device)
+	require.Equal(t, EventSessionResumed, event.Type)
+
+	hub.resume(device, 1)
+	assert.Equal(t, EventError, nextEvent(t, device).Type, "a client resumes once")
+}
+
+func TestResumeRequiresResyncPastTheBuffer(t *testing.T) {
+	client := newTestRedis(t)
+	hub := newTestHub(t, client, "", audiencePolicy{})
+	userID := uuid.New()
+
+	broadcastTo(t, hub, userID, EventMessageNew)
+
+	stream := openStream(hub, userID, 5, 16)
+	broadcastTo(t, hub, userID, EventMessageNew)
+	hub.resume(stream, 5)
+
+	// Held events still go out, the resync covers what is missing
+	assert.Equal(t, int64(2), nextEvent(t, stream).Seq)
+	assert.Equal(t, EventSessionResyncRequired, nextEvent(t, stream).Type)
+}
+
+func TestClientHoldsLiveEventsInOrder(t *testing.T) {
+	client := newClient(uuid.New(), "", TransportSSE, nil, nil, nil)
+	assert.True(t, client.admitLive(1, nil))
+
+	sentLive, first := client.startReplay()
+	require.True(t, first)
+	assert.Equal(t, map[int64]bool{1: true}, sentLive)
+
+	assert.False(t, client.admitLive(5, nil))
+	assert.False(t, client.admitLive(4, nil))
+
+	held, dropped := client.releaseHeld(3)
+	assert.False(t, dropped)
+	require.Len(t, held, 2)
+	assert.Equal(t, int64(4), held[0].seq)
+	assert.Equal(t, int64(5), held[1].seq)
+
+	held, _ = client.releaseHeld(5)
+	assert.Nil(t, held)
+	assert.False(t, client.admitLive(5, nil), "already sent")
+	assert.True(t, client.admitLive(6, nil))
+}
+
+func TestClientDropsHeldEventsPastTheLimit(t *testing.T) {
+	client := newClient(uuid.New(), "", TransportSSE, nil, nil, nil)
+	client.holdLive()
+	for seq := int64(1); seq <= maxHeldLiveEvents+1; seq++ {
+		client.admitLive(seq, nil)
+	}
+
+	held, dropped := client.releaseHeld(0)
+	assert.True(t, dropped)
+	assert.Len(t, held, maxHeldLiveEvents)
+}
@@ -0,0 +1,385 @@
+package websocket
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// Sent as an SSE comment to keep proxies from closing an idle stream
+	sseHeartbeatPeriod = pingPeriod
+
+	// sseRetry is how long EventSource waits before reconnecting, in milliseconds
+	sseRetry = 3000
+
+	defaultPollTimeout = 25 * time.Second
+	maxPollTimeout     = 55 * time.Second
+
+	// Events returned by one poll at most, the rest wait for the next one
+	maxPollBatch = 100
+
+	// pollSessionTTL is how long a long-poll session lives without a poll,
+	// its user counts as connected until then
+	pollSessionTTL = 60 * time.Second
+)
+
+// PollResponse is the answer to a long poll
+type PollResponse struct {
+	SessionID string            `json:"session_id"` // Pass it to the next poll
+	Events    []json.RawMessage `json:"events"`
+}
+
+// streamSession is an event stream or long-poll client, served by the node it was opened on.
+// Its client is registered with the hub like a WebSocket connection, so it counts as a device.
+type streamSession struct {
+	client *Client
+
+	// Held for reading while something runs for the client, and for writing to close it,
+	// so nothing sends to the client after the hub closed its channel
+	mu     sync.RWMutex
+	closed bool
+
+	// Client messages run one at a time, like the ones read from a WebSocket
+	inbound sync.Mutex
+
+	// Long poll only
+	poll          sync.Mutex   // Held by the poll waiting on the session
+	deliveredUpTo atomic.Int64 // Highest sequence number a poll returned
+	expiry        *time.Timer  // Closes the session when no poll comes
+}
+
+// run calls fn unless the session was closed
+func (s *streamSession) run(fn func(client *Client)) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return false
+	}
+	fn(s.client)
+	return true
+}
+
+// streamSessions are the open sessions of this node by ID
+type streamSessions struct {
+	mu       sync.Mutex
+	sessions map[string]*streamSession
+}
+
+func newStreamSessions() *streamSessions {
+	return &streamSessions{sessions: make(map[string]*streamSession)}
+}
+
+// get returns a user's session, nil when it is not open on this node
+func (s *streamSessions) get(sessionID string, userID uuid.UUID) *streamSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[sessionID]
+	if !ok || session.client.UserID != userID {
+		return nil
+	}
+	return session
+}
+
+// openSession registers a new session's client with the hub. A session resuming after lastSeq
+// holds live events from the start, so none get ahead of the replay.
+func (h *Handler) openSession(userID uuid.UUID, username, transport string, lastSeq int64) *streamSession {
+	client := newClient(userID, username, transport, nil, h.hub, NewMessageHandler(h.hub, h.messageService))
+	if lastSeq > 0 {
+		client.holdLive()
+	}
+	session := &streamSession{client: client}
+
+	h.sessions.mu.Lock()
+	h.sessions.sessions[client.ID] = session
+	h.sessions.mu.Unlock()
+
+	h.hub.register <- client
+
+	logger.Info("Event stream session opened",
+		zap.String("user_id", userID.String()),
+		zap.String("client_id", client.ID),
+		zap.String("transport", transport),
+	)
+	return session
+}
+
+// closeSession unregisters a session's client, once whatever runs for it has stopped
+func (h *Handler) closeSession(session *streamSession) {
+	// Cancelled first, so sends waiting for room give up
+	session.client.cancel()
+
+	session.mu.Lock()
+	if session.closed {
+		session.mu.Unlock()
+		return
+	}
+	session.closed = true
+	session.mu.Unlock()
+
+	h.sessions.mu.Lock()
+	delete(h.sessions.sessions, session.client.ID)
+	h.sessions.mu.Unlock()
+
+	h.hub.unregister <- session.client
+}
+
+// resumeSession replays the events after lastSeq in the background, while they are being sent.
+// The session is not closed before the replay stops.
+func (h *Handler) resumeSession(session *streamSession, lastSeq int64) {
+	session.mu.RLock()
+	if session.closed {
+		session.mu.RUnlock()
+		return
+	}
+
+	go func() {
+		defer session.mu.RUnlock()
+		h.hub.resume(session.client, lastSeq)
+	}()
+}
+
+// HandleEventStream streams events as Server-Sent Events, for networks that break WebSocket upgrades.
+// Each sequenced event carries its number as the SSE id, so a reconnecting EventSource resumes
+// from its Last-Event-ID. Client messages are posted to HandleClientMessage with the session ID
+// of the first event.
+func (h *Handler) HandleEventStream(c *gin.Context) {
+	userID, username, ok := requestUser(c)
+	if !ok {
+		return
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		// EventSource polyfills that cannot set headers
+		lastEventID = c.Query("last_event_id")
+	}
+	var lastSeq int64
+	if lastEventID != "" {
+		var err error
+		if lastSeq, err = strconv.ParseInt(lastEventID, 10, 64); err != nil || lastSeq < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	flusher, ok := c.Writer.(http.Flusher)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "streaming unsupported"})
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Keep nginx from buffering the stream
+	c.Status(http.StatusOK)
+
+	session := h.openSession(userID, username, TransportSSE, lastSeq)
+	defer h.closeSession(session)
+
+	w := c.Writer
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry); err != nil {
+		return
+	}
+	session.run(func(client *Client) {
+		client.sendEvent(EventSessionOpened, SessionOpenedPayload{SessionID: client.ID, Transport: TransportSSE})
+	})
+	if lastSeq > 0 {
+		h.resumeSession(session, lastSeq)
+	}
+
+	ticker := time.NewTicker(sseHeartbeatPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case data, ok := <-session.client.send:
+			if !ok {
+				return
+			}
+			if err := writeSSE(w, data); err != nil {
+				return
+			}
+
+			// Add queued events to the same flush
+			n := len(session.client.send)
+			for i := 0; i < n; i++ {
+				if err := writeSSE(w, <-session.client.send); err != nil {
+					return
+				}
+			}
+			flusher.Flush()
+
+		case <-ticker.C:
+			if _, err := io.WriteString(w, ": ping\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
+
+// HandleLongPoll returns the events queued for a long-poll session, waiting up to timeout seconds
+// for the first one. The session stays connected between polls. A poll whose session is gone,
+// or whose after is below what the last poll returned because that response was lost, opens a new
+// session that replays the events after after.
+func (h *Handler) HandleLongPoll(c *gin.Context) {
+	userID, username, ok := requestUser(c)
+	if !ok {
+		return
+	}
+
+	after, err := strconv.ParseInt(c.DefaultQuery("after", "0"), 10, 64)
+	if err != nil || after < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid after"})
+		return
+	}
+	timeout := defaultPollTimeout
+	if t := c.Query("timeout"); t != "" {
+		seconds, err := strconv.Atoi(t)
+		if err != nil || seconds < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timeout"})
+			return
+		}
+		timeout = time.Duration(seconds) * time.Second
+		if timeout > maxPollTimeout {
+			timeout = maxPollTimeout
+		}
+	}
+
+	session := h.sessions.get(c.Query("session_id"), userID)
+	if session == nil || session.client.Transport != TransportLongPoll || after < session.deliveredUpTo.Load() {
+		// Opened before the previous one is closed, so the user does not blink offline
+		opened := h.openSession(userID, username, TransportLongPoll, after)
+		opened.expiry = time.AfterFunc(pollSessionTTL, func() { h.closeSession(opened) })
+		if after > 0 {
+			h.resumeSession(opened, after)
+		}
+		if session != nil {
+			h.closeSession(session)
+		}
+		session = opened
+	}
+
+	if !session.poll.TryLock() {
+		c.JSON(http.StatusConflict, gin.H{"error": "another poll is waiting on this session"})
+		return
+	}
+	defer session.poll.Unlock()
+
+	session.expiry.Stop()
+	defer session.expiry.Reset(pollSessionTTL)
+
+	events := make([]json.RawMessage, 0)
+	wait := time.NewTimer(timeout)
+	defer wait.Stop()
+
+	select {
+	case data, ok := <-session.client.send:
+		if ok {
+			events = append(events, data)
+		}
+	case <-wait.C:
+	case <-c.Request.Context().Done():
+		return
+	}
+
+	// Take what else is queued without waiting
+collect:
+	for len(events) < maxPollBatch {
+		select {
+		case data, ok := <-session.client.send:
+			if !ok {
+				break collect
+			}
+			events = append(events, data)
+		default:
+			break collect
+		}
+	}
+
+	for _, data := range events {
+		if seq := eventSeq(data); seq > session.deliveredUpTo.Load() {
+			session.deliveredUpTo.Store(seq)
+		}
+	}
+
+	c.JSON(http.StatusOK, PollResponse{
+		SessionID: session.client.ID,
+		Events:    events,
+	})
+}
+
+// HandleClientMessage runs a client message posted for an event stream or long-poll session,
+// the same as one read from a WebSocket. Acks and errors arrive over the session's events.
+func (h *Handler) HandleClientMessage(c *gin.Context) {
+	userID, _, ok := requestUser(c)
+	if !ok {
+		return
+	}
+
+	session := h.sessions.get(c.Query("session_id"), userID)
+	if session == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+
+	message, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxMessageSize))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "message too large"})
+		return
+	}
+
+	handled := session.run(func(client *Client) {
+		session.inbound.Lock()
+		defer session.inbound.Unlock()
+		client.handleClientMessage(message)
+	})
+	if !handled {
+		c.JSON(http.StatusNotFound, gin.H{"error": "session not found"})
+		return
+	}
+
+	c.Status(http.StatusAccepted)
+}
+
+// writeSSE writes an event as an SSE message, with its sequence number as the id
+func writeSSE(w io.Writer, data []byte) error {
+	if seq := eventSeq(data); seq > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", seq); err != nil {
+			return err
+		}
+	}
+	// Marshaled JSON has no newlines, so it fits on one data line
+	if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+		return err
+	}
+	return nil
+}
+
+// eventSeq returns the sequence number of a marshaled event, 0 when it has none
+func eventSeq(data []byte) int64 {
+	var event struct {
+		Seq int64 `json:"seq"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return 0
+	}
+	return event.Seq
+}
@@ -0,0 +1,298 @@
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runHubLoop registers and unregisters clients the way Hub.Run does, until the test ends.
+// Unregistrations still queued then are handled before the hub stops
+func runHubLoop(t *testing.T, hub *Hub) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case client := <-hub.register:
+				hub.registerClient(client)
+			case client := <-hub.unregister:
+				hub.unregisterClient(client)
+			case <-done:
+				for len(hub.unregister) > 0 {
+					hub.unregisterClient(<-hub.unregister)
+				}
+				return
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		close(done)
+		<-stopped
+	})
+}
+
+// newStreamServer serves the event stream, long poll and client message endpoints to userID
+func newStreamServer(t *testing.T, hub *Hub, userID uuid.UUID) (*httptest.Server, *Handler, *commandService) {
+	gin.SetMode(gin.TestMode)
+	runHubLoop(t, hub)
+
+	service := &commandService{}
+	handler := NewHandler(hub, service)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID.String())
+	})
+	router.GET("/events", handler.HandleEventStream)
+	router.GET("/poll", handler.HandleLongPoll)
+	router.POST("/messages", handler.HandleClientMessage)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, handler, service
+}
+
+// sseEvent is one message of an event stream
+type sseEvent struct {
+	ID   string
+	Data receivedEvent
+}
+
+// nextSSE reads the next event of a stream, skipping the retry field and heartbeats
+func nextSSE(t *testing.T, r *bufio.Reader) sseEvent {
+	t.Helper()
+
+	var event sseEvent
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			event.ID = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event.Data))
+		case line == "" && event.Data.Type != "":
+			return event
+		}
+	}
+}
+
+func poll(t *testing.T, server *httptest.Server, query url.Values) (int, PollResponse) {
+	t.Helper()
+
+	resp, err := http.Get(server.URL + "/poll?" + query.Encode())
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var body PollResponse
+	if resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	}
+	return resp.StatusCode, body
+}
+
+// polledSeqs returns the sequence numbers of the polled events, leaving out unsequenced ones
+func polledSeqs(events []json.RawMessage) []int64 {
+	seqs := make([]int64, 0, len(events))
+	for _, data := range events {
+		if seq := eventSeq(data); seq > 0 {
+			seqs = append(seqs, seq)
+		}
+	}
+	return seqs
+}
+
+// pollReplay polls a resuming session until its replay finished, returning the session ID and the sequenced events
+func pollReplay(t *testing.T, server *httptest.Server, sessionID string, after int64) (string, []int64) {
+	t.Helper()
+
+	var seqs []int64
+	for i := 0; i < 10; i++ {
+		status, resp := poll(t, server, url.Values{"session_id": {sessionID}, "after": {strconv.FormatInt(after, 10)}, "timeout": {"1"}})
+		require.Equal(t, http.StatusOK, status)
+		sessionID = resp.SessionID
+
+		polled := polledSeqs(resp.Events)
+		seqs = append(seqs, polled...)
+		if len(polled) > 0 {
+			after = polled[len(polled)-1]
+		}
+		for _, data := range resp.Events {
+			var event receivedEvent
+			require.NoError(t, json.Unmarshal(data, &event))
+			if event.Type == EventSessionResumed {
+				return sessionID, seqs
+			}
+		}
+	}
+	require.FailNow(t, "the replay did not finish")
+	return "", nil
+}
+
+func TestWriteSSE(t *testing.T) {
+	var b strings.Builder
+	require.NoError(t, writeSSE(&b, []byte(`{"seq":4,"type":"message.new"}`)))
+	require.NoError(t, writeSSE(&b, []byte(`{"type":"ack"}`)))
+
+	assert.Equal(t, "id: 4\ndata: {\"seq\":4,\"type\":\"message.new\"}\n\ndata: {\"type\":\"ack\"}\n\n", b.String(),
+		"only sequenced events get an id, so Last-Event-ID stays on the last one")
+}
+
+func TestEventSeq(t *testing.T) {
+	assert.Equal(t, int64(12), eventSeq([]byte(`{"seq":12,"type":"message.new"}`)))
+	assert.Equal(t, int64(0), eventSeq([]byte(`{"type":"ping"}`)))
+	assert.Equal(t, int64(0), eventSeq([]byte(`not json`)))
+}
+
+func TestEventStreamResumesFromLastEventID(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	userID := uuid.New()
+	server, _, _ := newStreamServer(t, hub, userID)
+
+	for i := 0; i < 3; i++ {
+		broadcastTo(t, hub, userID, EventMessageNew)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	r := bufio.NewReader(resp.Body)
+	opened := nextSSE(t, r)
+	assert.Equal(t, EventSessionOpened, opened.Data.Type)
+	assert.Empty(t, opened.ID)
+
+	assert.Equal(t, "2", nextSSE(t, r).ID)
+	assert.Equal(t, "3", nextSSE(t, r).ID)
+	assert.Equal(t, EventSessionResumed, nextSSE(t, r).Data.Type)
+
+	broadcastTo(t, hub, userID, EventMessageNew)
+	assert.Equal(t, "4", nextSSE(t, r).ID, "live events follow the replay")
+}
+
+func TestEventStreamRunsPostedCommands(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	userID := uuid.New()
+	server, _, service := newStreamServer(t, hub, userID)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	r := bufio.NewReader(resp.Body)
+	var opened SessionOpenedPayload
+	require.NoError(t, json.Unmarshal(nextSSE(t, r).Data.Payload, &opened))
+	assert.Equal(t, TransportSSE, opened.Transport)
+
+	command := `{"type":"mark_read","request_id":"req-1","payload":{"conversation_id":"` + uuid.NewString() + `"}}`
+	posted, err := http.Post(server.URL+"/messages?session_id="+opened.SessionID, "application/json", strings.NewReader(command))
+	require.NoError(t, err)
+	posted.Body.Close()
+	assert.Equal(t, http.StatusAccepted, posted.StatusCode)
+
+	ack := nextSSE(t, r)
+	assert.Equal(t, EventAck, ack.Data.Type, "the ack arrives over the stream")
+	assert.NotNil(t, service.read)
+
+	unknown, err := http.Post(server.URL+"/messages?session_id="+uuid.NewString(), "application/json", strings.NewReader(command))
+	require.NoError(t, err)
+	unknown.Body.Close()
+	assert.Equal(t, http.StatusNotFound, unknown.StatusCode)
+}
+
+func TestEventStreamRejectsInvalidLastEventID(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	server, _, _ := newStreamServer(t, hub, uuid.New())
+
+	resp, err := http.Get(server.URL + "/events?last_event_id=-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestLongPoll(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	userID := uuid.New()
+	server, _, _ := newStreamServer(t, hub, userID)
+
+	// The first poll opens the session, the user is connected from then on
+	status, first := poll(t, server, url.Values{"timeout": {"0"}})
+	require.Equal(t, http.StatusOK, status)
+	require.NotEmpty(t, first.SessionID)
+	assert.Empty(t, first.Events)
+	assert.True(t, hub.IsUserOnline(userID))
+
+	broadcastTo(t, hub, userID, EventMessageNew)
+	broadcastTo(t, hub, userID, EventMessageNew)
+	status, second := poll(t, server, url.Values{"session_id": {first.SessionID}, "after": {"0"}, "timeout": {"1"}})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, first.SessionID, second.SessionID)
+	assert.Equal(t, []int64{1, 2}, polledSeqs(second.Events))
+
+	// The response with 2 was lost, so the client polls after 1 again and gets it replayed on a new session
+	broadcastTo(t, hub, userID, EventMessageNew)
+	sessionID, seqs := pollReplay(t, server, first.SessionID, 1)
+	assert.NotEqual(t, first.SessionID, sessionID)
+	assert.Equal(t, []int64{2, 3}, seqs)
+}
+
+func TestLongPollSessionsAreNotShared(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	userID := uuid.New()
+	server, handler, _ := newStreamServer(t, hub, userID)
+
+	_, first := poll(t, server, url.Values{"timeout": {"0"}})
+	session := handler.sessions.get(first.SessionID, userID)
+	require.NotNil(t, session)
+	assert.Nil(t, handler.sessions.get(first.SessionID, uuid.New()), "sessions of other users are not found")
+
+	// Another poll waiting on the session gets a conflict
+	session.poll.Lock()
+	status, _ := poll(t, server, url.Values{"session_id": {first.SessionID}, "timeout": {"0"}})
+	assert.Equal(t, http.StatusConflict, status)
+	session.poll.Unlock()
+
+	status, second := poll(t, server, url.Values{"session_id": {first.SessionID}, "timeout": {"0"}})
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, first.SessionID, second.SessionID)
+
+	_, third := poll(t, server, url.Values{"session_id": {uuid.NewString()}, "timeout": {"0"}})
+	assert.NotEqual(t, first.SessionID, third.SessionID, "an unknown session opens a new one")
+}
+
+func TestLongPollRejectsInvalidParameters(t *testing.T) {
+	hub := newTestHub(t, newTestRedis(t), "", audiencePolicy{})
+	server, _, _ := newStreamServer(t, hub, uuid.New())
+
+	for _, query := range []string{"after=-1", "after=x", "timeout=-5", "timeout=soon"} {
+		resp, err := http.Get(server.URL + "/poll?" + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+}
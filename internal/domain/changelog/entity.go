@@ -0,0 +1,54 @@
+package changelog
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kind is what a change log entry is about
+type Kind string
+
+const (
+	KindMessageNew          Kind = "message.new"
+	KindMessageUpdated      Kind = "message.updated" // Edited, link previews or poll results
+	KindMessageDeleted      Kind = "message.deleted"
+	KindReactionUpdated     Kind = "reaction.updated"
+	KindPinUpdated          Kind = "pin.updated"
+	KindMembershipUpdated   Kind = "membership.updated"   // Joined, left, removed or role changed
+	KindConversationUpdated Kind = "conversation.updated" // Info and settings, or per user state such as read marker, archive, pin, mute and folders
+)
+
+// Change is an entry of the change log clients sync from.
+// Seq comes from a single sequence, so the changes a user sees always increase.
+type Change struct {
+	Seq            int64
+	ConversationID uuid.UUID
+	MessageID      *uuid.UUID
+	UserID         *uuid.UUID // Only this user sees the change, nil for all participants
+	Kind           Kind
+	CreatedAt      time.Time
+}
+
+// NewConversationChange creates a change of a conversation seen by all its participants
+func NewConversationChange(conversationID uuid.UUID, kind Kind) *Change {
+	return &Change{
+		ConversationID: conversationID,
+		Kind:           kind,
+	}
+}
+
+// NewMessageChange creates a change of a message seen by all participants of its conversation
+func NewMessageChange(conversationID, messageID uuid.UUID, kind Kind) *Change {
+	return &Change{
+		ConversationID: conversationID,
+		MessageID:      &messageID,
+		Kind:           kind,
+	}
+}
+
+// ForUser limits the change to a single user
+func (c *Change) ForUser(userID uuid.UUID) *Change {
+	c.UserID = &userID
+	return c
+}
@@ -0,0 +1,25 @@
+package changelog
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Repository defines the interface for change log data operations.
+// Reads leave out changes younger than settleDelay, so a change committed late
+// with a lower Seq is not skipped by a client that already synced past it.
+type Repository interface {
+	// Record appends changes to the log
+	Record(ctx context.Context, changes ...*Change) error
+	// FindSince returns up to limit changes after since that the user can see, oldest first.
+	// A user sees changes scoped to them and changes of the conversations they take part in
+	FindSince(ctx context.Context, userID uuid.UUID, since int64, settleDelay time.Duration, limit int) ([]*Change, error)
+	// Exists checks if the change with the given Seq is still in the log
+	Exists(ctx context.Context, seq int64) (bool, error)
+	// LatestSeq returns the Seq of the newest settled change, 0 when the log is empty
+	LatestSeq(ctx context.Context, settleDelay time.Duration) (int64, error)
+	// DeleteOlderThan prunes up to limit changes older than age, returning how many were deleted
+	DeleteOlderThan(ctx context.Context, age time.Duration, limit int) (int64, error)
+}
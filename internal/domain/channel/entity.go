@@ -27,6 +27,8 @@ type Settings struct {
 	AdminsCanPost bool   // If false, only owner can post
 	LinkPreview   bool   // Show link previews
 	ForwardingAllowed bool // Allow forwarding messages
+	EditWindowSeconds int  // How long after sending a post can be edited, 0 = no limit
+	SubscriberVotes   bool // Subscribers can vote in polls, otherwise only the owner and admins
 }
 
 // Subscriber represents a channel subscriber
@@ -157,3 +159,8 @@ func (a *Admin) RevokeManageAdmins() {
 func (a *Admin) CanPost() bool {
 	return a.Permissions.CanPostMessages
 }
+
+// CanDelete checks if admin can delete other users' messages
+func (a *Admin) CanDelete() bool {
+	return a.Permissions.CanDeleteMessages
+}
@@ -61,6 +61,8 @@ type Repository interface {
 	GetUserContacts(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Contact, error)
 	UpdateContact(ctx context.Context, contact *Contact) error
 	IsContact(ctx context.Context, userID, targetID uuid.UUID) (bool, error)
+	// GetContactOwnerIDs returns the users who have contactID in their contacts
+	GetContactOwnerIDs(ctx context.Context, contactID uuid.UUID) ([]uuid.UUID, error)
 
 	// Favorites
 	SetFavorite(ctx context.Context, userID, contactID uuid.UUID, favorite bool) error
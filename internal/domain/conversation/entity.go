@@ -27,13 +27,30 @@ const (
 
 // Participant represents a user in a conversation
 type Participant struct {
-	ConversationID uuid.UUID
-	UserID         uuid.UUID
-	Role           Role
-	JoinedAt       time.Time
-	LastReadAt     *time.Time
-	ArchivedAt     *time.Time // Timestamp when user archived this conversation
-	IsOnline       bool       // Real-time online status (not persisted in DB)
+	ConversationID    uuid.UUID
+	UserID            uuid.UUID
+	Role              Role
+	JoinedAt          time.Time
+	LastReadAt        *time.Time // Read marker: messages created up to this time are read
+	LastReadMessageID *uuid.UUID // Message the read marker was placed on
+	ArchivedAt        *time.Time // Timestamp when user archived this conversation
+	RequestedAt       *time.Time // Set while the conversation waits in this user's message requests
+	PinOrder          *int       // Place among the user's pinned conversations, highest first; nil when not pinned
+	MutedUntil        *time.Time // No notifications are created for the user until this time
+	IsOnline          bool       // Real-time online status (not persisted in DB)
+}
+
+// MaxPinnedConversations is how many conversations a user can pin
+const MaxPinnedConversations = 10
+
+// MuteForever is stored as the mute end of conversations muted without a time limit
+var MuteForever = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+// ListFilter narrows down a user's conversation list. Nil fields do not filter
+type ListFilter struct {
+	FolderID *uuid.UUID
+	Pinned   *bool
+	Muted    *bool
 }
 
 // Role represents the role of a participant
@@ -97,8 +114,42 @@ func NewParticipant(conversationID, userID uuid.UUID, role Role) *Participant {
 	}
 }
 
+// IsRequest checks if the conversation is still a message request for this participant
+func (p *Participant) IsRequest() bool {
+	return p.RequestedAt != nil
+}
+
+// FindPendingRequest returns the participant who has not accepted the message request yet, or nil if there is none
+func FindPendingRequest(participants []*Participant) *Participant {
+	for _, p := range participants {
+		if p.IsRequest() {
+			return p
+		}
+	}
+	return nil
+}
+
 // UpdateLastRead updates the last read timestamp
 func (p *Participant) UpdateLastRead() {
 	now := time.Now()
 	p.LastReadAt = &now
 }
+
+// ReadUpTo returns the time messages are read up to. Messages from before
+// the user joined never count as unread, whether or not they were read
+func (p *Participant) ReadUpTo() time.Time {
+	if p.LastReadAt != nil && p.LastReadAt.After(p.JoinedAt) {
+		return *p.LastReadAt
+	}
+	return p.JoinedAt
+}
+
+// IsPinned checks if the participant pinned the conversation
+func (p *Participant) IsPinned() bool {
+	return p.PinOrder != nil
+}
+
+// IsMuted checks if notifications of the conversation are muted for the participant
+func (p *Participant) IsMuted() bool {
+	return p.MutedUntil != nil && time.Now().Before(*p.MutedUntil)
+}
@@ -0,0 +1,19 @@
+package conversation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParticipantReadUpTo(t *testing.T) {
+	joinedAt := time.Now().Add(-time.Hour)
+	before := joinedAt.Add(-time.Minute)
+	after := joinedAt.Add(time.Minute)
+
+	assert.Equal(t, joinedAt, (&Participant{JoinedAt: joinedAt}).ReadUpTo(), "nothing read yet")
+	assert.Equal(t, after, (&Participant{JoinedAt: joinedAt, LastReadAt: &after}).ReadUpTo())
+	assert.Equal(t, joinedAt, (&Participant{JoinedAt: joinedAt, LastReadAt: &before}).ReadUpTo(),
+		"messages from before joining never count as unread")
+}
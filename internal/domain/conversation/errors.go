@@ -14,4 +14,28 @@ var (
 
 	// ErrInvalidConversationType is returned when conversation type is invalid
 	ErrInvalidConversationType = errors.New("invalid conversation type")
+
+	// ErrMessageRequestNotFound is returned when a conversation is not a pending message request for the user
+	ErrMessageRequestNotFound = errors.New("message request not found")
+
+	// ErrPinLimitReached is returned when pinning more than MaxPinnedConversations conversations
+	ErrPinLimitReached = errors.New("pinned conversations limit reached")
+
+	// ErrInvalidPinOrder is returned when a new pin order does not list exactly the pinned conversations
+	ErrInvalidPinOrder = errors.New("pin order must list every pinned conversation once")
+
+	// ErrInvalidMuteUntil is returned when a mute end is not in the future
+	ErrInvalidMuteUntil = errors.New("mute end must be in the future")
+
+	// ErrFolderNotFound is returned when a folder does not exist or belongs to another user
+	ErrFolderNotFound = errors.New("folder not found")
+
+	// ErrInvalidFolderName is returned when a folder name is empty or too long
+	ErrInvalidFolderName = errors.New("invalid folder name")
+
+	// ErrFolderNameTaken is returned when the user already has a folder with the name
+	ErrFolderNameTaken = errors.New("folder name already in use")
+
+	// ErrFolderLimitReached is returned when creating more than MaxFolders folders
+	ErrFolderLimitReached = errors.New("folder limit reached")
 )
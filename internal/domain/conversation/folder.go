@@ -0,0 +1,52 @@
+package conversation
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Folder limits
+const (
+	MaxFolders          = 20
+	MaxFolderNameLength = 32
+)
+
+// Folder is a user-defined label grouping conversations, such as "Work" or "Payments".
+// A conversation can be in several folders of the same user
+type Folder struct {
+	ID                uuid.UUID
+	UserID            uuid.UUID
+	Name              string
+	ConversationCount int // Filled in when folders are listed
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// NewFolder creates a new folder for a user
+func NewFolder(userID uuid.UUID, name string) *Folder {
+	now := time.Now()
+	return &Folder{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      strings.TrimSpace(name),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Rename changes the name of the folder
+func (f *Folder) Rename(name string) {
+	f.Name = strings.TrimSpace(name)
+	f.UpdatedAt = time.Now()
+}
+
+// Validate checks the folder name
+func (f *Folder) Validate() error {
+	if f.Name == "" || utf8.RuneCountInString(f.Name) > MaxFolderNameLength {
+		return ErrInvalidFolderName
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package conversation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFolderValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		folder  string
+		wantErr error
+	}{
+		{name: "plain name", folder: "Work"},
+		{name: "longest name", folder: strings.Repeat("ж", MaxFolderNameLength)},
+		{name: "empty", folder: "", wantErr: ErrInvalidFolderName},
+		{name: "only spaces", folder: "   ", wantErr: ErrInvalidFolderName},
+		{name: "too long", folder: strings.Repeat("a", MaxFolderNameLength+1), wantErr: ErrInvalidFolderName},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, NewFolder(uuid.New(), tt.folder).Validate())
+		})
+	}
+}
+
+func TestFolderRenameTrimsTheName(t *testing.T) {
+	folder := NewFolder(uuid.New(), "  Work ")
+	assert.Equal(t, "Work", folder.Name)
+
+	createdAt := folder.UpdatedAt
+	folder.Rename(" Payments  ")
+	assert.Equal(t, "Payments", folder.Name)
+	assert.False(t, folder.UpdatedAt.Before(createdAt))
+}
+
+func TestParticipantIsPinned(t *testing.T) {
+	order := 0
+	assert.False(t, (&Participant{}).IsPinned())
+	assert.True(t, (&Participant{PinOrder: &order}).IsPinned(), "the lowest order is still pinned")
+}
+
+func TestParticipantIsMuted(t *testing.T) {
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Hour)
+
+	assert.False(t, (&Participant{}).IsMuted())
+	assert.False(t, (&Participant{MutedUntil: &past}).IsMuted(), "the mute ran out")
+	assert.True(t, (&Participant{MutedUntil: &future}).IsMuted())
+	assert.True(t, (&Participant{MutedUntil: &MuteForever}).IsMuted())
+}
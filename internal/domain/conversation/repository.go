@@ -2,6 +2,7 @@ package conversation
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,13 +10,16 @@ import (
 // PresenceChecker defines the interface for checking user online status
 type PresenceChecker interface {
 	IsUserOnline(userID uuid.UUID) bool
+	// OnlineUsers returns which of the users are online, in one lookup
+	OnlineUsers(userIDs []uuid.UUID) map[uuid.UUID]bool
 }
 
 // Repository defines the interface for conversation data operations
 type Repository interface {
 	Create(ctx context.Context, conversation *Conversation) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Conversation, error)
-	FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Conversation, error)
+	// FindByUserID lists a user's conversations, pinned ones first, then by latest activity
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter ListFilter, limit, offset int) ([]*Conversation, error)
 	FindDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (*Conversation, error)
 	Update(ctx context.Context, conversation *Conversation) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -25,9 +29,47 @@ type Repository interface {
 	RemoveParticipant(ctx context.Context, conversationID, userID uuid.UUID) error
 	FindParticipants(ctx context.Context, conversationID uuid.UUID) ([]*Participant, error)
 	IsParticipant(ctx context.Context, conversationID, userID uuid.UUID) (bool, error)
-	UpdateParticipantLastRead(ctx context.Context, conversationID, userID uuid.UUID) error
+	// FindParticipant returns a user's participation in a conversation, or ErrNotParticipant
+	FindParticipant(ctx context.Context, conversationID, userID uuid.UUID) (*Participant, error)
+
+	// Read markers
+	// AdvanceReadMarker moves the user's read marker forward to a message created at readUpTo.
+	// It reports false when the marker already was at or past it
+	AdvanceReadMarker(ctx context.Context, conversationID, userID, messageID uuid.UUID, readUpTo time.Time) (bool, error)
+	// SetReadMarker places the user's read marker, also backwards. Nil clears it
+	SetReadMarker(ctx context.Context, conversationID, userID uuid.UUID, messageID *uuid.UUID, readUpTo *time.Time) error
 
 	// Archive operations
 	ArchiveConversation(ctx context.Context, conversationID, userID uuid.UUID) error
 	UnarchiveConversation(ctx context.Context, conversationID, userID uuid.UUID) error
+
+	// Pin and mute operations
+	// PinConversation pins a conversation on top of the user's pinned ones, failing with ErrPinLimitReached past max
+	PinConversation(ctx context.Context, conversationID, userID uuid.UUID, max int) error
+	UnpinConversation(ctx context.Context, conversationID, userID uuid.UUID) error
+	// ReorderPinned sets the order of the user's pinned conversations, first on top
+	ReorderPinned(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) error
+	// SetMutedUntil mutes a conversation for the user until the given time, nil unmutes it
+	SetMutedUntil(ctx context.Context, conversationID, userID uuid.UUID, until *time.Time) error
+
+	// Folder operations
+	CreateFolder(ctx context.Context, folder *Folder) error
+	FindFolderByID(ctx context.Context, id uuid.UUID) (*Folder, error)
+	// FindFoldersByUserID lists a user's folders with their conversation counts, oldest first
+	FindFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*Folder, error)
+	UpdateFolder(ctx context.Context, folder *Folder) error
+	// DeleteFolder deletes a folder, the conversations in it are kept
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
+	AddToFolder(ctx context.Context, folderID, conversationID uuid.UUID) error
+	RemoveFromFolder(ctx context.Context, folderID, conversationID uuid.UUID) error
+	// FindFolderIDs returns the user's folders each conversation is in
+	FindFolderIDs(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error)
+
+	// Message request operations
+	FindRequestsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*Conversation, error)
+	AcceptRequest(ctx context.Context, conversationID, userID uuid.UUID) error
+
+	// FindPeerIDs returns the users sharing a direct or group conversation with the user,
+	// leaving out conversations still waiting as a message request on either side
+	FindPeerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 }
@@ -34,6 +34,8 @@ type Settings struct {
 	WhoCanMessage   string // all, admins_only
 	WhoCanAddMembers string // all, admins_only
 	WhoCanEditInfo  string // all, admins_only
+	EditWindowSeconds int  // How long after sending a message can be edited, 0 = no limit
+	LinkPreview       bool // Generate previews for links in messages
 }
 
 // Member represents a group member
@@ -66,6 +68,7 @@ func NewGroup(name, description string, creatorID uuid.UUID, conversationID uuid
 			WhoCanMessage:    "all",
 			WhoCanAddMembers: "admins_only",
 			WhoCanEditInfo:   "admins_only",
+			LinkPreview:      true,
 		},
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
@@ -149,6 +152,11 @@ func (m *Member) IsModerator() bool {
 	return m.Role == RoleModerator
 }
 
+// CanDeleteMessages checks if member can delete other members' messages
+func (m *Member) CanDeleteMessages() bool {
+	return m.Role == RoleAdmin || m.Role == RoleModerator
+}
+
 // CanManageMembers checks if member can manage other members
 func (m *Member) CanManageMembers() bool {
 	return m.Role == RoleAdmin || m.Role == RoleModerator
@@ -1,26 +1,181 @@
 package message
 
 import (
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 // Message represents a message in the system
 type Message struct {
-	ID             uuid.UUID
-	ConversationID uuid.UUID
-	SenderID       uuid.UUID
-	Content        string // Plain text content
-	ContentType    ContentType
-	Signature      string // Solana signature
-	ReplyToID      *uuid.UUID
-	Status         Status
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Reactions      map[string][]string // emoji -> list of user IDs
-	IsPinned       bool                // Deprecated: use PinnedBy instead. True if current user pinned it.
-	PinnedBy       []string            // List of user IDs who pinned this message
+	ID              uuid.UUID
+	ConversationID  uuid.UUID
+	SenderID        uuid.UUID
+	Content         string // Plain text content
+	ContentType     ContentType
+	Signature       string // Solana signature
+	ReplyToID       *uuid.UUID
+	Status          Status
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Reactions       map[string][]string // emoji -> list of user IDs
+	IsPinned        bool                // Deprecated: use PinnedBy instead. True if current user pinned it.
+	PinnedBy        []string            // List of user IDs who pinned this message
+	ExpiresAt       *time.Time          // Set when disappearing messages are enabled for the conversation
+	ThreadID        *uuid.UUID          // Root message of the thread this reply belongs to
+	Thread          *ThreadSummary      // Reply info, only set on thread roots
+	Receipts        *ReceiptCounts      // Per-recipient delivery info, only set on the requesting user's own messages
+	EditedAt        *time.Time          // Time of the last edit, nil if never edited
+	EditCount       int                 // Number of times the content was edited
+	DeletedAt       *time.Time          // Set when deleted for everyone, the message is kept as a tombstone
+	DeletedBy       *uuid.UUID          // Sender or moderator who deleted the message
+	ClientMessageID string              // Client-generated ID, unique per sender, used to dedupe retried sends
+	LinkPreviews    []LinkPreview       // Previews of links in the content, filled in after the message is sent
+	Poll            *Poll               // Question, options and results, only set on poll messages
+}
+
+// LinkPreview holds the metadata shown for a link in a message.
+// Stored as JSON on the message, so fields carry json tags.
+type LinkPreview struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// MaxLinkPreviews is how many links in a single message get a preview
+const MaxLinkPreviews = 3
+
+// DeleteScope selects who a message is deleted for
+type DeleteScope string
+
+const (
+	// DeleteForMe hides the message only for the requesting user
+	DeleteForMe DeleteScope = "me"
+	// DeleteForEveryone replaces the message with a tombstone for all participants
+	DeleteForEveryone DeleteScope = "everyone"
+)
+
+// MessageRevision is a prior version of a message's content kept when it is edited
+type MessageRevision struct {
+	ID          uuid.UUID
+	MessageID   uuid.UUID
+	Revision    int // 1 is the original content
+	Content     string
+	ContentType ContentType
+	EditedBy    uuid.UUID // User whose edit replaced this revision
+	EditedAt    time.Time // When this revision was replaced
+}
+
+// Cursor identifies a position in a conversation's message history.
+// Messages are ordered by (CreatedAt, ID) so the position is stable even
+// when several messages share the same timestamp.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorFor returns the cursor pointing at the given message
+func CursorFor(m *Message) Cursor {
+	return Cursor{CreatedAt: m.CreatedAt, ID: m.ID}
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseCursor decodes a cursor produced by Encode
+func ParseCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return Cursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return Cursor{CreatedAt: createdAt, ID: id}, nil
+}
+
+// SearchQuery describes a full-text search over the messages a user can see
+type SearchQuery struct {
+	UserID           uuid.UUID
+	Text             string
+	ConversationID   *uuid.UUID
+	SenderID         *uuid.UUID
+	ConversationType string // direct, group or channel
+	ContentTypes     []ContentType
+	From             *time.Time
+	To               *time.Time
+	HasReactions     bool
+	Pinned           bool
+	Cursor           *SearchCursor
+	Limit            int
+	Offset           int
+}
+
+// SearchResult is a message matched by a search with its relevance and highlighted snippet
+type SearchResult struct {
+	Message *Message
+	Rank    float64
+	Snippet string
+}
+
+// SearchCursor identifies a position in ranked search results
+type SearchCursor struct {
+	Rank      float64
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// Encode returns the opaque string form of the search cursor
+func (c SearchCursor) Encode() string {
+	raw := strconv.FormatFloat(c.Rank, 'g', -1, 64) + "|" + Cursor{CreatedAt: c.CreatedAt, ID: c.ID}.Encode()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// ParseSearchCursor decodes a search cursor produced by Encode
+func ParseSearchCursor(s string) (SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return SearchCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return SearchCursor{}, ErrInvalidCursor
+	}
+
+	rank, err := strconv.ParseFloat(parts[0], 64)
+	if err != nil {
+		return SearchCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	position, err := ParseCursor(parts[1])
+	if err != nil {
+		return SearchCursor{}, err
+	}
+
+	return SearchCursor{Rank: rank, CreatedAt: position.CreatedAt, ID: position.ID}, nil
 }
 
 // ContentType represents the type of message content
@@ -32,6 +187,7 @@ const (
 	ContentTypeVideo ContentType = "video"
 	ContentTypeAudio ContentType = "audio"
 	ContentTypeFile  ContentType = "file"
+	ContentTypePoll  ContentType = "poll"
 )
 
 // Status represents message delivery status
@@ -64,11 +220,83 @@ func (m *Message) SetSignature(signature string) {
 	m.Signature = signature
 }
 
+// SetClientMessageID sets the client-generated ID used to dedupe retried sends
+func (m *Message) SetClientMessageID(clientMessageID string) {
+	m.ClientMessageID = clientMessageID
+}
+
 // SetReplyTo sets the message this is replying to
 func (m *Message) SetReplyTo(messageID uuid.UUID) {
 	m.ReplyToID = &messageID
 }
 
+// SetThread attaches the message to the thread rooted at rootID
+func (m *Message) SetThread(rootID uuid.UUID) {
+	m.ThreadID = &rootID
+}
+
+// ThreadRootID returns the root of the thread this message starts or belongs to
+func (m *Message) ThreadRootID() uuid.UUID {
+	if m.ThreadID != nil {
+		return *m.ThreadID
+	}
+	return m.ID
+}
+
+// SetExpiry sets when the message should disappear
+func (m *Message) SetExpiry(expiresAt time.Time) {
+	m.ExpiresAt = &expiresAt
+}
+
+// IsExpired checks if the message has passed its expiry time
+func (m *Message) IsExpired() bool {
+	return m.ExpiresAt != nil && time.Now().After(*m.ExpiresAt)
+}
+
+// Edit replaces the message content and returns the revision holding the previous content
+func (m *Message) Edit(editorID uuid.UUID, newContent string) *MessageRevision {
+	now := time.Now()
+	previous := &MessageRevision{
+		ID:          uuid.New(),
+		MessageID:   m.ID,
+		Revision:    m.EditCount + 1,
+		Content:     m.Content,
+		ContentType: m.ContentType,
+		EditedBy:    editorID,
+		EditedAt:    now,
+	}
+
+	m.Content = newContent
+	m.LinkPreviews = nil
+	m.EditCount++
+	m.EditedAt = &now
+	m.UpdatedAt = now
+
+	return previous
+}
+
+// CanEdit checks if the message is still within the edit window, 0 means no limit
+func (m *Message) CanEdit(window time.Duration) bool {
+	return window <= 0 || time.Since(m.CreatedAt) <= window
+}
+
+// Tombstone clears the message content and records who deleted it.
+// The row is kept so replies, threads and pins referencing it still resolve.
+func (m *Message) Tombstone(actorID uuid.UUID) {
+	now := time.Now()
+	m.Content = ""
+	m.Reactions = nil
+	m.LinkPreviews = nil
+	m.DeletedAt = &now
+	m.DeletedBy = &actorID
+	m.UpdatedAt = now
+}
+
+// IsDeleted checks if the message was deleted for everyone
+func (m *Message) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
 // MarkAsSent marks the message as sent
 func (m *Message) MarkAsSent() {
 	m.Status = StatusSent
@@ -120,3 +348,297 @@ type ForwardedMessage struct {
 	TargetConversationID uuid.UUID
 	ForwardedAt          time.Time
 }
+
+// MessageMention represents a user mentioned in a message
+type MessageMention struct {
+	ID             uuid.UUID
+	MessageID      uuid.UUID
+	ConversationID uuid.UUID
+	UserID         uuid.UUID
+	Position       int // Offset of the @ in characters (Unicode code points)
+	Length         int // Length of the mention in characters, including the @
+	ReadAt         *time.Time
+	CreatedAt      time.Time
+}
+
+// IsRead checks if the mentioned user has seen the mention
+func (mm *MessageMention) IsRead() bool {
+	return mm.ReadAt != nil
+}
+
+// MentionToken is an @username occurrence found in message content
+type MentionToken struct {
+	Username string
+	Position int
+	Length   int
+}
+
+// mentionPattern matches @username not preceded by a word character (so emails are skipped)
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_@])@([\p{L}\p{N}_.]{3,50})`)
+
+// ParseMentions extracts @username tokens from message content
+func ParseMentions(content string) []MentionToken {
+	matches := mentionPattern.FindAllStringSubmatchIndex(content, -1)
+	tokens := make([]MentionToken, 0, len(matches))
+	for _, match := range matches {
+		// Trailing dots are sentence punctuation, not part of the username
+		username := strings.TrimRight(content[match[2]:match[3]], ".")
+		if utf8.RuneCountInString(username) < 3 {
+			continue
+		}
+
+		atIndex := match[2] - 1
+		tokens = append(tokens, MentionToken{
+			Username: username,
+			Position: utf8.RuneCountInString(content[:atIndex]),
+			Length:   utf8.RuneCountInString(username) + 1,
+		})
+	}
+	return tokens
+}
+
+// urlPattern matches http(s) links, trailing punctuation is trimmed by ExtractURLs
+var urlPattern = regexp.MustCompile(`(?i)\bhttps?://[^\s<>"']+`)
+
+// ExtractURLs returns the distinct http(s) links in message content in order of appearance, at most max
+func ExtractURLs(content string, max int) []string {
+	matches := urlPattern.FindAllString(content, -1)
+	urls := make([]string, 0, len(matches))
+	seen := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		// Sentence punctuation and a closing bracket around the link are not part of it
+		url := strings.TrimRight(match, ".,;:!?)]}")
+		if seen[url] {
+			continue
+		}
+		seen[url] = true
+		urls = append(urls, url)
+		if len(urls) == max {
+			break
+		}
+	}
+	return urls
+}
+
+// ThreadSummary holds reply information for a thread root message
+type ThreadSummary struct {
+	RootMessageID uuid.UUID
+	ReplyCount    int
+	LastReplyID   uuid.UUID
+	LastReplyAt   time.Time
+	LastReplierID uuid.UUID
+}
+
+// Receipt records when a single recipient received and read a message
+type Receipt struct {
+	MessageID   uuid.UUID
+	UserID      uuid.UUID
+	DeliveredAt *time.Time
+	ReadAt      *time.Time
+	ReadHidden  bool // Read while the recipient had read receipts turned off, only counts as unread cleared
+}
+
+// ReceiptCounts holds how many recipients received and read a message.
+// Reads made with read receipts turned off are not counted
+type ReceiptCounts struct {
+	Delivered int
+	Read      int
+}
+
+// ScheduledStatus represents the state of a scheduled message
+type ScheduledStatus string
+
+const (
+	ScheduledPending  ScheduledStatus = "pending"
+	ScheduledSending  ScheduledStatus = "sending" // Claimed by a dispatcher
+	ScheduledSent     ScheduledStatus = "sent"
+	ScheduledCanceled ScheduledStatus = "canceled"
+	ScheduledFailed   ScheduledStatus = "failed"
+)
+
+// ScheduledMessage is a message composed now and delivered at SendAt.
+// When sent, the delivered message reuses the scheduled message ID so a retried dispatch cannot send it twice.
+type ScheduledMessage struct {
+	ID             uuid.UUID
+	ConversationID uuid.UUID
+	SenderID       uuid.UUID
+	Content        string
+	ContentType    ContentType
+	ReplyToID      *uuid.UUID
+	SendAt         time.Time
+	Status         ScheduledStatus
+	Attempts       int        // Number of times a dispatcher claimed it
+	ClaimedUntil   *time.Time // Lease of the dispatcher currently sending it
+	SentAt         *time.Time
+	LastError      string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// NewScheduledMessage creates a new pending scheduled message
+func NewScheduledMessage(conversationID, senderID uuid.UUID, content string, contentType ContentType, sendAt time.Time) *ScheduledMessage {
+	return &ScheduledMessage{
+		ID:             uuid.New(),
+		ConversationID: conversationID,
+		SenderID:       senderID,
+		Content:        content,
+		ContentType:    contentType,
+		SendAt:         sendAt,
+		Status:         ScheduledPending,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+}
+
+// IsPending checks if the scheduled message can still be edited or canceled
+func (s *ScheduledMessage) IsPending() bool {
+	return s.Status == ScheduledPending
+}
+
+// ToMessage builds the message delivered for this schedule
+func (s *ScheduledMessage) ToMessage() *Message {
+	msg := NewMessage(s.ConversationID, s.SenderID, s.Content, s.ContentType)
+	msg.ID = s.ID
+	if s.ReplyToID != nil {
+		msg.SetReplyTo(*s.ReplyToID)
+	}
+	return msg
+}
+
+// Poll limits
+const (
+	MinPollOptions        = 2
+	MaxPollOptions        = 10
+	MaxPollQuestionLength = 300
+	MaxPollOptionLength   = 100
+)
+
+// Poll is the question and answer options of a poll message.
+// Vote counts are kept up to date on every vote, so results never need recounting.
+type Poll struct {
+	MessageID      uuid.UUID
+	Question       string
+	Options        []PollOption
+	MultipleChoice bool       // Voters can pick more than one option
+	Anonymous      bool       // Only counts are shown, not who voted
+	Quiz           bool       // One option is the correct answer and votes cannot be changed
+	CorrectOption  *int       // Position of the correct option, quiz mode only
+	ClosesAt       *time.Time // Voting ends at this time, nil keeps the poll open until closed
+	ClosedAt       *time.Time // Set when the sender closes the poll early
+	TotalVoters    int
+	CreatedAt      time.Time
+	Voted          []int // Positions chosen by the requesting user, only set when loaded for a user
+}
+
+// PollOption is one answer option of a poll with its results
+type PollOption struct {
+	Position  int
+	Text      string
+	VoteCount int
+	Voters    []uuid.UUID // Only loaded for public polls
+}
+
+// NewPoll creates the poll of a message
+func NewPoll(messageID uuid.UUID, question string, options []string, multipleChoice, anonymous bool) *Poll {
+	pollOptions := make([]PollOption, len(options))
+	for i, text := range options {
+		pollOptions[i] = PollOption{Position: i, Text: strings.TrimSpace(text)}
+	}
+
+	return &Poll{
+		MessageID:      messageID,
+		Question:       strings.TrimSpace(question),
+		Options:        pollOptions,
+		MultipleChoice: multipleChoice,
+		Anonymous:      anonymous,
+		CreatedAt:      time.Now(),
+	}
+}
+
+// SetQuiz turns the poll into a quiz with the given correct option
+func (p *Poll) SetQuiz(correctOption int) {
+	p.Quiz = true
+	p.CorrectOption = &correctOption
+}
+
+// SetClosesAt sets when voting ends
+func (p *Poll) SetClosesAt(closesAt time.Time) {
+	p.ClosesAt = &closesAt
+}
+
+// Validate checks the question, options and quiz settings
+func (p *Poll) Validate() error {
+	if p.Question == "" || utf8.RuneCountInString(p.Question) > MaxPollQuestionLength {
+		return ErrInvalidPoll
+	}
+	if len(p.Options) < MinPollOptions || len(p.Options) > MaxPollOptions {
+		return ErrInvalidPoll
+	}
+
+	seen := make(map[string]bool, len(p.Options))
+	for _, option := range p.Options {
+		if option.Text == "" || utf8.RuneCountInString(option.Text) > MaxPollOptionLength || seen[option.Text] {
+			return ErrInvalidPoll
+		}
+		seen[option.Text] = true
+	}
+
+	if p.Quiz {
+		// A quiz has exactly one right answer
+		if p.MultipleChoice || p.CorrectOption == nil || *p.CorrectOption < 0 || *p.CorrectOption >= len(p.Options) {
+			return ErrInvalidPoll
+		}
+	} else if p.CorrectOption != nil {
+		return ErrInvalidPoll
+	}
+
+	if p.ClosesAt != nil && !p.ClosesAt.After(time.Now()) {
+		return ErrInvalidPoll
+	}
+
+	return nil
+}
+
+// IsClosed checks if voting has ended
+func (p *Poll) IsClosed() bool {
+	return p.ClosedAt != nil || (p.ClosesAt != nil && time.Now().After(*p.ClosesAt))
+}
+
+// Close ends voting early
+func (p *Poll) Close() {
+	now := time.Now()
+	p.ClosedAt = &now
+}
+
+// HasVoted checks if the requesting user voted, the poll must be loaded for a user
+func (p *Poll) HasVoted() bool {
+	return len(p.Voted) > 0
+}
+
+// RevealsAnswer checks if the correct option of a quiz can be shown to the requesting user.
+// It is hidden until they voted or the quiz closed.
+func (p *Poll) RevealsAnswer() bool {
+	return p.Quiz && (p.HasVoted() || p.IsClosed())
+}
+
+// NormalizeVote validates a vote and returns its options sorted without duplicates
+func (p *Poll) NormalizeVote(positions []int) ([]int, error) {
+	chosen := make(map[int]bool, len(positions))
+	for _, position := range positions {
+		if position < 0 || position >= len(p.Options) {
+			return nil, ErrInvalidPollVote
+		}
+		chosen[position] = true
+	}
+	if len(chosen) == 0 || (!p.MultipleChoice && len(chosen) > 1) {
+		return nil, ErrInvalidPollVote
+	}
+
+	normalized := make([]int, 0, len(chosen))
+	for i := range p.Options {
+		if chosen[i] {
+			normalized = append(normalized, i)
+		}
+	}
+	return normalized, nil
+}
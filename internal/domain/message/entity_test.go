@@ -0,0 +1,286 @@
+package message
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	loc := time.FixedZone("UTC+2", 2*60*60)
+	cursor := Cursor{
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 45, 123456789, loc),
+		ID:        uuid.New(),
+	}
+
+	parsed, err := ParseCursor(cursor.Encode())
+	require.NoError(t, err)
+
+	assert.True(t, parsed.CreatedAt.Equal(cursor.CreatedAt), "keeps the nanoseconds")
+	assert.Equal(t, cursor.ID, parsed.ID)
+}
+
+func TestCursorForMessage(t *testing.T) {
+	msg := &Message{ID: uuid.New(), CreatedAt: time.Now()}
+
+	cursor := CursorFor(msg)
+
+	assert.Equal(t, msg.ID, cursor.ID)
+	assert.True(t, cursor.CreatedAt.Equal(msg.CreatedAt))
+}
+
+func TestParseCursorRejectsInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := map[string]string{
+		"not base64":     "%%%",
+		"no separator":   encode("2024-03-01T12:30:45Z"),
+		"bad timestamp":  encode("yesterday|" + uuid.NewString()),
+		"bad message id": encode("2024-03-01T12:30:45Z|not-a-uuid"),
+		"empty":          "",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCursor(value)
+			assert.True(t, errors.Is(err, ErrInvalidCursor), "got %v", err)
+		})
+	}
+}
+
+func TestSearchCursorRoundTrip(t *testing.T) {
+	cursor := SearchCursor{
+		Rank:      0.0607927,
+		CreatedAt: time.Date(2024, 3, 1, 12, 30, 45, 5, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	parsed, err := ParseSearchCursor(cursor.Encode())
+	require.NoError(t, err)
+
+	assert.Equal(t, cursor.Rank, parsed.Rank, "the rank survives exactly, so keyset paging does not skip ties")
+	assert.True(t, parsed.CreatedAt.Equal(cursor.CreatedAt))
+	assert.Equal(t, cursor.ID, parsed.ID)
+}
+
+func TestParseSearchCursorRejectsInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+	position := Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()
+
+	tests := map[string]string{
+		"not base64":       "%%%",
+		"no separator":     encode("0.5"),
+		"bad rank":         encode("high|" + position),
+		"bad position":     encode("0.5|" + encode("nope")),
+		"message cursor":   position,
+		"missing position": encode("0.5|"),
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSearchCursor(value)
+			assert.True(t, errors.Is(err, ErrInvalidCursor), "got %v", err)
+		})
+	}
+}
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []MentionToken
+	}{
+		{name: "none", content: "hello there", want: []MentionToken{}},
+		{name: "start of message", content: "@alice hi", want: []MentionToken{{Username: "alice", Position: 0, Length: 6}}},
+		{
+			name:    "several",
+			content: "hi @alice and @bob_99, see you",
+			want: []MentionToken{
+				{Username: "alice", Position: 3, Length: 6},
+				{Username: "bob_99", Position: 14, Length: 7},
+			},
+		},
+		{name: "trailing dot is punctuation", content: "thanks @carol.", want: []MentionToken{{Username: "carol", Position: 7, Length: 6}}},
+		{name: "dots inside the username", content: "ask @dan.k", want: []MentionToken{{Username: "dan.k", Position: 4, Length: 6}}},
+		{name: "email address", content: "mail me at eve@example.com", want: []MentionToken{}},
+		{name: "too short", content: "hey @jo", want: []MentionToken{}},
+		{name: "positions count characters", content: "héllo 👋 @zoë", want: []MentionToken{{Username: "zoë", Position: 8, Length: 4}}},
+		{name: "double at", content: "@@mallory", want: []MentionToken{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ParseMentions(tt.content))
+		})
+	}
+}
+
+func TestThreadRootID(t *testing.T) {
+	root := &Message{ID: uuid.New()}
+	assert.Equal(t, root.ID, root.ThreadRootID(), "a message roots its own thread")
+
+	reply := &Message{ID: uuid.New()}
+	reply.SetThread(root.ID)
+	assert.Equal(t, root.ID, reply.ThreadRootID())
+}
+
+func TestEditKeepsThePreviousContent(t *testing.T) {
+	msg := &Message{ID: uuid.New(), Content: "first", ContentType: ContentTypeText, LinkPreviews: []LinkPreview{{URL: "https://example.com"}}}
+	editor := uuid.New()
+
+	previous := msg.Edit(editor, "second")
+	assert.Equal(t, 1, previous.Revision)
+	assert.Equal(t, "first", previous.Content)
+	assert.Equal(t, editor, previous.EditedBy)
+	assert.Equal(t, "second", msg.Content)
+	assert.Equal(t, 1, msg.EditCount)
+	assert.NotNil(t, msg.EditedAt)
+	assert.Nil(t, msg.LinkPreviews, "previews of the old content are dropped")
+
+	assert.Equal(t, 2, msg.Edit(editor, "third").Revision)
+}
+
+func TestCanEdit(t *testing.T) {
+	msg := &Message{CreatedAt: time.Now().Add(-time.Minute)}
+	assert.True(t, msg.CanEdit(0), "no limit")
+	assert.True(t, msg.CanEdit(2*time.Minute))
+	assert.False(t, msg.CanEdit(30*time.Second))
+}
+
+func TestTombstone(t *testing.T) {
+	msg := &Message{ID: uuid.New(), Content: "hello", LinkPreviews: []LinkPreview{{URL: "https://example.com"}}}
+	moderator := uuid.New()
+
+	msg.Tombstone(moderator)
+	assert.True(t, msg.IsDeleted())
+	assert.Empty(t, msg.Content)
+	assert.Nil(t, msg.Reactions)
+	assert.Nil(t, msg.LinkPreviews)
+	assert.Equal(t, &moderator, msg.DeletedBy)
+}
+
+func TestScheduledMessageKeepsItsIDWhenSent(t *testing.T) {
+	replyTo := uuid.New()
+	scheduled := NewScheduledMessage(uuid.New(), uuid.New(), "later", ContentTypeText, time.Now().Add(time.Hour))
+	scheduled.ReplyToID = &replyTo
+	assert.True(t, scheduled.IsPending())
+
+	msg := scheduled.ToMessage()
+	assert.Equal(t, scheduled.ID, msg.ID, "a retried dispatch cannot send it twice")
+	assert.Equal(t, scheduled.ConversationID, msg.ConversationID)
+	assert.Equal(t, "later", msg.Content)
+	assert.Equal(t, &replyTo, msg.ReplyToID)
+}
+
+func TestPollValidate(t *testing.T) {
+	tooMany := make([]string, MaxPollOptions+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("option %d", i)
+	}
+
+	tests := []struct {
+		name    string
+		poll    func() *Poll
+		wantErr error
+	}{
+		{name: "valid", poll: func() *Poll { return NewPoll(uuid.New(), "Lunch?", []string{"pizza", "sushi"}, false, false) }},
+		{name: "blank question", poll: func() *Poll { return NewPoll(uuid.New(), "  ", []string{"a", "b"}, false, false) }, wantErr: ErrInvalidPoll},
+		{name: "one option", poll: func() *Poll { return NewPoll(uuid.New(), "Lunch?", []string{"a"}, false, false) }, wantErr: ErrInvalidPoll},
+		{name: "too many options", poll: func() *Poll { return NewPoll(uuid.New(), "Lunch?", tooMany, false, false) }, wantErr: ErrInvalidPoll},
+		{name: "blank option", poll: func() *Poll { return NewPoll(uuid.New(), "Lunch?", []string{"a", " "}, false, false) }, wantErr: ErrInvalidPoll},
+		{name: "repeated option", poll: func() *Poll { return NewPoll(uuid.New(), "Lunch?", []string{"a", " a"}, false, false) }, wantErr: ErrInvalidPoll},
+		{
+			name: "quiz",
+			poll: func() *Poll {
+				p := NewPoll(uuid.New(), "2+2?", []string{"3", "4"}, false, false)
+				p.SetQuiz(1)
+				return p
+			},
+		},
+		{
+			name: "quiz answer out of range", wantErr: ErrInvalidPoll,
+			poll: func() *Poll {
+				p := NewPoll(uuid.New(), "2+2?", []string{"3", "4"}, false, false)
+				p.SetQuiz(2)
+				return p
+			},
+		},
+		{
+			name: "multiple choice quiz", wantErr: ErrInvalidPoll,
+			poll: func() *Poll {
+				p := NewPoll(uuid.New(), "2+2?", []string{"3", "4"}, true, false)
+				p.SetQuiz(1)
+				return p
+			},
+		},
+		{
+			name: "closes in the past", wantErr: ErrInvalidPoll,
+			poll: func() *Poll {
+				p := NewPoll(uuid.New(), "Lunch?", []string{"a", "b"}, false, false)
+				p.SetClosesAt(time.Now().Add(-time.Minute))
+				return p
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantErr, tt.poll().Validate())
+		})
+	}
+}
+
+func TestPollNormalizeVote(t *testing.T) {
+	single := NewPoll(uuid.New(), "Lunch?", []string{"a", "b", "c"}, false, false)
+	multiple := NewPoll(uuid.New(), "Lunch?", []string{"a", "b", "c"}, true, false)
+
+	tests := []struct {
+		name      string
+		poll      *Poll
+		positions []int
+		want      []int
+		wantErr   error
+	}{
+		{name: "one option", poll: single, positions: []int{1}, want: []int{1}},
+		{name: "same option twice", poll: single, positions: []int{1, 1}, want: []int{1}},
+		{name: "two options", poll: single, positions: []int{0, 1}, wantErr: ErrInvalidPollVote},
+		{name: "none", poll: single, positions: nil, wantErr: ErrInvalidPollVote},
+		{name: "negative", poll: single, positions: []int{-1}, wantErr: ErrInvalidPollVote},
+		{name: "out of range", poll: multiple, positions: []int{0, 3}, wantErr: ErrInvalidPollVote},
+		{name: "several sorted", poll: multiple, positions: []int{2, 0}, want: []int{0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.poll.NormalizeVote(tt.positions)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestQuizRevealsTheAnswer(t *testing.T) {
+	poll := NewPoll(uuid.New(), "2+2?", []string{"3", "4"}, false, false)
+	assert.False(t, poll.RevealsAnswer(), "not a quiz")
+
+	poll.SetQuiz(1)
+	assert.False(t, poll.RevealsAnswer())
+
+	poll.Voted = []int{0}
+	assert.True(t, poll.RevealsAnswer(), "after answering")
+
+	poll.Voted = nil
+	poll.Close()
+	assert.True(t, poll.IsClosed())
+	assert.True(t, poll.RevealsAnswer(), "once closed")
+}
@@ -14,4 +14,61 @@ var (
 
 	// ErrUnauthorized is returned when user is not authorized
 	ErrUnauthorized = errors.New("unauthorized to perform this action")
+
+	// ErrSendNotAllowed is returned when user is not allowed to post in a conversation
+	ErrSendNotAllowed = errors.New("not allowed to send messages in this conversation")
+
+	// ErrEditWindowExpired is returned when a message is edited after its edit window has passed
+	ErrEditWindowExpired = errors.New("message can no longer be edited")
+
+	// ErrEditConflict is returned when a message was edited concurrently
+	ErrEditConflict = errors.New("message was modified by another edit")
+
+	// ErrMessageDeleted is returned when acting on a message that was deleted for everyone
+	ErrMessageDeleted = errors.New("message has been deleted")
+
+	// ErrInvalidDeleteScope is returned when a delete scope is not recognised
+	ErrInvalidDeleteScope = errors.New("invalid delete scope")
+
+	// ErrDuplicateClientMessage is returned when the sender already sent a message with the same client message ID
+	ErrDuplicateClientMessage = errors.New("message with this client message ID was already sent")
+
+	// ErrClientMessageIDReused is returned when a client message ID is reused for a different conversation
+	ErrClientMessageIDReused = errors.New("client message ID was already used in another conversation")
+
+	// ErrScheduledMessageNotFound is returned when a scheduled message is not found
+	ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+
+	// ErrScheduledNotPending is returned when a scheduled message was already sent, canceled or is being sent
+	ErrScheduledNotPending = errors.New("scheduled message is no longer pending")
+
+	// ErrInvalidSendTime is returned when a message is scheduled in the past
+	ErrInvalidSendTime = errors.New("send time must be in the future")
+
+	// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+	// ErrInvalidPoll is returned when a poll has a bad question, options or quiz answer
+	ErrInvalidPoll = errors.New("invalid poll")
+
+	// ErrNotAPoll is returned when voting on a message that is not a poll
+	ErrNotAPoll = errors.New("message is not a poll")
+
+	// ErrPollClosed is returned when voting on a poll that has ended
+	ErrPollClosed = errors.New("poll is closed")
+
+	// ErrInvalidPollVote is returned when a vote names unknown options or too many for a single-choice poll
+	ErrInvalidPollVote = errors.New("invalid poll vote")
+
+	// ErrPollVoteFinal is returned when changing or retracting a quiz answer
+	ErrPollVoteFinal = errors.New("quiz answers cannot be changed")
+
+	// ErrPollVoteNotFound is returned when retracting a vote that was never cast
+	ErrPollVoteNotFound = errors.New("poll vote not found")
+
+	// ErrPollVoteNotAllowed is returned when the user may not vote in the conversation's polls
+	ErrPollVoteNotAllowed = errors.New("not allowed to vote in this poll")
+
+	// ErrPollUnsupported is returned for actions polls do not support, such as editing, forwarding or scheduling
+	ErrPollUnsupported = errors.New("action is not supported for polls")
 )
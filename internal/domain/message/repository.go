@@ -2,6 +2,7 @@ package message
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -9,15 +10,38 @@ import (
 // Repository defines the interface for message data operations
 type Repository interface {
 	// Basic message operations
+	// Create stores a new message and the poll of a poll message, failing with ErrDuplicateClientMessage if the sender already used its client message ID
 	Create(ctx context.Context, message *Message) error
 	FindByID(ctx context.Context, id uuid.UUID) (*Message, error)
+	// FindByIDs finds messages across conversations, leaving out missing ones and the ones userID hid
+	FindByIDs(ctx context.Context, ids []uuid.UUID, userID *uuid.UUID) ([]*Message, error)
+	FindByClientMessageID(ctx context.Context, senderID uuid.UUID, clientMessageID string) (*Message, error)
 	// FindByConversationID finds messages. userID is optional - if provided, isPinned only true for messages pinned by that user
+	// and messages the user hid are left out. The same applies to the other history queries
 	FindByConversationID(ctx context.Context, conversationID uuid.UUID, limit, offset int, userID *uuid.UUID) ([]*Message, error)
+	// FindBefore returns up to limit messages older than the cursor, newest first
+	FindBefore(ctx context.Context, conversationID uuid.UUID, cursor Cursor, limit int, userID *uuid.UUID) ([]*Message, error)
+	// FindAfter returns up to limit messages newer than the cursor, newest first
+	FindAfter(ctx context.Context, conversationID uuid.UUID, cursor Cursor, limit int, userID *uuid.UUID) ([]*Message, error)
+	// FindAround returns the message at the cursor with up to before older and after newer messages, newest first
+	FindAround(ctx context.Context, conversationID uuid.UUID, cursor Cursor, before, after int, userID *uuid.UUID) ([]*Message, error)
 	Update(ctx context.Context, message *Message) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// MarkDeleted stores the tombstone of a message deleted for everyone and drops its reactions, mentions and revisions
+	MarkDeleted(ctx context.Context, message *Message) error
+	// HideForUser hides a message from one user's history (delete for me)
+	HideForUser(ctx context.Context, messageID, userID uuid.UUID) error
+	// SaveEdit stores the edited content and the revision it replaced, failing with ErrEditConflict if the message was edited meanwhile
+	SaveEdit(ctx context.Context, message *Message, previous *MessageRevision) error
+	// SetLinkPreviews stores the link previews generated for a message, failing with ErrEditConflict if the message was edited or deleted meanwhile
+	SetLinkPreviews(ctx context.Context, messageID uuid.UUID, editCount int, previews []LinkPreview) error
+	// GetRevisions returns prior revisions of a message, oldest first
+	GetRevisions(ctx context.Context, messageID uuid.UUID) ([]MessageRevision, error)
 	UpdateStatus(ctx context.Context, messageID uuid.UUID, status Status) error
-	CountByConversationID(ctx context.Context, conversationID uuid.UUID) (int64, error)
-	CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID) (int64, error)
+	// CountByConversationID counts messages in a conversation, leaving out the ones userID hid when set
+	CountByConversationID(ctx context.Context, conversationID uuid.UUID, userID *uuid.UUID) (int64, error)
+	// CountUnreadByConversationID counts messages from others created after readUpTo, leaving out deleted and hidden ones
+	CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID, readUpTo time.Time) (int64, error)
 
 	// Message Reactions (Day 13)
 	AddReaction(ctx context.Context, messageID, userID uuid.UUID, emoji string) error
@@ -35,5 +59,78 @@ type Repository interface {
 	GetForwardHistory(ctx context.Context, messageID uuid.UUID) ([]ForwardedMessage, error)
 
 	// Search (Day 13)
-	SearchMessages(ctx context.Context, userID uuid.UUID, query string, conversationID *uuid.UUID, limit, offset int) ([]*Message, error)
+	// SearchMessages runs a ranked full-text search and returns the page of results with the total match count
+	SearchMessages(ctx context.Context, query SearchQuery) ([]*SearchResult, int64, error)
+
+	// Threads
+	// FindThreadReplies returns replies in a thread oldest first, starting after the cursor if given
+	FindThreadReplies(ctx context.Context, rootID uuid.UUID, after *Cursor, limit int, userID *uuid.UUID) ([]*Message, error)
+	// GetThreadSummaries returns reply info keyed by root message ID; roots without replies are omitted
+	GetThreadSummaries(ctx context.Context, rootIDs []uuid.UUID) (map[uuid.UUID]ThreadSummary, error)
+	FollowThread(ctx context.Context, rootID, userID uuid.UUID) error
+	UnfollowThread(ctx context.Context, rootID, userID uuid.UUID) error
+	IsFollowingThread(ctx context.Context, rootID, userID uuid.UUID) (bool, error)
+	GetThreadFollowers(ctx context.Context, rootID uuid.UUID) ([]uuid.UUID, error)
+
+	// Mentions
+	// ReplaceMentions replaces all mentions of a message (used on send and edit)
+	ReplaceMentions(ctx context.Context, messageID uuid.UUID, mentions []MessageMention) error
+	GetMentionsByMessageID(ctx context.Context, messageID uuid.UUID) ([]MessageMention, error)
+	// GetMentionsForUser returns mentions of a user, newest first. conversationID is optional
+	GetMentionsForUser(ctx context.Context, userID uuid.UUID, conversationID *uuid.UUID, unreadOnly bool, limit, offset int) ([]MessageMention, error)
+	// CountUnreadMentions returns the number of unread mentions per conversation
+	CountUnreadMentions(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error)
+	// MarkMentionsRead marks the user's mentions in messages created up to readUpTo as read
+	MarkMentionsRead(ctx context.Context, userID, conversationID uuid.UUID, readUpTo time.Time) error
+
+	// Receipts
+	// MarkReceiptDelivered records that a recipient received a message, keeping the first delivery time
+	MarkReceiptDelivered(ctx context.Context, messageID, userID uuid.UUID, at time.Time) error
+	// MarkReceiptRead records that a recipient read a message, which also marks it delivered.
+	// hidden keeps the read from being shown to others when the recipient turned read receipts off
+	MarkReceiptRead(ctx context.Context, messageID, userID uuid.UUID, at time.Time, hidden bool) error
+	// MarkConversationRead records a read receipt for every message in the conversation not sent by the user,
+	// up to the ones created at readUpTo
+	MarkConversationRead(ctx context.Context, conversationID, userID uuid.UUID, readUpTo, at time.Time, hidden bool) error
+	// GetReceipts returns the receipts of a message, most recently read first
+	GetReceipts(ctx context.Context, messageID uuid.UUID) ([]Receipt, error)
+	// GetReceiptCounts returns delivered and read counts keyed by message ID; messages without receipts are omitted
+	GetReceiptCounts(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID]ReceiptCounts, error)
+	// SyncReceiptStatus advances the status of messages in a conversation once at least recipients users
+	// have delivered or visibly read them. messageID optionally limits the update to one message
+	SyncReceiptStatus(ctx context.Context, conversationID uuid.UUID, messageID *uuid.UUID, recipients int) error
+
+	// Scheduled Messages
+	CreateScheduled(ctx context.Context, scheduled *ScheduledMessage) error
+	FindScheduledByID(ctx context.Context, id uuid.UUID) (*ScheduledMessage, error)
+	// FindScheduledBySender returns a sender's scheduled messages by send time. conversationID and statuses are optional filters
+	FindScheduledBySender(ctx context.Context, senderID uuid.UUID, conversationID *uuid.UUID, statuses []ScheduledStatus, limit, offset int) ([]*ScheduledMessage, error)
+	// UpdateScheduled saves content and send time, failing with ErrScheduledNotPending unless the message is still pending
+	UpdateScheduled(ctx context.Context, scheduled *ScheduledMessage) error
+	// CancelScheduled cancels a pending scheduled message, failing with ErrScheduledNotPending otherwise
+	CancelScheduled(ctx context.Context, id uuid.UUID) error
+	// ClaimDueScheduled atomically leases up to limit messages due at now, including ones whose previous lease expired.
+	// Rows claimed by another dispatcher are skipped, so concurrent replicas never claim the same message
+	ClaimDueScheduled(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*ScheduledMessage, error)
+	MarkScheduledSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error
+	MarkScheduledFailed(ctx context.Context, id uuid.UUID, reason string) error
+	// ReleaseScheduled returns a claimed message to pending so it is retried
+	ReleaseScheduled(ctx context.Context, id uuid.UUID, reason string) error
+
+	// Polls
+	// GetPolls returns the polls of the given messages by message ID. When userID is set, each poll's Voted holds that user's choices
+	GetPolls(ctx context.Context, messageIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID]*Poll, error)
+	// SetPollVotes replaces a user's votes and updates the option counts, failing with ErrPollClosed if voting ended
+	// or ErrPollVoteFinal if the user already answered a quiz
+	SetPollVotes(ctx context.Context, messageID, userID uuid.UUID, positions []int) error
+	// RetractPollVotes removes a user's votes, failing with ErrPollVoteNotFound if they had none
+	// or ErrPollVoteFinal for quiz answers
+	RetractPollVotes(ctx context.Context, messageID, userID uuid.UUID) error
+	// ClosePoll ends voting, failing with ErrPollClosed if it already ended
+	ClosePoll(ctx context.Context, messageID uuid.UUID, closedAt time.Time) error
+
+	// Disappearing Messages
+	// DeleteExpired permanently deletes up to limit messages expired before the given time, along with
+	// their reactions, pins and mentions, and returns them. Rows another caller is deleting are skipped
+	DeleteExpired(ctx context.Context, before time.Time, limit int) ([]*Message, error)
 }
@@ -23,10 +23,24 @@ type PrivacySettings struct {
 	StatusVisibility       Visibility `json:"status_visibility"`
 	ReadReceiptsEnabled    bool       `json:"read_receipts_enabled"`
 	TypingIndicatorEnabled bool       `json:"typing_indicator_enabled"`
+	WhoCanMessage          Visibility `json:"who_can_message"` // Who can start a direct chat, others land in message requests
 	CreatedAt              time.Time  `json:"created_at"`
 	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
+// DefaultPrivacySettings returns the settings of a user who never changed them
+func DefaultPrivacySettings(userID uuid.UUID) *PrivacySettings {
+	return &PrivacySettings{
+		UserID:                 userID,
+		ProfilePhotoVisibility: VisibilityEveryone,
+		LastSeenVisibility:     VisibilityEveryone,
+		StatusVisibility:       VisibilityEveryone,
+		ReadReceiptsEnabled:    true,
+		TypingIndicatorEnabled: true,
+		WhoCanMessage:          VisibilityEveryone,
+	}
+}
+
 // BlockedUser represents a user blocking relationship
 type BlockedUser struct {
 	ID            uuid.UUID `json:"id"`
@@ -11,6 +11,10 @@ var (
 	ErrUserAlreadyBlocked = errors.New("user already blocked")
 	ErrUserNotBlocked     = errors.New("user not blocked")
 	ErrCannotBlockSelf    = errors.New("cannot block yourself")
+	// ErrUserUnavailable is returned to both sides of a block, so neither can tell a block exists
+	ErrUserUnavailable = errors.New("user is unavailable")
+	// ErrMessagingRestricted is returned when the recipient does not accept new direct chats
+	ErrMessagingRestricted = errors.New("user does not accept new chats")
 
 	// Disappearing Messages Errors
 	ErrInvalidDuration           = errors.New("invalid disappearing message duration")
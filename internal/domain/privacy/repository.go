@@ -18,7 +18,9 @@ type Repository interface {
 	UnblockUser(ctx context.Context, userID, blockedUserID uuid.UUID) error
 	IsUserBlocked(ctx context.Context, userID, targetUserID uuid.UUID) (bool, error)
 	GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]*BlockedUser, error)
-	GetBlockedByUsers(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) // Who blocked this user
+	GetBlockedByUsers(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)      // Who blocked this user
+	IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) // Either user blocked the other
+	GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)      // Users this user blocked or was blocked by
 
 	// Disappearing Messages
 	SetDisappearingMessages(ctx context.Context, config *DisappearingMessagesConfig) error
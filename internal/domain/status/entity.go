@@ -67,7 +67,7 @@ type Repository interface {
 	CreateStatus(ctx context.Context, status *Status) error
 	GetStatus(ctx context.Context, statusID uuid.UUID) (*Status, error)
 	GetUserStatuses(ctx context.Context, userID uuid.UUID) ([]*Status, error)
-	GetStatusFeed(ctx context.Context, viewerID uuid.UUID, limit, offset int) ([]*Status, error)
+	GetStatusFeed(ctx context.Context, viewerID uuid.UUID, limit, offset int) ([]*Status, error) // Leaves out users blocked either way
 	DeleteStatus(ctx context.Context, statusID uuid.UUID) error
 	DeleteExpiredStatuses(ctx context.Context) error
 
@@ -0,0 +1,136 @@
+package linkpreview
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+const (
+	maxTitleLength       = 200
+	maxDescriptionLength = 500
+	maxSiteNameLength    = 100
+	maxImageURLLength    = 2048
+)
+
+// parseMetadata reads the preview fields from the head of an HTML page.
+// OpenGraph tags win over Twitter card tags, which win over <title> and the meta description.
+func parseMetadata(body io.Reader, maxBytes int64, contentType string, pageURL *url.URL) (*message.LinkPreview, error) {
+	reader, err := charset.NewReader(io.LimitReader(body, maxBytes), contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNoPreview, err)
+	}
+
+	meta := make(map[string]string)
+	var title string
+
+	z := html.NewTokenizer(reader)
+	for done := false; !done; {
+		switch z.Next() {
+		case html.ErrorToken:
+			// EOF, or the size cap was reached; use what was read so far
+			done = true
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch string(name) {
+			case "meta":
+				if hasAttr {
+					readMetaTag(z, meta)
+				}
+			case "title":
+				if title == "" && z.Next() == html.TextToken {
+					title = string(z.Text())
+				}
+			case "body":
+				// Metadata lives in <head>
+				done = true
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "head" {
+				done = true
+			}
+		}
+	}
+
+	preview := &message.LinkPreview{
+		Title:       clean(firstOf(meta["og:title"], meta["twitter:title"], title), maxTitleLength),
+		Description: clean(firstOf(meta["og:description"], meta["twitter:description"], meta["description"]), maxDescriptionLength),
+		SiteName:    clean(meta["og:site_name"], maxSiteNameLength),
+		ImageURL:    resolveImageURL(firstOf(meta["og:image"], meta["og:image:url"], meta["twitter:image"], meta["twitter:image:src"]), pageURL),
+	}
+	if preview.Title == "" && preview.Description == "" {
+		return nil, ErrNoPreview
+	}
+
+	return preview, nil
+}
+
+// readMetaTag stores the content of a <meta property=...> or <meta name=...> tag, keeping the first occurrence
+func readMetaTag(z *html.Tokenizer, meta map[string]string) {
+	var key, content string
+	for {
+		attr, val, more := z.TagAttr()
+		switch string(attr) {
+		case "property", "name":
+			if key == "" {
+				key = strings.ToLower(strings.TrimSpace(string(val)))
+			}
+		case "content":
+			content = string(val)
+		}
+		if !more {
+			break
+		}
+	}
+
+	if key != "" && content != "" {
+		if _, ok := meta[key]; !ok {
+			meta[key] = content
+		}
+	}
+}
+
+// resolveImageURL makes a relative image link absolute, dropping anything that is not http(s)
+func resolveImageURL(raw string, pageURL *url.URL) string {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > maxImageURLLength {
+		return ""
+	}
+
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+	resolved := pageURL.ResolveReference(ref)
+	if validateURL(resolved) != nil {
+		return ""
+	}
+
+	return resolved.String()
+}
+
+// clean collapses whitespace, drops invalid UTF-8 and truncates to max characters
+func clean(s string, max int) string {
+	s = strings.Join(strings.Fields(strings.ToValidUTF8(s, "")), " ")
+	if utf8.RuneCountInString(s) <= max {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:max-1])) + "…"
+}
+
+// firstOf returns the first non-blank value
+func firstOf(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,100 @@
+package linkpreview
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetadataPrecedence(t *testing.T) {
+	pageURL, _ := url.Parse("https://example.com/articles/1")
+
+	tests := []struct {
+		name        string
+		head        string
+		title       string
+		description string
+		siteName    string
+		image       string
+	}{
+		{
+			name: "opengraph wins",
+			head: `<meta property="og:title" content="OG title">
+				<meta name="twitter:title" content="Twitter title">
+				<title>Page title</title>
+				<meta property="og:description" content="OG description">
+				<meta name="description" content="Meta description">
+				<meta property="og:site_name" content="Example">
+				<meta property="og:image" content="/img/cover.png">`,
+			title:       "OG title",
+			description: "OG description",
+			siteName:    "Example",
+			image:       "https://example.com/img/cover.png",
+		},
+		{
+			name: "twitter card without opengraph",
+			head: `<title>Page title</title>
+				<meta name="twitter:title" content="Twitter title">
+				<meta name="twitter:description" content="Twitter description">
+				<meta name="twitter:image:src" content="https://cdn.example.com/card.jpg">`,
+			title:       "Twitter title",
+			description: "Twitter description",
+			image:       "https://cdn.example.com/card.jpg",
+		},
+		{
+			name: "plain page",
+			head: `<title>  Page
+				title </title><meta name="description" content="Meta description">`,
+			title:       "Page title",
+			description: "Meta description",
+		},
+		{
+			name:  "first occurrence and attribute case",
+			head:  `<meta property="OG:TITLE" content="First"><meta property="og:title" content="Second">`,
+			title: "First",
+		},
+		{
+			name:  "non-http image dropped",
+			head:  `<meta property="og:title" content="Title"><meta property="og:image" content="javascript:alert(1)">`,
+			title: "Title",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := "<html><head>" + tt.head + "</head><body></body></html>"
+
+			preview, err := parseMetadata(strings.NewReader(page), 1<<20, "text/html; charset=utf-8", pageURL)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.title, preview.Title)
+			assert.Equal(t, tt.description, preview.Description)
+			assert.Equal(t, tt.siteName, preview.SiteName)
+			assert.Equal(t, tt.image, preview.ImageURL)
+		})
+	}
+}
+
+func TestParseMetadataStopsAtBody(t *testing.T) {
+	pageURL, _ := url.Parse("https://example.com/")
+	page := `<html><head></head><body><meta property="og:title" content="Not metadata"></body></html>`
+
+	_, err := parseMetadata(strings.NewReader(page), 1<<20, "text/html", pageURL)
+
+	assert.True(t, errors.Is(err, ErrNoPreview), "got %v", err)
+}
+
+func TestParseMetadataTruncates(t *testing.T) {
+	pageURL, _ := url.Parse("https://example.com/")
+	page := `<head><meta property="og:title" content="` + strings.Repeat("é", 300) + `"></head>`
+
+	preview, err := parseMetadata(strings.NewReader(page), 1<<20, "text/html; charset=utf-8", pageURL)
+	require.NoError(t, err)
+
+	assert.Equal(t, maxTitleLength, len([]rune(preview.Title)))
+	assert.True(t, strings.HasSuffix(preview.Title, "…"))
+}
@@ -0,0 +1,269 @@
+package linkpreview
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrUnsupportedURL is returned for links that are not plain http(s) URLs
+	ErrUnsupportedURL = errors.New("unsupported link")
+
+	// ErrBlockedAddress is returned when a link resolves to a private or reserved address
+	ErrBlockedAddress = errors.New("link resolves to a blocked address")
+
+	// ErrNoPreview is returned when a page has no metadata worth previewing
+	ErrNoPreview = errors.New("link has no preview")
+)
+
+// noPreview is cached for links that failed, so they are not fetched again on every message
+const noPreview = "none"
+
+// blockedPrefixes are reserved ranges not covered by the netip helpers
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),     // "This" network
+	netip.MustParsePrefix("100.64.0.0/10"), // Carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),  // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"), // Benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),   // Reserved, includes broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),  // NAT64, can reach IPv4 internals
+	netip.MustParsePrefix("2001:db8::/32"), // Documentation
+}
+
+// Config controls how links are fetched
+type Config struct {
+	Timeout      time.Duration // Total time for one link, redirects included
+	MaxBodyBytes int64         // How much of a page is read looking for metadata
+	MaxRedirects int
+	CacheTTL     time.Duration // How long a generated preview is cached
+	FailureTTL   time.Duration // How long a link without a preview is remembered
+	UserAgent    string
+
+	// AllowPrivateNetworks lets links resolve to private addresses.
+	// Only meant for running against a local HTTP server in development.
+	AllowPrivateNetworks bool
+}
+
+// Unfurler fetches OpenGraph and Twitter card metadata of links.
+// Only public addresses are dialed, the check runs on the resolved IP of every
+// connection so redirects and DNS rebinding cannot reach internal services.
+type Unfurler struct {
+	cfg    Config
+	client *http.Client
+	cache  *redis.Client
+
+	// blocked decides which resolved addresses are not dialed
+	blocked func(addr netip.Addr) bool
+}
+
+// NewUnfurler creates a new link unfurler, cache may be nil
+func NewUnfurler(cfg Config, cache *redis.Client) *Unfurler {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 512 * 1024
+	}
+	if cfg.MaxRedirects <= 0 {
+		cfg.MaxRedirects = 3
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = 24 * time.Hour
+	}
+	if cfg.FailureTTL <= 0 {
+		cfg.FailureTTL = 10 * time.Minute
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "SoTalkBot/1.0 (link preview)"
+	}
+
+	u := &Unfurler{
+		cfg:     cfg,
+		cache:   cache,
+		blocked: isBlockedAddr,
+	}
+
+	dialer := &net.Dialer{
+		Timeout: cfg.Timeout,
+		Control: u.checkDialAddress,
+	}
+	transport := &http.Transport{
+		Proxy:                  nil, // A proxy would dial on our behalf and skip the address check
+		DialContext:            dialer.DialContext,
+		TLSHandshakeTimeout:    cfg.Timeout,
+		ResponseHeaderTimeout:  cfg.Timeout,
+		MaxResponseHeaderBytes: 64 * 1024,
+		MaxIdleConns:           10,
+		IdleConnTimeout:        30 * time.Second,
+	}
+	u.client = &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > cfg.MaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", cfg.MaxRedirects)
+			}
+			return validateURL(req.URL)
+		},
+	}
+
+	return u
+}
+
+// Unfurl returns the preview of a link, served from the cache when it was fetched before
+func (u *Unfurler) Unfurl(ctx context.Context, rawURL string) (*message.LinkPreview, error) {
+	key := cacheKey(rawURL)
+
+	if u.cache != nil {
+		val, err := u.cache.Get(ctx, key).Result()
+		switch {
+		case err == nil:
+			if val == noPreview {
+				return nil, ErrNoPreview
+			}
+			var preview message.LinkPreview
+			if err := json.Unmarshal([]byte(val), &preview); err == nil {
+				return &preview, nil
+			}
+		case err != redis.Nil:
+			logger.Warn("Failed to get cached link preview", zap.Error(err))
+		}
+	}
+
+	preview, err := u.fetch(ctx, rawURL)
+	if err != nil {
+		// Timeouts and network errors say nothing about the link itself, the next message tries again
+		if u.cache != nil && isDefinitive(err) {
+			u.cache.Set(ctx, key, noPreview, u.cfg.FailureTTL)
+		}
+		return nil, err
+	}
+
+	if u.cache != nil {
+		if jsonData, err := json.Marshal(preview); err == nil {
+			u.cache.Set(ctx, key, jsonData, u.cfg.CacheTTL)
+		}
+	}
+
+	return preview, nil
+}
+
+// fetch downloads the page behind a link and reads its metadata
+func (u *Unfurler) fetch(ctx context.Context, rawURL string) (*message.LinkPreview, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, ErrUnsupportedURL
+	}
+	if err := validateURL(target); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", u.cfg.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrNoPreview, resp.StatusCode)
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("%w: content type %q", ErrNoPreview, mediaType)
+	}
+
+	// resp.Request is the last request made, so relative image links resolve against the final page
+	preview, err := parseMetadata(resp.Body, u.cfg.MaxBodyBytes, contentType, resp.Request.URL)
+	if err != nil {
+		return nil, err
+	}
+	preview.URL = rawURL
+
+	return preview, nil
+}
+
+// checkDialAddress rejects connections to non-public addresses.
+// It runs after DNS resolution, right before each connection is made.
+func (u *Unfurler) checkDialAddress(network, address string, _ syscall.RawConn) error {
+	if u.cfg.AllowPrivateNetworks {
+		return nil
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if u.blocked(addr) {
+		return ErrBlockedAddress
+	}
+
+	return nil
+}
+
+// isBlockedAddr checks if an address is private, loopback or otherwise not publicly routable
+func isBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return true
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isDefinitive checks if fetching a link failed in a way that fetching it again would not change
+func isDefinitive(err error) bool {
+	return errors.Is(err, ErrNoPreview) || errors.Is(err, ErrUnsupportedURL) || errors.Is(err, ErrBlockedAddress)
+}
+
+// validateURL only lets plain http(s) links with a host through
+func validateURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrUnsupportedURL
+	}
+	if u.Hostname() == "" || u.User != nil {
+		return ErrUnsupportedURL
+	}
+	return nil
+}
+
+func cacheKey(rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return fmt.Sprintf("linkpreview:%s", hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"gorm.io/gorm"
+)
+
+// settledBefore keeps changes younger than the settle delay out of a query
+func settledBefore(settleDelay time.Duration) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("created_at <= CURRENT_TIMESTAMP - make_interval(secs => ?)", settleDelay.Seconds())
+	}
+}
+
+type changeLogRepository struct {
+	db *gorm.DB
+}
+
+// NewChangeLogRepository creates a new change log repository
+func NewChangeLogRepository(db *gorm.DB) changelog.Repository {
+	return &changeLogRepository{db: db}
+}
+
+// Record appends changes to the log, filling in their Seq and creation time
+func (r *changeLogRepository) Record(ctx context.Context, changes ...*changelog.Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	models := make([]ChangeLog, len(changes))
+	for i, c := range changes {
+		models[i] = ChangeLog{
+			UserID:         c.UserID,
+			ConversationID: c.ConversationID,
+			MessageID:      c.MessageID,
+			Kind:           string(c.Kind),
+		}
+	}
+
+	if err := r.db.WithContext(ctx).Create(&models).Error; err != nil {
+		return err
+	}
+
+	for i, c := range changes {
+		c.Seq = models[i].Seq
+		c.CreatedAt = models[i].CreatedAt
+	}
+	return nil
+}
+
+// FindSince returns the settled changes after since that the user can see, oldest first
+func (r *changeLogRepository) FindSince(ctx context.Context, userID uuid.UUID, since int64, settleDelay time.Duration, limit int) ([]*changelog.Change, error) {
+	var models []ChangeLog
+
+	err := r.db.WithContext(ctx).
+		Scopes(settledBefore(settleDelay)).
+		Where("seq > ?", since).
+		Where("(user_id = ? OR (user_id IS NULL AND conversation_id IN (?)))",
+			userID,
+			r.db.Model(&ConversationParticipant{}).Select("conversation_id").Where("user_id = ?", userID),
+		).
+		Order("seq ASC").
+		Limit(limit).
+		Find(&models).Error
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]*changelog.Change, len(models))
+	for i := range models {
+		changes[i] = toDomainChange(&models[i])
+	}
+	return changes, nil
+}
+
+// Exists checks if a change is still in the log
+func (r *changeLogRepository) Exists(ctx context.Context, seq int64) (bool, error) {
+	var model ChangeLog
+	err := r.db.WithContext(ctx).Select("seq").Where("seq = ?", seq).Take(&model).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// LatestSeq returns the Seq of the newest settled change
+func (r *changeLogRepository) LatestSeq(ctx context.Context, settleDelay time.Duration) (int64, error) {
+	var seq int64
+	err := r.db.WithContext(ctx).
+		Model(&ChangeLog{}).
+		Scopes(settledBefore(settleDelay)).
+		Select("COALESCE(MAX(seq), 0)").
+		Scan(&seq).Error
+	return seq, err
+}
+
+// DeleteOlderThan prunes the oldest changes in batches so the table is not locked for long
+func (r *changeLogRepository) DeleteOlderThan(ctx context.Context, age time.Duration, limit int) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM change_log
+		WHERE seq IN (
+			SELECT seq FROM change_log
+			WHERE created_at < CURRENT_TIMESTAMP - make_interval(secs => ?)
+			ORDER BY seq
+			LIMIT ?
+		)`, age.Seconds(), limit)
+	return result.RowsAffected, result.Error
+}
+
+func toDomainChange(model *ChangeLog) *changelog.Change {
+	return &changelog.Change{
+		Seq:            model.Seq,
+		ConversationID: model.ConversationID,
+		MessageID:      model.MessageID,
+		UserID:         model.UserID,
+		Kind:           changelog.Kind(model.Kind),
+		CreatedAt:      model.CreatedAt,
+	}
+}
@@ -165,6 +165,21 @@ func (r *contactRepository) IsContact(ctx context.Context, userID, targetID uuid
 	return count > 0, nil
 }
 
+// GetContactOwnerIDs returns the users who have contactID in their contacts
+func (r *contactRepository) GetContactOwnerIDs(ctx context.Context, contactID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&Contact{}).
+		Where("contact_id = ?", contactID).
+		Pluck("user_id", &userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 // Favorites
 
 func (r *contactRepository) SetFavorite(ctx context.Context, userID, contactID uuid.UUID, favorite bool) error {
@@ -3,10 +3,12 @@ package postgres
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // conversationRepository implements conversation.Repository interface
@@ -54,16 +56,40 @@ func (r *conversationRepository) FindByID(ctx context.Context, id uuid.UUID) (*c
 	return toDomainConversation(&dbConversation), nil
 }
 
-// FindByUserID finds conversations for a user with pagination (excludes archived)
-func (r *conversationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*conversation.Conversation, error) {
+// FindByUserID finds conversations for a user with pagination (excludes archived and message requests).
+// Pinned conversations come first in the user's pin order
+func (r *conversationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter conversation.ListFilter, limit, offset int) ([]*conversation.Conversation, error) {
 	var dbConversations []Conversation
 
-	result := r.db.WithContext(ctx).
-		Select("DISTINCT conversations.*").
+	// The participant join yields one row per conversation, as (conversation_id, user_id) is the key
+	query := r.db.WithContext(ctx).
+		Select("conversations.*").
 		Joins("INNER JOIN conversation_participants ON conversation_participants.conversation_id = conversations.id").
 		Where("conversation_participants.user_id = ?", userID).
 		Where("conversation_participants.archived_at IS NULL"). // Exclude archived conversations
-		Order("conversations.last_message_at DESC NULLS LAST, conversations.created_at DESC").
+		// Message requests have their own inbox
+		Where("conversation_participants.requested_at IS NULL")
+
+	if filter.FolderID != nil {
+		query = query.Where("EXISTS (SELECT 1 FROM conversation_folder_items WHERE conversation_folder_items.folder_id = ? AND conversation_folder_items.conversation_id = conversations.id)", *filter.FolderID)
+	}
+	if filter.Pinned != nil {
+		if *filter.Pinned {
+			query = query.Where("conversation_participants.pin_order IS NOT NULL")
+		} else {
+			query = query.Where("conversation_participants.pin_order IS NULL")
+		}
+	}
+	if filter.Muted != nil {
+		if *filter.Muted {
+			query = query.Where("conversation_participants.muted_until > ?", time.Now())
+		} else {
+			query = query.Where("(conversation_participants.muted_until IS NULL OR conversation_participants.muted_until <= ?)", time.Now())
+		}
+	}
+
+	result := query.
+		Order("conversation_participants.pin_order DESC NULLS LAST, conversations.last_message_at DESC NULLS LAST, conversations.created_at DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&dbConversations)
@@ -149,21 +175,26 @@ func (r *conversationRepository) AddParticipant(ctx context.Context, p *conversa
 	return nil
 }
 
-// RemoveParticipant removes a participant from a conversation
+// RemoveParticipant removes a participant from a conversation, along with the conversation in the user's folders
 func (r *conversationRepository) RemoveParticipant(ctx context.Context, conversationID, userID uuid.UUID) error {
-	result := r.db.WithContext(ctx).
-		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
-		Delete(&ConversationParticipant{})
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.
+			Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+			Delete(&ConversationParticipant{})
 
-	if result.Error != nil {
-		return result.Error
-	}
+		if result.Error != nil {
+			return result.Error
+		}
 
-	if result.RowsAffected == 0 {
-		return conversation.ErrNotParticipant
-	}
+		if result.RowsAffected == 0 {
+			return conversation.ErrNotParticipant
+		}
 
-	return nil
+		userFolders := tx.Model(&ConversationFolder{}).Select("id").Where("user_id = ?", userID)
+		return tx.
+			Where("conversation_id = ? AND folder_id IN (?)", conversationID, userFolders).
+			Delete(&ConversationFolderItem{}).Error
+	})
 }
 
 // FindParticipants finds all participants of a conversation
@@ -180,14 +211,19 @@ func (r *conversationRepository) FindParticipants(ctx context.Context, conversat
 
 	participants := make([]*conversation.Participant, len(dbParticipants))
 	for i, dbPart := range dbParticipants {
-		participant := toDomainParticipant(&dbPart)
+		participants[i] = toDomainParticipant(&dbPart)
+	}
 
-		// Check online status from Hub
-		if r.presenceChecker != nil {
-			participant.IsOnline = r.presenceChecker.IsUserOnline(participant.UserID)
+	// Check online status from Hub
+	if r.presenceChecker != nil && len(participants) > 0 {
+		userIDs := make([]uuid.UUID, len(participants))
+		for i, participant := range participants {
+			userIDs[i] = participant.UserID
+		}
+		online := r.presenceChecker.OnlineUsers(userIDs)
+		for _, participant := range participants {
+			participant.IsOnline = online[participant.UserID]
 		}
-
-		participants[i] = participant
 	}
 
 	return participants, nil
@@ -207,11 +243,48 @@ func (r *conversationRepository) IsParticipant(ctx context.Context, conversation
 	return count > 0, nil
 }
 
-// UpdateParticipantLastRead updates when a participant last read messages
-func (r *conversationRepository) UpdateParticipantLastRead(ctx context.Context, conversationID, userID uuid.UUID) error {
+// FindParticipant finds a user's participation in a conversation
+func (r *conversationRepository) FindParticipant(ctx context.Context, conversationID, userID uuid.UUID) (*conversation.Participant, error) {
+	var participant ConversationParticipant
+	result := r.db.WithContext(ctx).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		First(&participant)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, conversation.ErrNotParticipant
+		}
+		return nil, result.Error
+	}
+
+	return toDomainParticipant(&participant), nil
+}
+
+// AdvanceReadMarker moves a participant's read marker forward, never back
+func (r *conversationRepository) AdvanceReadMarker(ctx context.Context, conversationID, userID, messageID uuid.UUID, readUpTo time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Where("last_read_at IS NULL OR last_read_at < ?", readUpTo).
+		Updates(map[string]interface{}{
+			"last_read_at":         readUpTo,
+			"last_read_message_id": messageID,
+		})
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// SetReadMarker places a participant's read marker, moving it back when marking messages unread
+func (r *conversationRepository) SetReadMarker(ctx context.Context, conversationID, userID uuid.UUID, messageID *uuid.UUID, readUpTo *time.Time) error {
 	result := r.db.WithContext(ctx).Model(&ConversationParticipant{}).
 		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
-		Update("last_read_at", gorm.Expr("CURRENT_TIMESTAMP"))
+		Updates(map[string]interface{}{
+			"last_read_at":         readUpTo,
+			"last_read_message_id": messageID,
+		})
 
 	if result.Error != nil {
 		return result.Error
@@ -258,6 +331,336 @@ func (r *conversationRepository) UnarchiveConversation(ctx context.Context, conv
 	return nil
 }
 
+// PinConversation pins a conversation above the user's other pinned conversations
+func (r *conversationRepository) PinConversation(ctx context.Context, conversationID, userID uuid.UUID, max int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock the user's pins so concurrent pins cannot pass the limit
+		pinned, err := lockPinned(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		highest := 0
+		for _, p := range pinned {
+			if p.ConversationID == conversationID {
+				return nil
+			}
+			if *p.PinOrder > highest {
+				highest = *p.PinOrder
+			}
+		}
+		if len(pinned) >= max {
+			return conversation.ErrPinLimitReached
+		}
+
+		result := tx.Model(&ConversationParticipant{}).
+			Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+			Update("pin_order", highest+1)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return conversation.ErrNotParticipant
+		}
+
+		return nil
+	})
+}
+
+// UnpinConversation unpins a conversation for a specific user
+func (r *conversationRepository) UnpinConversation(ctx context.Context, conversationID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Update("pin_order", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return conversation.ErrNotParticipant
+	}
+
+	return nil
+}
+
+// ReorderPinned sets the pin order of all the user's pinned conversations, the first one on top
+func (r *conversationRepository) ReorderPinned(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		pinned, err := lockPinned(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if len(conversationIDs) != len(pinned) {
+			return conversation.ErrInvalidPinOrder
+		}
+		isPinned := make(map[uuid.UUID]bool, len(pinned))
+		for _, p := range pinned {
+			isPinned[p.ConversationID] = true
+		}
+		for _, id := range conversationIDs {
+			if !isPinned[id] {
+				return conversation.ErrInvalidPinOrder
+			}
+			// Each conversation may only be listed once
+			delete(isPinned, id)
+		}
+
+		for i, id := range conversationIDs {
+			if err := tx.Model(&ConversationParticipant{}).
+				Where("conversation_id = ? AND user_id = ?", id, userID).
+				Update("pin_order", len(conversationIDs)-i).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// lockPinned locks and returns the user's pinned conversation rows for the rest of the transaction
+func lockPinned(tx *gorm.DB, userID uuid.UUID) ([]ConversationParticipant, error) {
+	var pinned []ConversationParticipant
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ? AND pin_order IS NOT NULL", userID).
+		Find(&pinned).Error
+	return pinned, err
+}
+
+// SetMutedUntil mutes a conversation for a specific user until the given time, nil unmutes it
+func (r *conversationRepository) SetMutedUntil(ctx context.Context, conversationID, userID uuid.UUID, until *time.Time) error {
+	var value interface{}
+	if until != nil {
+		value = *until
+	}
+
+	result := r.db.WithContext(ctx).Model(&ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, userID).
+		Update("muted_until", value)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return conversation.ErrNotParticipant
+	}
+
+	return nil
+}
+
+// CreateFolder creates a conversation folder
+func (r *conversationRepository) CreateFolder(ctx context.Context, folder *conversation.Folder) error {
+	result := r.db.WithContext(ctx).Create(toFolderModel(folder))
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return conversation.ErrFolderNameTaken
+		}
+		return result.Error
+	}
+
+	return nil
+}
+
+// FindFolderByID finds a conversation folder by ID
+func (r *conversationRepository) FindFolderByID(ctx context.Context, id uuid.UUID) (*conversation.Folder, error) {
+	var dbFolder ConversationFolder
+	result := r.db.WithContext(ctx).Where("id = ?", id).First(&dbFolder)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, conversation.ErrFolderNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toDomainFolder(&dbFolder), nil
+}
+
+// FindFoldersByUserID finds a user's folders with the number of conversations in each, oldest first
+func (r *conversationRepository) FindFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*conversation.Folder, error) {
+	var rows []struct {
+		ConversationFolder
+		ConversationCount int
+	}
+
+	result := r.db.WithContext(ctx).Model(&ConversationFolder{}).
+		Select("conversation_folders.*, COUNT(conversation_folder_items.conversation_id) AS conversation_count").
+		Joins("LEFT JOIN conversation_folder_items ON conversation_folder_items.folder_id = conversation_folders.id").
+		Where("conversation_folders.user_id = ?", userID).
+		Group("conversation_folders.id").
+		Order("conversation_folders.created_at ASC").
+		Scan(&rows)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	folders := make([]*conversation.Folder, len(rows))
+	for i, row := range rows {
+		folders[i] = toDomainFolder(&row.ConversationFolder)
+		folders[i].ConversationCount = row.ConversationCount
+	}
+
+	return folders, nil
+}
+
+// UpdateFolder updates the name of a conversation folder
+func (r *conversationRepository) UpdateFolder(ctx context.Context, folder *conversation.Folder) error {
+	result := r.db.WithContext(ctx).Model(&ConversationFolder{}).
+		Where("id = ?", folder.ID).
+		Updates(map[string]interface{}{
+			"name":       folder.Name,
+			"updated_at": folder.UpdatedAt,
+		})
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+			return conversation.ErrFolderNameTaken
+		}
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return conversation.ErrFolderNotFound
+	}
+
+	return nil
+}
+
+// DeleteFolder deletes a conversation folder and its entries
+func (r *conversationRepository) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("folder_id = ?", id).Delete(&ConversationFolderItem{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("id = ?", id).Delete(&ConversationFolder{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return conversation.ErrFolderNotFound
+		}
+
+		return nil
+	})
+}
+
+// AddToFolder adds a conversation to a folder, doing nothing if it is already in it
+func (r *conversationRepository) AddToFolder(ctx context.Context, folderID, conversationID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ConversationFolderItem{
+			FolderID:       folderID,
+			ConversationID: conversationID,
+			CreatedAt:      time.Now(),
+		}).Error
+}
+
+// RemoveFromFolder removes a conversation from a folder
+func (r *conversationRepository) RemoveFromFolder(ctx context.Context, folderID, conversationID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("folder_id = ? AND conversation_id = ?", folderID, conversationID).
+		Delete(&ConversationFolderItem{}).Error
+}
+
+// FindFolderIDs finds which of the user's folders each conversation is in
+func (r *conversationRepository) FindFolderIDs(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	folderIDs := make(map[uuid.UUID][]uuid.UUID)
+	if len(conversationIDs) == 0 {
+		return folderIDs, nil
+	}
+
+	var items []ConversationFolderItem
+	result := r.db.WithContext(ctx).
+		Select("conversation_folder_items.*").
+		Joins("INNER JOIN conversation_folders ON conversation_folders.id = conversation_folder_items.folder_id").
+		Where("conversation_folders.user_id = ?", userID).
+		Where("conversation_folder_items.conversation_id IN ?", conversationIDs).
+		Order("conversation_folders.created_at ASC").
+		Find(&items)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	for _, item := range items {
+		folderIDs[item.ConversationID] = append(folderIDs[item.ConversationID], item.FolderID)
+	}
+
+	return folderIDs, nil
+}
+
+// FindRequestsByUserID finds conversations waiting in a user's message requests, newest activity first
+func (r *conversationRepository) FindRequestsByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*conversation.Conversation, error) {
+	var dbConversations []Conversation
+
+	result := r.db.WithContext(ctx).
+		Select("conversations.*").
+		Joins("INNER JOIN conversation_participants ON conversation_participants.conversation_id = conversations.id").
+		Where("conversation_participants.user_id = ?", userID).
+		Where("conversation_participants.requested_at IS NOT NULL").
+		Order("conversations.last_message_at DESC NULLS LAST, conversation_participants.requested_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&dbConversations)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	conversations := make([]*conversation.Conversation, len(dbConversations))
+	for i, dbConv := range dbConversations {
+		conversations[i] = toDomainConversation(&dbConv)
+	}
+
+	return conversations, nil
+}
+
+// AcceptRequest moves a message request into the user's conversations
+func (r *conversationRepository) AcceptRequest(ctx context.Context, conversationID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ? AND requested_at IS NOT NULL", conversationID, userID).
+		Update("requested_at", nil)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return conversation.ErrMessageRequestNotFound
+	}
+
+	return nil
+}
+
+// FindPeerIDs returns the users sharing a direct or group conversation with the user,
+// leaving out conversations still waiting as a message request on either side
+func (r *conversationRepository) FindPeerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var peerIDs []uuid.UUID
+
+	err := r.db.WithContext(ctx).
+		Table("conversation_participants AS me").
+		Distinct("peer.user_id").
+		Joins("INNER JOIN conversation_participants AS peer ON peer.conversation_id = me.conversation_id AND peer.user_id <> me.user_id").
+		Joins("INNER JOIN conversations ON conversations.id = me.conversation_id").
+		Where("me.user_id = ?", userID).
+		Where("conversations.type IN ?", []string{string(conversation.TypeDirect), string(conversation.TypeGroup)}).
+		Where("me.requested_at IS NULL AND peer.requested_at IS NULL").
+		Pluck("peer.user_id", &peerIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return peerIDs, nil
+}
+
 // Mapper functions
 
 // toConversationModel converts domain Conversation to GORM Conversation model
@@ -287,23 +690,53 @@ func toDomainConversation(c *Conversation) *conversation.Conversation {
 // toParticipantModel converts domain Participant to GORM ConversationParticipant model
 func toParticipantModel(p *conversation.Participant) *ConversationParticipant {
 	return &ConversationParticipant{
-		ConversationID: p.ConversationID,
-		UserID:         p.UserID,
-		Role:           string(p.Role),
-		JoinedAt:       p.JoinedAt,
-		LastReadAt:     p.LastReadAt,
-		ArchivedAt:     p.ArchivedAt,
+		ConversationID:    p.ConversationID,
+		UserID:            p.UserID,
+		Role:              string(p.Role),
+		JoinedAt:          p.JoinedAt,
+		LastReadAt:        p.LastReadAt,
+		LastReadMessageID: p.LastReadMessageID,
+		ArchivedAt:        p.ArchivedAt,
+		RequestedAt:       p.RequestedAt,
+		PinOrder:          p.PinOrder,
+		MutedUntil:        p.MutedUntil,
 	}
 }
 
 // toDomainParticipant converts GORM ConversationParticipant model to domain Participant
 func toDomainParticipant(p *ConversationParticipant) *conversation.Participant {
 	return &conversation.Participant{
-		ConversationID: p.ConversationID,
-		UserID:         p.UserID,
-		Role:           conversation.Role(p.Role),
-		JoinedAt:       p.JoinedAt,
-		LastReadAt:     p.LastReadAt,
-		ArchivedAt:     p.ArchivedAt,
+		ConversationID:    p.ConversationID,
+		UserID:            p.UserID,
+		Role:              conversation.Role(p.Role),
+		JoinedAt:          p.JoinedAt,
+		LastReadAt:        p.LastReadAt,
+		LastReadMessageID: p.LastReadMessageID,
+		ArchivedAt:        p.ArchivedAt,
+		RequestedAt:       p.RequestedAt,
+		PinOrder:          p.PinOrder,
+		MutedUntil:        p.MutedUntil,
+	}
+}
+
+// toFolderModel converts domain Folder to GORM ConversationFolder model
+func toFolderModel(f *conversation.Folder) *ConversationFolder {
+	return &ConversationFolder{
+		ID:        f.ID,
+		UserID:    f.UserID,
+		Name:      f.Name,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+}
+
+// toDomainFolder converts GORM ConversationFolder model to domain Folder
+func toDomainFolder(f *ConversationFolder) *conversation.Folder {
+	return &conversation.Folder{
+		ID:        f.ID,
+		UserID:    f.UserID,
+		Name:      f.Name,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
 	}
 }
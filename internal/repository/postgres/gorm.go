@@ -84,6 +84,8 @@ func AutoMigrate(db *gorm.DB) error {
 		&User{},
 		&Conversation{},
 		&ConversationParticipant{},
+		&ConversationFolder{},
+		&ConversationFolderItem{},
 		&Message{},
 		// Group models (Day 5)
 		&Group{},
@@ -109,6 +111,15 @@ func AutoMigrate(db *gorm.DB) error {
 		&PinnedMessage{},
 		&ForwardedMessage{},
 		&MessageMention{},
+		&ThreadFollower{},
+		&MessageRevision{},
+		&MessageHide{},
+		&MessageReceipt{},
+		&ScheduledMessage{},
+		&ChangeLog{},
+		&Poll{},
+		&PollOption{},
+		&PollVote{},
 		&Status{},
 		&StatusView{},
 		&Contact{},
@@ -124,10 +135,23 @@ func AutoMigrate(db *gorm.DB) error {
 		return fmt.Errorf("auto-migration failed: %w", err)
 	}
 
+	// Expression indexes can't be declared with struct tags
+	if err := createMessageSearchIndex(db); err != nil {
+		log.Printf("⚠️  Message search index warning: %v", err)
+	}
+
 	log.Println("✅ Auto-migration completed successfully")
 	return nil
 }
 
+// createMessageSearchIndex creates the full-text search index on message content
+func createMessageSearchIndex(db *gorm.DB) error {
+	return db.Exec(`
+		CREATE INDEX IF NOT EXISTS idx_messages_search
+		ON messages USING GIN (to_tsvector('english'::regconfig, content))
+	`).Error
+}
+
 // dropDisplayNameColumn drops the display_name column from users table
 func dropDisplayNameColumn(db *gorm.DB) error {
 	// Check if display_name column exists
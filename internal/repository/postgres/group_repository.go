@@ -239,7 +239,8 @@ func toDBGroup(grp *group.Group) *Group {
 }
 
 func toDomainGroup(dbGroup *Group) *group.Group {
-	var settings group.Settings
+	// Groups saved before link previews existed keep them on
+	settings := group.Settings{LinkPreview: true}
 	if dbGroup.Settings != "" {
 		json.Unmarshal([]byte(dbGroup.Settings), &settings)
 	}
@@ -2,13 +2,50 @@ package postgres
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/domain/message"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+const (
+	// searchConfig is the text search configuration of message search. English stems words and drops
+	// stop words, so a search for "running" also finds "run" and ranking ignores words like "the".
+	// It must match the expression in idx_messages_search
+	searchConfig = "'english'::regconfig"
+
+	// searchVector is the text search document of a message, the expression idx_messages_search is built on.
+	// It is written out rather than bound, so the planner can match it to the index
+	searchVector = "to_tsvector(" + searchConfig + ", messages.content)"
+
+	// searchTSQuery parses the search text with the same configuration as searchVector
+	searchTSQuery = "websearch_to_tsquery(" + searchConfig + ", ?)"
+
+	// searchHeadlineOptions controls the highlighted snippet returned with search results
+	searchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=5, MaxFragments=2"
+)
+
+// searchRow is a message row with its search rank and snippet
+type searchRow struct {
+	Message
+	Rank    float64
+	Snippet string
+}
+
+// notHiddenFor leaves out messages the user deleted for themselves. A nil user keeps all messages
+func notHiddenFor(userID *uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if userID == nil {
+			return db
+		}
+		return db.Where("NOT EXISTS (SELECT 1 FROM message_hides WHERE message_hides.message_id = messages.id AND message_hides.user_id = ?)", *userID)
+	}
+}
+
 // messageRepository implements message.Repository interface
 type messageRepository struct {
 	db *gorm.DB
@@ -22,9 +59,37 @@ func NewMessageRepository(db *gorm.DB) message.Repository {
 // Create creates a new message
 func (r *messageRepository) Create(ctx context.Context, m *message.Message) error {
 	dbMessage := toMessageModel(m)
-	result := r.db.WithContext(ctx).Create(dbMessage)
-	if result.Error != nil {
-		return result.Error
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		q := tx
+		if dbMessage.ClientMessageID != nil {
+			// A retried send loses against the first attempt instead of failing the insert
+			q = q.Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "sender_id"}, {Name: "client_message_id"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "client_message_id IS NOT NULL"}}},
+				DoNothing:   true,
+			})
+		}
+
+		result := q.Create(dbMessage)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return message.ErrDuplicateClientMessage
+		}
+
+		if m.Poll == nil {
+			return nil
+		}
+		dbPoll, dbOptions := toPollModels(dbMessage.ID, m.Poll)
+		if err := tx.Create(dbPoll).Error; err != nil {
+			return err
+		}
+		return tx.Create(&dbOptions).Error
+	})
+	if err != nil {
+		return err
 	}
 
 	// Update domain entity with generated values
@@ -50,14 +115,57 @@ func (r *messageRepository) FindByID(ctx context.Context, id uuid.UUID) (*messag
 	return toDomainMessage(&dbMessage), nil
 }
 
-// FindByConversationID finds messages by conversation ID with pagination
+// FindByIDs finds messages by ID, each enriched for its own conversation
+func (r *messageRepository) FindByIDs(ctx context.Context, ids []uuid.UUID, userID *uuid.UUID) ([]*message.Message, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	var dbMessages []Message
+	result := r.db.WithContext(ctx).
+		Scopes(notHiddenFor(userID)).
+		Where("id IN ?", ids).
+		Order("created_at ASC, id ASC").
+		Find(&dbMessages)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	messages := make([]*message.Message, 0, len(dbMessages))
+	for i := range dbMessages {
+		messages = append(messages, r.enrichMessages(ctx, dbMessages[i:i+1], dbMessages[i].ConversationID, userID)...)
+	}
+
+	return messages, nil
+}
+
+// FindByClientMessageID finds the message a sender sent with the given client message ID
+func (r *messageRepository) FindByClientMessageID(ctx context.Context, senderID uuid.UUID, clientMessageID string) (*message.Message, error) {
+	var dbMessage Message
+	result := r.db.WithContext(ctx).
+		Where("sender_id = ? AND client_message_id = ?", senderID, clientMessageID).
+		First(&dbMessage)
+
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, message.ErrMessageNotFound
+		}
+		return nil, result.Error
+	}
+
+	return toDomainMessage(&dbMessage), nil
+}
+
+// FindByConversationID finds messages by conversation ID with pagination, thread replies are left to their thread
 // userID is optional - if provided, only returns messages pinned by that user
 func (r *messageRepository) FindByConversationID(ctx context.Context, conversationID uuid.UUID, limit, offset int, userID *uuid.UUID) ([]*message.Message, error) {
 	var dbMessages []Message
 
 	result := r.db.WithContext(ctx).
-		Where("conversation_id = ?", conversationID).
-		Order("created_at DESC").
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND thread_id IS NULL", conversationID).
+		Order("created_at DESC, id DESC").
 		Limit(limit).
 		Offset(offset).
 		Find(&dbMessages)
@@ -66,6 +174,85 @@ func (r *messageRepository) FindByConversationID(ctx context.Context, conversati
 		return nil, result.Error
 	}
 
+	return r.enrichMessages(ctx, dbMessages, conversationID, userID), nil
+}
+
+// FindBefore finds messages older than the cursor, newest first
+func (r *messageRepository) FindBefore(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	var dbMessages []Message
+
+	result := r.db.WithContext(ctx).
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND thread_id IS NULL AND (created_at, id) < (?, ?)", conversationID, cursor.CreatedAt, cursor.ID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&dbMessages)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return r.enrichMessages(ctx, dbMessages, conversationID, userID), nil
+}
+
+// FindAfter finds messages newer than the cursor, newest first
+func (r *messageRepository) FindAfter(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	var dbMessages []Message
+
+	// Walk forward from the cursor so the closest messages are kept when limited
+	result := r.db.WithContext(ctx).
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND thread_id IS NULL AND (created_at, id) > (?, ?)", conversationID, cursor.CreatedAt, cursor.ID).
+		Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&dbMessages)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	// Return in the same order as the other history queries
+	for i, j := 0, len(dbMessages)-1; i < j; i, j = i+1, j-1 {
+		dbMessages[i], dbMessages[j] = dbMessages[j], dbMessages[i]
+	}
+
+	return r.enrichMessages(ctx, dbMessages, conversationID, userID), nil
+}
+
+// FindAround finds the message at the cursor together with its older and newer neighbours, newest first.
+// Thread replies are only included as the message at the cursor
+func (r *messageRepository) FindAround(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, before, after int, userID *uuid.UUID) ([]*message.Message, error) {
+	var older []Message
+	if err := r.db.WithContext(ctx).
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND (thread_id IS NULL OR id = ?) AND (created_at, id) <= (?, ?)", conversationID, cursor.ID, cursor.CreatedAt, cursor.ID).
+		Order("created_at DESC, id DESC").
+		Limit(before + 1).
+		Find(&older).Error; err != nil {
+		return nil, err
+	}
+
+	var newer []Message
+	if err := r.db.WithContext(ctx).
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND thread_id IS NULL AND (created_at, id) > (?, ?)", conversationID, cursor.CreatedAt, cursor.ID).
+		Order("created_at ASC, id ASC").
+		Limit(after).
+		Find(&newer).Error; err != nil {
+		return nil, err
+	}
+
+	dbMessages := make([]Message, 0, len(newer)+len(older))
+	for i := len(newer) - 1; i >= 0; i-- {
+		dbMessages = append(dbMessages, newer[i])
+	}
+	dbMessages = append(dbMessages, older...)
+
+	return r.enrichMessages(ctx, dbMessages, conversationID, userID), nil
+}
+
+// enrichMessages converts models to domain messages with reactions and pin state
+func (r *messageRepository) enrichMessages(ctx context.Context, dbMessages []Message, conversationID uuid.UUID, userID *uuid.UUID) []*message.Message {
 	messages := make([]*message.Message, len(dbMessages))
 	for i, dbMsg := range dbMessages {
 		domainMsg := toDomainMessage(&dbMsg)
@@ -85,7 +272,7 @@ func (r *messageRepository) FindByConversationID(ctx context.Context, conversati
 		messages[i] = domainMsg
 	}
 
-	return messages, nil
+	return messages
 }
 
 // Update updates a message
@@ -104,6 +291,134 @@ func (r *messageRepository) Update(ctx context.Context, m *message.Message) erro
 	return nil
 }
 
+// SaveEdit stores the edited content and the revision it replaced.
+// The update only applies if the message is still at the revision the edit was based on.
+func (r *messageRepository) SaveEdit(ctx context.Context, m *message.Message, previous *message.MessageRevision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Message{}).
+			Where("id = ? AND edit_count = ?", m.ID, m.EditCount-1).
+			Updates(map[string]interface{}{
+				"content":       m.Content,
+				"edited_at":     m.EditedAt,
+				"edit_count":    m.EditCount,
+				"updated_at":    m.UpdatedAt,
+				"link_previews": nil, // Regenerated for the new content
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&Message{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return message.ErrMessageNotFound
+			}
+			return message.ErrEditConflict
+		}
+
+		return tx.Create(&MessageRevision{
+			ID:          previous.ID,
+			MessageID:   previous.MessageID,
+			Revision:    previous.Revision,
+			Content:     previous.Content,
+			ContentType: string(previous.ContentType),
+			EditedBy:    previous.EditedBy,
+			EditedAt:    previous.EditedAt,
+		}).Error
+	})
+}
+
+// SetLinkPreviews stores the link previews generated for a message.
+// Previews are only stored if the message is still at the revision they were generated for.
+func (r *messageRepository) SetLinkPreviews(ctx context.Context, messageID uuid.UUID, editCount int, previews []message.LinkPreview) error {
+	previewsJSON, err := json.Marshal(previews)
+	if err != nil {
+		return err
+	}
+
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Where("id = ? AND edit_count = ? AND deleted_at IS NULL", messageID, editCount).
+		Update("link_previews", string(previewsJSON))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return message.ErrEditConflict
+	}
+
+	return nil
+}
+
+// GetRevisions returns prior revisions of a message, oldest first
+func (r *messageRepository) GetRevisions(ctx context.Context, messageID uuid.UUID) ([]message.MessageRevision, error) {
+	var dbRevisions []MessageRevision
+	result := r.db.WithContext(ctx).
+		Where("message_id = ?", messageID).
+		Order("revision ASC").
+		Find(&dbRevisions)
+
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	revisions := make([]message.MessageRevision, len(dbRevisions))
+	for i, rev := range dbRevisions {
+		revisions[i] = message.MessageRevision{
+			ID:          rev.ID,
+			MessageID:   rev.MessageID,
+			Revision:    rev.Revision,
+			Content:     rev.Content,
+			ContentType: message.ContentType(rev.ContentType),
+			EditedBy:    rev.EditedBy,
+			EditedAt:    rev.EditedAt,
+		}
+	}
+
+	return revisions, nil
+}
+
+// MarkDeleted stores the tombstone of a message deleted for everyone.
+// Reactions, mentions and edit history go with the content; pins and thread links are kept.
+func (r *messageRepository) MarkDeleted(ctx context.Context, m *message.Message) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Message{}).
+			Where("id = ? AND deleted_at IS NULL", m.ID).
+			Updates(map[string]interface{}{
+				"content":       m.Content,
+				"deleted_at":    m.DeletedAt,
+				"deleted_by":    m.DeletedBy,
+				"updated_at":    m.UpdatedAt,
+				"link_previews": nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return message.ErrMessageNotFound
+		}
+
+		if err := tx.Where("message_id = ?", m.ID).Delete(&MessageReaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("message_id = ?", m.ID).Delete(&MessageMention{}).Error; err != nil {
+			return err
+		}
+		if err := deletePoll(tx, m.ID); err != nil {
+			return err
+		}
+		return tx.Where("message_id = ?", m.ID).Delete(&MessageRevision{}).Error
+	})
+}
+
+// HideForUser hides a message from one user's history
+func (r *messageRepository) HideForUser(ctx context.Context, messageID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&MessageHide{MessageID: messageID, UserID: userID}).Error
+}
+
 // Delete soft deletes a message
 func (r *messageRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	result := r.db.WithContext(ctx).Delete(&Message{}, id)
@@ -139,10 +454,13 @@ func (r *messageRepository) UpdateStatus(ctx context.Context, messageID uuid.UUI
 	return nil
 }
 
-// CountByConversationID counts messages in a conversation
-func (r *messageRepository) CountByConversationID(ctx context.Context, conversationID uuid.UUID) (int64, error) {
+// CountByConversationID counts messages in a conversation, with the same hidden messages and thread replies left out as the history pages
+func (r *messageRepository) CountByConversationID(ctx context.Context, conversationID uuid.UUID, userID *uuid.UUID) (int64, error) {
 	var count int64
-	result := r.db.WithContext(ctx).Model(&Message{}).Where("conversation_id = ?", conversationID).Count(&count)
+	result := r.db.WithContext(ctx).Model(&Message{}).
+		Scopes(notHiddenFor(userID)).
+		Where("conversation_id = ? AND thread_id IS NULL", conversationID).
+		Count(&count)
 
 	if result.Error != nil {
 		return 0, result.Error
@@ -152,10 +470,12 @@ func (r *messageRepository) CountByConversationID(ctx context.Context, conversat
 }
 
 // CountUnreadByConversationID counts unread messages in a conversation for a specific user
-func (r *messageRepository) CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID) (int64, error) {
+func (r *messageRepository) CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID, readUpTo time.Time) (int64, error) {
 	var count int64
+	// The user's read marker decides what they have read, receipts may be missing for messages skipped over
 	result := r.db.WithContext(ctx).Model(&Message{}).
-		Where("conversation_id = ? AND sender_id != ? AND status != ?", conversationID, userID, string(message.StatusRead)).
+		Scopes(notHiddenFor(&userID)).
+		Where("conversation_id = ? AND sender_id != ? AND created_at > ? AND deleted_at IS NULL", conversationID, userID, readUpTo).
 		Count(&count)
 
 	if result.Error != nil {
@@ -350,47 +670,904 @@ func (r *messageRepository) GetForwardHistory(ctx context.Context, messageID uui
 
 // Search Messages (Day 13)
 
-func (r *messageRepository) SearchMessages(ctx context.Context, userID uuid.UUID, query string, conversationID *uuid.UUID, limit, offset int) ([]*message.Message, error) {
-	var models []Message
-
-	// Basic text search using ILIKE
-	searchQuery := "%" + query + "%"
-	q := r.db.WithContext(ctx).
+func (r *messageRepository) SearchMessages(ctx context.Context, query message.SearchQuery) ([]*message.SearchResult, int64, error) {
+	// Matches use the same expression as idx_messages_search so the GIN index applies
+	matches := r.db.WithContext(ctx).
 		Table("messages").
-		Select("messages.*").
 		Joins("INNER JOIN conversation_participants ON messages.conversation_id = conversation_participants.conversation_id").
-		Where("conversation_participants.user_id = ?", userID).
-		Where("CAST(messages.content AS TEXT) ILIKE ?", searchQuery)
+		Where("conversation_participants.user_id = ?", query.UserID).
+		Where("messages.deleted_at IS NULL").
+		Scopes(notHiddenFor(&query.UserID)).
+		Where(searchVector+" @@ "+searchTSQuery, query.Text)
+
+	if query.ConversationID != nil {
+		matches = matches.Where("messages.conversation_id = ?", *query.ConversationID)
+	}
+	if query.SenderID != nil {
+		matches = matches.Where("messages.sender_id = ?", *query.SenderID)
+	}
+	if query.ConversationType != "" {
+		matches = matches.
+			Joins("INNER JOIN conversations ON conversations.id = messages.conversation_id").
+			Where("conversations.type = ?", query.ConversationType)
+	}
+	if len(query.ContentTypes) > 0 {
+		contentTypes := make([]string, len(query.ContentTypes))
+		for i, ct := range query.ContentTypes {
+			contentTypes[i] = string(ct)
+		}
+		matches = matches.Where("messages.content_type IN ?", contentTypes)
+	}
+	if query.From != nil {
+		matches = matches.Where("messages.created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		matches = matches.Where("messages.created_at < ?", *query.To)
+	}
+	if query.HasReactions {
+		matches = matches.Where("EXISTS (SELECT 1 FROM message_reactions WHERE message_reactions.message_id = messages.id)")
+	}
+	if query.Pinned {
+		matches = matches.Where("EXISTS (SELECT 1 FROM pinned_messages WHERE pinned_messages.message_id = messages.id)")
+	}
+
+	var total int64
+	if err := matches.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	ranked := matches.Session(&gorm.Session{}).
+		Select("messages.*, ts_rank("+searchVector+", "+searchTSQuery+")::float8 AS rank", query.Text)
+
+	// Snippets are only generated for the rows on the returned page
+	q := r.db.WithContext(ctx).
+		Table("(?) AS ranked", ranked).
+		Select("ranked.*, ts_headline("+searchConfig+", ranked.content, "+searchTSQuery+", ?) AS snippet",
+			query.Text, searchHeadlineOptions)
+
+	if query.Cursor != nil {
+		q = q.Where("(ranked.rank, ranked.created_at, ranked.id) < (?, ?, ?)",
+			query.Cursor.Rank, query.Cursor.CreatedAt, query.Cursor.ID)
+	} else if query.Offset > 0 {
+		q = q.Offset(query.Offset)
+	}
+
+	q = q.Order("ranked.rank DESC, ranked.created_at DESC, ranked.id DESC")
+
+	if query.Limit > 0 {
+		q = q.Limit(query.Limit)
+	}
+
+	var rows []searchRow
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	results := make([]*message.SearchResult, len(rows))
+	for i := range rows {
+		results[i] = &message.SearchResult{
+			Message: toDomainMessage(&rows[i].Message),
+			Rank:    rows[i].Rank,
+			Snippet: rows[i].Snippet,
+		}
+	}
+
+	return results, total, nil
+}
+
+// Threads
+
+// FindThreadReplies finds replies in a thread, oldest first
+func (r *messageRepository) FindThreadReplies(ctx context.Context, rootID uuid.UUID, after *message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	q := r.db.WithContext(ctx).Scopes(notHiddenFor(userID)).Where("thread_id = ?", rootID)
+
+	if after != nil {
+		q = q.Where("(created_at, id) > (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	var dbMessages []Message
+	if err := q.Order("created_at ASC, id ASC").
+		Limit(limit).
+		Find(&dbMessages).Error; err != nil {
+		return nil, err
+	}
+
+	if len(dbMessages) == 0 {
+		return []*message.Message{}, nil
+	}
+
+	return r.enrichMessages(ctx, dbMessages, dbMessages[0].ConversationID, userID), nil
+}
+
+// GetThreadSummaries gets reply count and last reply for each thread root
+func (r *messageRepository) GetThreadSummaries(ctx context.Context, rootIDs []uuid.UUID) (map[uuid.UUID]message.ThreadSummary, error) {
+	summaries := make(map[uuid.UUID]message.ThreadSummary)
+	if len(rootIDs) == 0 {
+		return summaries, nil
+	}
+
+	var rows []struct {
+		ThreadID      uuid.UUID
+		ReplyCount    int
+		LastReplyID   uuid.UUID
+		LastReplyAt   time.Time
+		LastReplierID uuid.UUID
+	}
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (thread_id)
+			thread_id,
+			COUNT(*) OVER (PARTITION BY thread_id) AS reply_count,
+			id AS last_reply_id,
+			created_at AS last_reply_at,
+			sender_id AS last_replier_id
+		FROM messages
+		WHERE thread_id IN ? AND deleted_at IS NULL
+		ORDER BY thread_id, created_at DESC, id DESC
+	`, rootIDs).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		summaries[row.ThreadID] = message.ThreadSummary{
+			RootMessageID: row.ThreadID,
+			ReplyCount:    row.ReplyCount,
+			LastReplyID:   row.LastReplyID,
+			LastReplyAt:   row.LastReplyAt,
+			LastReplierID: row.LastReplierID,
+		}
+	}
+
+	return summaries, nil
+}
+
+// FollowThread subscribes a user to thread updates
+func (r *messageRepository) FollowThread(ctx context.Context, rootID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ThreadFollower{RootMessageID: rootID, UserID: userID}).Error
+}
+
+// UnfollowThread unsubscribes a user from thread updates
+func (r *messageRepository) UnfollowThread(ctx context.Context, rootID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("root_message_id = ? AND user_id = ?", rootID, userID).
+		Delete(&ThreadFollower{}).Error
+}
+
+// IsFollowingThread checks if a user follows a thread
+func (r *messageRepository) IsFollowingThread(ctx context.Context, rootID, userID uuid.UUID) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).
+		Model(&ThreadFollower{}).
+		Where("root_message_id = ? AND user_id = ?", rootID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetThreadFollowers gets the IDs of users following a thread
+func (r *messageRepository) GetThreadFollowers(ctx context.Context, rootID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	if err := r.db.WithContext(ctx).
+		Model(&ThreadFollower{}).
+		Where("root_message_id = ?", rootID).
+		Pluck("user_id", &userIDs).Error; err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+// Mentions
+
+// ReplaceMentions replaces all mentions of a message
+func (r *messageRepository) ReplaceMentions(ctx context.Context, messageID uuid.UUID, mentions []message.MessageMention) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("message_id = ?", messageID).Delete(&MessageMention{}).Error; err != nil {
+			return err
+		}
+
+		if len(mentions) == 0 {
+			return nil
+		}
+
+		models := make([]MessageMention, len(mentions))
+		for i, m := range mentions {
+			models[i] = MessageMention{
+				ID:             m.ID,
+				MessageID:      messageID,
+				ConversationID: m.ConversationID,
+				UserID:         m.UserID,
+				Position:       m.Position,
+				Length:         m.Length,
+				ReadAt:         m.ReadAt,
+				CreatedAt:      m.CreatedAt,
+			}
+		}
+
+		return tx.Create(&models).Error
+	})
+}
+
+// GetMentionsByMessageID gets all mentions in a message
+func (r *messageRepository) GetMentionsByMessageID(ctx context.Context, messageID uuid.UUID) ([]message.MessageMention, error) {
+	var models []MessageMention
+	if err := r.db.WithContext(ctx).
+		Where("message_id = ?", messageID).
+		Order("position ASC").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainMentions(models), nil
+}
+
+// GetMentionsForUser gets mentions of a user, newest first.
+// A message mentioning the user more than once is listed once, at its first mention
+func (r *messageRepository) GetMentionsForUser(ctx context.Context, userID uuid.UUID, conversationID *uuid.UUID, unreadOnly bool, limit, offset int) ([]message.MessageMention, error) {
+	q := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where(`NOT EXISTS (
+			SELECT 1 FROM message_mentions earlier
+			WHERE earlier.message_id = message_mentions.message_id
+				AND earlier.user_id = message_mentions.user_id
+				AND earlier.position < message_mentions.position
+		)`)
+
+	if conversationID != nil {
+		q = q.Where("conversation_id = ?", *conversationID)
+	}
+	if unreadOnly {
+		q = q.Where("read_at IS NULL")
+	}
+
+	var models []MessageMention
+	if err := q.Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	return toDomainMentions(models), nil
+}
+
+// CountUnreadMentions counts the messages with unread mentions of a user grouped by conversation
+func (r *messageRepository) CountUnreadMentions(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	var rows []struct {
+		ConversationID uuid.UUID
+		Count          int64
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&MessageMention{}).
+		Select("conversation_id, COUNT(DISTINCT message_id) AS count").
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Group("conversation_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		counts[row.ConversationID] = row.Count
+	}
+
+	return counts, nil
+}
+
+// MarkMentionsRead marks the mentions of a user in a conversation as read, up to messages created at readUpTo
+func (r *messageRepository) MarkMentionsRead(ctx context.Context, userID, conversationID uuid.UUID, readUpTo time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&MessageMention{}).
+		Where("user_id = ? AND conversation_id = ? AND read_at IS NULL", userID, conversationID).
+		Where("message_id IN (SELECT id FROM messages WHERE conversation_id = ? AND created_at <= ?)", conversationID, readUpTo).
+		Update("read_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+}
+
+// toDomainMentions converts GORM MessageMention models to domain mentions
+func toDomainMentions(models []MessageMention) []message.MessageMention {
+	result := make([]message.MessageMention, len(models))
+	for i, m := range models {
+		result[i] = message.MessageMention{
+			ID:             m.ID,
+			MessageID:      m.MessageID,
+			ConversationID: m.ConversationID,
+			UserID:         m.UserID,
+			Position:       m.Position,
+			Length:         m.Length,
+			ReadAt:         m.ReadAt,
+			CreatedAt:      m.CreatedAt,
+		}
+	}
+	return result
+}
+
+// Receipts
+
+// MarkReceiptDelivered records a delivery receipt, keeping the first delivery time
+func (r *messageRepository) MarkReceiptDelivered(ctx context.Context, messageID, userID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO message_receipts (message_id, user_id, delivered_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (message_id, user_id) DO UPDATE
+		SET delivered_at = COALESCE(message_receipts.delivered_at, EXCLUDED.delivered_at)
+	`, messageID, userID, at).Error
+}
+
+// MarkReceiptRead records a read receipt, keeping the first read time and whether it was hidden
+func (r *messageRepository) MarkReceiptRead(ctx context.Context, messageID, userID uuid.UUID, at time.Time, hidden bool) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO message_receipts (message_id, user_id, delivered_at, read_at, read_hidden)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (message_id, user_id) DO UPDATE
+		SET delivered_at = COALESCE(message_receipts.delivered_at, EXCLUDED.delivered_at),
+			read_hidden = CASE WHEN message_receipts.read_at IS NULL THEN EXCLUDED.read_hidden ELSE message_receipts.read_hidden END,
+			read_at = COALESCE(message_receipts.read_at, EXCLUDED.read_at)
+	`, messageID, userID, at, at, hidden).Error
+}
+
+// MarkConversationRead records read receipts for the messages in a conversation the user has not read yet, up to readUpTo
+func (r *messageRepository) MarkConversationRead(ctx context.Context, conversationID, userID uuid.UUID, readUpTo, at time.Time, hidden bool) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO message_receipts (message_id, user_id, delivered_at, read_at, read_hidden)
+		SELECT m.id, ?, ?, ?, ?
+		FROM messages m
+		WHERE m.conversation_id = ?
+			AND m.sender_id <> ?
+			AND m.created_at <= ?
+			AND NOT EXISTS (
+				SELECT 1 FROM message_receipts r
+				WHERE r.message_id = m.id AND r.user_id = ? AND r.read_at IS NOT NULL
+			)
+		ON CONFLICT (message_id, user_id) DO UPDATE
+		SET delivered_at = COALESCE(message_receipts.delivered_at, EXCLUDED.delivered_at),
+			read_hidden = EXCLUDED.read_hidden,
+			read_at = EXCLUDED.read_at
+	`, userID, at, at, hidden, conversationID, userID, readUpTo, userID).Error
+}
+
+// GetReceipts gets the receipts of a message, most recently read first
+func (r *messageRepository) GetReceipts(ctx context.Context, messageID uuid.UUID) ([]message.Receipt, error) {
+	var models []MessageReceipt
+	if err := r.db.WithContext(ctx).
+		Where("message_id = ?", messageID).
+		Order("read_at DESC NULLS LAST, delivered_at DESC NULLS LAST").
+		Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	receipts := make([]message.Receipt, len(models))
+	for i, m := range models {
+		receipts[i] = message.Receipt{
+			MessageID:   m.MessageID,
+			UserID:      m.UserID,
+			DeliveredAt: m.DeliveredAt,
+			ReadAt:      m.ReadAt,
+			ReadHidden:  m.ReadHidden,
+		}
+	}
+
+	return receipts, nil
+}
+
+// GetReceiptCounts counts delivery and read receipts for each message
+func (r *messageRepository) GetReceiptCounts(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID]message.ReceiptCounts, error) {
+	counts := make(map[uuid.UUID]message.ReceiptCounts)
+	if len(messageIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		MessageID      uuid.UUID
+		DeliveredCount int
+		ReadCount      int
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&MessageReceipt{}).
+		Select("message_id, COUNT(delivered_at) AS delivered_count, COUNT(read_at) FILTER (WHERE NOT read_hidden) AS read_count").
+		Where("message_id IN ?", messageIDs).
+		Group("message_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.MessageID] = message.ReceiptCounts{
+			Delivered: row.DeliveredCount,
+			Read:      row.ReadCount,
+		}
+	}
 
+	return counts, nil
+}
+
+// SyncReceiptStatus marks messages read, or delivered, once enough recipients have receipts for them.
+// Hidden reads still count as deliveries
+func (r *messageRepository) SyncReceiptStatus(ctx context.Context, conversationID uuid.UUID, messageID *uuid.UUID, recipients int) error {
+	if recipients <= 0 {
+		return nil
+	}
+
+	advance := func(status message.Status, receipted string, from []string) error {
+		query := r.db.WithContext(ctx).Model(&Message{}).
+			Where("conversation_id = ? AND status IN ?", conversationID, from).
+			Where("(SELECT COUNT(*) FROM message_receipts r WHERE r.message_id = messages.id AND "+receipted+") >= ?", recipients)
+		if messageID != nil {
+			query = query.Where("id = ?", *messageID)
+		}
+		return query.Updates(map[string]interface{}{
+			"status":     string(status),
+			"updated_at": gorm.Expr("CURRENT_TIMESTAMP"),
+		}).Error
+	}
+
+	if err := advance(message.StatusRead, "r.read_at IS NOT NULL AND NOT r.read_hidden", []string{string(message.StatusSent), string(message.StatusDelivered)}); err != nil {
+		return err
+	}
+	return advance(message.StatusDelivered, "r.delivered_at IS NOT NULL", []string{string(message.StatusSent)})
+}
+
+// Disappearing Messages
+
+// CreateScheduled stores a new scheduled message
+func (r *messageRepository) CreateScheduled(ctx context.Context, scheduled *message.ScheduledMessage) error {
+	return r.db.WithContext(ctx).Create(toScheduledMessageModel(scheduled)).Error
+}
+
+// FindScheduledByID finds a scheduled message by ID
+func (r *messageRepository) FindScheduledByID(ctx context.Context, id uuid.UUID) (*message.ScheduledMessage, error) {
+	var model ScheduledMessage
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&model).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, message.ErrScheduledMessageNotFound
+		}
+		return nil, err
+	}
+
+	return toDomainScheduledMessage(&model), nil
+}
+
+// FindScheduledBySender returns a sender's scheduled messages, soonest first
+func (r *messageRepository) FindScheduledBySender(ctx context.Context, senderID uuid.UUID, conversationID *uuid.UUID, statuses []message.ScheduledStatus, limit, offset int) ([]*message.ScheduledMessage, error) {
+	q := r.db.WithContext(ctx).Where("sender_id = ?", senderID)
 	if conversationID != nil {
-		q = q.Where("messages.conversation_id = ?", *conversationID)
+		q = q.Where("conversation_id = ?", *conversationID)
+	}
+	if len(statuses) > 0 {
+		values := make([]string, len(statuses))
+		for i, status := range statuses {
+			values[i] = string(status)
+		}
+		q = q.Where("status IN ?", values)
+	}
+
+	var models []ScheduledMessage
+	if err := q.Order("send_at ASC, id ASC").Limit(limit).Offset(offset).Find(&models).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]*message.ScheduledMessage, len(models))
+	for i := range models {
+		result[i] = toDomainScheduledMessage(&models[i])
 	}
 
-	// Only show messages from conversations user is part of
-	// Implemented via INNER JOIN with conversation_participants table above
+	return result, nil
+}
 
-	q = q.Order("messages.created_at DESC")
+// UpdateScheduled saves the content and send time of a pending scheduled message
+func (r *messageRepository) UpdateScheduled(ctx context.Context, scheduled *message.ScheduledMessage) error {
+	result := r.db.WithContext(ctx).Model(&ScheduledMessage{}).
+		Where("id = ? AND status = ?", scheduled.ID, message.ScheduledPending).
+		Updates(map[string]interface{}{
+			"content":      scheduled.Content,
+			"content_type": string(scheduled.ContentType),
+			"reply_to_id":  scheduled.ReplyToID,
+			"send_at":      scheduled.SendAt,
+			"updated_at":   time.Now(),
+		})
+
+	return r.scheduledTransitionResult(ctx, result, scheduled.ID)
+}
+
+// CancelScheduled cancels a pending scheduled message
+func (r *messageRepository) CancelScheduled(ctx context.Context, id uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&ScheduledMessage{}).
+		Where("id = ? AND status = ?", id, message.ScheduledPending).
+		Updates(map[string]interface{}{
+			"status":     string(message.ScheduledCanceled),
+			"updated_at": time.Now(),
+		})
+
+	return r.scheduledTransitionResult(ctx, result, id)
+}
 
-	if limit > 0 {
-		q = q.Limit(limit)
+// ClaimDueScheduled leases due scheduled messages to the calling dispatcher.
+// FOR UPDATE SKIP LOCKED keeps concurrent dispatchers from claiming the same rows,
+// and the lease lets another dispatcher pick a message up if its claimer died mid-send.
+func (r *messageRepository) ClaimDueScheduled(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*message.ScheduledMessage, error) {
+	var models []ScheduledMessage
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE scheduled_messages
+		SET status = ?, attempts = attempts + 1, claimed_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM scheduled_messages
+			WHERE (status = ? AND send_at <= ?) OR (status = ? AND claimed_until <= ?)
+			ORDER BY send_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		message.ScheduledSending, now.Add(lease), now,
+		message.ScheduledPending, now, message.ScheduledSending, now,
+		limit,
+	).Scan(&models).Error
+	if err != nil {
+		return nil, err
 	}
 
-	if offset > 0 {
-		q = q.Offset(offset)
+	result := make([]*message.ScheduledMessage, len(models))
+	for i := range models {
+		result[i] = toDomainScheduledMessage(&models[i])
 	}
 
-	if err := q.Find(&models).Error; err != nil {
+	return result, nil
+}
+
+// MarkScheduledSent records that a claimed scheduled message was delivered
+func (r *messageRepository) MarkScheduledSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&ScheduledMessage{}).
+		Where("id = ? AND status = ?", id, message.ScheduledSending).
+		Updates(map[string]interface{}{
+			"status":        string(message.ScheduledSent),
+			"sent_at":       sentAt,
+			"claimed_until": nil,
+			"last_error":    "",
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// MarkScheduledFailed records that a claimed scheduled message will not be delivered
+func (r *messageRepository) MarkScheduledFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&ScheduledMessage{}).
+		Where("id = ? AND status = ?", id, message.ScheduledSending).
+		Updates(map[string]interface{}{
+			"status":        string(message.ScheduledFailed),
+			"claimed_until": nil,
+			"last_error":    reason,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// ReleaseScheduled returns a claimed scheduled message to pending for another attempt
+func (r *messageRepository) ReleaseScheduled(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.db.WithContext(ctx).Model(&ScheduledMessage{}).
+		Where("id = ? AND status = ?", id, message.ScheduledSending).
+		Updates(map[string]interface{}{
+			"status":        string(message.ScheduledPending),
+			"claimed_until": nil,
+			"last_error":    reason,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+// scheduledTransitionResult tells a missing scheduled message apart from one that is no longer pending
+func (r *messageRepository) scheduledTransitionResult(ctx context.Context, result *gorm.DB, id uuid.UUID) error {
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&ScheduledMessage{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return message.ErrScheduledMessageNotFound
+	}
+	return message.ErrScheduledNotPending
+}
+
+// GetPolls returns the polls of the given messages with their options.
+// Voters are only loaded for public polls; userID's own choices are loaded into Voted.
+func (r *messageRepository) GetPolls(ctx context.Context, messageIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID]*message.Poll, error) {
+	polls := make(map[uuid.UUID]*message.Poll)
+	if len(messageIDs) == 0 {
+		return polls, nil
+	}
+
+	var dbPolls []Poll
+	if err := r.db.WithContext(ctx).Where("message_id IN ?", messageIDs).Find(&dbPolls).Error; err != nil {
+		return nil, err
+	}
+	if len(dbPolls) == 0 {
+		return polls, nil
+	}
+
+	pollIDs := make([]uuid.UUID, len(dbPolls))
+	publicIDs := make([]uuid.UUID, 0, len(dbPolls))
+	for i := range dbPolls {
+		pollIDs[i] = dbPolls[i].MessageID
+		if !dbPolls[i].Anonymous {
+			publicIDs = append(publicIDs, dbPolls[i].MessageID)
+		}
+	}
+
+	var dbOptions []PollOption
+	if err := r.db.WithContext(ctx).
+		Where("message_id IN ?", pollIDs).
+		Order("message_id, position ASC").
+		Find(&dbOptions).Error; err != nil {
+		return nil, err
+	}
+	options := make(map[uuid.UUID][]PollOption, len(dbPolls))
+	for _, option := range dbOptions {
+		options[option.MessageID] = append(options[option.MessageID], option)
+	}
+
+	for i := range dbPolls {
+		poll := toDomainPoll(&dbPolls[i], options[dbPolls[i].MessageID])
+		if userID != nil {
+			poll.Voted = []int{}
+		}
+		polls[poll.MessageID] = poll
+	}
+
+	if len(publicIDs) > 0 {
+		var votes []PollVote
+		if err := r.db.WithContext(ctx).
+			Where("message_id IN ?", publicIDs).
+			Order("created_at ASC").
+			Find(&votes).Error; err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			poll := polls[vote.MessageID]
+			if vote.Position < len(poll.Options) {
+				poll.Options[vote.Position].Voters = append(poll.Options[vote.Position].Voters, vote.UserID)
+			}
+		}
+	}
+
+	if userID != nil {
+		var votes []PollVote
+		if err := r.db.WithContext(ctx).
+			Where("message_id IN ? AND user_id = ?", pollIDs, *userID).
+			Order("position ASC").
+			Find(&votes).Error; err != nil {
+			return nil, err
+		}
+		for _, vote := range votes {
+			polls[vote.MessageID].Voted = append(polls[vote.MessageID].Voted, vote.Position)
+		}
+	}
+
+	return polls, nil
+}
+
+// SetPollVotes replaces a user's votes. The poll row is locked so concurrent votes keep the counts exact.
+func (r *messageRepository) SetPollVotes(ctx context.Context, messageID, userID uuid.UUID, positions []int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dbPoll, err := lockOpenPoll(tx, messageID)
+		if err != nil {
+			return err
+		}
+
+		var previous []int
+		if err := tx.Model(&PollVote{}).
+			Where("message_id = ? AND user_id = ?", messageID, userID).
+			Pluck("position", &previous).Error; err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			if dbPoll.Quiz {
+				return message.ErrPollVoteFinal
+			}
+			if err := tx.Where("message_id = ? AND user_id = ?", messageID, userID).Delete(&PollVote{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Model(&PollOption{}).
+				Where("message_id = ? AND position IN ?", messageID, previous).
+				Update("vote_count", gorm.Expr("vote_count - 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		votes := make([]PollVote, len(positions))
+		for i, position := range positions {
+			votes[i] = PollVote{MessageID: messageID, UserID: userID, Position: position, CreatedAt: now}
+		}
+		if err := tx.Create(&votes).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&PollOption{}).
+			Where("message_id = ? AND position IN ?", messageID, positions).
+			Update("vote_count", gorm.Expr("vote_count + 1")).Error; err != nil {
+			return err
+		}
+
+		if len(previous) > 0 {
+			return nil
+		}
+		return tx.Model(&Poll{}).
+			Where("message_id = ?", messageID).
+			Update("total_voters", gorm.Expr("total_voters + 1")).Error
+	})
+}
+
+// RetractPollVotes removes a user's votes from an open poll
+func (r *messageRepository) RetractPollVotes(ctx context.Context, messageID, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dbPoll, err := lockOpenPoll(tx, messageID)
+		if err != nil {
+			return err
+		}
+
+		var previous []int
+		if err := tx.Model(&PollVote{}).
+			Where("message_id = ? AND user_id = ?", messageID, userID).
+			Pluck("position", &previous).Error; err != nil {
+			return err
+		}
+		if len(previous) == 0 {
+			return message.ErrPollVoteNotFound
+		}
+		if dbPoll.Quiz {
+			return message.ErrPollVoteFinal
+		}
+
+		if err := tx.Where("message_id = ? AND user_id = ?", messageID, userID).Delete(&PollVote{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&PollOption{}).
+			Where("message_id = ? AND position IN ?", messageID, previous).
+			Update("vote_count", gorm.Expr("vote_count - 1")).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Poll{}).
+			Where("message_id = ?", messageID).
+			Update("total_voters", gorm.Expr("total_voters - 1")).Error
+	})
+}
+
+// ClosePoll ends voting on a poll that is still open
+func (r *messageRepository) ClosePoll(ctx context.Context, messageID uuid.UUID, closedAt time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := lockOpenPoll(tx, messageID); err != nil {
+			return err
+		}
+		return tx.Model(&Poll{}).Where("message_id = ?", messageID).Update("closed_at", closedAt).Error
+	})
+}
+
+// lockOpenPoll locks a poll row for the rest of the transaction, failing if voting ended
+func lockOpenPoll(tx *gorm.DB, messageID uuid.UUID) (*Poll, error) {
+	var dbPoll Poll
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("message_id = ?", messageID).
+		First(&dbPoll).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, message.ErrNotAPoll
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if toDomainPoll(&dbPoll, nil).IsClosed() {
+		return nil, message.ErrPollClosed
+	}
+	return &dbPoll, nil
+}
+
+// deletePoll removes the poll of a message with its options and votes
+func deletePoll(tx *gorm.DB, messageID uuid.UUID) error {
+	if err := tx.Where("message_id = ?", messageID).Delete(&PollVote{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("message_id = ?", messageID).Delete(&PollOption{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("message_id = ?", messageID).Delete(&Poll{}).Error
+}
+
+// DeleteExpired permanently deletes up to limit messages whose expiry time is before the given time
+// and returns them. FOR UPDATE SKIP LOCKED keeps concurrent workers from picking the same rows.
+func (r *messageRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) ([]*message.Message, error) {
+	var models []Message
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`
+			SELECT * FROM messages
+			WHERE expires_at IS NOT NULL AND expires_at <= ?
+			ORDER BY expires_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED`,
+			before, limit,
+		).Scan(&models).Error; err != nil {
+			return err
+		}
+		if len(models) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, len(models))
+		for i, m := range models {
+			ids[i] = m.ID
+		}
+		return hardDeleteMessages(tx, ids)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	result := make([]*message.Message, len(models))
-	for i, m := range models {
-		result[i] = toDomainMessage(&m)
+	for i := range models {
+		result[i] = toDomainMessage(&models[i])
 	}
 
 	return result, nil
 }
 
+// hardDeleteMessages permanently deletes messages with everything attached to them,
+// and clears or repoints what other rows referenced them
+func hardDeleteMessages(tx *gorm.DB, ids []uuid.UUID) error {
+	for _, model := range []interface{}{
+		&MessageReaction{}, &PinnedMessage{}, &MessageMention{}, &MessageRevision{},
+		&MessageHide{}, &MessageReceipt{}, &PollVote{}, &PollOption{}, &Poll{},
+	} {
+		if err := tx.Where("message_id IN ?", ids).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("new_message_id IN ? OR original_message_id IN ?", ids, ids).Delete(&ForwardedMessage{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("root_message_id IN ?", ids).Delete(&ThreadFollower{}).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("id IN ?", ids).Delete(&Message{}).Error; err != nil {
+		return err
+	}
+
+	// Replies keep their content, without the quote or thread they pointed at
+	if err := tx.Model(&Message{}).Where("reply_to_id IN ?", ids).Update("reply_to_id", nil).Error; err != nil {
+		return err
+	}
+	if err := tx.Model(&Message{}).Where("thread_id IN ?", ids).Update("thread_id", nil).Error; err != nil {
+		return err
+	}
+
+	// Read markers move to the latest remaining message they cover
+	if err := tx.Exec(`
+		UPDATE conversation_participants
+		SET last_read_message_id = (
+			SELECT m.id FROM messages m
+			WHERE m.conversation_id = conversation_participants.conversation_id
+			AND m.created_at <= conversation_participants.last_read_at
+			ORDER BY m.created_at DESC, m.id DESC
+			LIMIT 1
+		)
+		WHERE last_read_message_id IN ?
+	`, ids).Error; err != nil {
+		return err
+	}
+
+	// Point conversations at the latest remaining message
+	return tx.Exec(`
+		UPDATE conversations
+		SET last_message_id = (
+			SELECT m.id FROM messages m
+			WHERE m.conversation_id = conversations.id
+			ORDER BY m.created_at DESC
+			LIMIT 1
+		)
+		WHERE last_message_id IN ?
+	`, ids).Error
+}
+
 // getMessageReactions fetches reactions for a message
 func (r *messageRepository) getMessageReactions(ctx context.Context, messageID uuid.UUID) (map[string][]string, error) {
 	var reactions []struct {
@@ -463,7 +1640,7 @@ func (r *messageRepository) isMessagePinned(ctx context.Context, messageID, conv
 
 // toMessageModel converts domain Message to GORM Message model
 func toMessageModel(m *message.Message) *Message {
-	return &Message{
+	model := &Message{
 		ID:             m.ID,
 		ConversationID: m.ConversationID,
 		SenderID:       m.SenderID,
@@ -474,12 +1651,30 @@ func toMessageModel(m *message.Message) *Message {
 		Status:         string(m.Status),
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
+		ExpiresAt:      m.ExpiresAt,
+		ThreadID:       m.ThreadID,
+		EditedAt:       m.EditedAt,
+		EditCount:      m.EditCount,
+		DeletedAt:      m.DeletedAt,
+		DeletedBy:      m.DeletedBy,
 	}
+	if m.ClientMessageID != "" {
+		clientMessageID := m.ClientMessageID
+		model.ClientMessageID = &clientMessageID
+	}
+	if len(m.LinkPreviews) > 0 {
+		if previewsJSON, err := json.Marshal(m.LinkPreviews); err == nil {
+			linkPreviews := string(previewsJSON)
+			model.LinkPreviews = &linkPreviews
+		}
+	}
+
+	return model
 }
 
 // toDomainMessage converts GORM Message model to domain Message
 func toDomainMessage(m *Message) *message.Message {
-	return &message.Message{
+	msg := &message.Message{
 		ID:             m.ID,
 		ConversationID: m.ConversationID,
 		SenderID:       m.SenderID,
@@ -490,5 +1685,111 @@ func toDomainMessage(m *Message) *message.Message {
 		Status:         message.Status(m.Status),
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
+		ExpiresAt:      m.ExpiresAt,
+		ThreadID:       m.ThreadID,
+		EditedAt:       m.EditedAt,
+		EditCount:      m.EditCount,
+		DeletedAt:      m.DeletedAt,
+		DeletedBy:      m.DeletedBy,
+	}
+	if m.ClientMessageID != nil {
+		msg.ClientMessageID = *m.ClientMessageID
+	}
+	if m.LinkPreviews != nil {
+		json.Unmarshal([]byte(*m.LinkPreviews), &msg.LinkPreviews)
+	}
+
+	return msg
+}
+
+// toPollModels converts a domain poll to its GORM poll and option models
+func toPollModels(messageID uuid.UUID, p *message.Poll) (*Poll, []PollOption) {
+	options := make([]PollOption, len(p.Options))
+	for i, option := range p.Options {
+		options[i] = PollOption{
+			MessageID: messageID,
+			Position:  option.Position,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+		}
+	}
+
+	return &Poll{
+		MessageID:      messageID,
+		Question:       p.Question,
+		MultipleChoice: p.MultipleChoice,
+		Anonymous:      p.Anonymous,
+		Quiz:           p.Quiz,
+		CorrectOption:  p.CorrectOption,
+		ClosesAt:       p.ClosesAt,
+		ClosedAt:       p.ClosedAt,
+		TotalVoters:    p.TotalVoters,
+		CreatedAt:      p.CreatedAt,
+	}, options
+}
+
+// toDomainPoll converts GORM poll and option models to a domain poll
+func toDomainPoll(p *Poll, options []PollOption) *message.Poll {
+	poll := &message.Poll{
+		MessageID:      p.MessageID,
+		Question:       p.Question,
+		MultipleChoice: p.MultipleChoice,
+		Anonymous:      p.Anonymous,
+		Quiz:           p.Quiz,
+		CorrectOption:  p.CorrectOption,
+		ClosesAt:       p.ClosesAt,
+		ClosedAt:       p.ClosedAt,
+		TotalVoters:    p.TotalVoters,
+		CreatedAt:      p.CreatedAt,
+		Options:        make([]message.PollOption, len(options)),
+	}
+	for i, option := range options {
+		poll.Options[i] = message.PollOption{
+			Position:  option.Position,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+		}
+	}
+
+	return poll
+}
+
+// toScheduledMessageModel converts domain scheduled message to GORM model
+func toScheduledMessageModel(s *message.ScheduledMessage) *ScheduledMessage {
+	return &ScheduledMessage{
+		ID:             s.ID,
+		ConversationID: s.ConversationID,
+		SenderID:       s.SenderID,
+		Content:        s.Content,
+		ContentType:    string(s.ContentType),
+		ReplyToID:      s.ReplyToID,
+		SendAt:         s.SendAt,
+		Status:         string(s.Status),
+		Attempts:       s.Attempts,
+		ClaimedUntil:   s.ClaimedUntil,
+		SentAt:         s.SentAt,
+		LastError:      s.LastError,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+// toDomainScheduledMessage converts GORM model to domain scheduled message
+func toDomainScheduledMessage(s *ScheduledMessage) *message.ScheduledMessage {
+	return &message.ScheduledMessage{
+		ID:             s.ID,
+		ConversationID: s.ConversationID,
+		SenderID:       s.SenderID,
+		Content:        s.Content,
+		ContentType:    message.ContentType(s.ContentType),
+		ReplyToID:      s.ReplyToID,
+		SendAt:         s.SendAt,
+		Status:         message.ScheduledStatus(s.Status),
+		Attempts:       s.Attempts,
+		ClaimedUntil:   s.ClaimedUntil,
+		SentAt:         s.SentAt,
+		LastError:      s.LastError,
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
 	}
 }
@@ -102,12 +102,16 @@ func (c *Conversation) BeforeUpdate(tx *gorm.DB) error {
 
 // ConversationParticipant is the GORM model for conversation_participants table
 type ConversationParticipant struct {
-	ConversationID uuid.UUID  `gorm:"type:uuid;primaryKey;not null"`
-	UserID         uuid.UUID  `gorm:"type:uuid;primaryKey;not null"`
-	Role           string     `gorm:"type:varchar(20);default:'member'"`
-	JoinedAt       time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
-	LastReadAt     *time.Time `gorm:"type:timestamp"`
-	ArchivedAt     *time.Time `gorm:"type:timestamp"` // For archiving conversations per user
+	ConversationID    uuid.UUID  `gorm:"type:uuid;primaryKey;not null"`
+	UserID            uuid.UUID  `gorm:"type:uuid;primaryKey;not null"`
+	Role              string     `gorm:"type:varchar(20);default:'member'"`
+	JoinedAt          time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	LastReadAt        *time.Time `gorm:"type:timestamp"` // Read marker: created time of the last read message
+	LastReadMessageID *uuid.UUID `gorm:"type:uuid"`
+	ArchivedAt        *time.Time `gorm:"type:timestamp"` // For archiving conversations per user
+	RequestedAt       *time.Time `gorm:"type:timestamp"` // Set while the conversation waits in this user's message requests
+	PinOrder          *int       `gorm:"type:int"`       // Highest first, NULL when not pinned
+	MutedUntil        *time.Time `gorm:"type:timestamp"`
 }
 
 // TableName specifies the table name for ConversationParticipant model
@@ -115,18 +119,53 @@ func (ConversationParticipant) TableName() string {
 	return "conversation_participants"
 }
 
+// ConversationFolder is the GORM model for conversation_folders table
+type ConversationFolder struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_conversation_folders_user_name,priority:1"`
+	Name      string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_conversation_folders_user_name,priority:2"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for ConversationFolder model
+func (ConversationFolder) TableName() string {
+	return "conversation_folders"
+}
+
+// ConversationFolderItem is the GORM model for conversation_folder_items table
+type ConversationFolderItem struct {
+	FolderID       uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
+	ConversationID uuid.UUID `gorm:"type:uuid;primaryKey;not null;index"`
+	CreatedAt      time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for ConversationFolderItem model
+func (ConversationFolderItem) TableName() string {
+	return "conversation_folder_items"
+}
+
 // Message is the GORM model for messages table
 type Message struct {
-	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	ConversationID uuid.UUID  `gorm:"type:uuid;not null;index:idx_messages_conversation"`
-	SenderID       uuid.UUID  `gorm:"type:uuid;not null;index"`
-	Content        string     `gorm:"type:text;not null"`
-	ContentType    string     `gorm:"type:varchar(20);not null"`
-	Signature      string     `gorm:"type:text"`
-	ReplyToID      *uuid.UUID `gorm:"type:uuid"`
-	Status         string     `gorm:"type:varchar(20);default:'sending'"`
-	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index:idx_messages_conversation"`
-	UpdatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid();index:idx_messages_conversation_cursor,priority:3;index:idx_messages_thread,priority:3"`
+	ConversationID  uuid.UUID  `gorm:"type:uuid;not null;index:idx_messages_conversation;index:idx_messages_conversation_cursor,priority:1"`
+	SenderID        uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_messages_sender_client_id,priority:1,where:client_message_id IS NOT NULL"`
+	Content         string     `gorm:"type:text;not null"`
+	ContentType     string     `gorm:"type:varchar(20);not null"`
+	Signature       string     `gorm:"type:text"`
+	ReplyToID       *uuid.UUID `gorm:"type:uuid"`
+	Status          string     `gorm:"type:varchar(20);default:'sending'"`
+	CreatedAt       time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;index:idx_messages_conversation;index:idx_messages_conversation_cursor,priority:2;index:idx_messages_thread,priority:2"`
+	UpdatedAt       time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ExpiresAt       *time.Time `gorm:"type:timestamp;index:idx_messages_expires_at,where:expires_at IS NOT NULL"` // Disappearing messages
+	ThreadID        *uuid.UUID `gorm:"type:uuid;index:idx_messages_thread,priority:1"`                            // Thread root message
+	EditedAt        *time.Time `gorm:"type:timestamp"`
+	EditCount       int        `gorm:"type:int;not null;default:0"`
+	DeletedAt       *time.Time `gorm:"type:timestamp"` // Tombstone of a message deleted for everyone
+	DeletedBy       *uuid.UUID `gorm:"type:uuid"`
+	ClientMessageID *string    `gorm:"type:varchar(64);uniqueIndex:idx_messages_sender_client_id,priority:2"` // Dedupes retried sends per sender
+	// JSON for link previews, nil until generated
+	LinkPreviews *string `gorm:"type:jsonb"`
 }
 
 // TableName specifies the table name for Message model
@@ -436,6 +475,7 @@ type PrivacySettings struct {
 	StatusVisibility       string    `gorm:"type:varchar(20);not null;default:'everyone'"`
 	ReadReceiptsEnabled    bool      `gorm:"type:boolean;not null;default:true"`
 	TypingIndicatorEnabled bool      `gorm:"type:boolean;not null;default:true"`
+	WhoCanMessage          string    `gorm:"type:varchar(20);not null;default:'everyone'"`
 	CreatedAt              time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 	UpdatedAt              time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
@@ -635,12 +675,14 @@ func (f *ForwardedMessage) BeforeCreate(tx *gorm.DB) error {
 
 // MessageMention is the GORM model for message_mentions table (Day 13)
 type MessageMention struct {
-	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	MessageID uuid.UUID `gorm:"type:uuid;not null;index:idx_mention_message"`
-	UserID    uuid.UUID `gorm:"type:uuid;not null;index:idx_mention_user"`
-	Position  int       `gorm:"type:int;not null"`
-	Length    int       `gorm:"type:int;not null"`
-	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MessageID      uuid.UUID  `gorm:"type:uuid;not null;index:idx_mention_message"`
+	ConversationID uuid.UUID  `gorm:"type:uuid;not null;index:idx_mention_user_conversation,priority:2"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index:idx_mention_user;index:idx_mention_user_conversation,priority:1"`
+	Position       int        `gorm:"type:int;not null"`
+	Length         int        `gorm:"type:int;not null"`
+	ReadAt         *time.Time `gorm:"type:timestamp"`
+	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
 }
 
 // TableName specifies the table name for MessageMention model
@@ -659,6 +701,144 @@ func (m *MessageMention) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// ThreadFollower is the GORM model for thread_followers table
+type ThreadFollower struct {
+	RootMessageID uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
+	UserID        uuid.UUID `gorm:"type:uuid;primaryKey;not null;index"`
+	CreatedAt     time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for ThreadFollower model
+func (ThreadFollower) TableName() string {
+	return "thread_followers"
+}
+
+// MessageRevision is the GORM model for message_revisions table
+type MessageRevision struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MessageID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_revision_message,priority:1"`
+	Revision    int       `gorm:"type:int;not null;uniqueIndex:idx_revision_message,priority:2"`
+	Content     string    `gorm:"type:text;not null"`
+	ContentType string    `gorm:"type:varchar(20);not null"`
+	EditedBy    uuid.UUID `gorm:"type:uuid;not null"`
+	EditedAt    time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for MessageRevision model
+func (MessageRevision) TableName() string {
+	return "message_revisions"
+}
+
+// MessageHide is the GORM model for message_hides table (messages deleted for one user)
+type MessageHide struct {
+	MessageID uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;not null;index"`
+	HiddenAt  time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for MessageHide model
+func (MessageHide) TableName() string {
+	return "message_hides"
+}
+
+// MessageReceipt is the GORM model for message_receipts table (per-recipient delivery and read times)
+type MessageReceipt struct {
+	MessageID   uuid.UUID  `gorm:"type:uuid;primaryKey;not null"`
+	UserID      uuid.UUID  `gorm:"type:uuid;primaryKey;not null;index"`
+	DeliveredAt *time.Time `gorm:"type:timestamp"`
+	ReadAt      *time.Time `gorm:"type:timestamp"`
+	ReadHidden  bool       `gorm:"type:boolean;not null;default:false"`
+}
+
+// TableName specifies the table name for MessageReceipt model
+func (MessageReceipt) TableName() string {
+	return "message_receipts"
+}
+
+// Poll is the GORM model for polls table (the poll of a poll message)
+type Poll struct {
+	MessageID      uuid.UUID  `gorm:"type:uuid;primaryKey"`
+	Question       string     `gorm:"type:text;not null"`
+	MultipleChoice bool       `gorm:"type:boolean;not null;default:false"`
+	Anonymous      bool       `gorm:"type:boolean;not null;default:false"`
+	Quiz           bool       `gorm:"type:boolean;not null;default:false"`
+	CorrectOption  *int       `gorm:"type:int"`
+	ClosesAt       *time.Time `gorm:"type:timestamp"`
+	ClosedAt       *time.Time `gorm:"type:timestamp"`
+	TotalVoters    int        `gorm:"type:int;not null;default:0"`
+	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for Poll model
+func (Poll) TableName() string {
+	return "polls"
+}
+
+// PollOption is the GORM model for poll_options table
+type PollOption struct {
+	MessageID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Position  int       `gorm:"type:int;primaryKey"`
+	Text      string    `gorm:"type:varchar(255);not null"`
+	VoteCount int       `gorm:"type:int;not null;default:0"`
+}
+
+// TableName specifies the table name for PollOption model
+func (PollOption) TableName() string {
+	return "poll_options"
+}
+
+// PollVote is the GORM model for poll_votes table (one row per chosen option)
+type PollVote struct {
+	MessageID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey;index"`
+	Position  int       `gorm:"type:int;primaryKey"`
+	CreatedAt time.Time `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for PollVote model
+func (PollVote) TableName() string {
+	return "poll_votes"
+}
+
+// ScheduledMessage is the GORM model for scheduled_messages table
+type ScheduledMessage struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ConversationID uuid.UUID  `gorm:"type:uuid;not null;index"`
+	SenderID       uuid.UUID  `gorm:"type:uuid;not null;index:idx_scheduled_messages_sender,priority:1"`
+	Content        string     `gorm:"type:text;not null"`
+	ContentType    string     `gorm:"type:varchar(20);not null"`
+	ReplyToID      *uuid.UUID `gorm:"type:uuid"`
+	SendAt         time.Time  `gorm:"type:timestamp;not null;index:idx_scheduled_messages_due,priority:2;index:idx_scheduled_messages_sender,priority:2"`
+	Status         string     `gorm:"type:varchar(20);not null;default:'pending';index:idx_scheduled_messages_due,priority:1"`
+	Attempts       int        `gorm:"type:int;not null;default:0"`
+	ClaimedUntil   *time.Time `gorm:"type:timestamp"` // Dispatcher lease while sending
+	SentAt         *time.Time `gorm:"type:timestamp"`
+	LastError      string     `gorm:"type:text;not null;default:''"`
+	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+	UpdatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP"`
+}
+
+// TableName specifies the table name for ScheduledMessage model
+func (ScheduledMessage) TableName() string {
+	return "scheduled_messages"
+}
+
+// ChangeLog is the GORM model for change_log table.
+// CreatedAt is set by the database, the settle and prune queries compare it to the database clock.
+type ChangeLog struct {
+	Seq            int64      `gorm:"primaryKey;autoIncrement;index:idx_change_log_user,priority:2;index:idx_change_log_conversation,priority:2"`
+	UserID         *uuid.UUID `gorm:"type:uuid;index:idx_change_log_user,priority:1"` // NULL for changes seen by all participants
+	ConversationID uuid.UUID  `gorm:"type:uuid;not null;index:idx_change_log_conversation,priority:1"`
+	MessageID      *uuid.UUID `gorm:"type:uuid"`
+	Kind           string     `gorm:"type:varchar(32);not null"`
+	CreatedAt      time.Time  `gorm:"type:timestamp;not null;default:CURRENT_TIMESTAMP;autoCreateTime:false;index"`
+}
+
+// TableName specifies the table name for ChangeLog model
+func (ChangeLog) TableName() string {
+	return "change_log"
+}
+
 // Status is the GORM model for statuses table (Day 13)
 type Status struct {
 	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
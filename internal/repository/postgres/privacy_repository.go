@@ -28,6 +28,7 @@ func (r *privacyRepository) CreatePrivacySettings(ctx context.Context, settings
 		StatusVisibility:       string(settings.StatusVisibility),
 		ReadReceiptsEnabled:    settings.ReadReceiptsEnabled,
 		TypingIndicatorEnabled: settings.TypingIndicatorEnabled,
+		WhoCanMessage:          string(settings.WhoCanMessage),
 	}
 
 	if err := r.db.WithContext(ctx).Create(model).Error; err != nil {
@@ -55,6 +56,7 @@ func (r *privacyRepository) GetPrivacySettings(ctx context.Context, userID uuid.
 		StatusVisibility:       domainPrivacy.Visibility(model.StatusVisibility),
 		ReadReceiptsEnabled:    model.ReadReceiptsEnabled,
 		TypingIndicatorEnabled: model.TypingIndicatorEnabled,
+		WhoCanMessage:          domainPrivacy.Visibility(model.WhoCanMessage),
 		CreatedAt:              model.CreatedAt,
 		UpdatedAt:              model.UpdatedAt,
 	}, nil
@@ -67,6 +69,7 @@ func (r *privacyRepository) UpdatePrivacySettings(ctx context.Context, settings
 		"status_visibility":        string(settings.StatusVisibility),
 		"read_receipts_enabled":    settings.ReadReceiptsEnabled,
 		"typing_indicator_enabled": settings.TypingIndicatorEnabled,
+		"who_can_message":          string(settings.WhoCanMessage),
 	}
 
 	result := r.db.WithContext(ctx).
@@ -184,6 +187,35 @@ func (r *privacyRepository) GetBlockedByUsers(ctx context.Context, userID uuid.U
 	return userIDs, nil
 }
 
+func (r *privacyRepository) IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&BlockedUser{}).
+		Where("(user_id = ? AND blocked_user_id = ?) OR (user_id = ? AND blocked_user_id = ?)", userID, otherUserID, otherUserID, userID).
+		Count(&count).Error
+
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func (r *privacyRepository) GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT blocked_user_id FROM blocked_users WHERE user_id = ?
+		UNION
+		SELECT user_id FROM blocked_users WHERE blocked_user_id = ?
+	`, userID, userID).Scan(&userIDs).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
 // Disappearing Messages
 
 func (r *privacyRepository) SetDisappearingMessages(ctx context.Context, config *domainPrivacy.DisappearingMessagesConfig) error {
@@ -91,6 +91,9 @@ func (r *statusRepository) GetStatusFeed(ctx context.Context, viewerID uuid.UUID
 	var models []Status
 	query := r.db.WithContext(ctx).
 		Where("expires_at > ?", time.Now()).
+		// Users the viewer blocked or was blocked by are left out before paging, so pages stay full
+		Where("user_id NOT IN (SELECT blocked_user_id FROM blocked_users WHERE user_id = ?)", viewerID).
+		Where("user_id NOT IN (SELECT user_id FROM blocked_users WHERE blocked_user_id = ?)", viewerID).
 		Order("created_at DESC")
 
 	if limit > 0 {
@@ -171,32 +171,101 @@ func (m *MessageCache) UpdateMessageStatus(ctx context.Context, messageID uuid.U
 	return m.SetMessage(ctx, msg, 15*time.Minute)
 }
 
+// unreadCountTTL is how long cached unread counts are kept without being recounted
+const unreadCountTTL = 24 * time.Hour
+
+// incrementUnreadScript bumps only counts that are cached, a missing count is recounted
+// from the database on its next read and starting it at 1 would undercount
+var incrementUnreadScript = redis.NewScript(`
+for i = 1, #ARGV do
+	if redis.call('HEXISTS', KEYS[1], ARGV[i]) == 1 then
+		redis.call('HINCRBY', KEYS[1], ARGV[i], 1)
+	end
+end
+return 0
+`)
+
+// incrementUnreadBatch bounds the users bumped by one script call, channels can have many subscribers
+const incrementUnreadBatch = 1000
+
 // GetUnreadCount gets the count of unread messages for a conversation (from cache)
 func (m *MessageCache) GetUnreadCount(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID) (int, error) {
-	key := m.unreadCountKey(conversationID, userID)
+	key := m.unreadCountKey(conversationID)
 
-	count, err := m.client.Get(ctx, key).Int()
+	count, err := m.client.HGet(ctx, key, userID.String()).Int()
 	if err == redis.Nil {
 		return 0, nil
 	}
 	return count, err
 }
 
-// SetUnreadCount sets the unread message count
-func (m *MessageCache) SetUnreadCount(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID, count int) error {
-	key := m.unreadCountKey(conversationID, userID)
-	return m.client.Set(ctx, key, count, 24*time.Hour).Err()
+// GetUnreadCounts gets the cached unread counts of a user's conversations.
+// Conversations without a cached count are left out of the result
+func (m *MessageCache) GetUnreadCounts(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	pipe := m.client.Pipeline()
+	cmds := make([]*redis.StringCmd, len(conversationIDs))
+	for i, conversationID := range conversationIDs {
+		cmds[i] = pipe.HGet(ctx, m.unreadCountKey(conversationID), userID.String())
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	counts := make(map[uuid.UUID]int, len(conversationIDs))
+	for i, cmd := range cmds {
+		count, err := cmd.Int()
+		if err != nil {
+			continue
+		}
+		counts[conversationIDs[i]] = count
+	}
+
+	return counts, nil
+}
+
+// SetUnreadCountIfAbsent caches a counted unread message count unless a count is cached already.
+// A cached count may have been bumped since the caller counted, overwriting it would lose that increment
+func (m *MessageCache) SetUnreadCountIfAbsent(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID, count int) error {
+	key := m.unreadCountKey(conversationID)
+
+	pipe := m.client.Pipeline()
+	pipe.HSetNX(ctx, key, userID.String(), count)
+	pipe.Expire(ctx, key, unreadCountTTL)
+	_, err := pipe.Exec(ctx)
+	return err
 }
 
-// IncrementUnreadCount increments the unread message count
-func (m *MessageCache) IncrementUnreadCount(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID) error {
-	key := m.unreadCountKey(conversationID, userID)
-	return m.client.Incr(ctx, key).Err()
+// IncrementUnreadCounts increments the cached unread counts of the given users after a new message
+func (m *MessageCache) IncrementUnreadCounts(ctx context.Context, conversationID uuid.UUID, userIDs []uuid.UUID) error {
+	key := m.unreadCountKey(conversationID)
+
+	for start := 0; start < len(userIDs); start += incrementUnreadBatch {
+		end := start + incrementUnreadBatch
+		if end > len(userIDs) {
+			end = len(userIDs)
+		}
+
+		fields := make([]interface{}, 0, end-start)
+		for _, userID := range userIDs[start:end] {
+			fields = append(fields, userID.String())
+		}
+		if err := incrementUnreadScript.Run(ctx, m.client, []string{key}, fields...).Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-// ResetUnreadCount resets the unread message count to 0
+// ResetUnreadCount drops a user's cached unread count, it is counted again on the next read
 func (m *MessageCache) ResetUnreadCount(ctx context.Context, conversationID uuid.UUID, userID uuid.UUID) error {
-	key := m.unreadCountKey(conversationID, userID)
+	key := m.unreadCountKey(conversationID)
+	return m.client.HDel(ctx, key, userID.String()).Err()
+}
+
+// ResetConversationUnreadCounts drops the cached unread counts of every participant, e.g. after a message is deleted
+func (m *MessageCache) ResetConversationUnreadCounts(ctx context.Context, conversationID uuid.UUID) error {
+	key := m.unreadCountKey(conversationID)
 	return m.client.Del(ctx, key).Err()
 }
 
@@ -209,8 +278,9 @@ func (m *MessageCache) conversationMessagesKey(conversationID uuid.UUID) string
 	return fmt.Sprintf("conversation:messages:%s", conversationID.String())
 }
 
-func (m *MessageCache) unreadCountKey(conversationID uuid.UUID, userID uuid.UUID) string {
-	return fmt.Sprintf("unread:%s:%s", conversationID.String(), userID.String())
+// unreadCountKey is a hash of the conversation's unread counts by user ID
+func (m *MessageCache) unreadCountKey(conversationID uuid.UUID) string {
+	return fmt.Sprintf("unread:%s", conversationID.String())
 }
 
 // GetConversationMessageCount returns the number of cached messages for a conversation
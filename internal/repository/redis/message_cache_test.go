@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMessageCache(t *testing.T) *MessageCache {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewMessageCache(client)
+}
+
+func TestMessageCache_IncrementSkipsUncachedCounts(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestMessageCache(t)
+	conversationID, cached, uncached := uuid.New(), uuid.New(), uuid.New()
+
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, cached, 2))
+	require.NoError(t, cache.IncrementUnreadCounts(ctx, conversationID, []uuid.UUID{cached, uncached}))
+
+	counts, err := cache.GetUnreadCounts(ctx, cached, []uuid.UUID{conversationID})
+	require.NoError(t, err)
+	assert.Equal(t, 3, counts[conversationID])
+
+	counts, err = cache.GetUnreadCounts(ctx, uncached, []uuid.UUID{conversationID})
+	require.NoError(t, err)
+	assert.NotContains(t, counts, conversationID, "a missing count must be recounted, not started at 1")
+}
+
+func TestMessageCache_SetUnreadCountIfAbsentKeepsIncrements(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestMessageCache(t)
+	conversationID, userID := uuid.New(), uuid.New()
+
+	// Another reader cached the count and a new message bumped it before this reader's stale count is written
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, userID, 4))
+	require.NoError(t, cache.IncrementUnreadCounts(ctx, conversationID, []uuid.UUID{userID}))
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, userID, 4))
+
+	count, err := cache.GetUnreadCount(ctx, conversationID, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, count)
+}
+
+func TestMessageCache_ResetUnreadCount(t *testing.T) {
+	ctx := context.Background()
+	cache := newTestMessageCache(t)
+	conversationID, reader, other := uuid.New(), uuid.New(), uuid.New()
+
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, reader, 7))
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, other, 1))
+	require.NoError(t, cache.ResetUnreadCount(ctx, conversationID, reader))
+
+	// Once dropped, the next count is stored again
+	require.NoError(t, cache.SetUnreadCountIfAbsent(ctx, conversationID, reader, 0))
+	count, err := cache.GetUnreadCount(ctx, conversationID, reader)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	require.NoError(t, cache.ResetConversationUnreadCounts(ctx, conversationID))
+	counts, err := cache.GetUnreadCounts(ctx, other, []uuid.UUID{conversationID})
+	require.NoError(t, err)
+	assert.Empty(t, counts)
+}
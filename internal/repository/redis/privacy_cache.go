@@ -0,0 +1,178 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// noPrivacySettings is cached for users who never saved settings, so they do not hit the database either
+const noPrivacySettings = "none"
+
+// PrivacyCache caches privacy settings and block relations in front of the privacy repository.
+// Realtime paths such as typing indicators and read receipts check them on every event,
+// so reads are served from Redis and writes through the cache invalidate the cached copy.
+// All other repository methods go straight to the wrapped repository.
+type PrivacyCache struct {
+	privacy.Repository
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewPrivacyCache wraps a privacy repository with a settings cache
+func NewPrivacyCache(repo privacy.Repository, client *redis.Client, ttl time.Duration) *PrivacyCache {
+	return &PrivacyCache{
+		Repository: repo,
+		client:     client,
+		ttl:        ttl,
+	}
+}
+
+// GetPrivacySettings returns cached settings, loading them from the repository on a miss
+func (p *PrivacyCache) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*privacy.PrivacySettings, error) {
+	key := p.settingsKey(userID)
+
+	val, err := p.client.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		if val == noPrivacySettings {
+			return nil, privacy.ErrPrivacySettingsNotFound
+		}
+		var settings privacy.PrivacySettings
+		if err := json.Unmarshal([]byte(val), &settings); err == nil {
+			return &settings, nil
+		}
+	case err != redis.Nil:
+		// Redis being unavailable should not break privacy checks
+		logger.Warn("Failed to get cached privacy settings", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+
+	settings, err := p.Repository.GetPrivacySettings(ctx, userID)
+	if errors.Is(err, privacy.ErrPrivacySettingsNotFound) {
+		p.client.Set(ctx, key, noPrivacySettings, p.ttl)
+		return nil, err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if jsonData, err := json.Marshal(settings); err == nil {
+		p.client.Set(ctx, key, jsonData, p.ttl)
+	}
+
+	return settings, nil
+}
+
+// CreatePrivacySettings creates settings and drops the cached copy
+func (p *PrivacyCache) CreatePrivacySettings(ctx context.Context, settings *privacy.PrivacySettings) error {
+	if err := p.Repository.CreatePrivacySettings(ctx, settings); err != nil {
+		return err
+	}
+	return p.invalidate(ctx, settings.UserID)
+}
+
+// UpdatePrivacySettings updates settings and drops the cached copy
+func (p *PrivacyCache) UpdatePrivacySettings(ctx context.Context, settings *privacy.PrivacySettings) error {
+	if err := p.Repository.UpdatePrivacySettings(ctx, settings); err != nil {
+		return err
+	}
+	return p.invalidate(ctx, settings.UserID)
+}
+
+// GetBlockRelations returns the cached users a user blocked or was blocked by, loading them on a miss
+func (p *PrivacyCache) GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	key := p.blocksKey(userID)
+
+	val, err := p.client.Get(ctx, key).Result()
+	switch {
+	case err == nil:
+		var userIDs []uuid.UUID
+		if err := json.Unmarshal([]byte(val), &userIDs); err == nil {
+			return userIDs, nil
+		}
+	case err != redis.Nil:
+		logger.Warn("Failed to get cached block relations", zap.String("user_id", userID.String()), zap.Error(err))
+	}
+
+	userIDs, err := p.Repository.GetBlockRelations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if userIDs == nil {
+		userIDs = []uuid.UUID{}
+	}
+
+	if jsonData, err := json.Marshal(userIDs); err == nil {
+		p.client.Set(ctx, key, jsonData, p.ttl)
+	}
+
+	return userIDs, nil
+}
+
+// IsBlockedBetween checks the cached block relations of userID for otherUserID
+func (p *PrivacyCache) IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	userIDs, err := p.GetBlockRelations(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, id := range userIDs {
+		if id == otherUserID {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// BlockUser blocks a user and drops the cached block relations of both users
+func (p *PrivacyCache) BlockUser(ctx context.Context, blocked *privacy.BlockedUser) error {
+	if err := p.Repository.BlockUser(ctx, blocked); err != nil {
+		return err
+	}
+	return p.invalidateBlocks(ctx, blocked.UserID, blocked.BlockedUserID)
+}
+
+// UnblockUser unblocks a user and drops the cached block relations of both users
+func (p *PrivacyCache) UnblockUser(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	if err := p.Repository.UnblockUser(ctx, userID, blockedUserID); err != nil {
+		return err
+	}
+	return p.invalidateBlocks(ctx, userID, blockedUserID)
+}
+
+// invalidate removes the cached settings of a user
+func (p *PrivacyCache) invalidate(ctx context.Context, userID uuid.UUID) error {
+	if err := p.client.Del(ctx, p.settingsKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate privacy settings: %w", err)
+	}
+	return nil
+}
+
+// invalidateBlocks removes the cached block relations of the given users
+func (p *PrivacyCache) invalidateBlocks(ctx context.Context, userIDs ...uuid.UUID) error {
+	keys := make([]string, len(userIDs))
+	for i, userID := range userIDs {
+		keys[i] = p.blocksKey(userID)
+	}
+	if err := p.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to invalidate block relations: %w", err)
+	}
+	return nil
+}
+
+func (p *PrivacyCache) settingsKey(userID uuid.UUID) string {
+	return fmt.Sprintf("privacy:settings:%s", userID.String())
+}
+
+func (p *PrivacyCache) blocksKey(userID uuid.UUID) string {
+	return fmt.Sprintf("privacy:blocks:%s", userID.String())
+}
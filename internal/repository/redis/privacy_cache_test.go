@@ -0,0 +1,130 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+)
+
+// privacyRepo counts the settings lookups that reach the database
+type privacyRepo struct {
+	privacy.Repository
+	settings map[uuid.UUID]*privacy.PrivacySettings
+	blocks   map[uuid.UUID][]uuid.UUID
+	loads    int
+}
+
+func (r *privacyRepo) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*privacy.PrivacySettings, error) {
+	r.loads++
+	if settings, ok := r.settings[userID]; ok {
+		copied := *settings
+		return &copied, nil
+	}
+	return nil, privacy.ErrPrivacySettingsNotFound
+}
+
+func (r *privacyRepo) UpdatePrivacySettings(ctx context.Context, settings *privacy.PrivacySettings) error {
+	r.settings[settings.UserID] = settings
+	return nil
+}
+
+func (r *privacyRepo) GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	r.loads++
+	return r.blocks[userID], nil
+}
+
+func (r *privacyRepo) BlockUser(ctx context.Context, blocked *privacy.BlockedUser) error {
+	r.blocks[blocked.UserID] = append(r.blocks[blocked.UserID], blocked.BlockedUserID)
+	r.blocks[blocked.BlockedUserID] = append(r.blocks[blocked.BlockedUserID], blocked.UserID)
+	return nil
+}
+
+func newTestPrivacyCache(t *testing.T) (*PrivacyCache, *privacyRepo) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	repo := &privacyRepo{settings: make(map[uuid.UUID]*privacy.PrivacySettings), blocks: make(map[uuid.UUID][]uuid.UUID)}
+	return NewPrivacyCache(repo, client, time.Minute), repo
+}
+
+func TestPrivacyCache_ServesSettingsFromRedis(t *testing.T) {
+	ctx := context.Background()
+	cache, repo := newTestPrivacyCache(t)
+	userID := uuid.New()
+	settings := privacy.DefaultPrivacySettings(userID)
+	settings.TypingIndicatorEnabled = false
+	repo.settings[userID] = settings
+
+	for i := 0; i < 3; i++ {
+		got, err := cache.GetPrivacySettings(ctx, userID)
+		require.NoError(t, err)
+		assert.False(t, got.TypingIndicatorEnabled)
+	}
+	assert.Equal(t, 1, repo.loads)
+}
+
+func TestPrivacyCache_CachesMissingSettings(t *testing.T) {
+	ctx := context.Background()
+	cache, repo := newTestPrivacyCache(t)
+	userID := uuid.New()
+
+	for i := 0; i < 3; i++ {
+		_, err := cache.GetPrivacySettings(ctx, userID)
+		assert.ErrorIs(t, err, privacy.ErrPrivacySettingsNotFound)
+	}
+	assert.Equal(t, 1, repo.loads)
+}
+
+func TestPrivacyCache_UpdateDropsCachedSettings(t *testing.T) {
+	ctx := context.Background()
+	cache, repo := newTestPrivacyCache(t)
+	userID := uuid.New()
+	repo.settings[userID] = privacy.DefaultPrivacySettings(userID)
+
+	_, err := cache.GetPrivacySettings(ctx, userID)
+	require.NoError(t, err)
+
+	updated := privacy.DefaultPrivacySettings(userID)
+	updated.ReadReceiptsEnabled = false
+	require.NoError(t, cache.UpdatePrivacySettings(ctx, updated))
+
+	got, err := cache.GetPrivacySettings(ctx, userID)
+	require.NoError(t, err)
+	assert.False(t, got.ReadReceiptsEnabled)
+	assert.Equal(t, 2, repo.loads)
+}
+
+func TestPrivacyCache_BlockDropsBothSides(t *testing.T) {
+	ctx := context.Background()
+	cache, repo := newTestPrivacyCache(t)
+	blocker, blocked := uuid.New(), uuid.New()
+
+	// Cache both sides before the block
+	for _, userID := range []uuid.UUID{blocker, blocked} {
+		isBlocked, err := cache.IsBlockedBetween(ctx, userID, uuid.New())
+		require.NoError(t, err)
+		assert.False(t, isBlocked)
+	}
+	isBlocked, err := cache.IsBlockedBetween(ctx, blocked, blocker)
+	require.NoError(t, err)
+	assert.False(t, isBlocked)
+	assert.Equal(t, 2, repo.loads)
+
+	require.NoError(t, cache.BlockUser(ctx, &privacy.BlockedUser{UserID: blocker, BlockedUserID: blocked}))
+
+	isBlocked, err = cache.IsBlockedBetween(ctx, blocker, blocked)
+	require.NoError(t, err)
+	assert.True(t, isBlocked)
+	isBlocked, err = cache.IsBlockedBetween(ctx, blocked, blocker)
+	require.NoError(t, err)
+	assert.True(t, isBlocked)
+	assert.Equal(t, 4, repo.loads)
+}
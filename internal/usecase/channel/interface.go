@@ -27,6 +27,9 @@ type Service interface {
 	// UpdateChannel updates channel information
 	UpdateChannel(ctx context.Context, userID, channelID uuid.UUID, req *dto.UpdateChannelRequest) error
 
+	// UpdateChannelSettings updates channel settings
+	UpdateChannelSettings(ctx context.Context, userID, channelID uuid.UUID, req *dto.UpdateChannelSettingsRequest) error
+
 	// DeleteChannel deletes a channel
 	DeleteChannel(ctx context.Context, userID, channelID uuid.UUID) error
 
@@ -39,6 +42,9 @@ type Service interface {
 	// ToggleNotifications toggles notifications for a channel subscription
 	ToggleNotifications(ctx context.Context, userID, channelID uuid.UUID) error
 
+	// GetChannelAdmins gets all admins of a channel
+	GetChannelAdmins(ctx context.Context, userID, channelID uuid.UUID) (*dto.GetChannelAdminsResponse, error)
+
 	// AddAdmin adds an admin to a channel
 	AddAdmin(ctx context.Context, userID, channelID uuid.UUID, req *dto.AddAdminRequest) error
 
@@ -5,16 +5,31 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
 	"github.com/yourusername/sotalk/internal/domain/channel"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
 	"github.com/yourusername/sotalk/internal/domain/user"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
 )
 
+// Broadcaster defines methods for WebSocket broadcasting
+type Broadcaster interface {
+	BroadcastChannelUpdated(ctx context.Context, conversationID uuid.UUID, channel dto.ChannelDTO) error
+	BroadcastChannelDeleted(ctx context.Context, conversationID uuid.UUID, channelID, deletedBy string) error
+	BroadcastChannelSettingsUpdated(ctx context.Context, conversationID uuid.UUID, channelID, updatedBy string, settings dto.ChannelSettings) error
+	BroadcastChannelAdminAdded(ctx context.Context, conversationID uuid.UUID, channelID, userID, username, addedBy string) error
+	BroadcastChannelAdminRemoved(ctx context.Context, conversationID uuid.UUID, channelID, userID, username, removedBy string) error
+	BroadcastChannelAdminPermissionsUpdated(ctx context.Context, conversationID uuid.UUID, channelID, userID, updatedBy string, permissions dto.ChannelAdminPermissions) error
+}
+
 type service struct {
 	channelRepo      channel.Repository
 	conversationRepo conversation.Repository
 	userRepo         user.Repository
+	changeLogRepo    changelog.Repository
+	broadcaster      Broadcaster
 }
 
 // NewService creates a new channel service
@@ -22,11 +37,15 @@ func NewService(
 	channelRepo channel.Repository,
 	conversationRepo conversation.Repository,
 	userRepo user.Repository,
+	changeLogRepo changelog.Repository,
+	broadcaster Broadcaster,
 ) Service {
 	return &service{
 		channelRepo:      channelRepo,
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
+		changeLogRepo:    changeLogRepo,
+		broadcaster:      broadcaster,
 	}
 }
 
@@ -54,6 +73,13 @@ func (s *service) CreateChannel(ctx context.Context, userID uuid.UUID, req *dto.
 	subscriber := channel.NewSubscriber(ch.ID, userID)
 	s.channelRepo.Subscribe(ctx, subscriber)
 
+	// Add owner to conversation so they can post
+	participant := conversation.NewParticipant(conv.ID, userID, conversation.RoleOwner)
+	if err := s.conversationRepo.AddParticipant(ctx, participant); err != nil {
+		return nil, fmt.Errorf("failed to add owner to conversation: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewConversationChange(conv.ID, changelog.KindMembershipUpdated))
+
 	return &dto.CreateChannelResponse{
 		Channel: toChannelDTO(ch),
 	}, nil
@@ -168,6 +194,66 @@ func (s *service) UpdateChannel(ctx context.Context, userID, channelID uuid.UUID
 	if err := s.channelRepo.Update(ctx, ch); err != nil {
 		return fmt.Errorf("failed to update channel: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindConversationUpdated))
+
+	// Broadcast channel updated event
+	if s.broadcaster != nil {
+		go s.broadcaster.BroadcastChannelUpdated(context.Background(), ch.ConversationID, toChannelDTO(ch))
+	}
+
+	return nil
+}
+
+// UpdateChannelSettings updates channel settings
+func (s *service) UpdateChannelSettings(ctx context.Context, userID, channelID uuid.UUID, req *dto.UpdateChannelSettingsRequest) error {
+	ch, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return err
+	}
+
+	// Only owner can update settings
+	if ch.OwnerID != userID {
+		return channel.ErrNotOwner
+	}
+
+	// Update only provided fields
+	settings := &channel.Settings{}
+	if ch.Settings != nil {
+		*settings = *ch.Settings
+	}
+	if req.AdminsCanPost != nil {
+		settings.AdminsCanPost = *req.AdminsCanPost
+	}
+	if req.LinkPreview != nil {
+		settings.LinkPreview = *req.LinkPreview
+	}
+	if req.ForwardingAllowed != nil {
+		settings.ForwardingAllowed = *req.ForwardingAllowed
+	}
+	if req.EditWindowSeconds != nil {
+		settings.EditWindowSeconds = *req.EditWindowSeconds
+	}
+	if req.SubscriberVotes != nil {
+		settings.SubscriberVotes = *req.SubscriberVotes
+	}
+	ch.UpdateSettings(settings)
+
+	if err := s.channelRepo.Update(ctx, ch); err != nil {
+		return fmt.Errorf("failed to update channel settings: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindConversationUpdated))
+
+	// Broadcast channel settings updated event
+	if s.broadcaster != nil {
+		settingsDTO := dto.ChannelSettings{
+			AdminsCanPost:     settings.AdminsCanPost,
+			LinkPreview:       settings.LinkPreview,
+			ForwardingAllowed: settings.ForwardingAllowed,
+			EditWindowSeconds: settings.EditWindowSeconds,
+			SubscriberVotes:   settings.SubscriberVotes,
+		}
+		go s.broadcaster.BroadcastChannelSettingsUpdated(context.Background(), ch.ConversationID, channelID.String(), userID.String(), settingsDTO)
+	}
 
 	return nil
 }
@@ -187,6 +273,12 @@ func (s *service) DeleteChannel(ctx context.Context, userID, channelID uuid.UUID
 	if err := s.channelRepo.Delete(ctx, channelID); err != nil {
 		return fmt.Errorf("failed to delete channel: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindConversationUpdated))
+
+	// Broadcast channel deleted event
+	if s.broadcaster != nil {
+		go s.broadcaster.BroadcastChannelDeleted(context.Background(), ch.ConversationID, channelID.String(), userID.String())
+	}
 
 	return nil
 }
@@ -213,6 +305,13 @@ func (s *service) Subscribe(ctx context.Context, userID uuid.UUID, username stri
 		return fmt.Errorf("failed to subscribe: %w", err)
 	}
 
+	// Add to conversation to receive channel posts
+	participant := conversation.NewParticipant(ch.ConversationID, userID, conversation.RoleMember)
+	s.conversationRepo.AddParticipant(ctx, participant)
+
+	// Subscribers are not listed to each other, so only the subscriber syncs the change
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindMembershipUpdated).ForUser(userID))
+
 	// Increment subscriber count
 	ch.IncrementSubscriberCount()
 	s.channelRepo.Update(ctx, ch)
@@ -236,6 +335,10 @@ func (s *service) Unsubscribe(ctx context.Context, userID uuid.UUID, username st
 		return fmt.Errorf("failed to unsubscribe: %w", err)
 	}
 
+	// Remove from conversation
+	s.conversationRepo.RemoveParticipant(ctx, ch.ConversationID, userID)
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindMembershipUpdated).ForUser(userID))
+
 	// Decrement subscriber count
 	ch.DecrementSubscriberCount()
 	s.channelRepo.Update(ctx, ch)
@@ -259,6 +362,60 @@ func (s *service) ToggleNotifications(ctx context.Context, userID, channelID uui
 	return nil
 }
 
+// GetChannelAdmins gets all admins of a channel
+func (s *service) GetChannelAdmins(ctx context.Context, userID, channelID uuid.UUID) (*dto.GetChannelAdminsResponse, error) {
+	ch, err := s.channelRepo.FindByID(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Private channels only expose admins to subscribers
+	if !ch.IsPublic && ch.OwnerID != userID {
+		isSubscribed, err := s.channelRepo.IsSubscribed(ctx, channelID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !isSubscribed {
+			return nil, channel.ErrNotSubscribed
+		}
+	}
+
+	admins, err := s.channelRepo.FindAdmins(ctx, channelID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	adminDTOs := make([]dto.ChannelAdminDTO, 0, len(admins))
+	for _, admin := range admins {
+		adminDTO := dto.ChannelAdminDTO{
+			UserID:  admin.UserID.String(),
+			AddedAt: admin.AddedAt,
+		}
+		if admin.Permissions != nil {
+			adminDTO.Permissions = &dto.ChannelAdminPermissions{
+				CanPostMessages:   admin.Permissions.CanPostMessages,
+				CanEditMessages:   admin.Permissions.CanEditMessages,
+				CanDeleteMessages: admin.Permissions.CanDeleteMessages,
+				CanManageAdmins:   admin.Permissions.CanManageAdmins,
+			}
+		}
+		if u, err := s.userRepo.FindByID(ctx, admin.UserID); err == nil {
+			adminDTO.User = &dto.UserDTO{
+				ID:            u.ID.String(),
+				WalletAddress: u.WalletAddress,
+				Username:      u.Username,
+				Avatar:        u.Avatar,
+				Status:        string(u.Status),
+			}
+		}
+		adminDTOs = append(adminDTOs, adminDTO)
+	}
+
+	return &dto.GetChannelAdminsResponse{
+		Admins: adminDTOs,
+	}, nil
+}
+
 // AddAdmin adds an admin to a channel
 func (s *service) AddAdmin(ctx context.Context, userID, channelID uuid.UUID, req *dto.AddAdminRequest) error {
 	ch, err := s.channelRepo.FindByID(ctx, channelID)
@@ -266,9 +423,9 @@ func (s *service) AddAdmin(ctx context.Context, userID, channelID uuid.UUID, req
 		return err
 	}
 
-	// Only owner can add admins
-	if ch.OwnerID != userID {
-		return channel.ErrNotOwner
+	// Only owner or admins with CanManageAdmins can add admins
+	if err := s.checkCanManageAdmins(ctx, ch, userID); err != nil {
+		return err
 	}
 
 	// Parse target user ID
@@ -278,7 +435,8 @@ func (s *service) AddAdmin(ctx context.Context, userID, channelID uuid.UUID, req
 	}
 
 	// Check if target user exists
-	if _, err := s.userRepo.FindByID(ctx, targetUserID); err != nil {
+	targetUser, err := s.userRepo.FindByID(ctx, targetUserID)
+	if err != nil {
 		return fmt.Errorf("target user not found: %w", err)
 	}
 
@@ -288,6 +446,19 @@ func (s *service) AddAdmin(ctx context.Context, userID, channelID uuid.UUID, req
 		return fmt.Errorf("failed to add admin: %w", err)
 	}
 
+	// Admins must be conversation participants to post
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, ch.ConversationID, targetUserID)
+	if err == nil && !isParticipant {
+		participant := conversation.NewParticipant(ch.ConversationID, targetUserID, conversation.RoleAdmin)
+		s.conversationRepo.AddParticipant(ctx, participant)
+	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindMembershipUpdated))
+
+	// Broadcast admin added event
+	if s.broadcaster != nil {
+		go s.broadcaster.BroadcastChannelAdminAdded(context.Background(), ch.ConversationID, channelID.String(), targetUserID.String(), targetUser.Username, userID.String())
+	}
+
 	return nil
 }
 
@@ -298,9 +469,9 @@ func (s *service) RemoveAdmin(ctx context.Context, userID, channelID, targetUser
 		return err
 	}
 
-	// Only owner can remove admins
-	if ch.OwnerID != userID {
-		return channel.ErrNotOwner
+	// Only owner or admins with CanManageAdmins can remove admins
+	if err := s.checkCanManageAdmins(ctx, ch, userID); err != nil {
+		return err
 	}
 
 	// Cannot remove owner
@@ -311,6 +482,16 @@ func (s *service) RemoveAdmin(ctx context.Context, userID, channelID, targetUser
 	if err := s.channelRepo.RemoveAdmin(ctx, channelID, targetUserID); err != nil {
 		return fmt.Errorf("failed to remove admin: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindMembershipUpdated))
+
+	// Broadcast admin removed event
+	if s.broadcaster != nil {
+		username := ""
+		if u, err := s.userRepo.FindByID(ctx, targetUserID); err == nil {
+			username = u.Username
+		}
+		go s.broadcaster.BroadcastChannelAdminRemoved(context.Background(), ch.ConversationID, channelID.String(), targetUserID.String(), username, userID.String())
+	}
 
 	return nil
 }
@@ -337,6 +518,45 @@ func (s *service) UpdateAdminPermissions(ctx context.Context, userID, channelID,
 	if err := s.channelRepo.UpdateAdminPermissions(ctx, channelID, targetUserID, permissions); err != nil {
 		return fmt.Errorf("failed to update permissions: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(ch.ConversationID, changelog.KindMembershipUpdated))
+
+	// Broadcast admin permissions updated event
+	if s.broadcaster != nil {
+		permissionsDTO := dto.ChannelAdminPermissions{
+			CanPostMessages:   permissions.CanPostMessages,
+			CanEditMessages:   permissions.CanEditMessages,
+			CanDeleteMessages: permissions.CanDeleteMessages,
+			CanManageAdmins:   permissions.CanManageAdmins,
+		}
+		go s.broadcaster.BroadcastChannelAdminPermissionsUpdated(context.Background(), ch.ConversationID, channelID.String(), targetUserID.String(), userID.String(), permissionsDTO)
+	}
+
+	return nil
+}
+
+// recordChange appends changes to the change log clients sync from, a failure is only logged
+func (s *service) recordChange(ctx context.Context, changes ...*changelog.Change) {
+	if err := s.changeLogRepo.Record(ctx, changes...); err != nil {
+		logger.Warn("Failed to record change", zap.Error(err))
+	}
+}
+
+// checkCanManageAdmins checks if user is the owner or an admin allowed to manage admins
+func (s *service) checkCanManageAdmins(ctx context.Context, ch *channel.Channel, userID uuid.UUID) error {
+	if ch.OwnerID == userID {
+		return nil
+	}
+
+	admin, err := s.channelRepo.FindAdmin(ctx, ch.ID, userID)
+	if err != nil {
+		if err == channel.ErrAdminNotFound {
+			return channel.ErrNotAuthorized
+		}
+		return err
+	}
+	if admin.Permissions == nil || !admin.Permissions.CanManageAdmins {
+		return channel.ErrNotAuthorized
+	}
 
 	return nil
 }
@@ -349,6 +569,8 @@ func toChannelDTO(ch *channel.Channel) dto.ChannelDTO {
 			AdminsCanPost:     ch.Settings.AdminsCanPost,
 			LinkPreview:       ch.Settings.LinkPreview,
 			ForwardingAllowed: ch.Settings.ForwardingAllowed,
+			EditWindowSeconds: ch.Settings.EditWindowSeconds,
+			SubscriberVotes:   ch.Settings.SubscriberVotes,
 		}
 	}
 
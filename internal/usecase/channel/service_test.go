@@ -0,0 +1,123 @@
+package channel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/channel"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// channelRepo holds one channel and its admins in memory
+type channelRepo struct {
+	channel.Repository
+	channel *channel.Channel
+	admins  map[uuid.UUID]*channel.Admin
+	removed []uuid.UUID
+}
+
+func (r *channelRepo) FindByID(ctx context.Context, id uuid.UUID) (*channel.Channel, error) {
+	if r.channel.ID != id {
+		return nil, channel.ErrChannelNotFound
+	}
+	return r.channel, nil
+}
+
+func (r *channelRepo) Update(ctx context.Context, ch *channel.Channel) error {
+	r.channel = ch
+	return nil
+}
+
+func (r *channelRepo) FindAdmin(ctx context.Context, channelID, userID uuid.UUID) (*channel.Admin, error) {
+	if admin, ok := r.admins[userID]; ok {
+		return admin, nil
+	}
+	return nil, channel.ErrAdminNotFound
+}
+
+func (r *channelRepo) RemoveAdmin(ctx context.Context, channelID, userID uuid.UUID) error {
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+// changeLog counts the recorded changes
+type changeLog struct {
+	changelog.Repository
+	recorded int
+}
+
+func (l *changeLog) Record(ctx context.Context, changes ...*changelog.Change) error {
+	l.recorded += len(changes)
+	return nil
+}
+
+func newTestService(owner uuid.UUID, admins ...*channel.Admin) (*service, *channelRepo) {
+	repo := &channelRepo{
+		channel: &channel.Channel{
+			ID:             uuid.New(),
+			ConversationID: uuid.New(),
+			OwnerID:        owner,
+			Settings:       &channel.Settings{AdminsCanPost: true, LinkPreview: true, ForwardingAllowed: true},
+		},
+		admins: make(map[uuid.UUID]*channel.Admin),
+	}
+	for _, admin := range admins {
+		repo.admins[admin.UserID] = admin
+	}
+	return &service{channelRepo: repo, changeLogRepo: &changeLog{}}, repo
+}
+
+func TestUpdateChannelSettingsChangesOnlyGivenFields(t *testing.T) {
+	owner := uuid.New()
+	s, repo := newTestService(owner)
+
+	linkPreview := false
+	editWindow := 600
+	err := s.UpdateChannelSettings(context.Background(), owner, repo.channel.ID, &dto.UpdateChannelSettingsRequest{
+		LinkPreview:       &linkPreview,
+		EditWindowSeconds: &editWindow,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, &channel.Settings{
+		AdminsCanPost:     true,
+		LinkPreview:       false,
+		ForwardingAllowed: true,
+		EditWindowSeconds: 600,
+	}, repo.channel.Settings)
+}
+
+func TestUpdateChannelSettingsIsForTheOwner(t *testing.T) {
+	admin := uuid.New()
+	s, repo := newTestService(uuid.New(), &channel.Admin{UserID: admin, Permissions: &channel.AdminPermissions{CanManageAdmins: true}})
+
+	err := s.UpdateChannelSettings(context.Background(), admin, repo.channel.ID, &dto.UpdateChannelSettingsRequest{})
+	assert.Equal(t, channel.ErrNotOwner, err)
+}
+
+func TestCheckCanManageAdmins(t *testing.T) {
+	owner, manager, poster, subscriber := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	s, repo := newTestService(owner,
+		&channel.Admin{UserID: manager, Permissions: &channel.AdminPermissions{CanManageAdmins: true}},
+		&channel.Admin{UserID: poster, Permissions: &channel.AdminPermissions{CanPostMessages: true}},
+	)
+
+	ctx := context.Background()
+	assert.NoError(t, s.checkCanManageAdmins(ctx, repo.channel, owner))
+	assert.NoError(t, s.checkCanManageAdmins(ctx, repo.channel, manager))
+	assert.Equal(t, channel.ErrNotAuthorized, s.checkCanManageAdmins(ctx, repo.channel, poster))
+	assert.Equal(t, channel.ErrNotAuthorized, s.checkCanManageAdmins(ctx, repo.channel, subscriber))
+}
+
+func TestRemoveAdminKeepsTheOwner(t *testing.T) {
+	owner, manager := uuid.New(), uuid.New()
+	s, repo := newTestService(owner, &channel.Admin{UserID: manager, Permissions: &channel.AdminPermissions{CanManageAdmins: true}})
+
+	err := s.RemoveAdmin(context.Background(), manager, repo.channel.ID, owner)
+	assert.Equal(t, channel.ErrCannotRemoveOwner, err)
+	assert.Empty(t, repo.removed)
+}
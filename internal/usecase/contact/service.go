@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	domainContact "github.com/yourusername/sotalk/internal/domain/contact"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/pkg/logger"
 	"go.uber.org/zap"
@@ -14,15 +15,29 @@ import (
 
 type service struct {
 	contactRepo domainContact.Repository
+	privacyRepo privacy.Repository
 }
 
 // NewService creates a new contact service
-func NewService(contactRepo domainContact.Repository) Service {
+func NewService(contactRepo domainContact.Repository, privacyRepo privacy.Repository) Service {
 	return &service{
 		contactRepo: contactRepo,
+		privacyRepo: privacyRepo,
 	}
 }
 
+// checkNotBlocked returns ErrUserUnavailable if either user blocked the other
+func (s *service) checkNotBlocked(ctx context.Context, userID, otherUserID uuid.UUID) error {
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, userID, otherUserID)
+	if err != nil {
+		return fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return privacy.ErrUserUnavailable
+	}
+	return nil
+}
+
 func (s *service) AddContact(ctx context.Context, userID uuid.UUID, req *dto.AddContactRequest) (*dto.ContactResponse, error) {
 	contactID, err := uuid.Parse(req.ContactID)
 	if err != nil {
@@ -38,12 +53,18 @@ func (s *service) AddContact(ctx context.Context, userID uuid.UUID, req *dto.Add
 		displayName = *req.DisplayName
 	}
 
+	// Keep the cached block flag in line with the user's block list
+	isBlocked, err := s.privacyRepo.IsUserBlocked(ctx, userID, contactID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check block: %w", err)
+	}
+
 	contact := &domainContact.Contact{
 		UserID:      userID,
 		ContactID:   contactID,
 		DisplayName: displayName,
 		IsFavorite:  false,
-		IsBlocked:   false,
+		IsBlocked:   isBlocked,
 	}
 
 	if err := s.contactRepo.AddContact(ctx, contact); err != nil {
@@ -141,6 +162,10 @@ func (s *service) SendInvite(ctx context.Context, senderID uuid.UUID, req *dto.S
 		return nil, fmt.Errorf("cannot invite yourself")
 	}
 
+	if err := s.checkNotBlocked(ctx, senderID, recipientID); err != nil {
+		return nil, err
+	}
+
 	// Check if already contacts
 	isContact, err := s.contactRepo.IsContact(ctx, senderID, recipientID)
 	if err != nil {
@@ -190,9 +215,22 @@ func (s *service) GetPendingInvites(ctx context.Context, userID uuid.UUID) ([]*d
 		return nil, err
 	}
 
-	result := make([]*dto.InviteResponse, len(invites))
-	for i, inv := range invites {
-		result[i] = &dto.InviteResponse{
+	// Invites from users blocked since they were sent are left out
+	relations, err := s.privacyRepo.GetBlockRelations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block relations: %w", err)
+	}
+	blocked := make(map[uuid.UUID]bool, len(relations))
+	for _, id := range relations {
+		blocked[id] = true
+	}
+
+	result := make([]*dto.InviteResponse, 0, len(invites))
+	for _, inv := range invites {
+		if blocked[inv.SenderID] {
+			continue
+		}
+		result = append(result, &dto.InviteResponse{
 			ID:          inv.ID.String(),
 			SenderID:    inv.SenderID.String(),
 			RecipientID: inv.RecipientID.String(),
@@ -201,7 +239,7 @@ func (s *service) GetPendingInvites(ctx context.Context, userID uuid.UUID) ([]*d
 			ExpiresAt:   inv.ExpiresAt,
 			CreatedAt:   inv.CreatedAt,
 			RespondedAt: inv.RespondedAt,
-		}
+		})
 	}
 
 	return result, nil
@@ -222,6 +260,10 @@ func (s *service) AcceptInvite(ctx context.Context, recipientID, inviteID uuid.U
 		return domainContact.ErrInviteAlreadyAnswered
 	}
 
+	if err := s.checkNotBlocked(ctx, recipientID, invite.SenderID); err != nil {
+		return err
+	}
+
 	// Update invite status
 	if err := s.contactRepo.UpdateInviteStatus(ctx, inviteID, domainContact.InviteStatusAccepted); err != nil {
 		return err
@@ -0,0 +1,139 @@
+package contact
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	domainContact "github.com/yourusername/sotalk/internal/domain/contact"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// contactRepo keeps contacts and invites in memory
+type contactRepo struct {
+	domainContact.Repository
+	contacts []*domainContact.Contact
+	invites  []*domainContact.ContactInvite
+}
+
+func (r *contactRepo) AddContact(ctx context.Context, contact *domainContact.Contact) error {
+	r.contacts = append(r.contacts, contact)
+	return nil
+}
+
+func (r *contactRepo) IsContact(ctx context.Context, userID, targetID uuid.UUID) (bool, error) {
+	for _, c := range r.contacts {
+		if c.UserID == userID && c.ContactID == targetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *contactRepo) CreateInvite(ctx context.Context, invite *domainContact.ContactInvite) error {
+	invite.ID = uuid.New()
+	r.invites = append(r.invites, invite)
+	return nil
+}
+
+func (r *contactRepo) GetPendingInvites(ctx context.Context, recipientID uuid.UUID) ([]*domainContact.ContactInvite, error) {
+	var pending []*domainContact.ContactInvite
+	for _, inv := range r.invites {
+		if inv.RecipientID == recipientID && inv.Status == domainContact.InviteStatusPending {
+			pending = append(pending, inv)
+		}
+	}
+	return pending, nil
+}
+
+// blocks holds who blocked whom
+type blocks struct {
+	privacy.Repository
+	blocked map[uuid.UUID][]uuid.UUID
+}
+
+func (b *blocks) IsUserBlocked(ctx context.Context, userID, targetUserID uuid.UUID) (bool, error) {
+	for _, id := range b.blocked[userID] {
+		if id == targetUserID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (b *blocks) IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	if blocked, _ := b.IsUserBlocked(ctx, userID, otherUserID); blocked {
+		return true, nil
+	}
+	return b.IsUserBlocked(ctx, otherUserID, userID)
+}
+
+func (b *blocks) GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	relations := append([]uuid.UUID{}, b.blocked[userID]...)
+	for blocker, blockedIDs := range b.blocked {
+		for _, id := range blockedIDs {
+			if id == userID {
+				relations = append(relations, blocker)
+			}
+		}
+	}
+	return relations, nil
+}
+
+func newTestService(blocked map[uuid.UUID][]uuid.UUID) (*service, *contactRepo) {
+	repo := &contactRepo{}
+	return &service{contactRepo: repo, privacyRepo: &blocks{blocked: blocked}}, repo
+}
+
+func TestSendInviteBetweenBlockedUsers(t *testing.T) {
+	blocker, blocked := uuid.New(), uuid.New()
+	s, repo := newTestService(map[uuid.UUID][]uuid.UUID{blocker: {blocked}})
+	ctx := context.Background()
+
+	_, err := s.SendInvite(ctx, blocked, &dto.SendInviteRequest{RecipientID: blocker.String()})
+	assert.Equal(t, privacy.ErrUserUnavailable, err)
+	_, err = s.SendInvite(ctx, blocker, &dto.SendInviteRequest{RecipientID: blocked.String()})
+	assert.Equal(t, privacy.ErrUserUnavailable, err)
+	assert.Empty(t, repo.invites)
+
+	_, err = s.SendInvite(ctx, blocker, &dto.SendInviteRequest{RecipientID: uuid.New().String()})
+	require.NoError(t, err)
+	assert.Len(t, repo.invites, 1)
+}
+
+func TestPendingInvitesLeaveOutBlockedSenders(t *testing.T) {
+	recipient, friend, blocked := uuid.New(), uuid.New(), uuid.New()
+	s, repo := newTestService(map[uuid.UUID][]uuid.UUID{})
+	ctx := context.Background()
+
+	for _, sender := range []uuid.UUID{friend, blocked} {
+		_, err := s.SendInvite(ctx, sender, &dto.SendInviteRequest{RecipientID: recipient.String()})
+		require.NoError(t, err)
+	}
+	// Blocked after the invite was sent
+	s.privacyRepo.(*blocks).blocked[recipient] = []uuid.UUID{blocked}
+
+	invites, err := s.GetPendingInvites(ctx, recipient)
+	require.NoError(t, err)
+	require.Len(t, invites, 1)
+	assert.Equal(t, friend.String(), invites[0].SenderID)
+	assert.Len(t, repo.invites, 2)
+}
+
+func TestAddContactCopiesTheBlock(t *testing.T) {
+	userID, blocked := uuid.New(), uuid.New()
+	s, repo := newTestService(map[uuid.UUID][]uuid.UUID{userID: {blocked}})
+	ctx := context.Background()
+
+	_, err := s.AddContact(ctx, userID, &dto.AddContactRequest{ContactID: blocked.String()})
+	require.NoError(t, err)
+	_, err = s.AddContact(ctx, userID, &dto.AddContactRequest{ContactID: uuid.New().String()})
+	require.NoError(t, err)
+
+	require.Len(t, repo.contacts, 2)
+	assert.True(t, repo.contacts[0].IsBlocked)
+	assert.False(t, repo.contacts[1].IsBlocked)
+}
@@ -98,15 +98,25 @@ type InviteResponse struct {
 // Message Search
 
 type SearchMessagesRequest struct {
-	Query          string  `json:"query" binding:"required"`
-	ConversationID *string `json:"conversation_id,omitempty"`
-	Limit          int     `json:"limit,omitempty"`
-	Offset         int     `json:"offset,omitempty"`
+	Query            string     `json:"query" binding:"required"`
+	ConversationID   *string    `json:"conversation_id,omitempty"`
+	SenderID         *string    `json:"sender_id,omitempty"`
+	ConversationType string     `json:"conversation_type,omitempty" binding:"omitempty,oneof=direct group channel"`
+	ContentTypes     []string   `json:"content_types,omitempty" binding:"omitempty,dive,oneof=text image video audio file"`
+	From             *time.Time `json:"from,omitempty"`
+	To               *time.Time `json:"to,omitempty"`
+	HasReactions     bool       `json:"has_reactions,omitempty"`
+	Pinned           bool       `json:"pinned,omitempty"`
+	Cursor           string     `json:"cursor,omitempty"` // next_cursor from a previous page
+	Limit            int        `json:"limit,omitempty"`
+	Offset           int        `json:"offset,omitempty"`
 }
 
 type SearchMessagesResponse struct {
-	Messages []*MessageDTO `json:"messages"`
-	Total    int           `json:"total"`
+	Messages   []*MessageDTO     `json:"messages"`
+	Total      int64             `json:"total"`
+	Snippets   map[string]string `json:"snippets,omitempty"` // message ID -> highlighted snippet
+	NextCursor string            `json:"next_cursor,omitempty"`
 }
 
 type MessageResponse struct {
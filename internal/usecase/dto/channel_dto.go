@@ -30,6 +30,15 @@ type UpdateChannelRequest struct {
 	Avatar      string `json:"avatar"`
 }
 
+// UpdateChannelSettingsRequest is the request DTO for updating channel settings
+type UpdateChannelSettingsRequest struct {
+	AdminsCanPost     *bool `json:"admins_can_post"`
+	LinkPreview       *bool `json:"link_preview"`
+	ForwardingAllowed *bool `json:"forwarding_allowed"`
+	EditWindowSeconds *int  `json:"edit_window_seconds"`
+	SubscriberVotes   *bool `json:"subscriber_votes"`
+}
+
 // GetChannelsResponse is the response DTO for getting channels
 type GetChannelsResponse struct {
 	Channels []ChannelDTO `json:"channels"`
@@ -45,6 +54,11 @@ type AddAdminRequest struct {
 	UserID string `json:"user_id" binding:"required"`
 }
 
+// GetChannelAdminsResponse is the response DTO for getting channel admins
+type GetChannelAdminsResponse struct {
+	Admins []ChannelAdminDTO `json:"admins"`
+}
+
 // UpdateAdminPermissionsRequest is the request DTO for updating admin permissions
 type UpdateAdminPermissionsRequest struct {
 	CanPostMessages   bool `json:"can_post_messages"`
@@ -74,6 +88,8 @@ type ChannelSettings struct {
 	AdminsCanPost     bool `json:"admins_can_post"`
 	LinkPreview       bool `json:"link_preview"`
 	ForwardingAllowed bool `json:"forwarding_allowed"`
+	EditWindowSeconds int  `json:"edit_window_seconds"`
+	SubscriberVotes   bool `json:"subscriber_votes"`
 }
 
 // SubscriberDTO is the subscriber data transfer object
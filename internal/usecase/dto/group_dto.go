@@ -29,9 +29,11 @@ type UpdateGroupRequest struct {
 
 // UpdateGroupSettingsRequest is the request DTO for updating group settings
 type UpdateGroupSettingsRequest struct {
-	WhoCanMessage    string `json:"who_can_message"`
-	WhoCanAddMembers string `json:"who_can_add_members"`
-	WhoCanEditInfo   string `json:"who_can_edit_info"`
+	WhoCanMessage     string `json:"who_can_message"`
+	WhoCanAddMembers  string `json:"who_can_add_members"`
+	WhoCanEditInfo    string `json:"who_can_edit_info"`
+	EditWindowSeconds *int   `json:"edit_window_seconds"` // nil keeps the current window
+	LinkPreview       *bool  `json:"link_preview"`        // nil keeps the current setting
 }
 
 // AddMemberRequest is the request DTO for adding a member
@@ -67,9 +69,11 @@ type GroupDTO struct {
 
 // GroupSettings represents group settings in DTO
 type GroupSettings struct {
-	WhoCanMessage    string `json:"who_can_message"`
-	WhoCanAddMembers string `json:"who_can_add_members"`
-	WhoCanEditInfo   string `json:"who_can_edit_info"`
+	WhoCanMessage     string `json:"who_can_message"`
+	WhoCanAddMembers  string `json:"who_can_add_members"`
+	WhoCanEditInfo    string `json:"who_can_edit_info"`
+	EditWindowSeconds int    `json:"edit_window_seconds"`
+	LinkPreview       bool   `json:"link_preview"`
 }
 
 // MemberDTO is the member data transfer object
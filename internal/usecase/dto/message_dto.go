@@ -8,11 +8,34 @@ import (
 
 // SendMessageRequest is the request for sending a message
 type SendMessageRequest struct {
-	RecipientID uuid.UUID `json:"recipient_id" validate:"required"`
-	Content     string    `json:"content" validate:"required"`
-	ContentType string    `json:"content_type" validate:"required"`
-	Signature   string    `json:"signature"`
-	ReplyToID   *uuid.UUID `json:"reply_to_id,omitempty"`
+	RecipientID     uuid.UUID          `json:"recipient_id" validate:"required"`
+	Content         string             `json:"content" validate:"required"`
+	ContentType     string             `json:"content_type" validate:"required"`
+	Signature       string             `json:"signature"`
+	ReplyToID       *uuid.UUID         `json:"reply_to_id,omitempty"`
+	ClientMessageID string             `json:"client_message_id,omitempty"` // Idempotency key, a retry with the same ID returns the original message
+	Poll            *CreatePollRequest `json:"poll,omitempty"`              // Required when content_type is poll, the content is the question
+}
+
+// SendConversationMessageRequest is the request for sending a message into an existing conversation
+type SendConversationMessageRequest struct {
+	ConversationID  uuid.UUID          `json:"conversation_id" validate:"required"`
+	Content         string             `json:"content" validate:"required"`
+	ContentType     string             `json:"content_type" validate:"required"`
+	Signature       string             `json:"signature"`
+	ReplyToID       *uuid.UUID         `json:"reply_to_id,omitempty"`
+	ClientMessageID string             `json:"client_message_id,omitempty"` // Idempotency key, a retry with the same ID returns the original message
+	Poll            *CreatePollRequest `json:"poll,omitempty"`              // Required when content_type is poll, the content is the question
+}
+
+// CreatePollRequest holds the options and settings of a new poll
+type CreatePollRequest struct {
+	Options        []string   `json:"options"`
+	MultipleChoice bool       `json:"multiple_choice"`
+	Anonymous      bool       `json:"anonymous"`
+	Quiz           bool       `json:"quiz"`
+	CorrectOption  *int       `json:"correct_option,omitempty"` // Position of the right answer, required for quizzes
+	ClosesAt       *time.Time `json:"closes_at,omitempty"`
 }
 
 // SendMessageResponse is the response for sending a message
@@ -22,21 +45,31 @@ type SendMessageResponse struct {
 
 // GetMessagesRequest is the request for getting messages
 type GetMessagesRequest struct {
-	ConversationID uuid.UUID `json:"conversation_id" validate:"required"`
-	Limit          int       `json:"limit"`
-	Offset         int       `json:"offset"`
+	ConversationID uuid.UUID  `json:"conversation_id" validate:"required"`
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+	Before         string     `json:"before,omitempty"` // Message ID or cursor; returns older messages
+	After          string     `json:"after,omitempty"`  // Message ID or cursor; returns newer messages
+	AroundID       *uuid.UUID `json:"around_id,omitempty"`
 }
 
 // GetMessagesResponse is the response for getting messages
 type GetMessagesResponse struct {
-	Messages []MessageDTO `json:"messages"`
-	Total    int64        `json:"total"`
+	Messages   []MessageDTO `json:"messages"`
+	Total      int64        `json:"total"`
+	NextCursor string       `json:"next_cursor,omitempty"` // Pass as before to load older messages
+	PrevCursor string       `json:"prev_cursor,omitempty"` // Pass as after to load newer messages
+	HasOlder   bool         `json:"has_older"`
+	HasNewer   bool         `json:"has_newer"`
 }
 
 // GetConversationsRequest is the request for getting conversations
 type GetConversationsRequest struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
+	Limit    int        `json:"limit"`
+	Offset   int        `json:"offset"`
+	FolderID *uuid.UUID `json:"folder_id,omitempty"` // Only conversations in this folder
+	Pinned   *bool      `json:"pinned,omitempty"`    // Only pinned, or only unpinned conversations
+	Muted    *bool      `json:"muted,omitempty"`     // Only muted, or only unmuted conversations
 }
 
 // GetConversationsResponse is the response for getting conversations
@@ -46,7 +79,23 @@ type GetConversationsResponse struct {
 
 // MarkAsReadRequest is the request for marking messages as read
 type MarkAsReadRequest struct {
-	ConversationID uuid.UUID `json:"conversation_id" validate:"required"`
+	ConversationID uuid.UUID  `json:"conversation_id" validate:"required"`
+	MessageID      *uuid.UUID `json:"message_id,omitempty"` // Read up to this message, nil reads up to the latest one
+}
+
+// MarkAsUnreadRequest is the request for marking messages as unread
+type MarkAsUnreadRequest struct {
+	ConversationID uuid.UUID  `json:"conversation_id" validate:"required"`
+	MessageID      *uuid.UUID `json:"message_id,omitempty"` // First unread message, nil marks the latest message from others unread
+}
+
+// ReadStateDTO is the data transfer object for a user's read marker in a conversation
+type ReadStateDTO struct {
+	ConversationID     string     `json:"conversation_id"`
+	LastReadMessageID  *string    `json:"last_read_message_id,omitempty"`
+	LastReadAt         *time.Time `json:"last_read_at,omitempty"` // Messages created up to this time are read
+	UnreadCount        int        `json:"unread_count"`
+	UnreadMentionCount int        `json:"unread_mention_count"`
 }
 
 // CreateConversationRequest is the request for creating a conversation
@@ -62,29 +111,273 @@ type CreateConversationResponse struct {
 
 // MessageDTO is the data transfer object for message
 type MessageDTO struct {
-	ID             string              `json:"id"`
-	ConversationID string              `json:"conversation_id"`
-	SenderID       string              `json:"sender_id"`
-	Content        string              `json:"content"`
-	ContentType    string              `json:"content_type"`
-	Signature      string              `json:"signature,omitempty"`
-	ReplyToID      *string             `json:"reply_to_id,omitempty"`
-	Status         string              `json:"status"`
-	CreatedAt      time.Time           `json:"created_at"`
-	UpdatedAt      time.Time           `json:"updated_at"`
-	Sender         *UserDTO            `json:"sender,omitempty"`
-	Reactions      map[string][]string `json:"reactions,omitempty"`
-	IsPinned       bool                `json:"is_pinned"`           // Deprecated: use PinnedBy instead
-	PinnedBy       []string            `json:"pinned_by,omitempty"` // List of user IDs who pinned this message
+	ID              string              `json:"id"`
+	ConversationID  string              `json:"conversation_id"`
+	SenderID        string              `json:"sender_id"`
+	Content         string              `json:"content"`
+	ContentType     string              `json:"content_type"`
+	Signature       string              `json:"signature,omitempty"`
+	ReplyToID       *string             `json:"reply_to_id,omitempty"`
+	Status          string              `json:"status"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+	Sender          *UserDTO            `json:"sender,omitempty"`
+	Reactions       map[string][]string `json:"reactions,omitempty"`
+	IsPinned        bool                `json:"is_pinned"`           // Deprecated: use PinnedBy instead
+	PinnedBy        []string            `json:"pinned_by,omitempty"` // List of user IDs who pinned this message
+	ExpiresAt       *time.Time          `json:"expires_at,omitempty"`
+	ThreadID        *string             `json:"thread_id,omitempty"` // Root message ID if this is a thread reply
+	Thread          *ThreadSummaryDTO   `json:"thread,omitempty"`    // Reply info if this is a thread root
+	Receipts        *ReceiptCountsDTO   `json:"receipts,omitempty"`  // Delivery info, only set on the requesting user's own messages
+	EditedAt        *time.Time          `json:"edited_at,omitempty"`
+	EditCount       int                 `json:"edit_count"`
+	DeletedAt       *time.Time          `json:"deleted_at,omitempty"` // Set on tombstones of messages deleted for everyone
+	DeletedBy       *string             `json:"deleted_by,omitempty"`
+	ClientMessageID string              `json:"client_message_id,omitempty"` // Echoed so the sender's devices can match optimistic messages
+	LinkPreviews    []LinkPreviewDTO    `json:"link_previews,omitempty"`     // Filled in after sending, announced with message.updated
+	Poll            *PollDTO            `json:"poll,omitempty"`
+}
+
+// LinkPreviewDTO is the preview of a link in a message
+type LinkPreviewDTO struct {
+	URL         string `json:"url"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	ImageURL    string `json:"image_url,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// PollDTO is the poll of a poll message with its results
+type PollDTO struct {
+	MessageID      string          `json:"message_id"`
+	Question       string          `json:"question"`
+	Options        []PollOptionDTO `json:"options"`
+	MultipleChoice bool            `json:"multiple_choice"`
+	Anonymous      bool            `json:"anonymous"`
+	Quiz           bool            `json:"quiz"`
+	CorrectOption  *int            `json:"correct_option,omitempty"` // Only shown once the user answered or the quiz closed
+	ClosesAt       *time.Time      `json:"closes_at,omitempty"`
+	IsClosed       bool            `json:"is_closed"`
+	TotalVoters    int             `json:"total_voters"`
+	Voted          []int           `json:"voted,omitempty"` // Options chosen by the requesting user
+}
+
+// PollOptionDTO is one answer option of a poll
+type PollOptionDTO struct {
+	Position  int      `json:"position"`
+	Text      string   `json:"text"`
+	VoteCount int      `json:"vote_count"`
+	Voters    []string `json:"voters,omitempty"` // Only set on public polls
+}
+
+// VotePollRequest is the request for voting in a poll
+type VotePollRequest struct {
+	Options []int `json:"options" validate:"required"`
+}
+
+// ThreadSummaryDTO is the reply information of a thread root message
+type ThreadSummaryDTO struct {
+	ReplyCount    int       `json:"reply_count"`
+	LastReplyID   string    `json:"last_reply_id"`
+	LastReplyAt   time.Time `json:"last_reply_at"`
+	LastReplierID string    `json:"last_replier_id"`
 }
 
 // ConversationDTO is the data transfer object for conversation
 type ConversationDTO struct {
-	ID            string     `json:"id"`
-	Type          string     `json:"type"`
-	Participants  []UserDTO  `json:"participants"`
-	LastMessage   *MessageDTO `json:"last_message,omitempty"`
-	UnreadCount   int        `json:"unread_count"`
-	CreatedAt     time.Time  `json:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at"`
+	ID                 string      `json:"id"`
+	Type               string      `json:"type"`
+	Participants       []UserDTO   `json:"participants"`
+	LastMessage        *MessageDTO `json:"last_message,omitempty"`
+	UnreadCount        int         `json:"unread_count"`
+	UnreadMentionCount int         `json:"unread_mention_count"`
+	IsRequest          bool        `json:"is_request"`      // In the current user's message requests
+	RequestPending     bool        `json:"request_pending"` // A message request not accepted yet, by either side
+	LastReadMessageID  *string     `json:"last_read_message_id,omitempty"`
+	IsArchived         bool        `json:"is_archived"`
+	IsPinned           bool        `json:"is_pinned"`
+	IsMuted            bool        `json:"is_muted"`
+	MutedUntil         *time.Time  `json:"muted_until,omitempty"` // Not set when muted without a time limit
+	FolderIDs          []string    `json:"folder_ids,omitempty"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+}
+
+// MuteConversationRequest is the request for muting a conversation
+type MuteConversationRequest struct {
+	Until *time.Time `json:"until,omitempty"` // Nil mutes until unmuted
+}
+
+// ReorderPinnedRequest is the request for ordering the pinned conversations
+type ReorderPinnedRequest struct {
+	ConversationIDs []uuid.UUID `json:"conversation_ids" validate:"required"` // Every pinned conversation, top first
+}
+
+// FolderDTO is the data transfer object for a conversation folder
+type FolderDTO struct {
+	ID                string    `json:"id"`
+	Name              string    `json:"name"`
+	ConversationCount int       `json:"conversation_count"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// GetFoldersResponse is the response for listing conversation folders
+type GetFoldersResponse struct {
+	Folders []FolderDTO `json:"folders"`
+}
+
+// GetMentionsRequest is the request for getting the mentions feed
+type GetMentionsRequest struct {
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	UnreadOnly     bool       `json:"unread_only"`
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+}
+
+// MentionDTO is the data transfer object for a mention of the current user
+type MentionDTO struct {
+	ID             string      `json:"id"`
+	MessageID      string      `json:"message_id"`
+	ConversationID string      `json:"conversation_id"`
+	Position       int         `json:"position"`
+	Length         int         `json:"length"`
+	Read           bool        `json:"read"`
+	CreatedAt      time.Time   `json:"created_at"`
+	Message        *MessageDTO `json:"message,omitempty"`
+}
+
+// GetMentionsResponse is the response for getting the mentions feed
+type GetMentionsResponse struct {
+	Mentions     []MentionDTO     `json:"mentions"`
+	UnreadCounts map[string]int64 `json:"unread_counts"` // conversation ID -> unread mentions
+	TotalUnread  int64            `json:"total_unread"`
+}
+
+// GetThreadRequest is the request for getting the replies of a thread
+type GetThreadRequest struct {
+	MessageID uuid.UUID `json:"message_id"` // Root message or any reply in the thread
+	After     string    `json:"after,omitempty"`
+	Limit     int       `json:"limit"`
+}
+
+// GetThreadResponse is the response for getting the replies of a thread
+type GetThreadResponse struct {
+	Root        MessageDTO   `json:"root"`
+	Replies     []MessageDTO `json:"replies"`
+	IsFollowing bool         `json:"is_following"`
+	NextCursor  string       `json:"next_cursor,omitempty"` // Pass as after to load more replies
+	HasMore     bool         `json:"has_more"`
+}
+
+// ReceiptCountsDTO is the number of recipients who received and read a message
+type ReceiptCountsDTO struct {
+	Delivered int `json:"delivered"`
+	Read      int `json:"read"`
+}
+
+// MessageReceiptDTO is the delivery and read time of a message for one recipient
+type MessageReceiptDTO struct {
+	UserID      string     `json:"user_id"`
+	User        *UserDTO   `json:"user,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	ReadAt      *time.Time `json:"read_at,omitempty"`
+}
+
+// GetMessageReceiptsResponse is the response for getting who received and read a message
+type GetMessageReceiptsResponse struct {
+	MessageID  string              `json:"message_id"`
+	Recipients int                 `json:"recipients"` // Current participants other than the sender
+	Delivered  int                 `json:"delivered"`
+	Read       int                 `json:"read"`
+	Receipts   []MessageReceiptDTO `json:"receipts"`
+}
+
+// MessageRevisionDTO is a prior version of a message's content
+type MessageRevisionDTO struct {
+	ID          string    `json:"id"`
+	Revision    int       `json:"revision"` // 1 is the original content
+	Content     string    `json:"content"`
+	ContentType string    `json:"content_type"`
+	EditedBy    string    `json:"edited_by"`
+	EditedAt    time.Time `json:"edited_at"` // When this revision was replaced
+}
+
+// GetMessageRevisionsResponse is the response for getting the edit history of a message
+type GetMessageRevisionsResponse struct {
+	MessageID      string               `json:"message_id"`
+	CurrentContent string               `json:"current_content"`
+	EditCount      int                  `json:"edit_count"`
+	EditedAt       *time.Time           `json:"edited_at,omitempty"`
+	Revisions      []MessageRevisionDTO `json:"revisions"`
+}
+
+// ScheduleMessageRequest is the request for scheduling a message
+type ScheduleMessageRequest struct {
+	ConversationID uuid.UUID  `json:"conversation_id" validate:"required"`
+	Content        string     `json:"content" validate:"required"`
+	ContentType    string     `json:"content_type" validate:"required"`
+	ReplyToID      *uuid.UUID `json:"reply_to_id,omitempty"`
+	SendAt         time.Time  `json:"send_at" validate:"required"`
+}
+
+// UpdateScheduledMessageRequest is the request for editing a pending scheduled message.
+// Fields left nil are kept
+type UpdateScheduledMessageRequest struct {
+	Content     *string    `json:"content,omitempty"`
+	ContentType *string    `json:"content_type,omitempty"`
+	SendAt      *time.Time `json:"send_at,omitempty"`
+}
+
+// GetScheduledMessagesRequest is the request for listing the user's scheduled messages
+type GetScheduledMessagesRequest struct {
+	ConversationID *uuid.UUID `json:"conversation_id,omitempty"`
+	Status         string     `json:"status,omitempty"` // Defaults to pending
+	Limit          int        `json:"limit"`
+	Offset         int        `json:"offset"`
+}
+
+// ScheduledMessageDTO is the data transfer object for a scheduled message
+type ScheduledMessageDTO struct {
+	ID             string     `json:"id"` // Also the ID of the delivered message once sent
+	ConversationID string     `json:"conversation_id"`
+	SenderID       string     `json:"sender_id"`
+	Content        string     `json:"content"`
+	ContentType    string     `json:"content_type"`
+	ReplyToID      *string    `json:"reply_to_id,omitempty"`
+	SendAt         time.Time  `json:"send_at"`
+	Status         string     `json:"status"` // pending, sending, sent, canceled or failed
+	SentAt         *time.Time `json:"sent_at,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// GetScheduledMessagesResponse is the response for listing scheduled messages
+type GetScheduledMessagesResponse struct {
+	ScheduledMessages []ScheduledMessageDTO `json:"scheduled_messages"`
+}
+
+// SyncRequest is the request for the changes after a change sequence number
+type SyncRequest struct {
+	Since int64 `json:"since"` // Seq of the previous sync, 0 for a full resync
+	Limit int   `json:"limit"` // Changes to go through, not items returned
+}
+
+// DeletedMessageDTO identifies a message that is gone for the user
+type DeletedMessageDTO struct {
+	ID             string `json:"id"`
+	ConversationID string `json:"conversation_id"`
+}
+
+// SyncResponse is the current state of everything that changed after the requested sequence number.
+// Several changes of the same message or conversation are compacted into one item
+type SyncResponse struct {
+	Seq                    int64               `json:"seq"` // Pass as since on the next sync
+	HasMore                bool                `json:"has_more"`
+	FullResync             bool                `json:"full_resync"` // since is too old or unknown, reload conversations and history
+	Conversations          []ConversationDTO   `json:"conversations"`
+	RemovedConversationIDs []string            `json:"removed_conversation_ids"` // Deleted, or the user is no longer a participant
+	Messages               []MessageDTO        `json:"messages"`
+	DeletedMessages        []DeletedMessageDTO `json:"deleted_messages"`
 }
@@ -9,6 +9,7 @@ type PrivacySettingsRequest struct {
 	StatusVisibility       *string `json:"status_visibility,omitempty"`
 	ReadReceiptsEnabled    *bool   `json:"read_receipts_enabled,omitempty"`
 	TypingIndicatorEnabled *bool   `json:"typing_indicator_enabled,omitempty"`
+	WhoCanMessage          *string `json:"who_can_message,omitempty"` // "everyone", "contacts", "nobody"
 }
 
 // PrivacySettingsResponse represents privacy settings
@@ -19,6 +20,7 @@ type PrivacySettingsResponse struct {
 	StatusVisibility       string    `json:"status_visibility"`
 	ReadReceiptsEnabled    bool      `json:"read_receipts_enabled"`
 	TypingIndicatorEnabled bool      `json:"typing_indicator_enabled"`
+	WhoCanMessage          string    `json:"who_can_message"`
 	UpdatedAt              time.Time `json:"updated_at"`
 }
 
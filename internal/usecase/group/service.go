@@ -5,10 +5,14 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
 	"github.com/yourusername/sotalk/internal/domain/group"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/domain/user"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
 )
 
 // Broadcaster defines methods for WebSocket broadcasting
@@ -27,6 +31,8 @@ type service struct {
 	groupRepo        group.Repository
 	conversationRepo conversation.Repository
 	userRepo         user.Repository
+	privacyRepo      privacy.Repository
+	changeLogRepo    changelog.Repository
 	broadcaster      Broadcaster
 }
 
@@ -35,12 +41,16 @@ func NewService(
 	groupRepo group.Repository,
 	conversationRepo conversation.Repository,
 	userRepo user.Repository,
+	privacyRepo privacy.Repository,
+	changeLogRepo changelog.Repository,
 	broadcaster Broadcaster,
 ) Service {
 	return &service{
 		groupRepo:        groupRepo,
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
+		privacyRepo:      privacyRepo,
+		changeLogRepo:    changeLogRepo,
 		broadcaster:      broadcaster,
 	}
 }
@@ -94,6 +104,15 @@ func (s *service) CreateGroup(ctx context.Context, userID uuid.UUID, req *dto.Cr
 			continue
 		}
 
+		// Skip users the creator blocked or was blocked by
+		blocked, err := s.privacyRepo.IsBlockedBetween(ctx, userID, memberID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check block: %w", err)
+		}
+		if blocked {
+			continue
+		}
+
 		// Add member to group
 		member := group.NewMember(grp.ID, memberID, group.RoleMember)
 		if err := s.groupRepo.AddMember(ctx, member); err != nil {
@@ -104,6 +123,7 @@ func (s *service) CreateGroup(ctx context.Context, userID uuid.UUID, req *dto.Cr
 		convParticipant := conversation.NewParticipant(conv.ID, memberID, conversation.RoleMember)
 		s.conversationRepo.AddParticipant(ctx, convParticipant)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(conv.ID, changelog.KindMembershipUpdated))
 
 	// Get member count
 	memberCount, _ := s.groupRepo.CountMembers(ctx, grp.ID)
@@ -233,6 +253,7 @@ func (s *service) UpdateGroup(ctx context.Context, userID, groupID uuid.UUID, re
 	if err := s.groupRepo.Update(ctx, grp); err != nil {
 		return fmt.Errorf("failed to update group: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(grp.ConversationID, changelog.KindConversationUpdated))
 
 	// Broadcast group updated event
 	if s.broadcaster != nil {
@@ -269,19 +290,32 @@ func (s *service) UpdateGroupSettings(ctx context.Context, userID, groupID uuid.
 		WhoCanAddMembers: req.WhoCanAddMembers,
 		WhoCanEditInfo:   req.WhoCanEditInfo,
 	}
+	if req.EditWindowSeconds != nil {
+		settings.EditWindowSeconds = *req.EditWindowSeconds
+	} else if grp.Settings != nil {
+		settings.EditWindowSeconds = grp.Settings.EditWindowSeconds
+	}
+	if req.LinkPreview != nil {
+		settings.LinkPreview = *req.LinkPreview
+	} else if grp.Settings != nil {
+		settings.LinkPreview = grp.Settings.LinkPreview
+	}
 	grp.UpdateSettings(settings)
 
 	// Save
 	if err := s.groupRepo.Update(ctx, grp); err != nil {
 		return fmt.Errorf("failed to update group settings: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(grp.ConversationID, changelog.KindConversationUpdated))
 
 	// Broadcast group settings updated event
 	if s.broadcaster != nil {
 		settingsDTO := dto.GroupSettings{
-			WhoCanMessage:    settings.WhoCanMessage,
-			WhoCanAddMembers: settings.WhoCanAddMembers,
-			WhoCanEditInfo:   settings.WhoCanEditInfo,
+			WhoCanMessage:     settings.WhoCanMessage,
+			WhoCanAddMembers:  settings.WhoCanAddMembers,
+			WhoCanEditInfo:    settings.WhoCanEditInfo,
+			EditWindowSeconds: settings.EditWindowSeconds,
+			LinkPreview:       settings.LinkPreview,
 		}
 		go s.broadcaster.BroadcastGroupSettingsUpdated(context.Background(), grp.ConversationID, groupID.String(), userID.String(), settingsDTO)
 	}
@@ -311,6 +345,7 @@ func (s *service) DeleteGroup(ctx context.Context, userID, groupID uuid.UUID) er
 	if err := s.groupRepo.Delete(ctx, groupID); err != nil {
 		return fmt.Errorf("failed to delete group: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(grp.ConversationID, changelog.KindConversationUpdated))
 
 	return nil
 }
@@ -339,6 +374,15 @@ func (s *service) AddMember(ctx context.Context, userID, groupID uuid.UUID, req
 		return fmt.Errorf("target user not found: %w", err)
 	}
 
+	// Users cannot be added by someone they blocked or who blocked them
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, userID, targetUserID)
+	if err != nil {
+		return fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return privacy.ErrUserUnavailable
+	}
+
 	// Check if already a member
 	isMember, err := s.groupRepo.IsMember(ctx, groupID, targetUserID)
 	if err != nil {
@@ -384,6 +428,7 @@ func (s *service) AddMember(ctx context.Context, userID, groupID uuid.UUID, req
 	if err := s.conversationRepo.AddParticipant(ctx, participant); err != nil {
 		return fmt.Errorf("failed to add to conversation: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(grp.ConversationID, changelog.KindMembershipUpdated))
 
 	// Broadcast member joined event
 	if s.broadcaster != nil {
@@ -441,6 +486,7 @@ func (s *service) RemoveMember(ctx context.Context, userID, groupID, targetUserI
 	if err := s.conversationRepo.RemoveParticipant(ctx, grp.ConversationID, targetUserID); err != nil {
 		return fmt.Errorf("failed to remove from conversation: %w", err)
 	}
+	s.recordMemberRemoved(ctx, grp.ConversationID, targetUserID)
 
 	return nil
 }
@@ -480,6 +526,7 @@ func (s *service) UpdateMemberRole(ctx context.Context, userID, groupID, targetU
 	if err := s.groupRepo.UpdateMemberRole(ctx, groupID, targetUserID, role); err != nil {
 		return fmt.Errorf("failed to update role: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewConversationChange(grp.ConversationID, changelog.KindMembershipUpdated))
 
 	// Broadcast member role changed event
 	if s.broadcaster != nil {
@@ -522,6 +569,7 @@ func (s *service) LeaveGroup(ctx context.Context, userID, groupID uuid.UUID) err
 	if err := s.conversationRepo.RemoveParticipant(ctx, grp.ConversationID, userID); err != nil {
 		return fmt.Errorf("failed to leave conversation: %w", err)
 	}
+	s.recordMemberRemoved(ctx, grp.ConversationID, userID)
 
 	// Broadcast member left event
 	if s.broadcaster != nil {
@@ -531,14 +579,32 @@ func (s *service) LeaveGroup(ctx context.Context, userID, groupID uuid.UUID) err
 	return nil
 }
 
+// recordChange appends changes to the change log clients sync from, a failure is only logged
+func (s *service) recordChange(ctx context.Context, changes ...*changelog.Change) {
+	if err := s.changeLogRepo.Record(ctx, changes...); err != nil {
+		logger.Warn("Failed to record change", zap.Error(err))
+	}
+}
+
+// recordMemberRemoved records a membership change for the remaining members,
+// and one for the removed user who no longer sees the changes of the conversation
+func (s *service) recordMemberRemoved(ctx context.Context, conversationID, userID uuid.UUID) {
+	s.recordChange(ctx,
+		changelog.NewConversationChange(conversationID, changelog.KindMembershipUpdated),
+		changelog.NewConversationChange(conversationID, changelog.KindMembershipUpdated).ForUser(userID),
+	)
+}
+
 // Helper function to convert group to DTO
 func toGroupDTO(grp *group.Group, memberCount int) dto.GroupDTO {
 	var settings *dto.GroupSettings
 	if grp.Settings != nil {
 		settings = &dto.GroupSettings{
-			WhoCanMessage:    grp.Settings.WhoCanMessage,
-			WhoCanAddMembers: grp.Settings.WhoCanAddMembers,
-			WhoCanEditInfo:   grp.Settings.WhoCanEditInfo,
+			WhoCanMessage:     grp.Settings.WhoCanMessage,
+			WhoCanAddMembers:  grp.Settings.WhoCanAddMembers,
+			WhoCanEditInfo:    grp.Settings.WhoCanEditInfo,
+			EditWindowSeconds: grp.Settings.EditWindowSeconds,
+			LinkPreview:       grp.Settings.LinkPreview,
 		}
 	}
 
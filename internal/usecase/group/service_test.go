@@ -0,0 +1,106 @@
+package group
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/group"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/domain/user"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// groupsRepo keeps the members added to groups
+type groupsRepo struct {
+	group.Repository
+	members []uuid.UUID
+}
+
+func (r *groupsRepo) Create(ctx context.Context, grp *group.Group) error {
+	return nil
+}
+
+func (r *groupsRepo) AddMember(ctx context.Context, member *group.Member) error {
+	r.members = append(r.members, member.UserID)
+	return nil
+}
+
+func (r *groupsRepo) CountMembers(ctx context.Context, groupID uuid.UUID) (int, error) {
+	return len(r.members), nil
+}
+
+type conversationsRepo struct {
+	conversation.Repository
+}
+
+func (r *conversationsRepo) Create(ctx context.Context, conv *conversation.Conversation) error {
+	return nil
+}
+
+func (r *conversationsRepo) AddParticipant(ctx context.Context, participant *conversation.Participant) error {
+	return nil
+}
+
+// usersRepo finds every user
+type usersRepo struct {
+	user.Repository
+}
+
+func (r *usersRepo) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	return &user.User{ID: id}, nil
+}
+
+// blocks reports the blocked users, or fails every check with err
+type blocks struct {
+	privacy.Repository
+	blocked map[uuid.UUID]bool
+	err     error
+}
+
+func (b *blocks) IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	return b.blocked[otherUserID], b.err
+}
+
+type changeLog struct {
+	changelog.Repository
+}
+
+func (l *changeLog) Record(ctx context.Context, changes ...*changelog.Change) error {
+	return nil
+}
+
+func newTestService(groups *groupsRepo, privacyRepo *blocks) Service {
+	return NewService(groups, &conversationsRepo{}, &usersRepo{}, privacyRepo, &changeLog{}, nil)
+}
+
+func TestCreateGroupSkipsBlockedMembers(t *testing.T) {
+	creator, friend, blocked := uuid.New(), uuid.New(), uuid.New()
+	groups := &groupsRepo{}
+	s := newTestService(groups, &blocks{blocked: map[uuid.UUID]bool{blocked: true}})
+
+	_, err := s.CreateGroup(context.Background(), creator, &dto.CreateGroupRequest{
+		Name:      "Team",
+		MemberIDs: []string{friend.String(), blocked.String()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{creator, friend}, groups.members)
+}
+
+func TestCreateGroupFailsWhenBlocksCannotBeChecked(t *testing.T) {
+	creator, friend := uuid.New(), uuid.New()
+	groups := &groupsRepo{}
+	s := newTestService(groups, &blocks{err: errors.New("database unavailable")})
+
+	_, err := s.CreateGroup(context.Background(), creator, &dto.CreateGroupRequest{
+		Name:      "Team",
+		MemberIDs: []string{friend.String()},
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []uuid.UUID{creator}, groups.members, "members are not added unchecked")
+}
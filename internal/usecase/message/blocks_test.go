@@ -0,0 +1,50 @@
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+func TestBlockedUsersCannotMessageEachOther(t *testing.T) {
+	s, repo, conversations, users := newSendFixture()
+	blocker, blocked, member := users.add("blocker"), users.add("blocked"), users.add("member")
+	blocks := newPrivacyRepo()
+	blocks.block(blocker.ID, blocked.ID)
+	s.privacyRepo = blocks
+
+	direct := conversations.add(conversation.TypeDirect, blocker.ID, blocked.ID)
+	group := conversations.add(conversation.TypeGroup, blocker.ID, blocked.ID, member.ID)
+	ctx := context.Background()
+
+	// Either side of the block is refused, with the same error whoever blocked whom
+	_, err := s.SendMessage(ctx, blocked.ID, &dto.SendMessageRequest{RecipientID: blocker.ID, Content: "hi", ContentType: "text"})
+	assert.Equal(t, privacy.ErrUserUnavailable, err)
+	_, err = s.SendToConversation(ctx, blocker.ID, sendRequest(direct.ID, "", "hi"))
+	assert.Equal(t, privacy.ErrUserUnavailable, err)
+	assert.Empty(t, repo.created)
+
+	// Groups they share are not affected
+	_, err = s.SendToConversation(ctx, blocked.ID, sendRequest(group.ID, "", "hi"))
+	require.NoError(t, err)
+	assert.Len(t, repo.created, 1)
+}
+
+func TestTypingAudienceLeavesOutBlockedUsers(t *testing.T) {
+	typist, blocker, member := uuid.New(), uuid.New(), uuid.New()
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, typist, blocker, member)
+	blocks := newPrivacyRepo()
+	blocks.block(blocker, typist)
+	s := &service{conversationRepo: conversations, privacyRepo: blocks}
+
+	audience, err := s.TypingAudience(context.Background(), typist, conv.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{typist, member}, audience)
+}
@@ -0,0 +1,104 @@
+package message
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ChangeLogWorker periodically prunes change log entries past the retention.
+// Clients that synced before the oldest remaining entry get a full resync.
+type ChangeLogWorker struct {
+	changeLogRepo changelog.Repository
+	retention     time.Duration
+	interval      time.Duration
+	batchSize     int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewChangeLogWorker creates a new change log pruning worker
+func NewChangeLogWorker(
+	changeLogRepo changelog.Repository,
+	retention time.Duration,
+	interval time.Duration,
+	batchSize int,
+) *ChangeLogWorker {
+	if retention <= 0 {
+		retention = 30 * 24 * time.Hour
+	}
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+
+	return &ChangeLogWorker{
+		changeLogRepo: changeLogRepo,
+		retention:     retention,
+		interval:      interval,
+		batchSize:     batchSize,
+		stop:          make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called
+func (w *ChangeLogWorker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.prune(context.Background())
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to exit and waits for the current run to finish
+func (w *ChangeLogWorker) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+}
+
+// prune deletes expired entries in batches until none are left
+func (w *ChangeLogWorker) prune(ctx context.Context) {
+	var total int64
+	defer func() {
+		if total > 0 {
+			logger.Info("Change log pruned", zap.Int64("deleted", total))
+		}
+	}()
+
+	for {
+		deleted, err := w.changeLogRepo.DeleteOlderThan(ctx, w.retention, w.batchSize)
+		if err != nil {
+			logger.Error("Failed to prune change log", zap.Error(err))
+			return
+		}
+		total += deleted
+
+		if deleted < int64(w.batchSize) {
+			return
+		}
+
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// sendsRepo stores created messages, unique per sender and client message ID
+type sendsRepo struct {
+	message.Repository
+	created []*message.Message
+	// concurrent is stored just before the next Create, as if another send won the race
+	concurrent *message.Message
+}
+
+func (r *sendsRepo) Create(ctx context.Context, msg *message.Message) error {
+	if r.concurrent != nil {
+		r.created = append(r.created, r.concurrent)
+		r.concurrent = nil
+	}
+	if _, err := r.FindByClientMessageID(ctx, msg.SenderID, msg.ClientMessageID); msg.ClientMessageID != "" && err == nil {
+		return message.ErrDuplicateClientMessage
+	}
+	r.created = append(r.created, msg)
+	return nil
+}
+
+func (r *sendsRepo) FindByClientMessageID(ctx context.Context, senderID uuid.UUID, clientMessageID string) (*message.Message, error) {
+	for _, msg := range r.created {
+		if msg.SenderID == senderID && msg.ClientMessageID == clientMessageID {
+			return msg, nil
+		}
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+func (r *sendsRepo) GetMentionsByMessageID(ctx context.Context, messageID uuid.UUID) ([]message.MessageMention, error) {
+	return nil, nil
+}
+
+func newSendFixture() (*service, *sendsRepo, *conversationsRepo, *usersRepo) {
+	repo := &sendsRepo{}
+	conversations := newConversationsRepo()
+	users := &usersRepo{}
+	s := &service{messageRepo: repo, conversationRepo: conversations, userRepo: users, changeLogRepo: &changeLog{}}
+	return s, repo, conversations, users
+}
+
+func sendRequest(conversationID uuid.UUID, clientMessageID, content string) *dto.SendConversationMessageRequest {
+	return &dto.SendConversationMessageRequest{
+		ConversationID:  conversationID,
+		Content:         content,
+		ContentType:     string(message.ContentTypeText),
+		ClientMessageID: clientMessageID,
+	}
+}
+
+func TestRetriedSendReturnsTheFirstMessage(t *testing.T) {
+	s, repo, conversations, users := newSendFixture()
+	sender := users.add("sender")
+	conv := conversations.add(conversation.TypeGroup, sender.ID)
+
+	ctx := context.Background()
+	first, err := s.SendToConversation(ctx, sender.ID, sendRequest(conv.ID, "c-1", "hello"))
+	require.NoError(t, err)
+	retried, err := s.SendToConversation(ctx, sender.ID, sendRequest(conv.ID, "c-1", "hello"))
+	require.NoError(t, err)
+
+	assert.Equal(t, first.Message.ID, retried.Message.ID)
+	assert.Equal(t, "c-1", retried.Message.ClientMessageID)
+	assert.Len(t, repo.created, 1)
+
+	// Sends without a client message ID are never deduplicated
+	_, err = s.SendToConversation(ctx, sender.ID, sendRequest(conv.ID, "", "hello"))
+	require.NoError(t, err)
+	_, err = s.SendToConversation(ctx, sender.ID, sendRequest(conv.ID, "", "hello"))
+	require.NoError(t, err)
+	assert.Len(t, repo.created, 3)
+}
+
+func TestClientMessageIDIsPerSender(t *testing.T) {
+	s, repo, conversations, users := newSendFixture()
+	alice, bob := users.add("alice"), users.add("bob")
+	conv := conversations.add(conversation.TypeGroup, alice.ID, bob.ID)
+
+	ctx := context.Background()
+	fromAlice, err := s.SendToConversation(ctx, alice.ID, sendRequest(conv.ID, "c-1", "hi"))
+	require.NoError(t, err)
+	fromBob, err := s.SendToConversation(ctx, bob.ID, sendRequest(conv.ID, "c-1", "hi"))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, fromAlice.Message.ID, fromBob.Message.ID)
+	assert.Len(t, repo.created, 2)
+}
+
+func TestClientMessageIDReusedInAnotherConversation(t *testing.T) {
+	s, repo, conversations, users := newSendFixture()
+	sender := users.add("sender")
+	first := conversations.add(conversation.TypeGroup, sender.ID)
+	second := conversations.add(conversation.TypeGroup, sender.ID)
+
+	ctx := context.Background()
+	_, err := s.SendToConversation(ctx, sender.ID, sendRequest(first.ID, "c-1", "hello"))
+	require.NoError(t, err)
+	_, err = s.SendToConversation(ctx, sender.ID, sendRequest(second.ID, "c-1", "hello"))
+	assert.Equal(t, message.ErrClientMessageIDReused, err)
+	assert.Len(t, repo.created, 1)
+}
+
+func TestConcurrentRetryReturnsTheStoredMessage(t *testing.T) {
+	s, repo, conversations, users := newSendFixture()
+	sender := users.add("sender")
+	conv := conversations.add(conversation.TypeGroup, sender.ID)
+
+	winner := message.NewMessage(conv.ID, sender.ID, "hello", message.ContentTypeText)
+	winner.SetClientMessageID("c-1")
+	repo.concurrent = winner
+
+	resp, err := s.SendToConversation(context.Background(), sender.ID, sendRequest(conv.ID, "c-1", "hello"))
+	require.NoError(t, err)
+	assert.Equal(t, winner.ID.String(), resp.Message.ID)
+	assert.Len(t, repo.created, 1)
+	assert.Nil(t, conversations.conversations[conv.ID].LastMessageID, "the winner's pipeline updates the conversation")
+}
@@ -0,0 +1,163 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/channel"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/group"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// deletesRepo stores copies of messages and who hid them
+type deletesRepo struct {
+	message.Repository
+	messages map[uuid.UUID]*message.Message
+	hidden   map[uuid.UUID][]uuid.UUID
+}
+
+func (r *deletesRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, message.ErrMessageNotFound
+	}
+	found := *msg
+	return &found, nil
+}
+
+func (r *deletesRepo) HideForUser(ctx context.Context, messageID, userID uuid.UUID) error {
+	r.hidden[messageID] = append(r.hidden[messageID], userID)
+	return nil
+}
+
+func (r *deletesRepo) MarkDeleted(ctx context.Context, msg *message.Message) error {
+	stored := *msg
+	r.messages[msg.ID] = &stored
+	return nil
+}
+
+type deleteFixture struct {
+	s       *service
+	repo    *deletesRepo
+	changes *changeLog
+	convs   *conversationsRepo
+}
+
+func newDeleteFixture(groups *groupsRepo, channels *channelsRepo) *deleteFixture {
+	f := &deleteFixture{
+		repo:    &deletesRepo{messages: make(map[uuid.UUID]*message.Message), hidden: make(map[uuid.UUID][]uuid.UUID)},
+		changes: &changeLog{},
+		convs:   newConversationsRepo(),
+	}
+	f.s = &service{messageRepo: f.repo, conversationRepo: f.convs, changeLogRepo: f.changes}
+	if groups != nil {
+		f.s.groupRepo = groups
+	}
+	if channels != nil {
+		f.s.channelRepo = channels
+	}
+	return f
+}
+
+func (f *deleteFixture) send(conv *conversation.Conversation, senderID uuid.UUID) uuid.UUID {
+	msg := &message.Message{ID: uuid.New(), ConversationID: conv.ID, SenderID: senderID, Content: "hello", CreatedAt: time.Now()}
+	f.repo.messages[msg.ID] = msg
+	return msg.ID
+}
+
+func TestDeleteForMeHidesOnlyForTheUser(t *testing.T) {
+	f := newDeleteFixture(nil, nil)
+	sender, reader := uuid.New(), uuid.New()
+	conv := f.convs.add(conversation.TypeDirect, sender, reader)
+	msgID := f.send(conv, sender)
+
+	// Anyone in the conversation can hide any message from their own history
+	require.NoError(t, f.s.DeleteMessage(context.Background(), reader, msgID, message.DeleteForMe))
+
+	assert.Equal(t, []uuid.UUID{reader}, f.repo.hidden[msgID])
+	assert.False(t, f.repo.messages[msgID].IsDeleted())
+	require.Len(t, f.changes.changes, 1)
+	assert.Equal(t, &reader, f.changes.changes[0].UserID)
+}
+
+func TestDeleteForEveryoneLeavesATombstone(t *testing.T) {
+	f := newDeleteFixture(nil, nil)
+	sender, reader := uuid.New(), uuid.New()
+	conv := f.convs.add(conversation.TypeDirect, sender, reader)
+	msgID := f.send(conv, sender)
+
+	ctx := context.Background()
+	require.NoError(t, f.s.DeleteMessage(ctx, sender, msgID, message.DeleteForEveryone))
+
+	stored := f.repo.messages[msgID]
+	assert.True(t, stored.IsDeleted())
+	assert.Empty(t, stored.Content)
+	assert.Equal(t, &sender, stored.DeletedBy)
+	require.Len(t, f.changes.changes, 1)
+	assert.Nil(t, f.changes.changes[0].UserID, "seen by every participant")
+
+	assert.Equal(t, message.ErrMessageDeleted, f.s.DeleteMessage(ctx, sender, msgID, message.DeleteForEveryone))
+}
+
+func TestDeleteMessageRejects(t *testing.T) {
+	f := newDeleteFixture(nil, nil)
+	sender, reader := uuid.New(), uuid.New()
+	conv := f.convs.add(conversation.TypeDirect, sender, reader)
+	msgID := f.send(conv, sender)
+
+	ctx := context.Background()
+	assert.Equal(t, conversation.ErrNotParticipant, f.s.DeleteMessage(ctx, uuid.New(), msgID, message.DeleteForMe))
+	assert.Equal(t, message.ErrUnauthorized, f.s.DeleteMessage(ctx, reader, msgID, message.DeleteForEveryone))
+	assert.Equal(t, message.ErrInvalidDeleteScope, f.s.DeleteMessage(ctx, sender, msgID, message.DeleteScope("all")))
+	assert.False(t, f.repo.messages[msgID].IsDeleted())
+}
+
+func TestModeratorsDeleteForEveryone(t *testing.T) {
+	owner, admin, moderator, deleter, poster, member := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	groups := &groupsRepo{group: &group.Group{ID: uuid.New()}, members: map[uuid.UUID]*group.Member{
+		admin:     {UserID: admin, Role: group.RoleAdmin},
+		moderator: {UserID: moderator, Role: group.RoleModerator},
+		member:    {UserID: member, Role: group.RoleMember},
+	}}
+	channels := &channelsRepo{channel: &channel.Channel{ID: uuid.New(), OwnerID: owner}, admins: map[uuid.UUID]*channel.Admin{
+		deleter: {UserID: deleter, Permissions: &channel.AdminPermissions{CanDeleteMessages: true}},
+		poster:  {UserID: poster, Permissions: &channel.AdminPermissions{CanPostMessages: true}},
+	}}
+
+	tests := []struct {
+		name     string
+		convType conversation.Type
+		actor    uuid.UUID
+		wantErr  error
+	}{
+		{name: "group admin", convType: conversation.TypeGroup, actor: admin},
+		{name: "group moderator", convType: conversation.TypeGroup, actor: moderator},
+		{name: "group member", convType: conversation.TypeGroup, actor: member, wantErr: message.ErrUnauthorized},
+		{name: "channel owner", convType: conversation.TypeChannel, actor: owner},
+		{name: "channel admin allowed to delete", convType: conversation.TypeChannel, actor: deleter},
+		{name: "channel admin without delete permission", convType: conversation.TypeChannel, actor: poster, wantErr: message.ErrUnauthorized},
+		{name: "channel subscriber", convType: conversation.TypeChannel, actor: member, wantErr: message.ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newDeleteFixture(groups, channels)
+			author := uuid.New()
+			conv := f.convs.add(tt.convType, author, tt.actor)
+			msgID := f.send(conv, author)
+
+			err := f.s.DeleteMessage(context.Background(), tt.actor, msgID, message.DeleteForEveryone)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil, f.repo.messages[msgID].IsDeleted())
+			if tt.wantErr == nil {
+				assert.Equal(t, &tt.actor, f.repo.messages[msgID].DeletedBy)
+			}
+		})
+	}
+}
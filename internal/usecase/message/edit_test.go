@@ -0,0 +1,185 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/group"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// editsRepo stores messages by value and the revisions their edits replaced
+type editsRepo struct {
+	message.Repository
+	messages  map[uuid.UUID]message.Message
+	revisions map[uuid.UUID][]message.MessageRevision
+}
+
+func newEditsRepo() *editsRepo {
+	return &editsRepo{
+		messages:  make(map[uuid.UUID]message.Message),
+		revisions: make(map[uuid.UUID][]message.MessageRevision),
+	}
+}
+
+func (r *editsRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	msg, ok := r.messages[id]
+	if !ok {
+		return nil, message.ErrMessageNotFound
+	}
+	return &msg, nil
+}
+
+func (r *editsRepo) SaveEdit(ctx context.Context, msg *message.Message, previous *message.MessageRevision) error {
+	if r.messages[msg.ID].EditCount != previous.Revision-1 {
+		return message.ErrEditConflict
+	}
+	r.messages[msg.ID] = *msg
+	r.revisions[msg.ID] = append(r.revisions[msg.ID], *previous)
+	return nil
+}
+
+func (r *editsRepo) GetRevisions(ctx context.Context, messageID uuid.UUID) ([]message.MessageRevision, error) {
+	return r.revisions[messageID], nil
+}
+
+func (r *editsRepo) GetMentionsByMessageID(ctx context.Context, messageID uuid.UUID) ([]message.MessageMention, error) {
+	return nil, nil
+}
+
+func (r *editsRepo) ReplaceMentions(ctx context.Context, messageID uuid.UUID, mentions []message.MessageMention) error {
+	return nil
+}
+
+type editFixture struct {
+	s       *service
+	repo    *editsRepo
+	changes *changeLog
+	users   *usersRepo
+	convs   *conversationsRepo
+	groups  *groupsRepo
+}
+
+func newEditFixture() *editFixture {
+	f := &editFixture{
+		repo:    newEditsRepo(),
+		changes: &changeLog{},
+		users:   &usersRepo{},
+		convs:   newConversationsRepo(),
+		groups:  &groupsRepo{group: &group.Group{ID: uuid.New(), Settings: &group.Settings{}}},
+	}
+	f.s = &service{
+		messageRepo:      f.repo,
+		conversationRepo: f.convs,
+		userRepo:         f.users,
+		groupRepo:        f.groups,
+		changeLogRepo:    f.changes,
+	}
+	return f
+}
+
+// send stores a message by the sender sent the given time ago
+func (f *editFixture) send(conv *conversation.Conversation, senderID uuid.UUID, content string, age time.Duration) *message.Message {
+	msg := message.Message{
+		ID:             uuid.New(),
+		ConversationID: conv.ID,
+		SenderID:       senderID,
+		Content:        content,
+		ContentType:    message.ContentTypeText,
+		CreatedAt:      time.Now().Add(-age),
+	}
+	f.repo.messages[msg.ID] = msg
+	return &msg
+}
+
+func TestEditMessageKeepsRevisions(t *testing.T) {
+	f := newEditFixture()
+	sender := f.users.add("sender")
+	reader := f.users.add("reader")
+	conv := f.convs.add(conversation.TypeDirect, sender.ID, reader.ID)
+	msg := f.send(conv, sender.ID, "first", 0)
+
+	ctx := context.Background()
+	_, err := f.s.EditMessage(ctx, sender.ID, msg.ID, "second")
+	require.NoError(t, err)
+	edited, err := f.s.EditMessage(ctx, sender.ID, msg.ID, "third")
+	require.NoError(t, err)
+	assert.Equal(t, "third", edited.Content)
+	assert.Len(t, f.changes.changes, 2)
+
+	history, err := f.s.GetMessageRevisions(ctx, reader.ID, msg.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "third", history.CurrentContent)
+	assert.Equal(t, 2, history.EditCount)
+	require.Len(t, history.Revisions, 2)
+	assert.Equal(t, 1, history.Revisions[0].Revision)
+	assert.Equal(t, "first", history.Revisions[0].Content)
+	assert.Equal(t, 2, history.Revisions[1].Revision)
+	assert.Equal(t, "second", history.Revisions[1].Content)
+
+	_, err = f.s.GetMessageRevisions(ctx, uuid.New(), msg.ID)
+	assert.Equal(t, conversation.ErrNotParticipant, err)
+}
+
+func TestEditMessageRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		convType   conversation.Type
+		editWindow int
+		age        time.Duration
+		byOther    bool
+		prepare    func(msg *message.Message)
+		wantErr    error
+	}{
+		{name: "sender edits", convType: conversation.TypeDirect},
+		{name: "someone else", convType: conversation.TypeDirect, byOther: true, wantErr: message.ErrUnauthorized},
+		{
+			name: "deleted message", convType: conversation.TypeDirect, wantErr: message.ErrMessageDeleted,
+			prepare: func(msg *message.Message) { msg.Tombstone(msg.SenderID) },
+		},
+		{
+			name: "poll", convType: conversation.TypeDirect, wantErr: message.ErrPollUnsupported,
+			prepare: func(msg *message.Message) { msg.ContentType = message.ContentTypePoll },
+		},
+		{name: "direct messages have no window", convType: conversation.TypeDirect, age: 24 * time.Hour},
+		{name: "group without a window", convType: conversation.TypeGroup, age: 24 * time.Hour},
+		{name: "within the group window", convType: conversation.TypeGroup, editWindow: 60, age: 30 * time.Second},
+		{name: "past the group window", convType: conversation.TypeGroup, editWindow: 60, age: 2 * time.Minute, wantErr: message.ErrEditWindowExpired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newEditFixture()
+			f.groups.group.Settings.EditWindowSeconds = tt.editWindow
+			sender := f.users.add("sender")
+			other := f.users.add("other")
+			conv := f.convs.add(tt.convType, sender.ID, other.ID)
+			msg := f.send(conv, sender.ID, "hello", tt.age)
+			if tt.prepare != nil {
+				tt.prepare(msg)
+				f.repo.messages[msg.ID] = *msg
+			}
+
+			editor := sender.ID
+			if tt.byOther {
+				editor = other.ID
+			}
+			_, err := f.s.EditMessage(context.Background(), editor, msg.ID, "edited")
+			assert.Equal(t, tt.wantErr, err)
+
+			stored := f.repo.messages[msg.ID]
+			if tt.wantErr == nil {
+				assert.Equal(t, "edited", stored.Content)
+				assert.Len(t, f.repo.revisions[msg.ID], 1)
+			} else {
+				assert.NotEqual(t, "edited", stored.Content)
+				assert.Empty(t, f.repo.revisions[msg.ID])
+			}
+		})
+	}
+}
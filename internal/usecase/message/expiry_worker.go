@@ -0,0 +1,163 @@
+package message
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/media"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/infrastructure/storage"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// ExpiryWorker periodically removes messages whose disappearing timer has elapsed.
+// Rows are locked while they are deleted, so any number of API replicas can run it.
+type ExpiryWorker struct {
+	messageRepo    message.Repository
+	mediaRepo      media.Repository
+	changeLogRepo  changelog.Repository
+	storageService storage.Service
+	wsBroadcaster  WSBroadcaster
+	unreadCounter  UnreadCounter
+	interval       time.Duration
+	batchSize      int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewExpiryWorker creates a new disappearing messages worker
+func NewExpiryWorker(
+	messageRepo message.Repository,
+	mediaRepo media.Repository,
+	changeLogRepo changelog.Repository,
+	storageService storage.Service,
+	wsBroadcaster WSBroadcaster,
+	unreadCounter UnreadCounter,
+	interval time.Duration,
+	batchSize int,
+) *ExpiryWorker {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &ExpiryWorker{
+		messageRepo:    messageRepo,
+		mediaRepo:      mediaRepo,
+		changeLogRepo:  changeLogRepo,
+		storageService: storageService,
+		wsBroadcaster:  wsBroadcaster,
+		unreadCounter:  unreadCounter,
+		interval:       interval,
+		batchSize:      batchSize,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called
+func (w *ExpiryWorker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.purgeExpired(context.Background())
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to exit and waits for the current run to finish
+func (w *ExpiryWorker) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+}
+
+// purgeExpired deletes expired messages in batches until none are left.
+// A failed batch ends the run, it is retried on the next tick.
+func (w *ExpiryWorker) purgeExpired(ctx context.Context) {
+	for {
+		deleted, err := w.messageRepo.DeleteExpired(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			logger.Error("Failed to delete expired messages", zap.Error(err))
+			return
+		}
+
+		conversationIDs := make(map[uuid.UUID]bool)
+		for _, msg := range deleted {
+			w.messageDeleted(ctx, msg)
+			conversationIDs[msg.ConversationID] = true
+		}
+
+		// The messages may have been unread, have the counts recounted
+		if w.unreadCounter != nil {
+			for conversationID := range conversationIDs {
+				if err := w.unreadCounter.ResetConversationUnreadCounts(ctx, conversationID); err != nil {
+					logger.Warn("Failed to reset unread counts", zap.String("conversation_id", conversationID.String()), zap.Error(err))
+				}
+			}
+		}
+
+		if len(deleted) < w.batchSize {
+			return
+		}
+
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+	}
+}
+
+// messageDeleted removes the media of a deleted expired message and notifies participants
+func (w *ExpiryWorker) messageDeleted(ctx context.Context, msg *message.Message) {
+	// The message is gone first, so it never points at files that were removed
+	if w.mediaRepo != nil {
+		attachments, err := w.mediaRepo.FindByMessageID(ctx, msg.ID)
+		if err != nil {
+			logger.Warn("Failed to find media for expired message",
+				zap.String("message_id", msg.ID.String()),
+				zap.Error(err),
+			)
+		}
+		for _, m := range attachments {
+			if w.storageService != nil {
+				if err := w.storageService.Delete(ctx, m.URL); err != nil {
+					logger.Warn("Failed to delete media file", zap.String("media_id", m.ID.String()), zap.Error(err))
+				}
+			}
+			if err := w.mediaRepo.Delete(ctx, m.ID); err != nil {
+				logger.Warn("Failed to delete media record", zap.String("media_id", m.ID.String()), zap.Error(err))
+			}
+		}
+	}
+
+	change := changelog.NewMessageChange(msg.ConversationID, msg.ID, changelog.KindMessageDeleted)
+	if err := w.changeLogRepo.Record(ctx, change); err != nil {
+		logger.Warn("Failed to record expired message deletion", zap.String("message_id", msg.ID.String()), zap.Error(err))
+	}
+
+	if w.wsBroadcaster != nil {
+		if err := w.wsBroadcaster.BroadcastMessageDeleted(ctx, msg.ConversationID, msg.ID.String(), ""); err != nil {
+			logger.Error("Failed to broadcast expired message deletion",
+				zap.String("message_id", msg.ID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+}
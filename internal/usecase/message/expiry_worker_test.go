@@ -0,0 +1,120 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/sotalk/internal/domain/media"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// expiryMessageRepo hands out expired messages in batches, recording what happened in calls
+type expiryMessageRepo struct {
+	message.Repository
+	batches [][]*message.Message
+	err     error
+	calls   *[]string
+}
+
+func (r *expiryMessageRepo) DeleteExpired(ctx context.Context, before time.Time, limit int) ([]*message.Message, error) {
+	*r.calls = append(*r.calls, "delete_expired")
+	if r.err != nil {
+		return nil, r.err
+	}
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+type expiryMediaRepo struct {
+	media.Repository
+	attachments map[uuid.UUID][]*media.Media
+	calls       *[]string
+}
+
+func (r *expiryMediaRepo) FindByMessageID(ctx context.Context, messageID uuid.UUID) ([]*media.Media, error) {
+	return r.attachments[messageID], nil
+}
+
+func (r *expiryMediaRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	*r.calls = append(*r.calls, "delete_media")
+	return nil
+}
+
+type expiryUnreadCounter struct {
+	UnreadCounter
+	resets map[uuid.UUID]int
+}
+
+func (c *expiryUnreadCounter) ResetConversationUnreadCounts(ctx context.Context, conversationID uuid.UUID) error {
+	c.resets[conversationID]++
+	return nil
+}
+
+func newExpiryUnreadCounter() *expiryUnreadCounter {
+	return &expiryUnreadCounter{resets: make(map[uuid.UUID]int)}
+}
+
+func newExpiryTestWorker(repo *expiryMessageRepo, mediaRepo *expiryMediaRepo, counter *expiryUnreadCounter, batchSize int) *ExpiryWorker {
+	return NewExpiryWorker(repo, mediaRepo, &changeLog{}, nil, nil, counter, time.Minute, batchSize)
+}
+
+func expiredMessage(conversationID uuid.UUID) *message.Message {
+	return &message.Message{ID: uuid.New(), ConversationID: conversationID}
+}
+
+func TestExpiryWorkerDeletesBatchesUntilShort(t *testing.T) {
+	var calls []string
+	conv := uuid.New()
+	repo := &expiryMessageRepo{
+		batches: [][]*message.Message{
+			{expiredMessage(conv), expiredMessage(conv)},
+			{expiredMessage(conv)},
+			{expiredMessage(conv)},
+		},
+		calls: &calls,
+	}
+	counter := newExpiryUnreadCounter()
+	w := newExpiryTestWorker(repo, &expiryMediaRepo{calls: &calls}, counter, 2)
+
+	w.purgeExpired(context.Background())
+
+	// The second batch came back short, so the third is left for the next run
+	assert.Equal(t, []string{"delete_expired", "delete_expired"}, calls)
+	assert.Len(t, repo.batches, 1)
+	assert.Equal(t, 2, counter.resets[conv], "counts are reset once per batch and conversation")
+}
+
+func TestExpiryWorkerStopsOnError(t *testing.T) {
+	var calls []string
+	repo := &expiryMessageRepo{err: errors.New("database unavailable"), calls: &calls}
+	w := newExpiryTestWorker(repo, &expiryMediaRepo{calls: &calls}, newExpiryUnreadCounter(), 1)
+
+	w.purgeExpired(context.Background())
+
+	assert.Equal(t, []string{"delete_expired"}, calls, "a failing batch must not be retried in a loop")
+}
+
+func TestExpiryWorkerRemovesMediaAfterMessage(t *testing.T) {
+	var calls []string
+	msg := expiredMessage(uuid.New())
+	repo := &expiryMessageRepo{batches: [][]*message.Message{{msg}}, calls: &calls}
+	mediaRepo := &expiryMediaRepo{
+		attachments: map[uuid.UUID][]*media.Media{
+			msg.ID: {{ID: uuid.New()}, {ID: uuid.New()}},
+		},
+		calls: &calls,
+	}
+	w := newExpiryTestWorker(repo, mediaRepo, newExpiryUnreadCounter(), 10)
+
+	w.purgeExpired(context.Background())
+
+	assert.Equal(t, []string{"delete_expired", "delete_media", "delete_media"}, calls)
+}
@@ -0,0 +1,222 @@
+package message
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/domain/user"
+)
+
+// conversationsRepo holds conversations and their participants in memory
+type conversationsRepo struct {
+	conversation.Repository
+	conversations map[uuid.UUID]*conversation.Conversation
+	participants  map[uuid.UUID][]*conversation.Participant
+}
+
+func newConversationsRepo() *conversationsRepo {
+	return &conversationsRepo{
+		conversations: make(map[uuid.UUID]*conversation.Conversation),
+		participants:  make(map[uuid.UUID][]*conversation.Participant),
+	}
+}
+
+// add creates a conversation of the given type between the users
+func (r *conversationsRepo) add(convType conversation.Type, userIDs ...uuid.UUID) *conversation.Conversation {
+	conv := &conversation.Conversation{ID: uuid.New(), Type: convType}
+	r.conversations[conv.ID] = conv
+	for _, userID := range userIDs {
+		r.participants[conv.ID] = append(r.participants[conv.ID], &conversation.Participant{
+			ConversationID: conv.ID,
+			UserID:         userID,
+			Role:           conversation.RoleMember,
+		})
+	}
+	return conv
+}
+
+func (r *conversationsRepo) Create(ctx context.Context, conv *conversation.Conversation) error {
+	r.conversations[conv.ID] = conv
+	return nil
+}
+
+func (r *conversationsRepo) AddParticipant(ctx context.Context, participant *conversation.Participant) error {
+	r.participants[participant.ConversationID] = append(r.participants[participant.ConversationID], participant)
+	return nil
+}
+
+func (r *conversationsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.conversations, id)
+	delete(r.participants, id)
+	return nil
+}
+
+func (r *conversationsRepo) FindDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (*conversation.Conversation, error) {
+	for id, conv := range r.conversations {
+		if conv.Type != conversation.TypeDirect {
+			continue
+		}
+		_, err1 := r.FindParticipant(ctx, id, user1ID)
+		_, err2 := r.FindParticipant(ctx, id, user2ID)
+		if err1 == nil && err2 == nil {
+			return conv, nil
+		}
+	}
+	return nil, conversation.ErrConversationNotFound
+}
+
+func (r *conversationsRepo) AcceptRequest(ctx context.Context, conversationID, userID uuid.UUID) error {
+	p, err := r.FindParticipant(ctx, conversationID, userID)
+	if err != nil || !p.IsRequest() {
+		return conversation.ErrMessageRequestNotFound
+	}
+	p.RequestedAt = nil
+	return nil
+}
+
+func (r *conversationsRepo) FindByID(ctx context.Context, id uuid.UUID) (*conversation.Conversation, error) {
+	if conv, ok := r.conversations[id]; ok {
+		return conv, nil
+	}
+	return nil, conversation.ErrConversationNotFound
+}
+
+func (r *conversationsRepo) Update(ctx context.Context, conv *conversation.Conversation) error {
+	r.conversations[conv.ID] = conv
+	return nil
+}
+
+func (r *conversationsRepo) AdvanceReadMarker(ctx context.Context, conversationID, userID, messageID uuid.UUID, readUpTo time.Time) (bool, error) {
+	p, err := r.FindParticipant(ctx, conversationID, userID)
+	if err != nil || !readUpTo.After(p.ReadUpTo()) {
+		return false, err
+	}
+	p.LastReadMessageID = &messageID
+	p.LastReadAt = &readUpTo
+	return true, nil
+}
+
+func (r *conversationsRepo) FindParticipants(ctx context.Context, conversationID uuid.UUID) ([]*conversation.Participant, error) {
+	return r.participants[conversationID], nil
+}
+
+func (r *conversationsRepo) FindParticipant(ctx context.Context, conversationID, userID uuid.UUID) (*conversation.Participant, error) {
+	for _, p := range r.participants[conversationID] {
+		if p.UserID == userID {
+			return p, nil
+		}
+	}
+	return nil, conversation.ErrNotParticipant
+}
+
+func (r *conversationsRepo) IsParticipant(ctx context.Context, conversationID, userID uuid.UUID) (bool, error) {
+	_, err := r.FindParticipant(ctx, conversationID, userID)
+	return err == nil, nil
+}
+
+// usersRepo finds the users it holds
+type usersRepo struct {
+	user.Repository
+	users map[uuid.UUID]*user.User
+}
+
+// add creates a user with the given username
+func (r *usersRepo) add(username string) *user.User {
+	if r.users == nil {
+		r.users = make(map[uuid.UUID]*user.User)
+	}
+	u := &user.User{ID: uuid.New(), Username: username}
+	r.users[u.ID] = u
+	return u
+}
+
+func (r *usersRepo) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, user.ErrUserNotFound
+}
+
+func (r *usersRepo) FindByUsername(ctx context.Context, username string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, user.ErrUserNotFound
+}
+
+// changeLog keeps the recorded changes
+type changeLog struct {
+	changelog.Repository
+	changes []*changelog.Change
+}
+
+func (l *changeLog) Record(ctx context.Context, changes ...*changelog.Change) error {
+	l.changes = append(l.changes, changes...)
+	return nil
+}
+
+// privacyRepo holds privacy settings and blocks, failing every settings lookup when err is set
+type privacyRepo struct {
+	privacy.Repository
+	settings map[uuid.UUID]*privacy.PrivacySettings
+	blocks   map[uuid.UUID][]uuid.UUID
+	err      error
+}
+
+func newPrivacyRepo() *privacyRepo {
+	return &privacyRepo{
+		settings: make(map[uuid.UUID]*privacy.PrivacySettings),
+		blocks:   make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+// set stores the user's settings, starting from the defaults
+func (r *privacyRepo) set(userID uuid.UUID, change func(settings *privacy.PrivacySettings)) {
+	settings := privacy.DefaultPrivacySettings(userID)
+	change(settings)
+	r.settings[userID] = settings
+}
+
+// block makes userID block blockedUserID
+func (r *privacyRepo) block(userID, blockedUserID uuid.UUID) {
+	r.blocks[userID] = append(r.blocks[userID], blockedUserID)
+	r.blocks[blockedUserID] = append(r.blocks[blockedUserID], userID)
+}
+
+func (r *privacyRepo) BlockUser(ctx context.Context, blocked *privacy.BlockedUser) error {
+	r.block(blocked.UserID, blocked.BlockedUserID)
+	return nil
+}
+
+func (r *privacyRepo) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*privacy.PrivacySettings, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if settings, ok := r.settings[userID]; ok {
+		return settings, nil
+	}
+	return nil, privacy.ErrPrivacySettingsNotFound
+}
+
+func (r *privacyRepo) GetBlockRelations(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	return r.blocks[userID], nil
+}
+
+func (r *privacyRepo) IsBlockedBetween(ctx context.Context, userID, otherUserID uuid.UUID) (bool, error) {
+	for _, id := range r.blocks[userID] {
+		if id == otherUserID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *privacyRepo) GetDisappearingMessagesConfig(ctx context.Context, conversationID uuid.UUID) (*privacy.DisappearingMessagesConfig, error) {
+	return nil, privacy.ErrDisappearingNotEnabled
+}
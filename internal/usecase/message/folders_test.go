@@ -0,0 +1,200 @@
+package message
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// foldersRepo keeps folders, what is in them and the mutes of participants
+type foldersRepo struct {
+	*conversationsRepo
+	folders map[uuid.UUID]*conversation.Folder
+	members map[uuid.UUID][]uuid.UUID
+}
+
+func newFoldersRepo() *foldersRepo {
+	return &foldersRepo{
+		conversationsRepo: newConversationsRepo(),
+		folders:           make(map[uuid.UUID]*conversation.Folder),
+		members:           make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func (r *foldersRepo) CreateFolder(ctx context.Context, folder *conversation.Folder) error {
+	r.folders[folder.ID] = folder
+	return nil
+}
+
+func (r *foldersRepo) FindFolderByID(ctx context.Context, id uuid.UUID) (*conversation.Folder, error) {
+	if folder, ok := r.folders[id]; ok {
+		copied := *folder
+		return &copied, nil
+	}
+	return nil, conversation.ErrFolderNotFound
+}
+
+func (r *foldersRepo) FindFoldersByUserID(ctx context.Context, userID uuid.UUID) ([]*conversation.Folder, error) {
+	var folders []*conversation.Folder
+	for _, folder := range r.folders {
+		if folder.UserID == userID {
+			copied := *folder
+			copied.ConversationCount = len(r.members[folder.ID])
+			folders = append(folders, &copied)
+		}
+	}
+	return folders, nil
+}
+
+func (r *foldersRepo) UpdateFolder(ctx context.Context, folder *conversation.Folder) error {
+	r.folders[folder.ID] = folder
+	return nil
+}
+
+func (r *foldersRepo) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	delete(r.folders, id)
+	delete(r.members, id)
+	return nil
+}
+
+func (r *foldersRepo) AddToFolder(ctx context.Context, folderID, conversationID uuid.UUID) error {
+	r.members[folderID] = append(r.members[folderID], conversationID)
+	return nil
+}
+
+func (r *foldersRepo) SetMutedUntil(ctx context.Context, conversationID, userID uuid.UUID, until *time.Time) error {
+	p, err := r.FindParticipant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	p.MutedUntil = until
+	return nil
+}
+
+func TestCreateFolder(t *testing.T) {
+	userID := uuid.New()
+	repo := newFoldersRepo()
+	s := &service{conversationRepo: repo, changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+
+	folder, err := s.CreateFolder(ctx, userID, " Work ")
+	require.NoError(t, err)
+	assert.Equal(t, "Work", folder.Name)
+
+	_, err = s.CreateFolder(ctx, userID, "work")
+	assert.Equal(t, conversation.ErrFolderNameTaken, err, "names are unique ignoring case")
+
+	_, err = s.CreateFolder(ctx, uuid.New(), "Work")
+	assert.NoError(t, err, "other users can use the same name")
+
+	_, err = s.CreateFolder(ctx, userID, strings.Repeat("a", conversation.MaxFolderNameLength+1))
+	assert.Equal(t, conversation.ErrInvalidFolderName, err)
+}
+
+func TestCreateFolderLimit(t *testing.T) {
+	userID := uuid.New()
+	s := &service{conversationRepo: newFoldersRepo(), changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+
+	for i := 0; i < conversation.MaxFolders; i++ {
+		_, err := s.CreateFolder(ctx, userID, uuid.NewString()[:8])
+		require.NoError(t, err)
+	}
+	_, err := s.CreateFolder(ctx, userID, "One more")
+	assert.Equal(t, conversation.ErrFolderLimitReached, err)
+}
+
+func TestRenameFolder(t *testing.T) {
+	userID := uuid.New()
+	repo := newFoldersRepo()
+	s := &service{conversationRepo: repo, changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+
+	work, err := s.CreateFolder(ctx, userID, "Work")
+	require.NoError(t, err)
+	_, err = s.CreateFolder(ctx, userID, "Payments")
+	require.NoError(t, err)
+	workID := uuid.MustParse(work.ID)
+
+	renamed, err := s.RenameFolder(ctx, userID, workID, "WORK")
+	require.NoError(t, err, "a folder can keep its own name")
+	assert.Equal(t, "WORK", renamed.Name)
+
+	_, err = s.RenameFolder(ctx, userID, workID, "payments")
+	assert.Equal(t, conversation.ErrFolderNameTaken, err)
+	assert.Equal(t, "WORK", repo.folders[workID].Name)
+
+	_, err = s.RenameFolder(ctx, uuid.New(), workID, "Mine")
+	assert.Equal(t, conversation.ErrFolderNotFound, err)
+}
+
+func TestFoldersOfOtherUsersAreNotFound(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	repo := newFoldersRepo()
+	conv := repo.add(conversation.TypeDirect, owner, other)
+	s := &service{conversationRepo: repo, changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+
+	folder, err := s.CreateFolder(ctx, owner, "Work")
+	require.NoError(t, err)
+	folderID := uuid.MustParse(folder.ID)
+
+	assert.Equal(t, conversation.ErrFolderNotFound, s.AddConversationToFolder(ctx, other, folderID, conv.ID))
+	assert.Equal(t, conversation.ErrFolderNotFound, s.DeleteFolder(ctx, other, folderID))
+	_, err = s.GetConversations(ctx, other, &dto.GetConversationsRequest{FolderID: &folderID})
+	assert.Equal(t, conversation.ErrFolderNotFound, err)
+	assert.Contains(t, repo.folders, folderID)
+}
+
+func TestAddConversationToFolder(t *testing.T) {
+	userID := uuid.New()
+	repo := newFoldersRepo()
+	mine := repo.add(conversation.TypeGroup, userID)
+	notMine := repo.add(conversation.TypeGroup, uuid.New())
+	s := &service{conversationRepo: repo, changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+
+	folder, err := s.CreateFolder(ctx, userID, "Work")
+	require.NoError(t, err)
+	folderID := uuid.MustParse(folder.ID)
+
+	require.NoError(t, s.AddConversationToFolder(ctx, userID, folderID, mine.ID))
+	assert.Equal(t, conversation.ErrNotParticipant, s.AddConversationToFolder(ctx, userID, folderID, notMine.ID))
+	assert.Equal(t, []uuid.UUID{mine.ID}, repo.members[folderID])
+
+	folders, err := s.GetFolders(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, folders.Folders, 1)
+	assert.Equal(t, 1, folders.Folders[0].ConversationCount)
+}
+
+func TestMuteConversation(t *testing.T) {
+	userID := uuid.New()
+	repo := newFoldersRepo()
+	conv := repo.add(conversation.TypeGroup, userID)
+	s := &service{conversationRepo: repo, changeLogRepo: &changeLog{}}
+	ctx := context.Background()
+	p, err := repo.FindParticipant(ctx, conv.ID, userID)
+	require.NoError(t, err)
+
+	require.NoError(t, s.MuteConversation(ctx, userID, conv.ID, &dto.MuteConversationRequest{}))
+	assert.Equal(t, &conversation.MuteForever, p.MutedUntil, "no end mutes until unmuted")
+
+	until := time.Now().Add(time.Hour)
+	require.NoError(t, s.MuteConversation(ctx, userID, conv.ID, &dto.MuteConversationRequest{Until: &until}))
+	assert.Equal(t, &until, p.MutedUntil)
+
+	past := time.Now().Add(-time.Minute)
+	assert.Equal(t, conversation.ErrInvalidMuteUntil, s.MuteConversation(ctx, userID, conv.ID, &dto.MuteConversationRequest{Until: &past}))
+	assert.Equal(t, &until, p.MutedUntil)
+
+	require.NoError(t, s.UnmuteConversation(ctx, userID, conv.ID))
+	assert.Nil(t, p.MutedUntil)
+}
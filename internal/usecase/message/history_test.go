@@ -0,0 +1,208 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// historyRepo serves one conversation's history from memory, with the same ordering as the database
+type historyRepo struct {
+	message.Repository
+	messages []*message.Message // Oldest first
+	hidden   map[uuid.UUID]bool
+}
+
+// newHistoryRepo creates a history of n messages, several of them sharing a timestamp
+func newHistoryRepo(conversationID uuid.UUID, n int) *historyRepo {
+	repo := &historyRepo{hidden: make(map[uuid.UUID]bool)}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		repo.messages = append(repo.messages, &message.Message{
+			ID:             uuid.New(),
+			ConversationID: conversationID,
+			CreatedAt:      base.Add(time.Duration(i/3) * time.Second),
+		})
+	}
+	// Ties are ordered by ID
+	for i := 1; i < len(repo.messages); i++ {
+		for j := i; j > 0 && cursorLess(message.CursorFor(repo.messages[j]), message.CursorFor(repo.messages[j-1])); j-- {
+			repo.messages[j], repo.messages[j-1] = repo.messages[j-1], repo.messages[j]
+		}
+	}
+	return repo
+}
+
+func cursorLess(a, b message.Cursor) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+func (r *historyRepo) visible() []*message.Message {
+	var visible []*message.Message
+	for _, msg := range r.messages {
+		if !r.hidden[msg.ID] {
+			visible = append(visible, msg)
+		}
+	}
+	return visible
+}
+
+func newestFirst(messages []*message.Message) []*message.Message {
+	out := make([]*message.Message, len(messages))
+	for i, msg := range messages {
+		out[len(messages)-1-i] = msg
+	}
+	return out
+}
+
+func (r *historyRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	for _, msg := range r.messages {
+		if msg.ID == id {
+			return msg, nil
+		}
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+func (r *historyRepo) FindByConversationID(ctx context.Context, conversationID uuid.UUID, limit, offset int, userID *uuid.UUID) ([]*message.Message, error) {
+	all := newestFirst(r.visible())
+	if offset >= len(all) {
+		return nil, nil
+	}
+	return all[offset:min(offset+limit, len(all))], nil
+}
+
+func (r *historyRepo) FindBefore(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	var older []*message.Message
+	for _, msg := range newestFirst(r.visible()) {
+		if cursorLess(message.CursorFor(msg), cursor) && len(older) < limit {
+			older = append(older, msg)
+		}
+	}
+	return older, nil
+}
+
+func (r *historyRepo) FindAfter(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	var newer []*message.Message
+	for _, msg := range r.visible() {
+		if cursorLess(cursor, message.CursorFor(msg)) && len(newer) < limit {
+			newer = append(newer, msg)
+		}
+	}
+	return newestFirst(newer), nil
+}
+
+func (r *historyRepo) FindAround(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, before, after int, userID *uuid.UUID) ([]*message.Message, error) {
+	newer, _ := r.FindAfter(ctx, conversationID, cursor, after, userID)
+	older, _ := r.FindBefore(ctx, conversationID, cursor, before, userID)
+	anchor, _ := r.FindByID(ctx, cursor.ID)
+	return append(append(newer, anchor), older...), nil
+}
+
+func messageIDs(messages []*message.Message) []uuid.UUID {
+	ids := make([]uuid.UUID, len(messages))
+	for i, msg := range messages {
+		ids[i] = msg.ID
+	}
+	return ids
+}
+
+func TestFindMessagePageWalksHistoryWithCursors(t *testing.T) {
+	conversationID := uuid.New()
+	repo := newHistoryRepo(conversationID, 25)
+	s := &service{messageRepo: repo}
+	userID := uuid.New()
+
+	// Page backwards from the newest message with encoded cursors
+	var seen []*message.Message
+	req := &dto.GetMessagesRequest{ConversationID: conversationID}
+	page, hasOlder, _, err := s.findMessagePage(context.Background(), userID, req, 10, 25)
+	require.NoError(t, err)
+	seen = append(seen, page...)
+	for hasOlder {
+		req = &dto.GetMessagesRequest{ConversationID: conversationID, Before: message.CursorFor(page[len(page)-1]).Encode()}
+		page, hasOlder, _, err = s.findMessagePage(context.Background(), userID, req, 10, 25)
+		require.NoError(t, err)
+		seen = append(seen, page...)
+	}
+	assert.Equal(t, messageIDs(newestFirst(repo.messages)), messageIDs(seen), "every message once, in order, across shared timestamps")
+
+	// And forwards again from the oldest one by message ID
+	oldest := repo.messages[0]
+	req = &dto.GetMessagesRequest{ConversationID: conversationID, After: oldest.ID.String()}
+	page, hasOlder, hasNewer, err := s.findMessagePage(context.Background(), userID, req, 5, 25)
+	require.NoError(t, err)
+	assert.True(t, hasOlder)
+	assert.True(t, hasNewer)
+	assert.Equal(t, messageIDs(newestFirst(repo.messages[1:6])), messageIDs(page))
+}
+
+func TestFindMessagePageAround(t *testing.T) {
+	conversationID := uuid.New()
+	repo := newHistoryRepo(conversationID, 20)
+	s := &service{messageRepo: repo}
+
+	tests := []struct {
+		name     string
+		anchor   int
+		limit    int
+		want     []*message.Message // Oldest first
+		hasOlder bool
+		hasNewer bool
+	}{
+		{name: "middle", anchor: 10, limit: 5, want: repo.messages[8:13], hasOlder: true, hasNewer: true},
+		{name: "oldest", anchor: 0, limit: 5, want: repo.messages[0:3], hasOlder: false, hasNewer: true},
+		{name: "newest", anchor: 19, limit: 5, want: repo.messages[17:20], hasOlder: true, hasNewer: false},
+		{name: "whole history", anchor: 10, limit: 40, want: repo.messages, hasOlder: false, hasNewer: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			anchorID := repo.messages[tt.anchor].ID
+			req := &dto.GetMessagesRequest{ConversationID: conversationID, AroundID: &anchorID}
+
+			page, hasOlder, hasNewer, err := s.findMessagePage(context.Background(), uuid.New(), req, tt.limit, 20)
+			require.NoError(t, err)
+
+			assert.Equal(t, messageIDs(newestFirst(tt.want)), messageIDs(page))
+			assert.Equal(t, tt.hasOlder, hasOlder)
+			assert.Equal(t, tt.hasNewer, hasNewer)
+		})
+	}
+}
+
+func TestFindMessagePageRejectsOtherConversation(t *testing.T) {
+	repo := newHistoryRepo(uuid.New(), 3)
+	s := &service{messageRepo: repo}
+
+	req := &dto.GetMessagesRequest{ConversationID: uuid.New(), Before: repo.messages[1].ID.String()}
+	_, _, _, err := s.findMessagePage(context.Background(), uuid.New(), req, 10, 0)
+
+	assert.ErrorIs(t, err, message.ErrMessageNotFound)
+}
+
+func TestFindMessagePageOffsetCountsHiddenMessagesOut(t *testing.T) {
+	conversationID := uuid.New()
+	repo := newHistoryRepo(conversationID, 12)
+	repo.hidden[repo.messages[0].ID] = true
+	repo.hidden[repo.messages[1].ID] = true
+	s := &service{messageRepo: repo}
+
+	// Ten messages are left, so the second page of five ends at the oldest visible one
+	req := &dto.GetMessagesRequest{ConversationID: conversationID, Offset: 5}
+	page, hasOlder, hasNewer, err := s.findMessagePage(context.Background(), uuid.New(), req, 5, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, messageIDs(newestFirst(repo.messages[2:7])), messageIDs(page))
+	assert.False(t, hasOlder, "the hidden messages are not left to load")
+	assert.True(t, hasNewer)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/message"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 )
 
@@ -12,6 +13,9 @@ type Service interface {
 	// SendMessage sends a message to a recipient
 	SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.SendMessageRequest) (*dto.SendMessageResponse, error)
 
+	// SendToConversation sends a message into a direct, group or channel conversation
+	SendToConversation(ctx context.Context, senderID uuid.UUID, req *dto.SendConversationMessageRequest) (*dto.SendMessageResponse, error)
+
 	// GetMessages gets messages from a conversation
 	GetMessages(ctx context.Context, userID uuid.UUID, req *dto.GetMessagesRequest) (*dto.GetMessagesResponse, error)
 
@@ -24,15 +28,23 @@ type Service interface {
 	// GetOrCreateDirectConversation gets or creates a direct conversation
 	GetOrCreateDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (uuid.UUID, error)
 
-	// MarkAsRead marks messages in a conversation as read
-	MarkAsRead(ctx context.Context, userID uuid.UUID, req *dto.MarkAsReadRequest) error
+	// MarkAsRead marks messages in a conversation as read, up to a message or the latest one
+	MarkAsRead(ctx context.Context, userID uuid.UUID, req *dto.MarkAsReadRequest) (*dto.ReadStateDTO, error)
+
+	// MarkAsUnread marks messages in a conversation as unread, from a message or the latest one from others
+	MarkAsUnread(ctx context.Context, userID uuid.UUID, req *dto.MarkAsUnreadRequest) (*dto.ReadStateDTO, error)
 
-	// DeleteMessage deletes a message
-	DeleteMessage(ctx context.Context, userID, messageID uuid.UUID) error
+	// DeleteMessage deletes a message for the user only or for everyone
+	DeleteMessage(ctx context.Context, userID, messageID uuid.UUID, scope message.DeleteScope) error
 
 	// EditMessage edits a message content
 	EditMessage(ctx context.Context, userID, messageID uuid.UUID, newContent string) (*dto.MessageDTO, error)
 
+	// GetMessageRevisions gets the edit history of a message
+	GetMessageRevisions(ctx context.Context, userID, messageID uuid.UUID) (*dto.GetMessageRevisionsResponse, error)
+	// GetMessageReceipts gets who received and read a message, only available to its sender
+	GetMessageReceipts(ctx context.Context, userID, messageID uuid.UUID) (*dto.GetMessageReceiptsResponse, error)
+
 	// Message Reactions (Day 13)
 	AddReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error
 	RemoveReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error
@@ -46,6 +58,27 @@ type Service interface {
 	// Message Forwarding (Day 13)
 	ForwardMessage(ctx context.Context, userID, messageID, targetConversationID uuid.UUID) (*dto.SendMessageResponse, error)
 
+	// Threads
+	GetThread(ctx context.Context, userID uuid.UUID, req *dto.GetThreadRequest) (*dto.GetThreadResponse, error)
+	FollowThread(ctx context.Context, userID, messageID uuid.UUID) error
+	UnfollowThread(ctx context.Context, userID, messageID uuid.UUID) error
+
+	// Polls
+	VotePoll(ctx context.Context, userID, messageID uuid.UUID, options []int) (*dto.PollDTO, error)
+	RetractPollVote(ctx context.Context, userID, messageID uuid.UUID) (*dto.PollDTO, error)
+	ClosePoll(ctx context.Context, userID, messageID uuid.UUID) (*dto.PollDTO, error)
+
+	// Mentions
+	GetMentions(ctx context.Context, userID uuid.UUID, req *dto.GetMentionsRequest) (*dto.GetMentionsResponse, error)
+
+	// Scheduled Messages
+	ScheduleMessage(ctx context.Context, userID uuid.UUID, req *dto.ScheduleMessageRequest) (*dto.ScheduledMessageDTO, error)
+	GetScheduledMessages(ctx context.Context, userID uuid.UUID, req *dto.GetScheduledMessagesRequest) (*dto.GetScheduledMessagesResponse, error)
+	UpdateScheduledMessage(ctx context.Context, userID, scheduledID uuid.UUID, req *dto.UpdateScheduledMessageRequest) (*dto.ScheduledMessageDTO, error)
+	CancelScheduledMessage(ctx context.Context, userID, scheduledID uuid.UUID) error
+	// DispatchScheduledMessage delivers a scheduled message claimed by the dispatcher. The result is nil if it was already delivered
+	DispatchScheduledMessage(ctx context.Context, scheduled *message.ScheduledMessage) (*dto.MessageDTO, error)
+
 	// Message Search (Day 13)
 	SearchMessages(ctx context.Context, userID uuid.UUID, req *dto.SearchMessagesRequest) (*dto.SearchMessagesResponse, error)
 
@@ -54,8 +87,33 @@ type Service interface {
 	UnarchiveConversation(ctx context.Context, userID, conversationID uuid.UUID) error
 	DeleteConversation(ctx context.Context, userID, conversationID uuid.UUID) error
 
+	// Pins, mutes and folders of a user's conversation list
+	PinConversation(ctx context.Context, userID, conversationID uuid.UUID) error
+	UnpinConversation(ctx context.Context, userID, conversationID uuid.UUID) error
+	ReorderPinnedConversations(ctx context.Context, userID uuid.UUID, req *dto.ReorderPinnedRequest) error
+	MuteConversation(ctx context.Context, userID, conversationID uuid.UUID, req *dto.MuteConversationRequest) error
+	UnmuteConversation(ctx context.Context, userID, conversationID uuid.UUID) error
+	GetFolders(ctx context.Context, userID uuid.UUID) (*dto.GetFoldersResponse, error)
+	CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*dto.FolderDTO, error)
+	RenameFolder(ctx context.Context, userID, folderID uuid.UUID, name string) (*dto.FolderDTO, error)
+	DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error
+	AddConversationToFolder(ctx context.Context, userID, folderID, conversationID uuid.UUID) error
+	RemoveConversationFromFolder(ctx context.Context, userID, folderID, conversationID uuid.UUID) error
+
+	// Delta sync
+	// Sync returns what changed after a change sequence number, compacted to the current state of each conversation and message
+	Sync(ctx context.Context, userID uuid.UUID, req *dto.SyncRequest) (*dto.SyncResponse, error)
+
+	// Message requests
+	GetMessageRequests(ctx context.Context, userID uuid.UUID, req *dto.GetConversationsRequest) (*dto.GetConversationsResponse, error)
+	AcceptMessageRequest(ctx context.Context, userID, conversationID uuid.UUID) error
+	DeclineMessageRequest(ctx context.Context, userID, conversationID uuid.UUID, block bool) error
+
 	// Helper methods
 	GetMessageByID(ctx context.Context, messageID uuid.UUID) (*dto.MessageDTO, error)
 	GetConversationParticipants(ctx context.Context, conversationID uuid.UUID) ([]uuid.UUID, error)
-	UpdateMessageStatus(ctx context.Context, messageID uuid.UUID, status string) error
+	// RecordReceipt records a delivered or read receipt and returns the users to notify, honoring read receipt settings
+	RecordReceipt(ctx context.Context, userID, conversationID, messageID uuid.UUID, status string) ([]uuid.UUID, error)
+	// TypingAudience returns the participants who may see a user typing, honoring typing settings and blocks
+	TypingAudience(ctx context.Context, userID, conversationID uuid.UUID) ([]uuid.UUID, error)
 }
@@ -0,0 +1,93 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// mentionsRepo keeps the mentions stored per message
+type mentionsRepo struct {
+	message.Repository
+	mentions map[uuid.UUID][]message.MessageMention
+}
+
+func (r *mentionsRepo) GetMentionsByMessageID(ctx context.Context, messageID uuid.UUID) ([]message.MessageMention, error) {
+	return r.mentions[messageID], nil
+}
+
+func (r *mentionsRepo) ReplaceMentions(ctx context.Context, messageID uuid.UUID, mentions []message.MessageMention) error {
+	r.mentions[messageID] = mentions
+	return nil
+}
+
+func TestSyncMentions(t *testing.T) {
+	users := &usersRepo{}
+	sender := users.add("sender")
+	alice := users.add("alice")
+	bob := users.add("bob")
+	outsider := users.add("outsider")
+
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, sender.ID, alice.ID, bob.ID)
+	repo := &mentionsRepo{mentions: make(map[uuid.UUID][]message.MessageMention)}
+	s := &service{messageRepo: repo, conversationRepo: conversations, userRepo: users}
+
+	msg := &message.Message{ID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID,
+		Content: "@alice @alice @sender @outsider @nobody"}
+	mentioned := s.syncMentions(context.Background(), msg)
+
+	// Self mentions, non-participants and unknown usernames are dropped
+	assert.Equal(t, []uuid.UUID{alice.ID}, mentioned)
+	stored := repo.mentions[msg.ID]
+	require.Len(t, stored, 1)
+	assert.Equal(t, alice.ID, stored[0].UserID)
+	assert.NotEqual(t, outsider.ID, stored[0].UserID)
+
+	// An edit keeps the read state of users still mentioned and only reports the new ones
+	readAt := time.Now()
+	for i := range repo.mentions[msg.ID] {
+		repo.mentions[msg.ID][i].ReadAt = &readAt
+	}
+	msg.Content = "@bob and @alice"
+	mentioned = s.syncMentions(context.Background(), msg)
+
+	assert.Equal(t, []uuid.UUID{bob.ID}, mentioned)
+	stored = repo.mentions[msg.ID]
+	require.Len(t, stored, 2)
+	assert.Nil(t, stored[0].ReadAt)
+	assert.Equal(t, &readAt, stored[1].ReadAt)
+
+	// Removing every mention clears them
+	msg.Content = "never mind"
+	assert.Empty(t, s.syncMentions(context.Background(), msg))
+	assert.Empty(t, repo.mentions[msg.ID])
+}
+
+func TestSyncMentionsStoresRepeatsOnce(t *testing.T) {
+	users := &usersRepo{}
+	sender := users.add("sender")
+	bob := users.add("bob")
+
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, sender.ID, bob.ID)
+	repo := &mentionsRepo{mentions: make(map[uuid.UUID][]message.MessageMention)}
+	s := &service{messageRepo: repo, conversationRepo: conversations, userRepo: users}
+
+	msg := &message.Message{ID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID, Content: "@bob hi @bob"}
+	assert.Equal(t, []uuid.UUID{bob.ID}, s.syncMentions(context.Background(), msg))
+	require.Len(t, repo.mentions[msg.ID], 1, "one unread mention however often the user is named")
+	assert.Equal(t, 0, repo.mentions[msg.ID][0].Position, "the first mention is kept")
+
+	// Repeating the mention in an edit mentions no one new
+	msg.Content = "hi @bob, @bob?"
+	assert.Empty(t, s.syncMentions(context.Background(), msg))
+	require.Len(t, repo.mentions[msg.ID], 1)
+	assert.Equal(t, 3, repo.mentions[msg.ID][0].Position)
+}
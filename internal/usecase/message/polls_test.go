@@ -0,0 +1,291 @@
+package message
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/channel"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// pollsRepo keeps poll messages and each user's votes, counting them on read
+type pollsRepo struct {
+	message.Repository
+	messages map[uuid.UUID]*message.Message
+	polls    map[uuid.UUID]*message.Poll
+	votes    map[uuid.UUID]map[uuid.UUID][]int
+}
+
+func newPollsRepo() *pollsRepo {
+	return &pollsRepo{
+		messages: make(map[uuid.UUID]*message.Message),
+		polls:    make(map[uuid.UUID]*message.Poll),
+		votes:    make(map[uuid.UUID]map[uuid.UUID][]int),
+	}
+}
+
+// add stores a poll sent by the sender into the conversation
+func (r *pollsRepo) add(t *testing.T, conversationID, senderID uuid.UUID, req *dto.CreatePollRequest) *message.Message {
+	t.Helper()
+	msg := message.NewMessage(conversationID, senderID, "Lunch?", message.ContentTypePoll)
+	require.NoError(t, applyPoll(msg, req))
+	r.messages[msg.ID] = msg
+	r.polls[msg.ID] = msg.Poll
+	r.votes[msg.ID] = make(map[uuid.UUID][]int)
+	return msg
+}
+
+func (r *pollsRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	if msg, ok := r.messages[id]; ok {
+		found := *msg
+		return &found, nil
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+func (r *pollsRepo) GetPolls(ctx context.Context, messageIDs []uuid.UUID, userID *uuid.UUID) (map[uuid.UUID]*message.Poll, error) {
+	polls := make(map[uuid.UUID]*message.Poll)
+	for _, id := range messageIDs {
+		stored, ok := r.polls[id]
+		if !ok {
+			continue
+		}
+		poll := *stored
+		poll.Options = make([]message.PollOption, len(stored.Options))
+		copy(poll.Options, stored.Options)
+		poll.TotalVoters = len(r.votes[id])
+
+		voters := make([]uuid.UUID, 0, len(r.votes[id]))
+		for voterID := range r.votes[id] {
+			voters = append(voters, voterID)
+		}
+		sort.Slice(voters, func(i, j int) bool { return voters[i].String() < voters[j].String() })
+		for _, voterID := range voters {
+			for _, position := range r.votes[id][voterID] {
+				poll.Options[position].VoteCount++
+				poll.Options[position].Voters = append(poll.Options[position].Voters, voterID)
+			}
+		}
+		if userID != nil {
+			poll.Voted = r.votes[id][*userID]
+		}
+		polls[id] = &poll
+	}
+	return polls, nil
+}
+
+func (r *pollsRepo) SetPollVotes(ctx context.Context, messageID, userID uuid.UUID, positions []int) error {
+	r.votes[messageID][userID] = positions
+	return nil
+}
+
+func (r *pollsRepo) RetractPollVotes(ctx context.Context, messageID, userID uuid.UUID) error {
+	if _, ok := r.votes[messageID][userID]; !ok {
+		return message.ErrPollVoteNotFound
+	}
+	delete(r.votes[messageID], userID)
+	return nil
+}
+
+func (r *pollsRepo) ClosePoll(ctx context.Context, messageID uuid.UUID, closedAt time.Time) error {
+	r.polls[messageID].ClosedAt = &closedAt
+	return nil
+}
+
+func pollRequest(options ...string) *dto.CreatePollRequest {
+	return &dto.CreatePollRequest{Options: options}
+}
+
+func newPollService(repo *pollsRepo, conversations *conversationsRepo) *service {
+	return &service{messageRepo: repo, conversationRepo: conversations, changeLogRepo: &changeLog{}}
+}
+
+func TestVotePollReplacesTheVote(t *testing.T) {
+	sender, voter := uuid.New(), uuid.New()
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, sender, voter)
+	repo := newPollsRepo()
+	msg := repo.add(t, conv.ID, sender, pollRequest("pizza", "sushi", "tacos"))
+	s := newPollService(repo, conversations)
+	ctx := context.Background()
+
+	_, err := s.VotePoll(ctx, voter, msg.ID, []int{0})
+	require.NoError(t, err)
+	poll, err := s.VotePoll(ctx, voter, msg.ID, []int{2})
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{2}, poll.Voted)
+	assert.Equal(t, 1, poll.TotalVoters)
+	assert.Equal(t, 0, poll.Options[0].VoteCount)
+	assert.Equal(t, 1, poll.Options[2].VoteCount)
+	assert.Equal(t, []string{voter.String()}, poll.Options[2].Voters)
+
+	poll, err = s.RetractPollVote(ctx, voter, msg.ID)
+	require.NoError(t, err)
+	assert.Empty(t, poll.Voted)
+	assert.Equal(t, 0, poll.TotalVoters)
+}
+
+func TestVotePollRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *dto.CreatePollRequest
+		prepare func(repo *pollsRepo, msg *message.Message, voter uuid.UUID)
+		options []int
+		want    []int
+		wantErr error
+	}{
+		{name: "single choice", req: pollRequest("a", "b"), options: []int{1}, want: []int{1}},
+		{name: "two options in a single choice poll", req: pollRequest("a", "b"), options: []int{0, 1}, wantErr: message.ErrInvalidPollVote},
+		{name: "no option", req: pollRequest("a", "b"), options: []int{}, wantErr: message.ErrInvalidPollVote},
+		{name: "unknown option", req: pollRequest("a", "b"), options: []int{2}, wantErr: message.ErrInvalidPollVote},
+		{
+			name: "multiple choice sorted without repeats", options: []int{2, 0, 2}, want: []int{0, 2},
+			req: &dto.CreatePollRequest{Options: []string{"a", "b", "c"}, MultipleChoice: true},
+		},
+		{
+			name: "closed early", req: pollRequest("a", "b"), options: []int{0}, wantErr: message.ErrPollClosed,
+			prepare: func(repo *pollsRepo, msg *message.Message, voter uuid.UUID) { repo.polls[msg.ID].Close() },
+		},
+		{
+			name: "past its close time", req: pollRequest("a", "b"), options: []int{0}, wantErr: message.ErrPollClosed,
+			prepare: func(repo *pollsRepo, msg *message.Message, voter uuid.UUID) {
+				repo.polls[msg.ID].SetClosesAt(time.Now().Add(-time.Minute))
+			},
+		},
+		{
+			name: "quiz answers are final", options: []int{1}, wantErr: message.ErrPollVoteFinal,
+			req: &dto.CreatePollRequest{Options: []string{"a", "b"}, Quiz: true, CorrectOption: ptr(0)},
+			prepare: func(repo *pollsRepo, msg *message.Message, voter uuid.UUID) {
+				repo.votes[msg.ID][voter] = []int{0}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, voter := uuid.New(), uuid.New()
+			conversations := newConversationsRepo()
+			conv := conversations.add(conversation.TypeGroup, sender, voter)
+			repo := newPollsRepo()
+			msg := repo.add(t, conv.ID, sender, tt.req)
+			if tt.prepare != nil {
+				tt.prepare(repo, msg, voter)
+			}
+			s := newPollService(repo, conversations)
+
+			poll, err := s.VotePoll(context.Background(), voter, msg.ID, tt.options)
+			assert.Equal(t, tt.wantErr, err)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, poll.Voted)
+			}
+		})
+	}
+}
+
+func TestVotePollRejects(t *testing.T) {
+	sender, voter := uuid.New(), uuid.New()
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, sender, voter)
+	repo := newPollsRepo()
+	msg := repo.add(t, conv.ID, sender, pollRequest("a", "b"))
+	text := &message.Message{ID: uuid.New(), ConversationID: conv.ID, SenderID: sender, ContentType: message.ContentTypeText}
+	repo.messages[text.ID] = text
+	s := newPollService(repo, conversations)
+	ctx := context.Background()
+
+	_, err := s.VotePoll(ctx, uuid.New(), msg.ID, []int{0})
+	assert.Equal(t, conversation.ErrNotParticipant, err)
+	_, err = s.VotePoll(ctx, voter, text.ID, []int{0})
+	assert.Equal(t, message.ErrNotAPoll, err)
+
+	_, err = s.ClosePoll(ctx, voter, msg.ID)
+	assert.Equal(t, message.ErrUnauthorized, err, "only the sender closes a poll")
+	poll, err := s.ClosePoll(ctx, sender, msg.ID)
+	require.NoError(t, err)
+	assert.True(t, poll.IsClosed)
+
+	repo.messages[msg.ID].Tombstone(sender)
+	_, err = s.VotePoll(ctx, voter, msg.ID, []int{0})
+	assert.Equal(t, message.ErrMessageDeleted, err)
+}
+
+func TestChannelPollVoters(t *testing.T) {
+	owner, admin, subscriber := uuid.New(), uuid.New(), uuid.New()
+
+	for _, subscriberVotes := range []bool{false, true} {
+		conversations := newConversationsRepo()
+		conv := conversations.add(conversation.TypeChannel, owner, admin, subscriber)
+		repo := newPollsRepo()
+		msg := repo.add(t, conv.ID, owner, pollRequest("a", "b"))
+		s := newPollService(repo, conversations)
+		s.channelRepo = &channelsRepo{
+			channel: &channel.Channel{ID: uuid.New(), OwnerID: owner, Settings: &channel.Settings{SubscriberVotes: subscriberVotes}},
+			admins:  map[uuid.UUID]*channel.Admin{admin: {UserID: admin, Permissions: &channel.AdminPermissions{}}},
+		}
+		ctx := context.Background()
+
+		for _, voter := range []uuid.UUID{owner, admin} {
+			_, err := s.VotePoll(ctx, voter, msg.ID, []int{0})
+			assert.NoError(t, err)
+		}
+		_, err := s.VotePoll(ctx, subscriber, msg.ID, []int{1})
+		if subscriberVotes {
+			assert.NoError(t, err)
+		} else {
+			assert.Equal(t, message.ErrPollVoteNotAllowed, err)
+		}
+	}
+}
+
+func TestApplyPoll(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType message.ContentType
+		req         *dto.CreatePollRequest
+		wantErr     error
+	}{
+		{name: "text without a poll", contentType: message.ContentTypeText},
+		{name: "text with a poll", contentType: message.ContentTypeText, req: pollRequest("a", "b"), wantErr: message.ErrInvalidPoll},
+		{name: "poll without options", contentType: message.ContentTypePoll, wantErr: message.ErrInvalidPoll},
+		{name: "poll", contentType: message.ContentTypePoll, req: pollRequest("a", "b")},
+		{
+			name: "quiz without an answer", contentType: message.ContentTypePoll, wantErr: message.ErrInvalidPoll,
+			req: &dto.CreatePollRequest{Options: []string{"a", "b"}, Quiz: true},
+		},
+		{
+			name: "answer outside a quiz", contentType: message.ContentTypePoll, wantErr: message.ErrInvalidPoll,
+			req: &dto.CreatePollRequest{Options: []string{"a", "b"}, CorrectOption: ptr(0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := message.NewMessage(uuid.New(), uuid.New(), "Lunch?", tt.contentType)
+			err := applyPoll(msg, tt.req)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.wantErr == nil && tt.req != nil, msg.Poll != nil)
+		})
+	}
+}
+
+func TestPollDTOHidesWhatTheUserMayNotSee(t *testing.T) {
+	voter := uuid.New()
+	poll := message.NewPoll(uuid.New(), "Capital of France?", []string{"Paris", "Lyon"}, false, true)
+	poll.SetQuiz(0)
+	poll.Options[0].Voters = []uuid.UUID{voter}
+
+	pollDTO := toPollDTO(poll)
+	assert.Nil(t, pollDTO.CorrectOption, "not answered yet")
+	assert.Empty(t, pollDTO.Options[0].Voters, "anonymous")
+
+	poll.Voted = []int{1}
+	assert.Equal(t, ptr(0), toPollDTO(poll).CorrectOption)
+}
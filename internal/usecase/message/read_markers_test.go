@@ -0,0 +1,279 @@
+package message
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// markersRepo lists messages newest first and counts the unread ones from the read marker
+type markersRepo struct {
+	*readsRepo
+	at time.Time
+}
+
+func newMarkersRepo() *markersRepo {
+	return &markersRepo{readsRepo: newReadsRepo(), at: time.Now().Add(-time.Hour)}
+}
+
+// add stores a message a second after the previous one
+func (r *markersRepo) add(conversationID, senderID uuid.UUID) *message.Message {
+	msg := r.readsRepo.add(conversationID, senderID)
+	r.at = r.at.Add(time.Second)
+	msg.CreatedAt = r.at
+	return msg
+}
+
+func (r *markersRepo) newestFirst(conversationID uuid.UUID, before *time.Time) []*message.Message {
+	var messages []*message.Message
+	for _, msg := range r.messages {
+		if msg.ConversationID == conversationID && (before == nil || msg.CreatedAt.Before(*before)) {
+			messages = append(messages, msg)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].CreatedAt.After(messages[j].CreatedAt) })
+	return messages
+}
+
+func (r *markersRepo) FindByConversationID(ctx context.Context, conversationID uuid.UUID, limit, offset int, userID *uuid.UUID) ([]*message.Message, error) {
+	messages := r.newestFirst(conversationID, nil)
+	return messages[:min(limit, len(messages))], nil
+}
+
+func (r *markersRepo) FindBefore(ctx context.Context, conversationID uuid.UUID, cursor message.Cursor, limit int, userID *uuid.UUID) ([]*message.Message, error) {
+	messages := r.newestFirst(conversationID, &cursor.CreatedAt)
+	return messages[:min(limit, len(messages))], nil
+}
+
+func (r *markersRepo) CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID, readUpTo time.Time) (int64, error) {
+	var count int64
+	for _, msg := range r.messages {
+		if msg.ConversationID == conversationID && msg.SenderID != userID && msg.CreatedAt.After(readUpTo) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// markersConversations also places read markers backwards
+type markersConversations struct {
+	*conversationsRepo
+}
+
+func (r *markersConversations) SetReadMarker(ctx context.Context, conversationID, userID uuid.UUID, messageID *uuid.UUID, readUpTo *time.Time) error {
+	p, err := r.FindParticipant(ctx, conversationID, userID)
+	if err != nil {
+		return err
+	}
+	p.LastReadMessageID = messageID
+	p.LastReadAt = readUpTo
+	return nil
+}
+
+func (r *markersConversations) FindByUserID(ctx context.Context, userID uuid.UUID, filter conversation.ListFilter, limit, offset int) ([]*conversation.Conversation, error) {
+	var conversations []*conversation.Conversation
+	for id, participants := range r.participants {
+		for _, p := range participants {
+			if p.UserID == userID {
+				conversations = append(conversations, r.conversations[id])
+			}
+		}
+	}
+	return conversations, nil
+}
+
+func (r *markersConversations) FindFolderIDs(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	return map[uuid.UUID][]uuid.UUID{}, nil
+}
+
+// unreadCounter caches counts like the Redis one, increments only bump counts it holds
+type unreadCounter struct {
+	counts map[[2]uuid.UUID]int
+}
+
+func newUnreadCounter() *unreadCounter {
+	return &unreadCounter{counts: make(map[[2]uuid.UUID]int)}
+}
+
+func (c *unreadCounter) GetUnreadCounts(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	for _, conversationID := range conversationIDs {
+		if count, ok := c.counts[[2]uuid.UUID{conversationID, userID}]; ok {
+			counts[conversationID] = count
+		}
+	}
+	return counts, nil
+}
+
+func (c *unreadCounter) SetUnreadCountIfAbsent(ctx context.Context, conversationID, userID uuid.UUID, count int) error {
+	if _, ok := c.counts[[2]uuid.UUID{conversationID, userID}]; !ok {
+		c.counts[[2]uuid.UUID{conversationID, userID}] = count
+	}
+	return nil
+}
+
+func (c *unreadCounter) IncrementUnreadCounts(ctx context.Context, conversationID uuid.UUID, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		if _, ok := c.counts[[2]uuid.UUID{conversationID, userID}]; ok {
+			c.counts[[2]uuid.UUID{conversationID, userID}]++
+		}
+	}
+	return nil
+}
+
+func (c *unreadCounter) ResetUnreadCount(ctx context.Context, conversationID, userID uuid.UUID) error {
+	delete(c.counts, [2]uuid.UUID{conversationID, userID})
+	return nil
+}
+
+func (c *unreadCounter) ResetConversationUnreadCounts(ctx context.Context, conversationID uuid.UUID) error {
+	for key := range c.counts {
+		if key[0] == conversationID {
+			delete(c.counts, key)
+		}
+	}
+	return nil
+}
+
+type markersFixture struct {
+	s        *service
+	messages *markersRepo
+	convs    *conversationsRepo
+	conv     *conversation.Conversation
+	reader   uuid.UUID
+	sender   uuid.UUID
+}
+
+func newMarkersFixture(convType conversation.Type) *markersFixture {
+	f := &markersFixture{
+		messages: newMarkersRepo(),
+		convs:    newConversationsRepo(),
+		reader:   uuid.New(),
+		sender:   uuid.New(),
+	}
+	f.conv = f.convs.add(convType, f.sender, f.reader)
+	f.s = &service{
+		messageRepo:      f.messages,
+		conversationRepo: &markersConversations{f.convs},
+		privacyRepo:      newPrivacyRepo(),
+		changeLogRepo:    &changeLog{},
+	}
+	return f
+}
+
+func (f *markersFixture) participant(t *testing.T) *conversation.Participant {
+	t.Helper()
+	p, err := f.convs.FindParticipant(context.Background(), f.conv.ID, f.reader)
+	require.NoError(t, err)
+	return p
+}
+
+func TestMarkAsReadMovesOnlyForward(t *testing.T) {
+	f := newMarkersFixture(conversation.TypeGroup)
+	ctx := context.Background()
+	first := f.messages.add(f.conv.ID, f.sender)
+	second := f.messages.add(f.conv.ID, f.sender)
+	f.messages.add(f.conv.ID, f.sender)
+
+	state, err := f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID, MessageID: &second.ID})
+	require.NoError(t, err)
+	assert.Equal(t, second.ID.String(), *state.LastReadMessageID)
+	assert.Equal(t, 1, state.UnreadCount)
+
+	// Reading an older message again changes nothing
+	state, err = f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID, MessageID: &first.ID})
+	require.NoError(t, err)
+	assert.Equal(t, second.ID.String(), *state.LastReadMessageID)
+	assert.Equal(t, 1, state.UnreadCount)
+
+	// Without a message everything is read
+	state, err = f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, state.UnreadCount)
+	assert.Len(t, f.messages.read, 3, "receipts recorded up to the marker")
+}
+
+func TestMarkAsReadRejects(t *testing.T) {
+	f := newMarkersFixture(conversation.TypeDirect)
+	ctx := context.Background()
+	elsewhere := f.messages.add(uuid.New(), f.sender)
+
+	_, err := f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID, MessageID: &elsewhere.ID})
+	assert.Equal(t, message.ErrMessageNotFound, err, "a message of another conversation")
+
+	_, err = f.s.MarkAsRead(ctx, uuid.New(), &dto.MarkAsReadRequest{ConversationID: f.conv.ID})
+	assert.Equal(t, conversation.ErrNotParticipant, err)
+
+	// An empty conversation has nothing to read
+	state, err := f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+	assert.Nil(t, state.LastReadMessageID)
+}
+
+func TestMarkAsUnread(t *testing.T) {
+	f := newMarkersFixture(conversation.TypeDirect)
+	ctx := context.Background()
+	first := f.messages.add(f.conv.ID, f.sender)
+	second := f.messages.add(f.conv.ID, f.sender)
+	f.messages.add(f.conv.ID, f.reader)
+
+	_, err := f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+
+	// The latest message from others becomes the first unread one, the reader's own reply is skipped
+	state, err := f.s.MarkAsUnread(ctx, f.reader, &dto.MarkAsUnreadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+	assert.Equal(t, first.ID.String(), *state.LastReadMessageID)
+	assert.Equal(t, 1, state.UnreadCount)
+	assert.Equal(t, &first.ID, f.participant(t).LastReadMessageID)
+
+	// Marking the first message unread clears the marker
+	state, err = f.s.MarkAsUnread(ctx, f.reader, &dto.MarkAsUnreadRequest{ConversationID: f.conv.ID, MessageID: &first.ID})
+	require.NoError(t, err)
+	assert.Nil(t, state.LastReadMessageID)
+	assert.Equal(t, 2, state.UnreadCount)
+	assert.Equal(t, message.StatusRead, second.Status, "receipts already seen are kept")
+}
+
+func TestMarkAsUnreadWithoutMessagesFromOthers(t *testing.T) {
+	f := newMarkersFixture(conversation.TypeGroup)
+	f.messages.add(f.conv.ID, f.reader)
+
+	_, err := f.s.MarkAsUnread(context.Background(), f.reader, &dto.MarkAsUnreadRequest{ConversationID: f.conv.ID})
+	assert.Equal(t, message.ErrMessageNotFound, err)
+}
+
+func TestReadMarkersKeepCachedUnreadCountsFresh(t *testing.T) {
+	f := newMarkersFixture(conversation.TypeGroup)
+	f.s.unreadCounter = newUnreadCounter()
+	f.s.userRepo = &usersRepo{}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		f.messages.add(f.conv.ID, f.sender)
+	}
+
+	listedCount := func() int {
+		t.Helper()
+		resp, err := f.s.GetConversations(ctx, f.reader, &dto.GetConversationsRequest{})
+		require.NoError(t, err)
+		require.Len(t, resp.Conversations, 1)
+		return resp.Conversations[0].UnreadCount
+	}
+	assert.Equal(t, 3, listedCount(), "the first listing caches the count")
+
+	_, err := f.s.MarkAsRead(ctx, f.reader, &dto.MarkAsReadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 0, listedCount())
+
+	_, err = f.s.MarkAsUnread(ctx, f.reader, &dto.MarkAsUnreadRequest{ConversationID: f.conv.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 1, listedCount())
+}
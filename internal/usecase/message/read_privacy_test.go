@@ -0,0 +1,173 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+)
+
+// readsRepo records read receipts on top of receiptsRepo, and whether they were hidden
+type readsRepo struct {
+	*receiptsRepo
+	read   map[uuid.UUID]bool
+	counts map[uuid.UUID]message.ReceiptCounts
+}
+
+func newReadsRepo() *readsRepo {
+	return &readsRepo{receiptsRepo: newReceiptsRepo(), read: make(map[uuid.UUID]bool), counts: make(map[uuid.UUID]message.ReceiptCounts)}
+}
+
+func (r *readsRepo) MarkReceiptRead(ctx context.Context, messageID, userID uuid.UUID, at time.Time, hidden bool) error {
+	r.read[messageID] = hidden
+	return nil
+}
+
+func (r *readsRepo) MarkConversationRead(ctx context.Context, conversationID, userID uuid.UUID, readUpTo, at time.Time, hidden bool) error {
+	for _, msg := range r.messages {
+		if msg.ConversationID == conversationID && msg.SenderID != userID && !msg.CreatedAt.After(readUpTo) {
+			r.read[msg.ID] = hidden
+		}
+	}
+	return nil
+}
+
+func (r *readsRepo) FindByConversationID(ctx context.Context, conversationID uuid.UUID, limit, offset int, userID *uuid.UUID) ([]*message.Message, error) {
+	var messages []*message.Message
+	for _, msg := range r.messages {
+		if msg.ConversationID == conversationID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *readsRepo) MarkMentionsRead(ctx context.Context, userID, conversationID uuid.UUID, readUpTo time.Time) error {
+	return nil
+}
+
+func (r *readsRepo) CountUnreadMentions(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	return nil, nil
+}
+
+func (r *readsRepo) CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID, readUpTo time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *readsRepo) GetReceiptCounts(ctx context.Context, messageIDs []uuid.UUID) (map[uuid.UUID]message.ReceiptCounts, error) {
+	return r.counts, nil
+}
+
+func receiptsOff(settings *privacy.PrivacySettings) {
+	settings.ReadReceiptsEnabled = false
+}
+
+func TestReadReceiptsFollowPrivacySettings(t *testing.T) {
+	tests := []struct {
+		name           string
+		readerOff      bool
+		senderOff      bool
+		wantRecipients bool
+		wantStatus     message.Status
+	}{
+		{name: "both share reads", wantRecipients: true, wantStatus: message.StatusRead},
+		{name: "reader turned receipts off", readerOff: true, wantStatus: message.StatusDelivered},
+		{name: "sender turned receipts off", senderOff: true, wantStatus: message.StatusRead},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender, reader := uuid.New(), uuid.New()
+			conversations := newConversationsRepo()
+			conv := conversations.add(conversation.TypeDirect, sender, reader)
+			messages := newReadsRepo()
+			msg := messages.add(conv.ID, sender)
+			settings := newPrivacyRepo()
+			if tt.readerOff {
+				settings.set(reader, receiptsOff)
+			}
+			if tt.senderOff {
+				settings.set(sender, receiptsOff)
+			}
+			s := &service{messageRepo: messages, conversationRepo: conversations, privacyRepo: settings, changeLogRepo: &changeLog{}}
+
+			recipients, err := s.RecordReceipt(context.Background(), reader, conv.ID, msg.ID, "read")
+			require.NoError(t, err)
+
+			if tt.wantRecipients {
+				assert.Equal(t, []uuid.UUID{sender, reader}, recipients)
+			} else {
+				assert.Empty(t, recipients)
+			}
+			assert.Equal(t, tt.wantStatus, msg.Status)
+			assert.Equal(t, tt.readerOff, messages.read[msg.ID], "hidden read")
+
+			// The read still clears the reader's unread count
+			participant, err := conversations.FindParticipant(context.Background(), conv.ID, reader)
+			require.NoError(t, err)
+			assert.Equal(t, &msg.ID, participant.LastReadMessageID)
+		})
+	}
+}
+
+func TestSendersWithReceiptsOffSeeNoReads(t *testing.T) {
+	sender := uuid.New()
+	messages := newReadsRepo()
+	msg := messages.add(uuid.New(), sender)
+	msg.Status = message.StatusRead
+	messages.counts[msg.ID] = message.ReceiptCounts{Delivered: 3, Read: 2}
+
+	settings := newPrivacyRepo()
+	settings.set(sender, receiptsOff)
+	s := &service{messageRepo: messages, privacyRepo: settings}
+
+	s.attachReceiptCounts(context.Background(), sender, []*message.Message{msg})
+
+	assert.Equal(t, message.StatusDelivered, msg.Status)
+	assert.Equal(t, &message.ReceiptCounts{Delivered: 3}, msg.Receipts)
+}
+
+func TestPrivacySettingsFallBack(t *testing.T) {
+	userID := uuid.New()
+
+	s := &service{privacyRepo: newPrivacyRepo()}
+	settings := s.privacySettings(context.Background(), userID)
+	assert.True(t, settings.ReadReceiptsEnabled, "never saved settings use the defaults")
+	assert.True(t, settings.TypingIndicatorEnabled)
+
+	failing := newPrivacyRepo()
+	failing.err = errors.New("connection refused")
+	s = &service{privacyRepo: failing}
+	settings = s.privacySettings(context.Background(), userID)
+	assert.False(t, settings.ReadReceiptsEnabled, "nothing is shared when the settings cannot be loaded")
+	assert.False(t, settings.TypingIndicatorEnabled)
+}
+
+func TestTypingAudienceFollowsTypingSetting(t *testing.T) {
+	typist, other := uuid.New(), uuid.New()
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, typist, other)
+	settings := newPrivacyRepo()
+	s := &service{conversationRepo: conversations, privacyRepo: settings}
+	ctx := context.Background()
+
+	audience, err := s.TypingAudience(ctx, typist, conv.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{typist, other}, audience)
+
+	audience, err = s.TypingAudience(ctx, uuid.New(), conv.ID)
+	require.NoError(t, err)
+	assert.Empty(t, audience, "not a participant")
+
+	settings.set(typist, func(settings *privacy.PrivacySettings) { settings.TypingIndicatorEnabled = false })
+	audience, err = s.TypingAudience(ctx, typist, conv.ID)
+	require.NoError(t, err)
+	assert.Empty(t, audience)
+}
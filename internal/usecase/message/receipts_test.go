@@ -0,0 +1,150 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// receiptsRepo keeps messages and the receipts recorded on them
+type receiptsRepo struct {
+	message.Repository
+	messages  map[uuid.UUID]*message.Message
+	delivered map[uuid.UUID][]uuid.UUID
+	synced    int
+}
+
+func newReceiptsRepo() *receiptsRepo {
+	return &receiptsRepo{
+		messages:  make(map[uuid.UUID]*message.Message),
+		delivered: make(map[uuid.UUID][]uuid.UUID),
+	}
+}
+
+func (r *receiptsRepo) add(conversationID, senderID uuid.UUID) *message.Message {
+	msg := &message.Message{ID: uuid.New(), ConversationID: conversationID, SenderID: senderID, Status: message.StatusSent, CreatedAt: time.Now()}
+	r.messages[msg.ID] = msg
+	return msg
+}
+
+func (r *receiptsRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	if msg, ok := r.messages[id]; ok {
+		return msg, nil
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+func (r *receiptsRepo) MarkReceiptDelivered(ctx context.Context, messageID, userID uuid.UUID, at time.Time) error {
+	r.delivered[messageID] = append(r.delivered[messageID], userID)
+	return nil
+}
+
+func (r *receiptsRepo) UpdateStatus(ctx context.Context, messageID uuid.UUID, status message.Status) error {
+	r.messages[messageID].Status = status
+	return nil
+}
+
+func (r *receiptsRepo) SyncReceiptStatus(ctx context.Context, conversationID uuid.UUID, messageID *uuid.UUID, recipients int) error {
+	r.synced++
+	return nil
+}
+
+func TestRecordDeliveredReceiptGoesToSenderOnly(t *testing.T) {
+	sender := uuid.New()
+	subscriber := uuid.New()
+	others := []uuid.UUID{sender, subscriber}
+	for i := 0; i < 50; i++ {
+		others = append(others, uuid.New())
+	}
+
+	for _, convType := range []conversation.Type{conversation.TypeDirect, conversation.TypeGroup, conversation.TypeChannel} {
+		t.Run(string(convType), func(t *testing.T) {
+			conversations := newConversationsRepo()
+			conv := conversations.add(convType, others...)
+			messages := newReceiptsRepo()
+			msg := messages.add(conv.ID, sender)
+			s := &service{messageRepo: messages, conversationRepo: conversations}
+
+			recipients, err := s.RecordReceipt(context.Background(), subscriber, conv.ID, msg.ID, "delivered")
+			require.NoError(t, err)
+
+			assert.Equal(t, []uuid.UUID{sender}, recipients)
+			assert.Equal(t, []uuid.UUID{subscriber}, messages.delivered[msg.ID])
+		})
+	}
+}
+
+func TestRecordDeliveredReceiptStatus(t *testing.T) {
+	sender, recipient := uuid.New(), uuid.New()
+
+	t.Run("direct message status follows the receipt", func(t *testing.T) {
+		conversations := newConversationsRepo()
+		conv := conversations.add(conversation.TypeDirect, sender, recipient)
+		messages := newReceiptsRepo()
+		msg := messages.add(conv.ID, sender)
+		s := &service{messageRepo: messages, conversationRepo: conversations}
+
+		_, err := s.RecordReceipt(context.Background(), recipient, conv.ID, msg.ID, "delivered")
+		require.NoError(t, err)
+
+		assert.Equal(t, message.StatusDelivered, msg.Status)
+	})
+
+	t.Run("late delivery does not undo a read", func(t *testing.T) {
+		conversations := newConversationsRepo()
+		conv := conversations.add(conversation.TypeDirect, sender, recipient)
+		messages := newReceiptsRepo()
+		msg := messages.add(conv.ID, sender)
+		msg.Status = message.StatusRead
+		s := &service{messageRepo: messages, conversationRepo: conversations}
+
+		_, err := s.RecordReceipt(context.Background(), recipient, conv.ID, msg.ID, "delivered")
+		require.NoError(t, err)
+
+		assert.Equal(t, message.StatusRead, msg.Status)
+	})
+
+	t.Run("group status is synced from the receipts", func(t *testing.T) {
+		conversations := newConversationsRepo()
+		conv := conversations.add(conversation.TypeGroup, sender, recipient, uuid.New())
+		messages := newReceiptsRepo()
+		msg := messages.add(conv.ID, sender)
+		s := &service{messageRepo: messages, conversationRepo: conversations}
+
+		_, err := s.RecordReceipt(context.Background(), recipient, conv.ID, msg.ID, "delivered")
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, messages.synced)
+		assert.Equal(t, message.StatusSent, msg.Status)
+	})
+}
+
+func TestRecordReceiptRejects(t *testing.T) {
+	sender, recipient := uuid.New(), uuid.New()
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, sender, recipient)
+	messages := newReceiptsRepo()
+	msg := messages.add(conv.ID, sender)
+	s := &service{messageRepo: messages, conversationRepo: conversations}
+	ctx := context.Background()
+
+	recipients, err := s.RecordReceipt(ctx, sender, conv.ID, msg.ID, "delivered")
+	require.NoError(t, err)
+	assert.Empty(t, recipients, "senders do not receipt their own messages")
+
+	_, err = s.RecordReceipt(ctx, recipient, uuid.New(), msg.ID, "delivered")
+	assert.ErrorIs(t, err, message.ErrMessageNotFound)
+
+	_, err = s.RecordReceipt(ctx, uuid.New(), conv.ID, msg.ID, "delivered")
+	assert.ErrorIs(t, err, conversation.ErrNotParticipant)
+
+	_, err = s.RecordReceipt(ctx, recipient, conv.ID, msg.ID, "seen")
+	assert.Error(t, err)
+	assert.Empty(t, messages.delivered[msg.ID])
+}
@@ -0,0 +1,194 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/contact"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+)
+
+// contactsRepo holds each user's saved contacts
+type contactsRepo struct {
+	contact.Repository
+	contacts map[uuid.UUID][]uuid.UUID
+}
+
+func (r *contactsRepo) IsContact(ctx context.Context, userID, targetID uuid.UUID) (bool, error) {
+	for _, id := range r.contacts[userID] {
+		if id == targetID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type requestFixture struct {
+	s             *service
+	conversations *conversationsRepo
+	settings      *privacyRepo
+	contacts      *contactsRepo
+}
+
+func newRequestFixture() *requestFixture {
+	f := &requestFixture{
+		conversations: newConversationsRepo(),
+		settings:      newPrivacyRepo(),
+		contacts:      &contactsRepo{contacts: make(map[uuid.UUID][]uuid.UUID)},
+	}
+	f.s = &service{conversationRepo: f.conversations, privacyRepo: f.settings, contactRepo: f.contacts, changeLogRepo: &changeLog{}}
+	return f
+}
+
+func whoCanMessage(visibility privacy.Visibility) func(settings *privacy.PrivacySettings) {
+	return func(settings *privacy.PrivacySettings) { settings.WhoCanMessage = visibility }
+}
+
+// startChat opens a direct chat from initiator to recipient and returns the recipient's side of it
+func (f *requestFixture) startChat(t *testing.T, initiator, recipient uuid.UUID) (uuid.UUID, *conversation.Participant) {
+	t.Helper()
+	conversationID, err := f.s.GetOrCreateDirectConversation(context.Background(), initiator, recipient)
+	require.NoError(t, err)
+	p, err := f.conversations.FindParticipant(context.Background(), conversationID, recipient)
+	require.NoError(t, err)
+	return conversationID, p
+}
+
+func TestNewChatsFollowWhoCanMessage(t *testing.T) {
+	tests := []struct {
+		name        string
+		setting     func(settings *privacy.PrivacySettings)
+		isContact   bool
+		settingsErr error
+		wantRequest bool
+		wantErr     error
+	}{
+		{name: "never saved settings"},
+		{name: "everyone", setting: whoCanMessage(privacy.VisibilityEveryone)},
+		{name: "cached before the option existed", setting: whoCanMessage("")},
+		{name: "contacts, from a contact", setting: whoCanMessage(privacy.VisibilityContacts), isContact: true},
+		{name: "contacts, from a stranger", setting: whoCanMessage(privacy.VisibilityContacts), wantRequest: true},
+		{name: "nobody", setting: whoCanMessage(privacy.VisibilityNobody), isContact: true, wantErr: privacy.ErrMessagingRestricted},
+		{name: "settings unavailable", settingsErr: errors.New("connection refused"), wantRequest: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newRequestFixture()
+			initiator, recipient := uuid.New(), uuid.New()
+			if tt.setting != nil {
+				f.settings.set(recipient, tt.setting)
+			}
+			if tt.isContact {
+				f.contacts.contacts[recipient] = []uuid.UUID{initiator}
+			}
+			f.settings.err = tt.settingsErr
+
+			conversationID, err := f.s.GetOrCreateDirectConversation(context.Background(), initiator, recipient)
+			if tt.wantErr != nil {
+				assert.Equal(t, tt.wantErr, err)
+				assert.Empty(t, f.conversations.conversations)
+				return
+			}
+			require.NoError(t, err)
+
+			participants := f.conversations.participants[conversationID]
+			pending := conversation.FindPendingRequest(participants)
+			if tt.wantRequest {
+				require.NotNil(t, pending)
+				assert.Equal(t, recipient, pending.UserID, "only the recipient has to accept")
+			} else {
+				assert.Nil(t, pending)
+			}
+		})
+	}
+}
+
+func TestReplyingAcceptsTheRequest(t *testing.T) {
+	f := newRequestFixture()
+	initiator, recipient := uuid.New(), uuid.New()
+	f.settings.set(recipient, whoCanMessage(privacy.VisibilityContacts))
+	conversationID, p := f.startChat(t, initiator, recipient)
+
+	// The initiator writing again does not accept it for the recipient
+	f.s.acceptRequestOnReply(context.Background(), conversationID, initiator)
+	assert.True(t, p.IsRequest())
+
+	f.s.acceptRequestOnReply(context.Background(), conversationID, recipient)
+	assert.False(t, p.IsRequest())
+}
+
+func TestRequestsShareNothingUntilAccepted(t *testing.T) {
+	f := newRequestFixture()
+	initiator, recipient := uuid.New(), uuid.New()
+	f.settings.set(recipient, whoCanMessage(privacy.VisibilityContacts))
+	conversationID, _ := f.startChat(t, initiator, recipient)
+	conv := f.conversations.conversations[conversationID]
+	ctx := context.Background()
+
+	assert.False(t, f.s.readReceiptsShared(ctx, conv, recipient))
+	assert.True(t, f.s.readReceiptsShared(ctx, conv, initiator))
+	audience, err := f.s.TypingAudience(ctx, recipient, conversationID)
+	require.NoError(t, err)
+	assert.Empty(t, audience)
+	audience, err = f.s.TypingAudience(ctx, initiator, conversationID)
+	require.NoError(t, err)
+	assert.Empty(t, audience)
+
+	require.NoError(t, f.s.AcceptMessageRequest(ctx, recipient, conversationID))
+	assert.True(t, f.s.readReceiptsShared(ctx, conv, recipient))
+	audience, err = f.s.TypingAudience(ctx, recipient, conversationID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{initiator, recipient}, audience)
+
+	assert.Equal(t, conversation.ErrMessageRequestNotFound, f.s.AcceptMessageRequest(ctx, recipient, conversationID))
+}
+
+func TestDeclineMessageRequest(t *testing.T) {
+	f := newRequestFixture()
+	initiator, recipient := uuid.New(), uuid.New()
+	f.settings.set(recipient, whoCanMessage(privacy.VisibilityContacts))
+	conversationID, _ := f.startChat(t, initiator, recipient)
+	ctx := context.Background()
+
+	// Only the recipient of a pending request can decline it
+	assert.Equal(t, conversation.ErrMessageRequestNotFound, f.s.DeclineMessageRequest(ctx, initiator, conversationID, false))
+
+	require.NoError(t, f.s.DeclineMessageRequest(ctx, recipient, conversationID, true))
+	assert.Empty(t, f.conversations.conversations)
+	blocked, err := f.settings.IsBlockedBetween(ctx, initiator, recipient)
+	require.NoError(t, err)
+	assert.True(t, blocked)
+
+	// Once blocked the requester cannot start another chat
+	_, err = f.s.GetOrCreateDirectConversation(ctx, initiator, recipient)
+	assert.Equal(t, privacy.ErrUserUnavailable, err)
+}
+
+func TestRequestState(t *testing.T) {
+	initiator, recipient := uuid.New(), uuid.New()
+	requested := conversation.NewParticipant(uuid.New(), recipient, conversation.RoleMember)
+	requested.RequestedAt = &requested.JoinedAt
+	participants := []*conversation.Participant{
+		conversation.NewParticipant(requested.ConversationID, initiator, conversation.RoleMember),
+		requested,
+	}
+
+	isRequest, pending := requestState(participants, recipient)
+	assert.True(t, isRequest, "in the recipient's requests")
+	assert.True(t, pending)
+
+	isRequest, pending = requestState(participants, initiator)
+	assert.False(t, isRequest, "in the initiator's chats, waiting on the recipient")
+	assert.True(t, pending)
+
+	requested.RequestedAt = nil
+	isRequest, pending = requestState(participants, recipient)
+	assert.False(t, isRequest)
+	assert.False(t, pending)
+}
@@ -0,0 +1,148 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/domain/user"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	// scheduledClaimLease is how long a dispatcher owns a claimed message before another may retry it
+	scheduledClaimLease = 2 * time.Minute
+
+	// scheduledMaxAttempts is how many times sending is tried before the message is marked failed
+	scheduledMaxAttempts = 5
+)
+
+// ScheduledWorker periodically sends scheduled messages that are due.
+// Messages are claimed in the database, so any number of API replicas can run it.
+type ScheduledWorker struct {
+	messageRepo    message.Repository
+	messageService Service
+	interval       time.Duration
+	batchSize      int
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewScheduledWorker creates a new scheduled messages dispatcher
+func NewScheduledWorker(
+	messageRepo message.Repository,
+	messageService Service,
+	interval time.Duration,
+	batchSize int,
+) *ScheduledWorker {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	return &ScheduledWorker{
+		messageRepo:    messageRepo,
+		messageService: messageService,
+		interval:       interval,
+		batchSize:      batchSize,
+		stop:           make(chan struct{}),
+	}
+}
+
+// Start runs the worker in the background until Stop is called
+func (w *ScheduledWorker) Start() {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				w.dispatchDue(context.Background())
+			case <-w.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop signals the worker to exit and waits for the current run to finish
+func (w *ScheduledWorker) Stop() {
+	close(w.stop)
+	w.wg.Wait()
+}
+
+// dispatchDue claims and sends due messages in batches until none are left
+func (w *ScheduledWorker) dispatchDue(ctx context.Context) {
+	for {
+		claimed, err := w.messageRepo.ClaimDueScheduled(ctx, time.Now(), scheduledClaimLease, w.batchSize)
+		if err != nil {
+			logger.Error("Failed to claim scheduled messages", zap.Error(err))
+			return
+		}
+
+		for _, scheduled := range claimed {
+			w.dispatch(ctx, scheduled)
+		}
+
+		if len(claimed) < w.batchSize {
+			return
+		}
+
+		select {
+		case <-w.stop:
+			return
+		default:
+		}
+	}
+}
+
+// dispatch sends a single claimed message, retrying transient failures on a later run
+func (w *ScheduledWorker) dispatch(ctx context.Context, scheduled *message.ScheduledMessage) {
+	_, err := w.messageService.DispatchScheduledMessage(ctx, scheduled)
+	if err == nil {
+		logger.Info("Scheduled message sent",
+			zap.String("scheduled_id", scheduled.ID.String()),
+			zap.String("conversation_id", scheduled.ConversationID.String()),
+		)
+		return
+	}
+
+	logger.Error("Failed to send scheduled message",
+		zap.String("scheduled_id", scheduled.ID.String()),
+		zap.Int("attempt", scheduled.Attempts),
+		zap.Error(err),
+	)
+
+	if isPermanentDispatchError(err) || scheduled.Attempts >= scheduledMaxAttempts {
+		if err := w.messageRepo.MarkScheduledFailed(ctx, scheduled.ID, err.Error()); err != nil {
+			logger.Error("Failed to mark scheduled message failed", zap.String("scheduled_id", scheduled.ID.String()), zap.Error(err))
+		}
+		return
+	}
+
+	if err := w.messageRepo.ReleaseScheduled(ctx, scheduled.ID, err.Error()); err != nil {
+		// The lease expires on its own, so the message is still retried
+		logger.Warn("Failed to release scheduled message", zap.String("scheduled_id", scheduled.ID.String()), zap.Error(err))
+	}
+}
+
+// isPermanentDispatchError checks if retrying the send cannot succeed
+func isPermanentDispatchError(err error) bool {
+	return errors.Is(err, conversation.ErrNotParticipant) ||
+		errors.Is(err, conversation.ErrConversationNotFound) ||
+		errors.Is(err, message.ErrSendNotAllowed) ||
+		errors.Is(err, privacy.ErrUserUnavailable) ||
+		errors.Is(err, user.ErrUserNotFound)
+}
@@ -0,0 +1,166 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/domain/user"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// scheduledRepo hands out claimed messages in batches and records how each one ended
+type scheduledRepo struct {
+	message.Repository
+	batches  [][]*message.ScheduledMessage
+	claims   int
+	sent     map[uuid.UUID]bool
+	failed   map[uuid.UUID]string
+	released map[uuid.UUID]string
+	messages map[uuid.UUID]*message.Message
+}
+
+func newScheduledRepo(batches ...[]*message.ScheduledMessage) *scheduledRepo {
+	return &scheduledRepo{
+		batches:  batches,
+		sent:     make(map[uuid.UUID]bool),
+		failed:   make(map[uuid.UUID]string),
+		released: make(map[uuid.UUID]string),
+		messages: make(map[uuid.UUID]*message.Message),
+	}
+}
+
+func (r *scheduledRepo) ClaimDueScheduled(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*message.ScheduledMessage, error) {
+	r.claims++
+	if len(r.batches) == 0 {
+		return nil, nil
+	}
+	batch := r.batches[0]
+	r.batches = r.batches[1:]
+	return batch, nil
+}
+
+func (r *scheduledRepo) MarkScheduledSent(ctx context.Context, id uuid.UUID, sentAt time.Time) error {
+	r.sent[id] = true
+	return nil
+}
+
+func (r *scheduledRepo) MarkScheduledFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	r.failed[id] = reason
+	return nil
+}
+
+func (r *scheduledRepo) ReleaseScheduled(ctx context.Context, id uuid.UUID, reason string) error {
+	r.released[id] = reason
+	return nil
+}
+
+func (r *scheduledRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	if msg, ok := r.messages[id]; ok {
+		return msg, nil
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+// dispatcher fails the scheduled messages it has an error for
+type dispatcher struct {
+	Service
+	errs       map[uuid.UUID]error
+	dispatched []uuid.UUID
+}
+
+func (d *dispatcher) DispatchScheduledMessage(ctx context.Context, scheduled *message.ScheduledMessage) (*dto.MessageDTO, error) {
+	d.dispatched = append(d.dispatched, scheduled.ID)
+	return nil, d.errs[scheduled.ID]
+}
+
+func claimed(attempts int) *message.ScheduledMessage {
+	return &message.ScheduledMessage{ID: uuid.New(), ConversationID: uuid.New(), Status: message.ScheduledSending, Attempts: attempts}
+}
+
+func TestScheduledWorkerHandlesDispatchErrors(t *testing.T) {
+	ok, transient, permanent, exhausted := claimed(1), claimed(1), claimed(1), claimed(scheduledMaxAttempts)
+	repo := newScheduledRepo([]*message.ScheduledMessage{ok, transient, permanent, exhausted})
+	service := &dispatcher{errs: map[uuid.UUID]error{
+		transient.ID: errors.New("connection reset"),
+		permanent.ID: fmt.Errorf("send: %w", message.ErrSendNotAllowed),
+		exhausted.ID: errors.New("connection reset"),
+	}}
+
+	NewScheduledWorker(repo, service, time.Minute, 10).dispatchDue(context.Background())
+
+	assert.Equal(t, map[uuid.UUID]string{transient.ID: "connection reset"}, repo.released, "retried on a later run")
+	assert.Equal(t, map[uuid.UUID]string{
+		permanent.ID: "send: " + message.ErrSendNotAllowed.Error(),
+		exhausted.ID: "connection reset",
+	}, repo.failed)
+}
+
+func TestScheduledWorkerClaimsUntilABatchIsShort(t *testing.T) {
+	repo := newScheduledRepo(
+		[]*message.ScheduledMessage{claimed(1), claimed(1)},
+		[]*message.ScheduledMessage{claimed(1), claimed(1)},
+		[]*message.ScheduledMessage{claimed(1)},
+		[]*message.ScheduledMessage{claimed(1)},
+	)
+	service := &dispatcher{}
+
+	NewScheduledWorker(repo, service, time.Minute, 2).dispatchDue(context.Background())
+
+	assert.Equal(t, 3, repo.claims)
+	assert.Len(t, service.dispatched, 5)
+}
+
+func TestIsPermanentDispatchError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: conversation.ErrNotParticipant, want: true},
+		{err: conversation.ErrConversationNotFound, want: true},
+		{err: fmt.Errorf("failed to find conversation: %w", conversation.ErrConversationNotFound), want: true},
+		{err: message.ErrSendNotAllowed, want: true},
+		{err: privacy.ErrUserUnavailable, want: true},
+		{err: fmt.Errorf("sender not found: %w", user.ErrUserNotFound), want: true},
+		{err: errors.New("connection reset"), want: false},
+		{err: fmt.Errorf("failed to save message: %w", context.DeadlineExceeded), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.want, isPermanentDispatchError(tt.err))
+		})
+	}
+}
+
+func TestDispatchScheduledMessageRetries(t *testing.T) {
+	users := &usersRepo{}
+	sender := users.add("sender")
+	conversations := newConversationsRepo()
+	conv := conversations.add(conversation.TypeGroup, uuid.New())
+
+	// A retry of a message an earlier attempt already delivered is only marked sent
+	repo := newScheduledRepo()
+	s := &service{messageRepo: repo, conversationRepo: conversations, userRepo: users}
+	delivered := &message.ScheduledMessage{ID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID, Attempts: 2}
+	repo.messages[delivered.ID] = delivered.ToMessage()
+
+	messageDTO, err := s.DispatchScheduledMessage(context.Background(), delivered)
+	require.NoError(t, err)
+	assert.Nil(t, messageDTO)
+	assert.True(t, repo.sent[delivered.ID])
+
+	// The sender left the conversation since scheduling it
+	left := &message.ScheduledMessage{ID: uuid.New(), ConversationID: conv.ID, SenderID: sender.ID, Attempts: 2}
+	_, err = s.DispatchScheduledMessage(context.Background(), left)
+	assert.Equal(t, conversation.ErrNotParticipant, err)
+	assert.False(t, repo.sent[left.ID])
+}
@@ -0,0 +1,112 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// searchRepo records the query it is given and answers with a fixed page
+type searchRepo struct {
+	message.Repository
+	query   message.SearchQuery
+	results []*message.SearchResult
+	total   int64
+}
+
+func (r *searchRepo) SearchMessages(ctx context.Context, query message.SearchQuery) ([]*message.SearchResult, int64, error) {
+	r.query = query
+	return r.results, r.total, nil
+}
+
+func searchResults(n int) []*message.SearchResult {
+	results := make([]*message.SearchResult, n)
+	for i := range results {
+		results[i] = &message.SearchResult{
+			Message: &message.Message{ID: uuid.New(), SenderID: uuid.New(), CreatedAt: time.Now()},
+			Rank:    1 / float64(i+1),
+			Snippet: "<mark>hello</mark>",
+		}
+	}
+	return results
+}
+
+func TestSearchMessagesBuildsQuery(t *testing.T) {
+	repo := &searchRepo{}
+	s := &service{messageRepo: repo, userRepo: &usersRepo{}}
+	userID := uuid.New()
+	conversationID := uuid.New()
+	senderID := uuid.New()
+	from := time.Now().Add(-time.Hour)
+	cursor := message.SearchCursor{Rank: 0.25, CreatedAt: from, ID: uuid.New()}
+	conversationIDStr := conversationID.String()
+	senderIDStr := senderID.String()
+
+	_, err := s.SearchMessages(context.Background(), userID, &dto.SearchMessagesRequest{
+		Query:          "hello world",
+		ConversationID: &conversationIDStr,
+		SenderID:       &senderIDStr,
+		ContentTypes:   []string{"image", "video"},
+		From:           &from,
+		Pinned:         true,
+		Cursor:         cursor.Encode(),
+		Limit:          500,
+	})
+	require.NoError(t, err)
+
+	q := repo.query
+	assert.Equal(t, userID, q.UserID)
+	assert.Equal(t, "hello world", q.Text)
+	assert.Equal(t, conversationID, *q.ConversationID)
+	assert.Equal(t, senderID, *q.SenderID)
+	assert.Equal(t, []message.ContentType{message.ContentTypeImage, message.ContentTypeVideo}, q.ContentTypes)
+	assert.True(t, q.Pinned)
+	assert.False(t, q.HasReactions)
+	require.NotNil(t, q.Cursor)
+	assert.Equal(t, cursor.ID, q.Cursor.ID)
+	assert.Equal(t, 100, q.Limit, "the page size is capped")
+}
+
+func TestSearchMessagesRejectsInvalidCursor(t *testing.T) {
+	s := &service{messageRepo: &searchRepo{}, userRepo: &usersRepo{}}
+
+	_, err := s.SearchMessages(context.Background(), uuid.New(), &dto.SearchMessagesRequest{Query: "hello", Cursor: "garbage"})
+
+	assert.ErrorIs(t, err, message.ErrInvalidCursor)
+}
+
+func TestSearchMessagesNextCursor(t *testing.T) {
+	t.Run("full page", func(t *testing.T) {
+		repo := &searchRepo{results: searchResults(20), total: 45}
+		s := &service{messageRepo: repo, userRepo: &usersRepo{}}
+
+		resp, err := s.SearchMessages(context.Background(), uuid.New(), &dto.SearchMessagesRequest{Query: "hello"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 20, repo.query.Limit)
+		assert.Equal(t, int64(45), resp.Total)
+		assert.Len(t, resp.Snippets, 20)
+
+		next, err := message.ParseSearchCursor(resp.NextCursor)
+		require.NoError(t, err)
+		last := repo.results[19]
+		assert.Equal(t, last.Message.ID, next.ID)
+		assert.Equal(t, last.Rank, next.Rank)
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		repo := &searchRepo{results: searchResults(3), total: 3}
+		s := &service{messageRepo: repo, userRepo: &usersRepo{}}
+
+		resp, err := s.SearchMessages(context.Background(), uuid.New(), &dto.SearchMessagesRequest{Query: "hello"})
+		require.NoError(t, err)
+
+		assert.Empty(t, resp.NextCursor)
+	})
+}
@@ -0,0 +1,118 @@
+package message
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/yourusername/sotalk/internal/domain/channel"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/group"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// groupsRepo holds one group and its members
+type groupsRepo struct {
+	group.Repository
+	group   *group.Group
+	members map[uuid.UUID]*group.Member
+}
+
+func (r *groupsRepo) FindByConversationID(ctx context.Context, conversationID uuid.UUID) (*group.Group, error) {
+	return r.group, nil
+}
+
+func (r *groupsRepo) FindMember(ctx context.Context, groupID, userID uuid.UUID) (*group.Member, error) {
+	if member, ok := r.members[userID]; ok {
+		return member, nil
+	}
+	return nil, group.ErrMemberNotFound
+}
+
+// channelsRepo holds one channel and its admins
+type channelsRepo struct {
+	channel.Repository
+	channel *channel.Channel
+	admins  map[uuid.UUID]*channel.Admin
+}
+
+func (r *channelsRepo) FindByConversationID(ctx context.Context, conversationID uuid.UUID) (*channel.Channel, error) {
+	return r.channel, nil
+}
+
+func (r *channelsRepo) FindAdmin(ctx context.Context, channelID, userID uuid.UUID) (*channel.Admin, error) {
+	if admin, ok := r.admins[userID]; ok {
+		return admin, nil
+	}
+	return nil, channel.ErrAdminNotFound
+}
+
+func (r *channelsRepo) IsAdmin(ctx context.Context, channelID, userID uuid.UUID) (bool, error) {
+	_, ok := r.admins[userID]
+	return ok, nil
+}
+
+func TestCheckSendPermissionInGroups(t *testing.T) {
+	admin, member, muted, outsider := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		whoCanMessage string
+		sender        uuid.UUID
+		wantErr       error
+	}{
+		{name: "member posts", whoCanMessage: "all", sender: member},
+		{name: "not a member", whoCanMessage: "all", sender: outsider, wantErr: message.ErrSendNotAllowed},
+		{name: "member without send permission", whoCanMessage: "all", sender: muted, wantErr: message.ErrSendNotAllowed},
+		{name: "admins only, member", whoCanMessage: "admins_only", sender: member, wantErr: message.ErrSendNotAllowed},
+		{name: "admins only, admin", whoCanMessage: "admins_only", sender: admin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grp := &group.Group{ID: uuid.New(), Settings: &group.Settings{WhoCanMessage: tt.whoCanMessage}}
+			groups := &groupsRepo{group: grp, members: map[uuid.UUID]*group.Member{
+				admin:  {UserID: admin, Role: group.RoleAdmin},
+				member: {UserID: member, Role: group.RoleMember},
+				muted:  {UserID: muted, Role: group.RoleMember, Permissions: &group.Permissions{CanSendMessages: false}},
+			}}
+			s := &service{groupRepo: groups}
+
+			err := s.checkSendPermission(context.Background(), &conversation.Conversation{ID: uuid.New(), Type: conversation.TypeGroup}, tt.sender)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
+
+func TestCheckSendPermissionInChannels(t *testing.T) {
+	owner, poster, editor, subscriber := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	tests := []struct {
+		name          string
+		adminsCanPost bool
+		sender        uuid.UUID
+		wantErr       error
+	}{
+		{name: "owner posts", adminsCanPost: true, sender: owner},
+		{name: "admin with post permission", adminsCanPost: true, sender: poster},
+		{name: "admin without post permission", adminsCanPost: true, sender: editor, wantErr: message.ErrSendNotAllowed},
+		{name: "subscriber", adminsCanPost: true, sender: subscriber, wantErr: message.ErrSendNotAllowed},
+		{name: "only the owner posts", adminsCanPost: false, sender: poster, wantErr: message.ErrSendNotAllowed},
+		{name: "owner posts when admins cannot", adminsCanPost: false, sender: owner},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := &channel.Channel{ID: uuid.New(), OwnerID: owner, Settings: &channel.Settings{AdminsCanPost: tt.adminsCanPost}}
+			channels := &channelsRepo{channel: ch, admins: map[uuid.UUID]*channel.Admin{
+				poster: {UserID: poster, Permissions: &channel.AdminPermissions{CanPostMessages: true}},
+				editor: {UserID: editor, Permissions: &channel.AdminPermissions{CanEditMessages: true}},
+			}}
+			s := &service{channelRepo: channels}
+
+			err := s.checkSendPermission(context.Background(), &conversation.Conversation{ID: uuid.New(), Type: conversation.TypeChannel}, tt.sender)
+			assert.Equal(t, tt.wantErr, err)
+		})
+	}
+}
@@ -2,12 +2,19 @@ package message
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/channel"
+	"github.com/yourusername/sotalk/internal/domain/contact"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/group"
 	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/domain/user"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
 	"github.com/yourusername/sotalk/pkg/logger"
@@ -17,20 +24,50 @@ import (
 // WSBroadcaster defines the interface for WebSocket broadcasting
 type WSBroadcaster interface {
 	BroadcastNewMessage(ctx context.Context, conversationID uuid.UUID, msg dto.MessageDTO) error
-	BroadcastMessageDeleted(ctx context.Context, conversationID uuid.UUID, messageID string) error
+	BroadcastMessageDeleted(ctx context.Context, conversationID uuid.UUID, messageID, deletedBy string) error
+	BroadcastMessageHidden(ctx context.Context, userID, conversationID uuid.UUID, messageID string) error
 	BroadcastMessageUpdated(ctx context.Context, conversationID uuid.UUID, msg dto.MessageDTO) error
 	BroadcastReactionAdded(ctx context.Context, conversationID uuid.UUID, messageID, userID, emoji string) error
 	BroadcastReactionRemoved(ctx context.Context, conversationID uuid.UUID, messageID, userID, emoji string) error
 	BroadcastMessagePinned(ctx context.Context, conversationID uuid.UUID, messageID, userID string) error
 	BroadcastMessageUnpinned(ctx context.Context, conversationID uuid.UUID, messageID, userID string) error
+	BroadcastMention(ctx context.Context, userID uuid.UUID, msg dto.MessageDTO, unreadMentions int64) error
+	BroadcastThreadUpdated(ctx context.Context, userIDs []uuid.UUID, thread dto.ThreadSummaryDTO, reply dto.MessageDTO) error
+	BroadcastPollUpdated(ctx context.Context, conversationID uuid.UUID, poll dto.PollDTO) error
+	BroadcastReadState(ctx context.Context, userID uuid.UUID, state dto.ReadStateDTO) error
 }
 
+// LinkPreviewer fetches the preview of a link
+type LinkPreviewer interface {
+	Unfurl(ctx context.Context, url string) (*message.LinkPreview, error)
+}
+
+// UnreadCounter keeps unread message counts, so listing conversations does not count messages every time.
+// Counts it does not have are counted from the read markers and stored with SetUnreadCountIfAbsent
+type UnreadCounter interface {
+	GetUnreadCounts(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	SetUnreadCountIfAbsent(ctx context.Context, conversationID, userID uuid.UUID, count int) error
+	IncrementUnreadCounts(ctx context.Context, conversationID uuid.UUID, userIDs []uuid.UUID) error
+	ResetUnreadCount(ctx context.Context, conversationID, userID uuid.UUID) error
+	ResetConversationUnreadCounts(ctx context.Context, conversationID uuid.UUID) error
+}
+
+// linkPreviewTimeout bounds generating all previews of one message
+const linkPreviewTimeout = 30 * time.Second
+
 // service implements the Service interface
 type service struct {
 	messageRepo      message.Repository
 	conversationRepo conversation.Repository
 	userRepo         user.Repository
+	groupRepo        group.Repository
+	channelRepo      channel.Repository
+	privacyRepo      privacy.Repository
+	contactRepo      contact.Repository
+	changeLogRepo    changelog.Repository
 	wsBroadcaster    WSBroadcaster
+	linkPreviewer    LinkPreviewer
+	unreadCounter    UnreadCounter
 }
 
 // NewService creates a new messaging service
@@ -38,13 +75,27 @@ func NewService(
 	messageRepo message.Repository,
 	conversationRepo conversation.Repository,
 	userRepo user.Repository,
+	groupRepo group.Repository,
+	channelRepo channel.Repository,
+	privacyRepo privacy.Repository,
+	contactRepo contact.Repository,
+	changeLogRepo changelog.Repository,
 	wsBroadcaster WSBroadcaster,
+	linkPreviewer LinkPreviewer,
+	unreadCounter UnreadCounter,
 ) Service {
 	return &service{
 		messageRepo:      messageRepo,
 		conversationRepo: conversationRepo,
 		userRepo:         userRepo,
+		groupRepo:        groupRepo,
+		channelRepo:      channelRepo,
+		privacyRepo:      privacyRepo,
+		contactRepo:      contactRepo,
+		changeLogRepo:    changeLogRepo,
 		wsBroadcaster:    wsBroadcaster,
+		linkPreviewer:    linkPreviewer,
+		unreadCounter:    unreadCounter,
 	}
 }
 
@@ -62,11 +113,27 @@ func (s *service) SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.
 		return nil, fmt.Errorf("recipient not found: %w", err)
 	}
 
+	if err := s.checkNotBlocked(ctx, senderID, req.RecipientID); err != nil {
+		return nil, err
+	}
+
 	// Get or create conversation
 	conversationID, err := s.GetOrCreateDirectConversation(ctx, senderID, req.RecipientID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get/create conversation: %w", err)
 	}
+	s.acceptRequestOnReply(ctx, conversationID, senderID)
+
+	// A retried send returns the message stored by the first attempt
+	original, err := s.findClientMessage(ctx, senderID, req.ClientMessageID, conversationID)
+	if err != nil {
+		return nil, err
+	}
+	if original != nil {
+		return &dto.SendMessageResponse{
+			Message: toMessageDTO(original, sender, recipient),
+		}, nil
+	}
 
 	// Create message
 	msg := message.NewMessage(
@@ -83,10 +150,24 @@ func (s *service) SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.
 	if req.ReplyToID != nil {
 		msg.SetReplyTo(*req.ReplyToID)
 	}
+	if req.ClientMessageID != "" {
+		msg.SetClientMessageID(req.ClientMessageID)
+	}
+	if err := applyPoll(msg, req.Poll); err != nil {
+		return nil, err
+	}
 
 	// Save message
 	msg.MarkAsSent()
+	s.applyDisappearingTimer(ctx, msg)
+	s.applyThread(ctx, msg)
 	if err := s.messageRepo.Create(ctx, msg); err != nil {
+		// A concurrent retry stored it first
+		if original := s.originalOnDuplicate(ctx, msg, err); original != nil {
+			return &dto.SendMessageResponse{
+				Message: toMessageDTO(original, sender, recipient),
+			}, nil
+		}
 		return nil, fmt.Errorf("failed to create message: %w", err)
 	}
 
@@ -99,10 +180,19 @@ func (s *service) SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.
 	if err := s.conversationRepo.Update(ctx, conv); err != nil {
 		return nil, fmt.Errorf("failed to update conversation: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewMessageChange(conv.ID, msg.ID, changelog.KindMessageNew))
 
 	// Map to DTO
 	messageDTO := toMessageDTO(msg, sender, recipient)
 
+	// Record @mentions and notify mentioned users
+	mentioned := s.syncMentions(ctx, msg)
+	s.notifyMentions(messageDTO, mentioned)
+	s.incrementUnreadCounts(ctx, conv.ID, msg.SenderID)
+
+	// Update thread followers if this is a thread reply
+	s.publishThreadReply(msg, messageDTO)
+
 	// Broadcast new message via WebSocket (async to avoid blocking HTTP response)
 	if s.wsBroadcaster != nil {
 		logger.Info("🔔 Starting async broadcast",
@@ -129,15 +219,28 @@ func (s *service) SendMessage(ctx context.Context, senderID uuid.UUID, req *dto.
 		logger.Warn("⚠️ wsBroadcaster is nil, cannot broadcast")
 	}
 
+	s.generateLinkPreviews(msg, messageDTO)
+
 	return &dto.SendMessageResponse{
 		Message: messageDTO,
 	}, nil
 }
 
-// GetMessages gets messages from a conversation
-func (s *service) GetMessages(ctx context.Context, userID uuid.UUID, req *dto.GetMessagesRequest) (*dto.GetMessagesResponse, error) {
+// SendToConversation sends a message into an existing conversation (direct, group or channel)
+func (s *service) SendToConversation(ctx context.Context, senderID uuid.UUID, req *dto.SendConversationMessageRequest) (*dto.SendMessageResponse, error) {
+	// Validate sender exists
+	sender, err := s.userRepo.FindByID(ctx, senderID)
+	if err != nil {
+		return nil, fmt.Errorf("sender not found: %w", err)
+	}
+
+	conv, err := s.conversationRepo.FindByID(ctx, req.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find conversation: %w", err)
+	}
+
 	// Check if user is participant
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, req.ConversationID, userID)
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conv.ID, senderID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check participant: %w", err)
 	}
@@ -145,596 +248,2975 @@ func (s *service) GetMessages(ctx context.Context, userID uuid.UUID, req *dto.Ge
 		return nil, conversation.ErrNotParticipant
 	}
 
-	// Set defaults
-	limit := req.Limit
-	if limit == 0 {
-		limit = 50
-	}
-	if limit > 100 {
-		limit = 100
-	}
-
-	// Get messages (isPinned will only be true for messages pinned by this user)
-	messages, err := s.messageRepo.FindByConversationID(ctx, req.ConversationID, limit, req.Offset, &userID)
+	// A retried send returns the message stored by the first attempt
+	original, err := s.findClientMessage(ctx, senderID, req.ClientMessageID, conv.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get messages: %w", err)
+		return nil, err
+	}
+	if original != nil {
+		return &dto.SendMessageResponse{
+			Message: toMessageDTO(original, sender, nil),
+		}, nil
 	}
 
-	// Get total count
-	total, err := s.messageRepo.CountByConversationID(ctx, req.ConversationID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to count messages: %w", err)
+	// Check group/channel posting rules
+	if err := s.checkSendPermission(ctx, conv, senderID); err != nil {
+		return nil, err
 	}
 
-	// Get senders info (cache user lookups)
-	userCache := make(map[uuid.UUID]*user.User)
-	messageDTOs := make([]dto.MessageDTO, len(messages))
+	// Create message
+	msg := message.NewMessage(
+		conv.ID,
+		senderID,
+		req.Content,
+		message.ContentType(req.ContentType),
+	)
 
-	for i, msg := range messages {
-		var sender *user.User
-		if cachedUser, exists := userCache[msg.SenderID]; exists {
-			sender = cachedUser
-		} else {
-			sender, err = s.userRepo.FindByID(ctx, msg.SenderID)
-			if err == nil {
-				userCache[msg.SenderID] = sender
-			}
-		}
+	// Set optional fields
+	if req.Signature != "" {
+		msg.SetSignature(req.Signature)
+	}
+	if req.ReplyToID != nil {
+		msg.SetReplyTo(*req.ReplyToID)
+	}
+	if req.ClientMessageID != "" {
+		msg.SetClientMessageID(req.ClientMessageID)
+	}
+	if err := applyPoll(msg, req.Poll); err != nil {
+		return nil, err
+	}
 
-		messageDTOs[i] = toMessageDTO(msg, sender, nil)
+	messageDTO, err := s.deliverToConversation(ctx, conv, msg, sender)
+	if err != nil {
+		return nil, err
 	}
 
-	return &dto.GetMessagesResponse{
-		Messages: messageDTOs,
-		Total:    total,
+	return &dto.SendMessageResponse{
+		Message: messageDTO,
 	}, nil
 }
 
-// GetConversations gets user's conversations
-func (s *service) GetConversations(ctx context.Context, userID uuid.UUID, req *dto.GetConversationsRequest) (*dto.GetConversationsResponse, error) {
-	// Set defaults
-	limit := req.Limit
-	if limit == 0 {
-		limit = 20
+// deliverToConversation saves a new message into a conversation and runs the send pipeline:
+// last message update, mentions, thread followers and the WebSocket broadcast
+func (s *service) deliverToConversation(ctx context.Context, conv *conversation.Conversation, msg *message.Message, sender *user.User) (dto.MessageDTO, error) {
+	if conv.Type == conversation.TypeDirect {
+		s.acceptRequestOnReply(ctx, conv.ID, msg.SenderID)
 	}
-	if limit > 50 {
-		limit = 50
+
+	// Save message
+	msg.MarkAsSent()
+	s.applyDisappearingTimer(ctx, msg)
+	s.applyThread(ctx, msg)
+	if err := s.messageRepo.Create(ctx, msg); err != nil {
+		// A concurrent retry stored it first; its pipeline already ran
+		if original := s.originalOnDuplicate(ctx, msg, err); original != nil {
+			return toMessageDTO(original, sender, nil), nil
+		}
+		return dto.MessageDTO{}, fmt.Errorf("failed to create message: %w", err)
 	}
 
-	// Get conversations
-	conversations, err := s.conversationRepo.FindByUserID(ctx, userID, limit, req.Offset)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	// Update conversation last message
+	conv.UpdateLastMessage(msg.ID)
+	if err := s.conversationRepo.Update(ctx, conv); err != nil {
+		return dto.MessageDTO{}, fmt.Errorf("failed to update conversation: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewMessageChange(conv.ID, msg.ID, changelog.KindMessageNew))
 
-	// Map to DTOs
-	conversationDTOs := make([]dto.ConversationDTO, len(conversations))
-	for i, conv := range conversations {
-		// Get participants
-		participants, err := s.conversationRepo.FindParticipants(ctx, conv.ID)
-		if err != nil {
-			continue
-		}
+	messageDTO := toMessageDTO(msg, sender, nil)
 
-		// Get participant users
-		participantDTOs := make([]dto.UserDTO, 0, len(participants))
-		for _, p := range participants {
-			u, err := s.userRepo.FindByID(ctx, p.UserID)
-			if err != nil {
-				continue
-			}
-			participantDTOs = append(participantDTOs, dto.UserDTO{
-				ID:            u.ID.String(),
-				WalletAddress: u.WalletAddress,
-				Username:      u.Username,
-				Avatar:        u.Avatar,
-				Status:        string(u.Status),
-				IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
-			})
-		}
+	// Record @mentions and notify mentioned users
+	mentioned := s.syncMentions(ctx, msg)
+	s.notifyMentions(messageDTO, mentioned)
+	s.incrementUnreadCounts(ctx, conv.ID, msg.SenderID)
 
-		// Get last message if exists
-		var lastMessageDTO *dto.MessageDTO
-		if conv.LastMessageID != nil {
-			lastMsg, err := s.messageRepo.FindByID(ctx, *conv.LastMessageID)
-			if err == nil {
-				sender, _ := s.userRepo.FindByID(ctx, lastMsg.SenderID)
-				msgDTO := toMessageDTO(lastMsg, sender, nil)
-				lastMessageDTO = &msgDTO
+	// Update thread followers if this is a thread reply
+	s.publishThreadReply(msg, messageDTO)
+
+	// Broadcast new message via WebSocket (async to avoid blocking HTTP response)
+	if s.wsBroadcaster != nil {
+		go func() {
+			if err := s.wsBroadcaster.BroadcastNewMessage(context.Background(), conv.ID, messageDTO); err != nil {
+				logger.Error("Failed to broadcast new message",
+					zap.String("message_id", msg.ID.String()),
+					zap.String("conversation_id", conv.ID.String()),
+					zap.Error(err),
+				)
 			}
-		}
+		}()
+	}
 
-		// Count unread messages
-		unreadCount, err := s.messageRepo.CountUnreadByConversationID(ctx, conv.ID, userID)
-		if err != nil {
-			unreadCount = 0 // Default to 0 if error
-		}
+	s.generateLinkPreviews(msg, messageDTO)
 
-		conversationDTOs[i] = dto.ConversationDTO{
-			ID:           conv.ID.String(),
-			Type:         string(conv.Type),
-			Participants: participantDTOs,
-			LastMessage:  lastMessageDTO,
-			UnreadCount:  int(unreadCount),
-			CreatedAt:    conv.CreatedAt,
-			UpdatedAt:    conv.UpdatedAt,
+	return messageDTO, nil
+}
+
+// findClientMessage returns the message the sender already sent with this client message ID, nil if there is none
+func (s *service) findClientMessage(ctx context.Context, senderID uuid.UUID, clientMessageID string, conversationID uuid.UUID) (*message.Message, error) {
+	if clientMessageID == "" {
+		return nil, nil
+	}
+
+	original, err := s.messageRepo.FindByClientMessageID(ctx, senderID, clientMessageID)
+	if err != nil {
+		if errors.Is(err, message.ErrMessageNotFound) {
+			return nil, nil
 		}
+		return nil, fmt.Errorf("failed to find message by client ID: %w", err)
 	}
+	if original.ConversationID != conversationID {
+		return nil, message.ErrClientMessageIDReused
+	}
+	s.attachPolls(ctx, &senderID, []*message.Message{original})
 
-	return &dto.GetConversationsResponse{
-		Conversations: conversationDTOs,
-	}, nil
+	return original, nil
 }
 
-// CreateConversation creates a new conversation
-func (s *service) CreateConversation(ctx context.Context, userID uuid.UUID, req *dto.CreateConversationRequest) (*dto.CreateConversationResponse, error) {
-	// Resolve participant_id (can be UUID or wallet address)
-	participantID, err := s.resolveParticipantID(ctx, req.ParticipantID)
+// originalOnDuplicate returns the stored message when Create lost against another send with the same client message ID
+func (s *service) originalOnDuplicate(ctx context.Context, msg *message.Message, createErr error) *message.Message {
+	if !errors.Is(createErr, message.ErrDuplicateClientMessage) {
+		return nil
+	}
+
+	original, err := s.findClientMessage(ctx, msg.SenderID, msg.ClientMessageID, msg.ConversationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to resolve participant: %w", err)
+		logger.Warn("Failed to load original of duplicate send",
+			zap.String("client_message_id", msg.ClientMessageID),
+			zap.Error(err),
+		)
+		return nil
 	}
 
-	// Check if direct conversation already exists
-	if req.Type == "direct" {
-		existingConv, err := s.conversationRepo.FindDirectConversation(ctx, userID, participantID)
-		if err == nil {
-			// Conversation already exists, return it
-			participants, _ := s.conversationRepo.FindParticipants(ctx, existingConv.ID)
-			participantDTOs := make([]dto.UserDTO, 0, len(participants))
-			for _, p := range participants {
-				u, err := s.userRepo.FindByID(ctx, p.UserID)
-				if err != nil {
-					continue
-				}
-				participantDTOs = append(participantDTOs, dto.UserDTO{
-					ID:            u.ID.String(),
-					WalletAddress: u.WalletAddress,
-					Username:      u.Username,
-					Avatar:        u.Avatar,
-					Status:        string(u.Status),
-					IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
-				})
-			}
+	return original
+}
 
-			return &dto.CreateConversationResponse{
-				Conversation: dto.ConversationDTO{
-					ID:           existingConv.ID.String(),
-					Type:         string(existingConv.Type),
-					Participants: participantDTOs,
-					UnreadCount:  0,
-					CreatedAt:    existingConv.CreatedAt,
-					UpdatedAt:    existingConv.UpdatedAt,
-				},
-			}, nil
-		}
+// syncMentions resolves @username tokens in the message, stores them and
+// returns the users who were not already mentioned in this message
+func (s *service) syncMentions(ctx context.Context, msg *message.Message) []uuid.UUID {
+	existing, err := s.messageRepo.GetMentionsByMessageID(ctx, msg.ID)
+	if err != nil {
+		logger.Warn("Failed to get existing mentions", zap.String("message_id", msg.ID.String()), zap.Error(err))
 	}
-
-	// Create new conversation
-	conv := conversation.NewDirectConversation()
-	if err := s.conversationRepo.Create(ctx, conv); err != nil {
-		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	previous := make(map[uuid.UUID]message.MessageMention, len(existing))
+	for _, m := range existing {
+		previous[m.UserID] = m
 	}
 
-	// Add current user as participant
-	participant1 := conversation.NewParticipant(conv.ID, userID, conversation.RoleMember)
-	if err := s.conversationRepo.AddParticipant(ctx, participant1); err != nil {
-		return nil, fmt.Errorf("failed to add current user: %w", err)
+	tokens := message.ParseMentions(msg.Content)
+	if len(tokens) == 0 && len(existing) == 0 {
+		return nil
 	}
 
-	// Add other participant
-	participant2 := conversation.NewParticipant(conv.ID, participantID, conversation.RoleMember)
-	if err := s.conversationRepo.AddParticipant(ctx, participant2); err != nil {
-		return nil, fmt.Errorf("failed to add participant: %w", err)
-	}
+	resolved := make(map[string]*user.User)
+	mentions := make([]message.MessageMention, 0, len(tokens))
+	var newlyMentioned []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
 
-	// Get participants for response
-	participants, _ := s.conversationRepo.FindParticipants(ctx, conv.ID)
-	participantDTOs := make([]dto.UserDTO, 0, len(participants))
-	for _, p := range participants {
-		u, err := s.userRepo.FindByID(ctx, p.UserID)
-		if err != nil {
+	for _, token := range tokens {
+		u, cached := resolved[token.Username]
+		if !cached {
+			u, _ = s.userRepo.FindByUsername(ctx, token.Username)
+			resolved[token.Username] = u
+		}
+		// A user mentioned again in the same message keeps the first mention only
+		if u == nil || u.ID == msg.SenderID || seen[u.ID] {
 			continue
 		}
-		participantDTOs = append(participantDTOs, dto.UserDTO{
-			ID:            u.ID.String(),
-			WalletAddress: u.WalletAddress,
-			Username:      u.Username,
-			Avatar:        u.Avatar,
-			Status:        string(u.Status),
-			IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
-		})
-	}
 
-	return &dto.CreateConversationResponse{
-		Conversation: dto.ConversationDTO{
-			ID:           conv.ID.String(),
-			Type:         string(conv.Type),
-			Participants: participantDTOs,
-			UnreadCount:  0,
-			CreatedAt:    conv.CreatedAt,
-			UpdatedAt:    conv.UpdatedAt,
-		},
-	}, nil
-}
+		// Only participants can be mentioned
+		isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, u.ID)
+		if err != nil || !isParticipant {
+			resolved[token.Username] = nil
+			continue
+		}
 
-// GetOrCreateDirectConversation gets or creates a direct conversation
-func (s *service) GetOrCreateDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (uuid.UUID, error) {
-	// Try to find existing conversation
-	existingConv, err := s.conversationRepo.FindDirectConversation(ctx, user1ID, user2ID)
-	if err == nil {
-		return existingConv.ID, nil
+		mention := message.MessageMention{
+			ConversationID: msg.ConversationID,
+			UserID:         u.ID,
+			Position:       token.Position,
+			Length:         token.Length,
+		}
+		if prev, ok := previous[u.ID]; ok {
+			// Keep read state across edits
+			mention.ReadAt = prev.ReadAt
+		} else {
+			newlyMentioned = append(newlyMentioned, u.ID)
+		}
+		seen[u.ID] = true
+		mentions = append(mentions, mention)
 	}
 
-	if err != conversation.ErrConversationNotFound {
-		return uuid.Nil, fmt.Errorf("failed to find conversation: %w", err)
+	if err := s.messageRepo.ReplaceMentions(ctx, msg.ID, mentions); err != nil {
+		logger.Error("Failed to save mentions", zap.String("message_id", msg.ID.String()), zap.Error(err))
+		return nil
 	}
 
-	// Create new conversation
-	conv := conversation.NewDirectConversation()
-	if err := s.conversationRepo.Create(ctx, conv); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to create conversation: %w", err)
-	}
+	return newlyMentioned
+}
 
-	// Add participants
-	participant1 := conversation.NewParticipant(conv.ID, user1ID, conversation.RoleMember)
-	if err := s.conversationRepo.AddParticipant(ctx, participant1); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to add participant 1: %w", err)
+// notifyMentions sends a targeted mention event to each mentioned user
+func (s *service) notifyMentions(msg dto.MessageDTO, userIDs []uuid.UUID) {
+	if s.wsBroadcaster == nil || len(userIDs) == 0 {
+		return
 	}
 
-	participant2 := conversation.NewParticipant(conv.ID, user2ID, conversation.RoleMember)
-	if err := s.conversationRepo.AddParticipant(ctx, participant2); err != nil {
-		return uuid.Nil, fmt.Errorf("failed to add participant 2: %w", err)
+	conversationID, err := uuid.Parse(msg.ConversationID)
+	if err != nil {
+		return
 	}
 
-	return conv.ID, nil
+	go func() {
+		ctx := context.Background()
+		for _, userID := range userIDs {
+			counts, err := s.messageRepo.CountUnreadMentions(ctx, userID)
+			if err != nil {
+				logger.Warn("Failed to count unread mentions", zap.Error(err))
+			}
+			if err := s.wsBroadcaster.BroadcastMention(ctx, userID, msg, counts[conversationID]); err != nil {
+				logger.Error("Failed to broadcast mention",
+					zap.String("message_id", msg.ID),
+					zap.String("user_id", userID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}()
 }
 
-// MarkAsRead marks messages in a conversation as read
-func (s *service) MarkAsRead(ctx context.Context, userID uuid.UUID, req *dto.MarkAsReadRequest) error {
-	// Check if user is participant
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, req.ConversationID, userID)
-	if err != nil {
-		return fmt.Errorf("failed to check participant: %w", err)
+// applyThread places a reply in the thread of the message it replies to
+func (s *service) applyThread(ctx context.Context, msg *message.Message) {
+	if msg.ReplyToID == nil {
+		return
 	}
-	if !isParticipant {
-		return conversation.ErrNotParticipant
+
+	parent, err := s.messageRepo.FindByID(ctx, *msg.ReplyToID)
+	if err != nil || parent.ConversationID != msg.ConversationID {
+		return
 	}
 
-	// Get all messages in conversation (isPinned will only be true for messages pinned by this user)
-	messages, err := s.messageRepo.FindByConversationID(ctx, req.ConversationID, 1000, 0, &userID)
-	if err != nil {
-		return fmt.Errorf("failed to get messages: %w", err)
-	}
+	// Replies to replies join the parent's thread rather than starting a new one
+	msg.SetThread(parent.ThreadRootID())
+}
 
-	// Update status to "read" for all messages not sent by this user
-	var messageIDs []string
+// attachThreadSummaries sets reply info on the thread roots among the messages
+func (s *service) attachThreadSummaries(ctx context.Context, messages []*message.Message) {
+	rootIDs := make([]uuid.UUID, 0, len(messages))
 	for _, msg := range messages {
-		// Only mark messages from other users as read
-		if msg.SenderID != userID && msg.Status != message.StatusRead {
-			if err := s.messageRepo.UpdateStatus(ctx, msg.ID, message.StatusRead); err != nil {
-				logger.Error("Failed to update message status", zap.Error(err), zap.String("message_id", msg.ID.String()))
-				continue
-			}
-			messageIDs = append(messageIDs, msg.ID.String())
+		if msg.ThreadID == nil {
+			rootIDs = append(rootIDs, msg.ID)
 		}
 	}
-
-	// Update last read timestamp
-	if err := s.conversationRepo.UpdateParticipantLastRead(ctx, req.ConversationID, userID); err != nil {
-		return fmt.Errorf("failed to update last read: %w", err)
+	if len(rootIDs) == 0 {
+		return
 	}
 
-	return nil
-}
-
-// DeleteMessage deletes a message
-func (s *service) DeleteMessage(ctx context.Context, userID, messageID uuid.UUID) error {
-	// Get message
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	summaries, err := s.messageRepo.GetThreadSummaries(ctx, rootIDs)
 	if err != nil {
-		return fmt.Errorf("message not found: %w", err)
+		logger.Warn("Failed to get thread summaries", zap.Error(err))
+		return
 	}
 
-	// Check if user is the sender
-	if msg.SenderID != userID {
-		return message.ErrUnauthorized
+	for _, msg := range messages {
+		if summary, ok := summaries[msg.ID]; ok {
+			msg.Thread = &summary
+		}
 	}
+}
 
-	// Delete message
-	if err := s.messageRepo.Delete(ctx, messageID); err != nil {
-		return fmt.Errorf("failed to delete message: %w", err)
+// publishThreadReply auto-follows the thread for the replier and root author,
+// then notifies followers with the updated reply info
+func (s *service) publishThreadReply(msg *message.Message, reply dto.MessageDTO) {
+	if msg.ThreadID == nil {
+		return
 	}
+	rootID := *msg.ThreadID
 
-	return nil
-}
+	go func() {
+		ctx := context.Background()
 
-// EditMessage edits the content of a message
-func (s *service) EditMessage(ctx context.Context, userID, messageID uuid.UUID, newContent string) (*dto.MessageDTO, error) {
-	// Get message
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
-	if err != nil {
-		return nil, fmt.Errorf("message not found: %w", err)
-	}
+		if err := s.messageRepo.FollowThread(ctx, rootID, msg.SenderID); err != nil {
+			logger.Warn("Failed to follow thread", zap.String("root_message_id", rootID.String()), zap.Error(err))
+		}
+		if root, err := s.messageRepo.FindByID(ctx, rootID); err == nil {
+			if err := s.messageRepo.FollowThread(ctx, rootID, root.SenderID); err != nil {
+				logger.Warn("Failed to follow thread", zap.String("root_message_id", rootID.String()), zap.Error(err))
+			}
+		}
 
-	// Check if user is the sender
-	if msg.SenderID != userID {
-		return nil, message.ErrUnauthorized
-	}
+		// The reply count and last reply of the root changed
+		s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, rootID, changelog.KindMessageUpdated))
 
-	// Update message content
-	msg.Content = newContent
-	msg.UpdatedAt = time.Now()
+		if s.wsBroadcaster == nil {
+			return
+		}
 
-	// Save updated message
-	if err := s.messageRepo.Update(ctx, msg); err != nil {
-		return nil, fmt.Errorf("failed to update message: %w", err)
-	}
+		summaries, err := s.messageRepo.GetThreadSummaries(ctx, []uuid.UUID{rootID})
+		if err != nil {
+			logger.Error("Failed to get thread summary", zap.String("root_message_id", rootID.String()), zap.Error(err))
+			return
+		}
+		summary, ok := summaries[rootID]
+		if !ok {
+			return
+		}
 
-	// Get sender for DTO
-	sender, err := s.userRepo.FindByID(ctx, msg.SenderID)
-	if err != nil {
-		return nil, fmt.Errorf("sender not found: %w", err)
-	}
+		followers, err := s.messageRepo.GetThreadFollowers(ctx, rootID)
+		if err != nil {
+			logger.Error("Failed to get thread followers", zap.String("root_message_id", rootID.String()), zap.Error(err))
+			return
+		}
 
-	// Convert to DTO
-	messageDTO := toMessageDTO(msg, sender, nil)
+		// Followers who have since left the conversation don't get updates
+		recipients := make([]uuid.UUID, 0, len(followers))
+		for _, userID := range followers {
+			if isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, userID); err == nil && isParticipant {
+				recipients = append(recipients, userID)
+			}
+		}
+		if len(recipients) == 0 {
+			return
+		}
 
-	// Broadcast message updated via WebSocket
-	if s.wsBroadcaster != nil {
-		if err := s.wsBroadcaster.BroadcastMessageUpdated(
-			ctx,
-			msg.ConversationID,
-			messageDTO,
-		); err != nil {
-			logger.Error("Failed to broadcast message updated",
-				zap.String("message_id", messageID.String()),
+		if err := s.wsBroadcaster.BroadcastThreadUpdated(ctx, recipients, toThreadSummaryDTO(summary), reply); err != nil {
+			logger.Error("Failed to broadcast thread update",
+				zap.String("root_message_id", rootID.String()),
 				zap.Error(err),
 			)
 		}
+	}()
+}
+
+// applyDisappearingTimer stamps the message expiry if the conversation has disappearing messages enabled
+func (s *service) applyDisappearingTimer(ctx context.Context, msg *message.Message) {
+	if s.privacyRepo == nil {
+		return
 	}
 
-	return &messageDTO, nil
+	config, err := s.privacyRepo.GetDisappearingMessagesConfig(ctx, msg.ConversationID)
+	if err != nil || !config.IsDisappearing() {
+		return
+	}
+
+	// CreatedAt is assigned by the database, so base the expiry on the send time
+	msg.SetExpiry(config.GetExpiryTime(time.Now()))
 }
 
-// GetMessageByID gets a message by ID
-func (s *service) GetMessageByID(ctx context.Context, messageID uuid.UUID) (*dto.MessageDTO, error) {
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
-	if err != nil {
-		return nil, fmt.Errorf("message not found: %w", err)
+// privacySettings gets a user's privacy settings, using the defaults if they never changed them.
+// If the settings cannot be loaded, read receipts and typing are treated as off so nothing leaks,
+// and new chats from non-contacts go to message requests
+func (s *service) privacySettings(ctx context.Context, userID uuid.UUID) *privacy.PrivacySettings {
+	defaults := privacy.DefaultPrivacySettings(userID)
+	if s.privacyRepo == nil {
+		return defaults
 	}
 
-	// Get sender
-	sender, err := s.userRepo.FindByID(ctx, msg.SenderID)
-	if err != nil {
-		return nil, fmt.Errorf("sender not found: %w", err)
+	settings, err := s.privacyRepo.GetPrivacySettings(ctx, userID)
+	if err == nil {
+		return settings
 	}
-
-	messageDTO := toMessageDTO(msg, sender, nil)
-	return &messageDTO, nil
+	if !errors.Is(err, privacy.ErrPrivacySettingsNotFound) {
+		logger.Warn("Failed to get privacy settings", zap.String("user_id", userID.String()), zap.Error(err))
+		defaults.ReadReceiptsEnabled = false
+		defaults.TypingIndicatorEnabled = false
+		defaults.WhoCanMessage = privacy.VisibilityContacts
+	}
+	return defaults
 }
 
-// GetConversationParticipants gets participant IDs for a conversation
-func (s *service) GetConversationParticipants(ctx context.Context, conversationID uuid.UUID) ([]uuid.UUID, error) {
+// TypingAudience returns the participants who may see a user typing in a conversation.
+// It is empty if the user turned typing indicators off, is not a participant or the conversation
+// is a message request that was not accepted yet, and leaves out
+// anyone the user blocked or was blocked by
+func (s *service) TypingAudience(ctx context.Context, userID, conversationID uuid.UUID) ([]uuid.UUID, error) {
+	if !s.privacySettings(ctx, userID).TypingIndicatorEnabled {
+		return nil, nil
+	}
+
 	participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find participants: %w", err)
+	}
+
+	isParticipant := false
+	for _, p := range participants {
+		if p.UserID == userID {
+			isParticipant = true
+			break
+		}
+	}
+	// Nothing is shared either way until a message request is accepted
+	if !isParticipant || conversation.FindPendingRequest(participants) != nil {
+		return nil, nil
+	}
+
+	blocked, err := s.blockedUsers(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	participantIDs := make([]uuid.UUID, len(participants))
-	for i, p := range participants {
-		participantIDs[i] = p.UserID
+	audience := make([]uuid.UUID, 0, len(participants))
+	for _, p := range participants {
+		if !blocked[p.UserID] {
+			audience = append(audience, p.UserID)
+		}
 	}
 
-	return participantIDs, nil
+	return audience, nil
 }
 
-// UpdateMessageStatus updates the status of a message (delivered/read)
-func (s *service) UpdateMessageStatus(ctx context.Context, messageID uuid.UUID, status string) error {
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
-	if err != nil {
-		return err
+// blockedUsers returns the users a user blocked or was blocked by
+func (s *service) blockedUsers(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]bool, error) {
+	if s.privacyRepo == nil {
+		return nil, nil
 	}
 
-	// Update status based on string value
-	switch status {
-	case "delivered":
-		msg.MarkAsDelivered()
-	case "read":
-		msg.MarkAsRead()
-	default:
-		return fmt.Errorf("invalid status: %s", status)
+	relations, err := s.privacyRepo.GetBlockRelations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block relations: %w", err)
 	}
 
-	// Save updated message
-	if err := s.messageRepo.Update(ctx, msg); err != nil {
-		return err
+	blocked := make(map[uuid.UUID]bool, len(relations))
+	for _, id := range relations {
+		blocked[id] = true
 	}
-
-	return nil
+	return blocked, nil
 }
 
-// Helper function to map domain message to DTO
-func toMessageDTO(msg *message.Message, sender *user.User, recipient *user.User) dto.MessageDTO {
-	var replyToID *string
-	if msg.ReplyToID != nil {
-		id := msg.ReplyToID.String()
-		replyToID = &id
+// readReceiptsShared checks if a user's reads in a conversation are shown to others.
+// They are not if the user turned read receipts off or has not accepted the conversation's message request
+func (s *service) readReceiptsShared(ctx context.Context, conv *conversation.Conversation, userID uuid.UUID) bool {
+	if !s.privacySettings(ctx, userID).ReadReceiptsEnabled {
+		return false
 	}
-
-	msgDTO := dto.MessageDTO{
-		ID:             msg.ID.String(),
-		ConversationID: msg.ConversationID.String(),
-		SenderID:       msg.SenderID.String(),
-		Content:        msg.Content,
-		ContentType:    string(msg.ContentType),
-		Signature:      msg.Signature,
-		ReplyToID:      replyToID,
-		Status:         string(msg.Status),
-		CreatedAt:      msg.CreatedAt,
-		UpdatedAt:      msg.UpdatedAt,
-		Reactions:      msg.Reactions,
-		IsPinned:       msg.IsPinned,
-		PinnedBy:       msg.PinnedBy,
+	if conv.Type != conversation.TypeDirect {
+		return true
 	}
 
-	if sender != nil {
-		msgDTO.Sender = &dto.UserDTO{
-			ID:            sender.ID.String(),
-			WalletAddress: sender.WalletAddress,
-			Username:      sender.Username,
-			Avatar:        sender.Avatar,
-			Status:        string(sender.Status),
-		}
+	participants, err := s.conversationRepo.FindParticipants(ctx, conv.ID)
+	if err != nil {
+		logger.Warn("Failed to find participants", zap.String("conversation_id", conv.ID.String()), zap.Error(err))
+		return false
 	}
 
-	return msgDTO
+	pending := conversation.FindPendingRequest(participants)
+	return pending == nil || pending.UserID != userID
 }
 
-// Message Reactions (Day 13)
+// checkNotBlocked returns privacy.ErrUserUnavailable if either user blocked the other
+func (s *service) checkNotBlocked(ctx context.Context, userID, otherUserID uuid.UUID) error {
+	if s.privacyRepo == nil {
+		return nil
+	}
 
-func (s *service) AddReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error {
-	// Get message to find conversation ID
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, userID, otherUserID)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return privacy.ErrUserUnavailable
 	}
+	return nil
+}
 
-	if err := s.messageRepo.AddReaction(ctx, messageID, userID, emoji); err != nil {
-		return err
+// checkDirectNotBlocked checks the sender and the other member of a direct conversation have not blocked each other
+func (s *service) checkDirectNotBlocked(ctx context.Context, conv *conversation.Conversation, senderID uuid.UUID) error {
+	if conv.Type != conversation.TypeDirect {
+		return nil
 	}
 
-	// Broadcast reaction added via WebSocket
-	if s.wsBroadcaster != nil {
-		if err := s.wsBroadcaster.BroadcastReactionAdded(
-			ctx,
-			msg.ConversationID,
-			messageID.String(),
-			userID.String(),
-			emoji,
-		); err != nil {
-			logger.Error("Failed to broadcast reaction added",
-				zap.String("message_id", messageID.String()),
-				zap.Error(err),
-			)
+	participants, err := s.conversationRepo.FindParticipants(ctx, conv.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find participants: %w", err)
+	}
+
+	for _, p := range participants {
+		if p.UserID == senderID {
+			continue
+		}
+		if err := s.checkNotBlocked(ctx, senderID, p.UserID); err != nil {
+			return err
 		}
 	}
 
 	return nil
 }
 
-func (s *service) RemoveReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error {
-	logger.Info("🗑️ RemoveReaction called",
-		zap.String("messageID", messageID.String()),
-		zap.String("userID", userID.String()),
-		zap.String("emoji", emoji),
-	)
+// checkSendPermission enforces blocks in direct chats and group and channel posting rules for a sender
+func (s *service) checkSendPermission(ctx context.Context, conv *conversation.Conversation, senderID uuid.UUID) error {
+	switch conv.Type {
+	case conversation.TypeDirect:
+		return s.checkDirectNotBlocked(ctx, conv, senderID)
 
-	// Get message to find conversation ID
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
-	if err != nil {
-		logger.Error("Failed to find message", zap.Error(err))
-		return err
-	}
+	case conversation.TypeGroup:
+		if s.groupRepo == nil {
+			return nil
+		}
 
-	logger.Info("📝 Message found, removing reaction from DB",
-		zap.String("conversationID", msg.ConversationID.String()),
-	)
+		grp, err := s.groupRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find group: %w", err)
+		}
 
-	if err := s.messageRepo.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
-		logger.Error("Failed to remove reaction from DB", zap.Error(err))
-		return err
-	}
+		member, err := s.groupRepo.FindMember(ctx, grp.ID, senderID)
+		if err != nil {
+			if err == group.ErrMemberNotFound {
+				return message.ErrSendNotAllowed
+			}
+			return fmt.Errorf("failed to find group member: %w", err)
+		}
 
-	logger.Info("✅ Reaction removed from DB")
+		// Only admins can post when the group is restricted
+		if grp.Settings != nil && grp.Settings.WhoCanMessage == "admins_only" && !member.IsAdmin() {
+			return message.ErrSendNotAllowed
+		}
+		if member.Permissions != nil && !member.Permissions.CanSendMessages {
+			return message.ErrSendNotAllowed
+		}
 
-	// Broadcast reaction removed via WebSocket
-	if s.wsBroadcaster != nil {
-		if err := s.wsBroadcaster.BroadcastReactionRemoved(
-			ctx,
-			msg.ConversationID,
-			messageID.String(),
-			userID.String(),
-			emoji,
-		); err != nil {
-			logger.Error("Failed to broadcast reaction removed",
-				zap.String("message_id", messageID.String()),
-				zap.Error(err),
-			)
+	case conversation.TypeChannel:
+		if s.channelRepo == nil {
+			return nil
+		}
+
+		ch, err := s.channelRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return fmt.Errorf("failed to find channel: %w", err)
+		}
+
+		// Owner can always post
+		if ch.OwnerID == senderID {
+			return nil
+		}
+
+		// If admins can't post, only owner can post
+		if ch.Settings != nil && !ch.Settings.AdminsCanPost {
+			return message.ErrSendNotAllowed
+		}
+
+		admin, err := s.channelRepo.FindAdmin(ctx, ch.ID, senderID)
+		if err != nil {
+			if err == channel.ErrAdminNotFound {
+				return message.ErrSendNotAllowed
+			}
+			return fmt.Errorf("failed to find channel admin: %w", err)
+		}
+		if !admin.CanPost() {
+			return message.ErrSendNotAllowed
 		}
 	}
 
 	return nil
 }
 
-func (s *service) GetMessageReactions(ctx context.Context, messageID uuid.UUID) (*dto.MessageReactionsResponse, error) {
-	reactions, err := s.messageRepo.GetMessageReactions(ctx, messageID)
+// editWindow returns how long after sending a message in the conversation can be edited, 0 means no limit
+func (s *service) editWindow(ctx context.Context, conversationID uuid.UUID) (time.Duration, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
 	if err != nil {
-		return nil, err
+		return 0, fmt.Errorf("conversation not found: %w", err)
 	}
 
-	reactionDTOs := make([]dto.ReactionResponse, len(reactions))
-	for i, r := range reactions {
-		reactionDTOs[i] = dto.ReactionResponse{
-			ID:        r.ID.String(),
-			MessageID: r.MessageID.String(),
-			UserID:    r.UserID.String(),
-			Emoji:     r.Emoji,
-			CreatedAt: r.CreatedAt,
+	seconds := 0
+	switch conv.Type {
+	case conversation.TypeGroup:
+		if s.groupRepo == nil {
+			return 0, nil
+		}
+		grp, err := s.groupRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find group: %w", err)
+		}
+		if grp.Settings != nil {
+			seconds = grp.Settings.EditWindowSeconds
+		}
+
+	case conversation.TypeChannel:
+		if s.channelRepo == nil {
+			return 0, nil
+		}
+		ch, err := s.channelRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to find channel: %w", err)
+		}
+		if ch.Settings != nil {
+			seconds = ch.Settings.EditWindowSeconds
 		}
 	}
 
-	return &dto.MessageReactionsResponse{
-		MessageID: messageID.String(),
-		Reactions: reactionDTOs,
-	}, nil
+	return time.Duration(seconds) * time.Second, nil
 }
 
-// Pinned Messages (Day 13)
-
-func (s *service) PinMessage(ctx context.Context, userID, conversationID, messageID uuid.UUID) error {
-	// Verify user is participant of conversation
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
-	if err != nil {
-		return fmt.Errorf("failed to check participant: %w", err)
+// generateLinkPreviews unfurls the links of a text message in the background.
+// Once the previews are stored the message is broadcast again as message.updated.
+func (s *service) generateLinkPreviews(msg *message.Message, messageDTO dto.MessageDTO) {
+	if s.linkPreviewer == nil || msg.ContentType != message.ContentTypeText {
+		return
 	}
-	if !isParticipant {
-		return conversation.ErrNotParticipant
+	urls := message.ExtractURLs(msg.Content, message.MaxLinkPreviews)
+	if len(urls) == 0 {
+		return
 	}
 
-	// Verify message exists and belongs to conversation
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
-	if err != nil {
-		return fmt.Errorf("message not found: %w", err)
-	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), linkPreviewTimeout)
+		defer cancel()
 
-	if msg.ConversationID != conversationID {
-		return message.ErrMessageNotFound
-	}
+		if !s.linkPreviewsEnabled(ctx, msg.ConversationID) {
+			return
+		}
 
-	if err := s.messageRepo.PinMessage(ctx, conversationID, messageID, userID); err != nil {
+		previews := make([]message.LinkPreview, 0, len(urls))
+		for _, url := range urls {
+			preview, err := s.linkPreviewer.Unfurl(ctx, url)
+			if err != nil {
+				logger.Debug("No link preview",
+					zap.String("message_id", msg.ID.String()),
+					zap.Error(err),
+				)
+				continue
+			}
+			previews = append(previews, *preview)
+		}
+		if len(previews) == 0 {
+			return
+		}
+
+		// The message may have been edited or deleted while the links were fetched
+		if err := s.messageRepo.SetLinkPreviews(ctx, msg.ID, msg.EditCount, previews); err != nil {
+			if !errors.Is(err, message.ErrEditConflict) {
+				logger.Error("Failed to save link previews", zap.String("message_id", msg.ID.String()), zap.Error(err))
+			}
+			return
+		}
+		s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, msg.ID, changelog.KindMessageUpdated))
+
+		if s.wsBroadcaster != nil {
+			messageDTO.LinkPreviews = toLinkPreviewDTOs(previews)
+			if err := s.wsBroadcaster.BroadcastMessageUpdated(ctx, msg.ConversationID, messageDTO); err != nil {
+				logger.Error("Failed to broadcast link previews",
+					zap.String("message_id", msg.ID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+	}()
+}
+
+// linkPreviewsEnabled checks the group or channel link preview setting, direct chats always get previews
+func (s *service) linkPreviewsEnabled(ctx context.Context, conversationID uuid.UUID) bool {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		return false
+	}
+
+	switch conv.Type {
+	case conversation.TypeGroup:
+		if s.groupRepo == nil {
+			return true
+		}
+		grp, err := s.groupRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return false
+		}
+		return grp.Settings == nil || grp.Settings.LinkPreview
+
+	case conversation.TypeChannel:
+		if s.channelRepo == nil {
+			return true
+		}
+		ch, err := s.channelRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return false
+		}
+		return ch.Settings == nil || ch.Settings.LinkPreview
+	}
+
+	return true
+}
+
+// GetMessages gets messages from a conversation
+func (s *service) GetMessages(ctx context.Context, userID uuid.UUID, req *dto.GetMessagesRequest) (*dto.GetMessagesResponse, error) {
+	// Check if user is participant
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, req.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	// Set defaults
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// Get total count of the messages the user's pages can return
+	total, err := s.messageRepo.CountByConversationID(ctx, req.ConversationID, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count messages: %w", err)
+	}
+
+	// Get messages (isPinned will only be true for messages pinned by this user)
+	messages, hasOlder, hasNewer, err := s.findMessagePage(ctx, userID, req, limit, total)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attach reply counts to thread roots and receipt counts to the user's own messages
+	s.attachThreadSummaries(ctx, messages)
+	s.attachReceiptCounts(ctx, userID, messages)
+	s.attachPolls(ctx, &userID, messages)
+
+	// Get senders info (cache user lookups)
+	userCache := make(map[uuid.UUID]*user.User)
+	messageDTOs := make([]dto.MessageDTO, len(messages))
+
+	for i, msg := range messages {
+		var sender *user.User
+		if cachedUser, exists := userCache[msg.SenderID]; exists {
+			sender = cachedUser
+		} else {
+			sender, err = s.userRepo.FindByID(ctx, msg.SenderID)
+			if err == nil {
+				userCache[msg.SenderID] = sender
+			}
+		}
+
+		messageDTOs[i] = toMessageDTO(msg, sender, nil)
+	}
+
+	resp := &dto.GetMessagesResponse{
+		Messages: messageDTOs,
+		Total:    total,
+		HasOlder: hasOlder,
+		HasNewer: hasNewer,
+	}
+	if len(messages) > 0 {
+		resp.PrevCursor = message.CursorFor(messages[0]).Encode()
+		resp.NextCursor = message.CursorFor(messages[len(messages)-1]).Encode()
+	}
+
+	return resp, nil
+}
+
+// findMessagePage loads one page of history, newest first, using the offset,
+// before/after cursor or around-message mode selected by the request
+func (s *service) findMessagePage(ctx context.Context, userID uuid.UUID, req *dto.GetMessagesRequest, limit int, total int64) ([]*message.Message, bool, bool, error) {
+	switch {
+	case req.AroundID != nil:
+		anchor, err := s.messageRepo.FindByID(ctx, *req.AroundID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to find message: %w", err)
+		}
+		if anchor.ConversationID != req.ConversationID {
+			return nil, false, false, message.ErrMessageNotFound
+		}
+
+		// Center the window on the anchor, fetching one extra on each side to detect more
+		olderLimit := (limit - 1) / 2
+		newerLimit := limit - 1 - olderLimit
+		messages, err := s.messageRepo.FindAround(ctx, req.ConversationID, message.CursorFor(anchor), olderLimit+1, newerLimit+1, &userID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to get messages: %w", err)
+		}
+
+		anchorIndex := 0
+		for i, msg := range messages {
+			if msg.ID == anchor.ID {
+				anchorIndex = i
+				break
+			}
+		}
+
+		hasNewer := anchorIndex > newerLimit
+		hasOlder := len(messages)-anchorIndex-1 > olderLimit
+		if hasOlder {
+			messages = messages[:len(messages)-1]
+		}
+		if hasNewer {
+			messages = messages[1:]
+		}
+		return messages, hasOlder, hasNewer, nil
+
+	case req.Before != "":
+		cursor, err := s.resolveCursor(ctx, req.ConversationID, req.Before)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		messages, err := s.messageRepo.FindBefore(ctx, req.ConversationID, cursor, limit+1, &userID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to get messages: %w", err)
+		}
+
+		hasOlder := len(messages) > limit
+		if hasOlder {
+			messages = messages[:limit]
+		}
+		return messages, hasOlder, true, nil
+
+	case req.After != "":
+		cursor, err := s.resolveCursor(ctx, req.ConversationID, req.After)
+		if err != nil {
+			return nil, false, false, err
+		}
+
+		messages, err := s.messageRepo.FindAfter(ctx, req.ConversationID, cursor, limit+1, &userID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to get messages: %w", err)
+		}
+
+		// Results are newest first, so the extra row is at the front
+		hasNewer := len(messages) > limit
+		if hasNewer {
+			messages = messages[1:]
+		}
+		return messages, true, hasNewer, nil
+
+	default:
+		messages, err := s.messageRepo.FindByConversationID(ctx, req.ConversationID, limit, req.Offset, &userID)
+		if err != nil {
+			return nil, false, false, fmt.Errorf("failed to get messages: %w", err)
+		}
+
+		hasOlder := int64(req.Offset+len(messages)) < total
+		return messages, hasOlder, req.Offset > 0, nil
+	}
+}
+
+// resolveCursor accepts either a message ID or an encoded cursor
+func (s *service) resolveCursor(ctx context.Context, conversationID uuid.UUID, value string) (message.Cursor, error) {
+	if messageID, err := uuid.Parse(value); err == nil {
+		msg, err := s.messageRepo.FindByID(ctx, messageID)
+		if err != nil {
+			return message.Cursor{}, fmt.Errorf("failed to find cursor message: %w", err)
+		}
+		if msg.ConversationID != conversationID {
+			return message.Cursor{}, message.ErrMessageNotFound
+		}
+		return message.CursorFor(msg), nil
+	}
+
+	return message.ParseCursor(value)
+}
+
+// GetConversations gets user's conversations
+func (s *service) GetConversations(ctx context.Context, userID uuid.UUID, req *dto.GetConversationsRequest) (*dto.GetConversationsResponse, error) {
+	// Set defaults
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	// Get conversations
+	filter := conversation.ListFilter{
+		Pinned: req.Pinned,
+		Muted:  req.Muted,
+	}
+	if req.FolderID != nil {
+		if _, err := s.findOwnFolder(ctx, userID, *req.FolderID); err != nil {
+			return nil, err
+		}
+		filter.FolderID = req.FolderID
+	}
+	conversations, err := s.conversationRepo.FindByUserID(ctx, userID, filter, limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversations: %w", err)
+	}
+
+	return &dto.GetConversationsResponse{
+		Conversations: s.toConversationDTOs(ctx, userID, conversations),
+	}, nil
+}
+
+// GetMessageRequests gets the direct chats waiting in a user's message requests
+func (s *service) GetMessageRequests(ctx context.Context, userID uuid.UUID, req *dto.GetConversationsRequest) (*dto.GetConversationsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	conversations, err := s.conversationRepo.FindRequestsByUserID(ctx, userID, limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message requests: %w", err)
+	}
+
+	return &dto.GetConversationsResponse{
+		Conversations: s.toConversationDTOs(ctx, userID, conversations),
+	}, nil
+}
+
+// toConversationDTOs maps conversations to DTOs with participants, last message and unread counts for a user
+func (s *service) toConversationDTOs(ctx context.Context, userID uuid.UUID, conversations []*conversation.Conversation) []dto.ConversationDTO {
+	// Unread mentions for all conversations in one query
+	mentionCounts, err := s.messageRepo.CountUnreadMentions(ctx, userID)
+	if err != nil {
+		logger.Warn("Failed to count unread mentions", zap.Error(err))
+		mentionCounts = map[uuid.UUID]int64{}
+	}
+
+	// Folders of all conversations in one query
+	conversationIDs := make([]uuid.UUID, len(conversations))
+	for i, conv := range conversations {
+		conversationIDs[i] = conv.ID
+	}
+	folderIDs, err := s.conversationRepo.FindFolderIDs(ctx, userID, conversationIDs)
+	if err != nil {
+		logger.Warn("Failed to get conversation folders", zap.Error(err))
+		folderIDs = map[uuid.UUID][]uuid.UUID{}
+	}
+
+	// Cached unread counts, the missing ones are counted below
+	unreadCounts := map[uuid.UUID]int{}
+	if s.unreadCounter != nil {
+		if cached, err := s.unreadCounter.GetUnreadCounts(ctx, userID, conversationIDs); err == nil {
+			unreadCounts = cached
+		} else {
+			logger.Warn("Failed to get cached unread counts", zap.Error(err))
+		}
+	}
+
+	// Map to DTOs
+	conversationDTOs := make([]dto.ConversationDTO, len(conversations))
+	for i, conv := range conversations {
+		// Get participants
+		participants, err := s.conversationRepo.FindParticipants(ctx, conv.ID)
+		if err != nil {
+			continue
+		}
+
+		// Get participant users
+		participantDTOs := make([]dto.UserDTO, 0, len(participants))
+		for _, p := range participants {
+			u, err := s.userRepo.FindByID(ctx, p.UserID)
+			if err != nil {
+				continue
+			}
+			participantDTOs = append(participantDTOs, dto.UserDTO{
+				ID:            u.ID.String(),
+				WalletAddress: u.WalletAddress,
+				Username:      u.Username,
+				Avatar:        u.Avatar,
+				Status:        string(u.Status),
+				IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
+			})
+		}
+
+		// Get last message if exists
+		var lastMessageDTO *dto.MessageDTO
+		if conv.LastMessageID != nil {
+			lastMsg, err := s.messageRepo.FindByID(ctx, *conv.LastMessageID)
+			if err == nil {
+				sender, _ := s.userRepo.FindByID(ctx, lastMsg.SenderID)
+				msgDTO := toMessageDTO(lastMsg, sender, nil)
+				lastMessageDTO = &msgDTO
+			}
+		}
+
+		var self *conversation.Participant
+		for _, p := range participants {
+			if p.UserID == userID {
+				self = p
+			}
+		}
+
+		// Count unread messages from the read marker unless the count is cached
+		unreadCount, cached := unreadCounts[conv.ID]
+		if !cached && self != nil {
+			unreadCount = s.countUnread(ctx, self)
+		}
+
+		isRequest, requestPending := requestState(participants, userID)
+		conversationDTOs[i] = dto.ConversationDTO{
+			ID:                 conv.ID.String(),
+			Type:               string(conv.Type),
+			Participants:       participantDTOs,
+			LastMessage:        lastMessageDTO,
+			UnreadCount:        unreadCount,
+			UnreadMentionCount: int(mentionCounts[conv.ID]),
+			IsRequest:          isRequest,
+			RequestPending:     requestPending,
+			FolderIDs:          uuidStrings(folderIDs[conv.ID]),
+			CreatedAt:          conv.CreatedAt,
+			UpdatedAt:          conv.UpdatedAt,
+		}
+		if self != nil {
+			if self.LastReadMessageID != nil {
+				lastReadMessageID := self.LastReadMessageID.String()
+				conversationDTOs[i].LastReadMessageID = &lastReadMessageID
+			}
+			conversationDTOs[i].IsArchived = self.ArchivedAt != nil
+			conversationDTOs[i].IsPinned = self.IsPinned()
+			conversationDTOs[i].IsMuted = self.IsMuted()
+			if self.IsMuted() && self.MutedUntil.Before(conversation.MuteForever) {
+				conversationDTOs[i].MutedUntil = self.MutedUntil
+			}
+		}
+	}
+
+	return conversationDTOs
+}
+
+// uuidStrings formats IDs as strings, nil when there are none
+func uuidStrings(ids []uuid.UUID) []string {
+	if len(ids) == 0 {
+		return nil
+	}
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = id.String()
+	}
+	return result
+}
+
+// requestState reports if a conversation sits in the user's message requests,
+// and if it is a message request either side is still waiting on
+func requestState(participants []*conversation.Participant, userID uuid.UUID) (isRequest, pending bool) {
+	p := conversation.FindPendingRequest(participants)
+	if p == nil {
+		return false, false
+	}
+	return p.UserID == userID, true
+}
+
+// CreateConversation creates a new conversation
+func (s *service) CreateConversation(ctx context.Context, userID uuid.UUID, req *dto.CreateConversationRequest) (*dto.CreateConversationResponse, error) {
+	// Resolve participant_id (can be UUID or wallet address)
+	participantID, err := s.resolveParticipantID(ctx, req.ParticipantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve participant: %w", err)
+	}
+
+	// Check if direct conversation already exists
+	if req.Type == "direct" {
+		existingConv, err := s.conversationRepo.FindDirectConversation(ctx, userID, participantID)
+		if err == nil {
+			// Conversation already exists, return it
+			participants, _ := s.conversationRepo.FindParticipants(ctx, existingConv.ID)
+			participantDTOs := make([]dto.UserDTO, 0, len(participants))
+			for _, p := range participants {
+				u, err := s.userRepo.FindByID(ctx, p.UserID)
+				if err != nil {
+					continue
+				}
+				participantDTOs = append(participantDTOs, dto.UserDTO{
+					ID:            u.ID.String(),
+					WalletAddress: u.WalletAddress,
+					Username:      u.Username,
+					Avatar:        u.Avatar,
+					Status:        string(u.Status),
+					IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
+				})
+			}
+
+			isRequest, requestPending := requestState(participants, userID)
+			return &dto.CreateConversationResponse{
+				Conversation: dto.ConversationDTO{
+					ID:             existingConv.ID.String(),
+					Type:           string(existingConv.Type),
+					Participants:   participantDTOs,
+					UnreadCount:    0,
+					IsRequest:      isRequest,
+					RequestPending: requestPending,
+					CreatedAt:      existingConv.CreatedAt,
+					UpdatedAt:      existingConv.UpdatedAt,
+				},
+			}, nil
+		}
+	}
+
+	// Create new conversation
+	conv, err := s.createDirectConversation(ctx, userID, participantID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get participants for response
+	participants, _ := s.conversationRepo.FindParticipants(ctx, conv.ID)
+	participantDTOs := make([]dto.UserDTO, 0, len(participants))
+	for _, p := range participants {
+		u, err := s.userRepo.FindByID(ctx, p.UserID)
+		if err != nil {
+			continue
+		}
+		participantDTOs = append(participantDTOs, dto.UserDTO{
+			ID:            u.ID.String(),
+			WalletAddress: u.WalletAddress,
+			Username:      u.Username,
+			Avatar:        u.Avatar,
+			Status:        string(u.Status),
+			IsOnline:      p.IsOnline, // Get online status from participant (checked via Hub)
+		})
+	}
+
+	_, requestPending := requestState(participants, userID)
+	return &dto.CreateConversationResponse{
+		Conversation: dto.ConversationDTO{
+			ID:             conv.ID.String(),
+			Type:           string(conv.Type),
+			Participants:   participantDTOs,
+			UnreadCount:    0,
+			RequestPending: requestPending,
+			CreatedAt:      conv.CreatedAt,
+			UpdatedAt:      conv.UpdatedAt,
+		},
+	}, nil
+}
+
+// GetOrCreateDirectConversation gets or creates a direct conversation
+func (s *service) GetOrCreateDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (uuid.UUID, error) {
+	// Try to find existing conversation
+	existingConv, err := s.conversationRepo.FindDirectConversation(ctx, user1ID, user2ID)
+	if err == nil {
+		return existingConv.ID, nil
+	}
+
+	if err != conversation.ErrConversationNotFound {
+		return uuid.Nil, fmt.Errorf("failed to find conversation: %w", err)
+	}
+
+	// Create new conversation
+	conv, err := s.createDirectConversation(ctx, user1ID, user2ID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return conv.ID, nil
+}
+
+// createDirectConversation starts a direct conversation from initiatorID to recipientID.
+// It lands in the recipient's message requests if they only accept chats from their contacts
+func (s *service) createDirectConversation(ctx context.Context, initiatorID, recipientID uuid.UUID) (*conversation.Conversation, error) {
+	if err := s.checkNotBlocked(ctx, initiatorID, recipientID); err != nil {
+		return nil, err
+	}
+
+	isRequest, err := s.needsMessageRequest(ctx, initiatorID, recipientID)
+	if err != nil {
+		return nil, err
+	}
+
+	conv := conversation.NewDirectConversation()
+	if err := s.conversationRepo.Create(ctx, conv); err != nil {
+		return nil, fmt.Errorf("failed to create conversation: %w", err)
+	}
+
+	// Add participants
+	initiator := conversation.NewParticipant(conv.ID, initiatorID, conversation.RoleMember)
+	if err := s.conversationRepo.AddParticipant(ctx, initiator); err != nil {
+		return nil, fmt.Errorf("failed to add initiator: %w", err)
+	}
+
+	recipient := conversation.NewParticipant(conv.ID, recipientID, conversation.RoleMember)
+	if isRequest {
+		recipient.RequestedAt = &recipient.JoinedAt
+	}
+	if err := s.conversationRepo.AddParticipant(ctx, recipient); err != nil {
+		return nil, fmt.Errorf("failed to add recipient: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewConversationChange(conv.ID, changelog.KindMembershipUpdated))
+
+	return conv, nil
+}
+
+// needsMessageRequest checks if a new direct chat has to wait in the recipient's message requests.
+// It returns privacy.ErrMessagingRestricted if the recipient does not accept new chats at all
+func (s *service) needsMessageRequest(ctx context.Context, initiatorID, recipientID uuid.UUID) (bool, error) {
+	switch s.privacySettings(ctx, recipientID).WhoCanMessage {
+	case privacy.VisibilityEveryone, "": // Empty for settings cached before the option existed
+		return false, nil
+	case privacy.VisibilityNobody:
+		return false, privacy.ErrMessagingRestricted
+	}
+
+	// Contacts only: chats from anyone the recipient has not saved are requests
+	if s.contactRepo == nil {
+		return true, nil
+	}
+	isContact, err := s.contactRepo.IsContact(ctx, recipientID, initiatorID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check contact: %w", err)
+	}
+	return !isContact, nil
+}
+
+// acceptRequestOnReply accepts a pending message request when its recipient replies
+func (s *service) acceptRequestOnReply(ctx context.Context, conversationID, senderID uuid.UUID) {
+	err := s.conversationRepo.AcceptRequest(ctx, conversationID, senderID)
+	if err == nil {
+		s.recordChange(ctx, changelog.NewConversationChange(conversationID, changelog.KindConversationUpdated))
+		return
+	}
+	if !errors.Is(err, conversation.ErrMessageRequestNotFound) {
+		logger.Warn("Failed to accept message request on reply",
+			zap.String("conversation_id", conversationID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// MarkAsRead moves the user's read marker up to a message, or the latest one, and records read receipts up to it.
+// The marker only moves forward, reading an older message again changes nothing
+func (s *service) MarkAsRead(ctx context.Context, userID uuid.UUID, req *dto.MarkAsReadRequest) (*dto.ReadStateDTO, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, req.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("conversation not found: %w", err)
+	}
+
+	participant, err := s.conversationRepo.FindParticipant(ctx, req.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *message.Message
+	if req.MessageID != nil {
+		target, err = s.messageRepo.FindByID(ctx, *req.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		if target.ConversationID != conv.ID {
+			return nil, message.ErrMessageNotFound
+		}
+	} else {
+		latest, err := s.messageRepo.FindByConversationID(ctx, conv.ID, 1, 0, &userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages: %w", err)
+		}
+		if len(latest) == 0 {
+			// Nothing to read yet
+			return s.readState(ctx, participant, true), nil
+		}
+		target = latest[0]
+	}
+
+	if err := s.readUpTo(ctx, conv, participant, target); err != nil {
+		return nil, err
+	}
+	s.recordOwnChange(ctx, userID, conv.ID)
+
+	// The cached count is from before the marker moved, drop it so the recount below replaces it
+	s.resetUnreadCount(ctx, conv.ID, userID)
+	state := s.readState(ctx, participant, false)
+	s.publishReadState(ctx, userID, state)
+
+	return state, nil
+}
+
+// MarkAsUnread moves the user's read marker back to right before a message, or the latest message from others.
+// Read receipts others already saw are kept
+func (s *service) MarkAsUnread(ctx context.Context, userID uuid.UUID, req *dto.MarkAsUnreadRequest) (*dto.ReadStateDTO, error) {
+	participant, err := s.conversationRepo.FindParticipant(ctx, req.ConversationID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var target *message.Message
+	if req.MessageID != nil {
+		target, err = s.messageRepo.FindByID(ctx, *req.MessageID)
+		if err != nil {
+			return nil, err
+		}
+		if target.ConversationID != req.ConversationID {
+			return nil, message.ErrMessageNotFound
+		}
+	} else {
+		recent, err := s.messageRepo.FindByConversationID(ctx, req.ConversationID, unreadLookback, 0, &userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get messages: %w", err)
+		}
+		for _, msg := range recent {
+			if msg.SenderID != userID && !msg.IsDeleted() {
+				target = msg
+				break
+			}
+		}
+		if target == nil {
+			return nil, message.ErrMessageNotFound
+		}
+	}
+
+	// The marker goes on the message before the first unread one
+	var readMessageID *uuid.UUID
+	var readUpTo *time.Time
+	previous, err := s.messageRepo.FindBefore(ctx, req.ConversationID, message.Cursor{CreatedAt: target.CreatedAt, ID: target.ID}, 1, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages: %w", err)
+	}
+	if len(previous) > 0 {
+		readMessageID = &previous[0].ID
+		readUpTo = &previous[0].CreatedAt
+	}
+
+	if err := s.conversationRepo.SetReadMarker(ctx, req.ConversationID, userID, readMessageID, readUpTo); err != nil {
+		return nil, err
+	}
+	participant.LastReadMessageID = readMessageID
+	participant.LastReadAt = readUpTo
+	s.recordOwnChange(ctx, userID, req.ConversationID)
+
+	s.resetUnreadCount(ctx, req.ConversationID, userID)
+	state := s.readState(ctx, participant, false)
+	s.publishReadState(ctx, userID, state)
+
+	logger.Info("Conversation marked unread",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", req.ConversationID.String()),
+		zap.String("message_id", target.ID.String()))
+
+	return state, nil
+}
+
+// unreadLookback is how many recent messages are searched for one from others when marking a conversation unread
+const unreadLookback = 50
+
+// readUpTo moves a participant's read marker forward to a message and records read receipts and mentions up to it.
+// Direct messages carry their read status themselves, group statuses follow from the receipts
+func (s *service) readUpTo(ctx context.Context, conv *conversation.Conversation, participant *conversation.Participant, target *message.Message) error {
+	userID := participant.UserID
+	if !target.CreatedAt.After(participant.ReadUpTo()) {
+		return nil
+	}
+
+	// Record a read receipt for this user on everything they have not read yet.
+	// With read receipts off the reads only clear the user's unread count
+	receiptsEnabled := s.readReceiptsShared(ctx, conv, userID)
+	if err := s.messageRepo.MarkConversationRead(ctx, conv.ID, userID, target.CreatedAt, time.Now(), !receiptsEnabled); err != nil {
+		return fmt.Errorf("failed to record read receipts: %w", err)
+	}
+
+	if conv.Type == conversation.TypeDirect {
+		readStatus := message.StatusRead
+		if !receiptsEnabled {
+			readStatus = message.StatusDelivered
+		}
+
+		// Get all messages in conversation (isPinned will only be true for messages pinned by this user)
+		messages, err := s.messageRepo.FindByConversationID(ctx, conv.ID, 1000, 0, &userID)
+		if err != nil {
+			return fmt.Errorf("failed to get messages: %w", err)
+		}
+
+		// Update status to "read" for the messages from other users up to the marker
+		for _, msg := range messages {
+			if msg.CreatedAt.After(target.CreatedAt) {
+				continue
+			}
+			if msg.SenderID != userID && msg.Status != message.StatusRead && msg.Status != readStatus {
+				if err := s.messageRepo.UpdateStatus(ctx, msg.ID, readStatus); err != nil {
+					logger.Error("Failed to update message status", zap.Error(err), zap.String("message_id", msg.ID.String()))
+				}
+			}
+		}
+	} else {
+		s.syncGroupReceiptStatus(ctx, conv, nil)
+	}
+
+	advanced, err := s.conversationRepo.AdvanceReadMarker(ctx, conv.ID, userID, target.ID, target.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update read marker: %w", err)
+	}
+	if advanced {
+		participant.LastReadMessageID = &target.ID
+		participant.LastReadAt = &target.CreatedAt
+	}
+
+	// Reading a conversation clears its mentions
+	if err := s.messageRepo.MarkMentionsRead(ctx, userID, conv.ID, target.CreatedAt); err != nil {
+		logger.Warn("Failed to mark mentions as read", zap.Error(err))
+	}
+
+	return nil
+}
+
+// readState returns a participant's read marker with fresh unread counts.
+// Unless cachedCount is set the unread count is recounted and stored in the cache
+func (s *service) readState(ctx context.Context, participant *conversation.Participant, cachedCount bool) *dto.ReadStateDTO {
+	state := &dto.ReadStateDTO{
+		ConversationID: participant.ConversationID.String(),
+		LastReadAt:     participant.LastReadAt,
+	}
+	if participant.LastReadMessageID != nil {
+		lastReadMessageID := participant.LastReadMessageID.String()
+		state.LastReadMessageID = &lastReadMessageID
+	}
+
+	if cachedCount && s.unreadCounter != nil {
+		counts, err := s.unreadCounter.GetUnreadCounts(ctx, participant.UserID, []uuid.UUID{participant.ConversationID})
+		if count, ok := counts[participant.ConversationID]; err == nil && ok {
+			state.UnreadCount = count
+		} else {
+			state.UnreadCount = s.countUnread(ctx, participant)
+		}
+	} else {
+		state.UnreadCount = s.countUnread(ctx, participant)
+	}
+
+	mentionCounts, err := s.messageRepo.CountUnreadMentions(ctx, participant.UserID)
+	if err != nil {
+		logger.Warn("Failed to count unread mentions", zap.Error(err))
+	}
+	state.UnreadMentionCount = int(mentionCounts[participant.ConversationID])
+
+	return state
+}
+
+// countUnread counts a participant's unread messages from their read marker and caches the count
+func (s *service) countUnread(ctx context.Context, participant *conversation.Participant) int {
+	count, err := s.messageRepo.CountUnreadByConversationID(ctx, participant.ConversationID, participant.UserID, participant.ReadUpTo())
+	if err != nil {
+		logger.Warn("Failed to count unread messages",
+			zap.String("conversation_id", participant.ConversationID.String()),
+			zap.Error(err))
+		return 0
+	}
+
+	if s.unreadCounter != nil {
+		// Only fills a missing count, one cached meanwhile may already hold increments this count missed
+		if err := s.unreadCounter.SetUnreadCountIfAbsent(ctx, participant.ConversationID, participant.UserID, int(count)); err != nil {
+			logger.Warn("Failed to cache unread count", zap.Error(err))
+		}
+	}
+
+	return int(count)
+}
+
+// incrementUnreadCounts bumps the cached unread counts of everyone in a conversation but the sender of a new message
+func (s *service) incrementUnreadCounts(ctx context.Context, conversationID, senderID uuid.UUID) {
+	if s.unreadCounter == nil {
+		return
+	}
+
+	participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
+	if err != nil {
+		// Stale counts would stick around, so drop them to have them recounted
+		logger.Warn("Failed to find participants for unread counts", zap.Error(err))
+		s.resetUnreadCounts(ctx, conversationID)
+		return
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(participants))
+	for _, p := range participants {
+		if p.UserID != senderID {
+			userIDs = append(userIDs, p.UserID)
+		}
+	}
+
+	if err := s.unreadCounter.IncrementUnreadCounts(ctx, conversationID, userIDs); err != nil {
+		logger.Warn("Failed to increment unread counts", zap.Error(err))
+		s.resetUnreadCounts(ctx, conversationID)
+	}
+}
+
+// resetUnreadCount drops a user's cached unread count of a conversation, it is recounted on the next read
+func (s *service) resetUnreadCount(ctx context.Context, conversationID, userID uuid.UUID) {
+	if s.unreadCounter == nil {
+		return
+	}
+	if err := s.unreadCounter.ResetUnreadCount(ctx, conversationID, userID); err != nil {
+		logger.Warn("Failed to reset unread count",
+			zap.String("conversation_id", conversationID.String()),
+			zap.Error(err))
+	}
+}
+
+// resetUnreadCounts drops the cached unread counts of a conversation, they are recounted on the next read
+func (s *service) resetUnreadCounts(ctx context.Context, conversationID uuid.UUID) {
+	if s.unreadCounter == nil {
+		return
+	}
+	if err := s.unreadCounter.ResetConversationUnreadCounts(ctx, conversationID); err != nil {
+		logger.Warn("Failed to reset unread counts",
+			zap.String("conversation_id", conversationID.String()),
+			zap.Error(err))
+	}
+}
+
+// publishReadState tells the user's devices that their read marker in a conversation moved
+func (s *service) publishReadState(ctx context.Context, userID uuid.UUID, state *dto.ReadStateDTO) {
+	if s.wsBroadcaster == nil {
+		return
+	}
+	if err := s.wsBroadcaster.BroadcastReadState(ctx, userID, *state); err != nil {
+		logger.Error("Failed to broadcast read state",
+			zap.String("conversation_id", state.ConversationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// DeleteMessage deletes a message for the user only or for everyone in the conversation
+func (s *service) DeleteMessage(ctx context.Context, userID, messageID uuid.UUID, scope message.DeleteScope) error {
+	// Get message
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("message not found: %w", err)
+	}
+
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return conversation.ErrNotParticipant
+	}
+
+	switch scope {
+	case message.DeleteForMe:
+		if err := s.messageRepo.HideForUser(ctx, messageID, userID); err != nil {
+			return fmt.Errorf("failed to hide message: %w", err)
+		}
+		s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, messageID, changelog.KindMessageDeleted).ForUser(userID))
+
+		s.resetUnreadCount(ctx, msg.ConversationID, userID)
+
+		// Let the user's other devices drop the message too
+		if s.wsBroadcaster != nil {
+			if err := s.wsBroadcaster.BroadcastMessageHidden(ctx, userID, msg.ConversationID, messageID.String()); err != nil {
+				logger.Error("Failed to broadcast message hidden",
+					zap.String("message_id", messageID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+
+	case message.DeleteForEveryone:
+		if msg.IsDeleted() {
+			return message.ErrMessageDeleted
+		}
+
+		// Senders can delete their own messages, moderators anyone's
+		if msg.SenderID != userID {
+			canDelete, err := s.canDeleteOthers(ctx, msg.ConversationID, userID)
+			if err != nil {
+				return err
+			}
+			if !canDelete {
+				return message.ErrUnauthorized
+			}
+		}
+
+		msg.Tombstone(userID)
+		if err := s.messageRepo.MarkDeleted(ctx, msg); err != nil {
+			return fmt.Errorf("failed to delete message: %w", err)
+		}
+
+		logger.Info("Message deleted for everyone",
+			zap.String("message_id", messageID.String()),
+			zap.String("conversation_id", msg.ConversationID.String()),
+			zap.String("deleted_by", userID.String()),
+		)
+		s.resetUnreadCounts(ctx, msg.ConversationID)
+		s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, messageID, changelog.KindMessageDeleted))
+
+		if s.wsBroadcaster != nil {
+			if err := s.wsBroadcaster.BroadcastMessageDeleted(ctx, msg.ConversationID, messageID.String(), userID.String()); err != nil {
+				logger.Error("Failed to broadcast message deleted",
+					zap.String("message_id", messageID.String()),
+					zap.Error(err),
+				)
+			}
+		}
+
+	default:
+		return message.ErrInvalidDeleteScope
+	}
+
+	return nil
+}
+
+// canDeleteOthers checks if a user may delete other participants' messages:
+// group admins and moderators, channel owners and channel admins allowed to delete
+func (s *service) canDeleteOthers(ctx context.Context, conversationID, userID uuid.UUID) (bool, error) {
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		return false, fmt.Errorf("conversation not found: %w", err)
+	}
+
+	switch conv.Type {
+	case conversation.TypeGroup:
+		if s.groupRepo == nil {
+			return false, nil
+		}
+		grp, err := s.groupRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to find group: %w", err)
+		}
+		member, err := s.groupRepo.FindMember(ctx, grp.ID, userID)
+		if err != nil {
+			if err == group.ErrMemberNotFound {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to find group member: %w", err)
+		}
+		return member.CanDeleteMessages(), nil
+
+	case conversation.TypeChannel:
+		if s.channelRepo == nil {
+			return false, nil
+		}
+		ch, err := s.channelRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return false, fmt.Errorf("failed to find channel: %w", err)
+		}
+		if ch.OwnerID == userID {
+			return true, nil
+		}
+		admin, err := s.channelRepo.FindAdmin(ctx, ch.ID, userID)
+		if err != nil {
+			if err == channel.ErrAdminNotFound {
+				return false, nil
+			}
+			return false, fmt.Errorf("failed to find channel admin: %w", err)
+		}
+		return admin.CanDelete(), nil
+	}
+
+	return false, nil
+}
+
+// EditMessage edits the content of a message
+func (s *service) EditMessage(ctx context.Context, userID, messageID uuid.UUID, newContent string) (*dto.MessageDTO, error) {
+	// Get message
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	// Check if user is the sender
+	if msg.SenderID != userID {
+		return nil, message.ErrUnauthorized
+	}
+	if msg.IsDeleted() {
+		return nil, message.ErrMessageDeleted
+	}
+	if msg.ContentType == message.ContentTypePoll {
+		return nil, message.ErrPollUnsupported
+	}
+
+	// Enforce the group/channel edit window
+	window, err := s.editWindow(ctx, msg.ConversationID)
+	if err != nil {
+		return nil, err
+	}
+	if !msg.CanEdit(window) {
+		return nil, message.ErrEditWindowExpired
+	}
+
+	// Update message content, keeping the previous revision
+	previous := msg.Edit(userID, newContent)
+
+	// Save updated message
+	if err := s.messageRepo.SaveEdit(ctx, msg, previous); err != nil {
+		return nil, fmt.Errorf("failed to update message: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, msg.ID, changelog.KindMessageUpdated))
+
+	// Get sender for DTO
+	sender, err := s.userRepo.FindByID(ctx, msg.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("sender not found: %w", err)
+	}
+
+	// Convert to DTO
+	messageDTO := toMessageDTO(msg, sender, nil)
+
+	// Re-sync @mentions, only notifying users who were newly mentioned
+	s.notifyMentions(messageDTO, s.syncMentions(ctx, msg))
+
+	// Broadcast message updated via WebSocket
+	if s.wsBroadcaster != nil {
+		if err := s.wsBroadcaster.BroadcastMessageUpdated(
+			ctx,
+			msg.ConversationID,
+			messageDTO,
+		); err != nil {
+			logger.Error("Failed to broadcast message updated",
+				zap.String("message_id", messageID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	// Previews of the old content were dropped, generate them for the new content
+	s.generateLinkPreviews(msg, messageDTO)
+
+	return &messageDTO, nil
+}
+
+// GetMessageByID gets a message by ID
+func (s *service) GetMessageByID(ctx context.Context, messageID uuid.UUID) (*dto.MessageDTO, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	s.attachThreadSummaries(ctx, []*message.Message{msg})
+	s.attachPolls(ctx, nil, []*message.Message{msg})
+
+	// Get sender
+	sender, err := s.userRepo.FindByID(ctx, msg.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("sender not found: %w", err)
+	}
+
+	messageDTO := toMessageDTO(msg, sender, nil)
+	return &messageDTO, nil
+}
+
+// GetConversationParticipants gets participant IDs for a conversation
+func (s *service) GetConversationParticipants(ctx context.Context, conversationID uuid.UUID) ([]uuid.UUID, error) {
+	participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	participantIDs := make([]uuid.UUID, len(participants))
+	for i, p := range participants {
+		participantIDs[i] = p.UserID
+	}
+
+	return participantIDs, nil
+}
+
+// RecordReceipt records that a recipient received or read a message (status "delivered" or "read")
+// and returns the users who should be told about it: the sender, and for reads also the reader, whose other
+// devices follow along; none when there is nothing to share.
+// Direct messages have one recipient, so the receipt is also the message status; in groups the
+// status only advances once every other member has caught up.
+// Users with read receipts off still clear their unread count, but neither send nor receive read receipts
+func (s *service) RecordReceipt(ctx context.Context, userID, conversationID, messageID uuid.UUID, status string) ([]uuid.UUID, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+	if msg.ConversationID != conversationID {
+		return nil, message.ErrMessageNotFound
+	}
+	if msg.SenderID == userID {
+		return nil, nil
+	}
+
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	conv, err := s.conversationRepo.FindByID(ctx, msg.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("conversation not found: %w", err)
+	}
+
+	now := time.Now()
+	var newStatus message.Status
+	receiptsEnabled := true
+	switch status {
+	case "delivered":
+		newStatus = message.StatusDelivered
+		err = s.messageRepo.MarkReceiptDelivered(ctx, messageID, userID, now)
+	case "read":
+		newStatus = message.StatusRead
+		receiptsEnabled = s.readReceiptsShared(ctx, conv, userID)
+		if !receiptsEnabled {
+			newStatus = message.StatusDelivered
+		}
+		err = s.messageRepo.MarkReceiptRead(ctx, messageID, userID, now, !receiptsEnabled)
+	default:
+		return nil, fmt.Errorf("invalid status: %s", status)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record receipt: %w", err)
+	}
+
+	if conv.Type != conversation.TypeDirect {
+		s.syncGroupReceiptStatus(ctx, conv, &msg.ID)
+	} else if msg.Status != message.StatusRead && msg.Status != newStatus {
+		// A late delivery receipt must not move a read message back to delivered
+		if err := s.messageRepo.UpdateStatus(ctx, msg.ID, newStatus); err != nil {
+			return nil, err
+		}
+	}
+
+	if status == "read" {
+		s.advanceReadMarker(ctx, conv, userID, msg)
+
+		// Read receipts go to the sender and the reader's other devices, and not at all if either side turned them off
+		if !receiptsEnabled || !s.privacySettings(ctx, msg.SenderID).ReadReceiptsEnabled {
+			return nil, nil
+		}
+		return []uuid.UUID{msg.SenderID, userID}, nil
+	}
+
+	// Delivery receipts only concern the sender, so a channel post does not fan out to every subscriber
+	return []uuid.UUID{msg.SenderID}, nil
+}
+
+// advanceReadMarker moves the reader's marker up to a message read over WebSocket, so their unread count follows
+func (s *service) advanceReadMarker(ctx context.Context, conv *conversation.Conversation, userID uuid.UUID, msg *message.Message) {
+	participant, err := s.conversationRepo.FindParticipant(ctx, conv.ID, userID)
+	if err != nil {
+		logger.Warn("Failed to find participant for read marker", zap.Error(err))
+		return
+	}
+	if !msg.CreatedAt.After(participant.ReadUpTo()) {
+		return
+	}
+
+	if err := s.readUpTo(ctx, conv, participant, msg); err != nil {
+		logger.Warn("Failed to advance read marker", zap.String("message_id", msg.ID.String()), zap.Error(err))
+		return
+	}
+	s.recordOwnChange(ctx, userID, conv.ID)
+	s.publishReadState(ctx, userID, s.readState(ctx, participant, false))
+}
+
+// syncGroupReceiptStatus advances group message statuses once every other member has received or read them.
+// Channel messages keep their sent status, their receipts are only exposed as counts
+func (s *service) syncGroupReceiptStatus(ctx context.Context, conv *conversation.Conversation, messageID *uuid.UUID) {
+	if conv.Type != conversation.TypeGroup {
+		return
+	}
+
+	participants, err := s.conversationRepo.FindParticipants(ctx, conv.ID)
+	if err != nil {
+		logger.Warn("Failed to get participants for receipts", zap.String("conversation_id", conv.ID.String()), zap.Error(err))
+		return
+	}
+
+	if err := s.messageRepo.SyncReceiptStatus(ctx, conv.ID, messageID, len(participants)-1); err != nil {
+		logger.Warn("Failed to sync message status from receipts", zap.String("conversation_id", conv.ID.String()), zap.Error(err))
+	}
+}
+
+// attachReceiptCounts loads delivered and read counts for the messages sent by the user,
+// hiding reads from users who turned read receipts off
+func (s *service) attachReceiptCounts(ctx context.Context, userID uuid.UUID, messages []*message.Message) {
+	ownIDs := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		if msg.SenderID == userID {
+			ownIDs = append(ownIDs, msg.ID)
+		}
+	}
+	if len(ownIDs) == 0 {
+		return
+	}
+
+	// Users who turned read receipts off do not see other people's reads either
+	receiptsEnabled := s.privacySettings(ctx, userID).ReadReceiptsEnabled
+
+	counts, err := s.messageRepo.GetReceiptCounts(ctx, ownIDs)
+	if err != nil {
+		logger.Warn("Failed to get receipt counts", zap.Error(err))
+	}
+
+	for _, msg := range messages {
+		if msg.SenderID != userID {
+			continue
+		}
+		if !receiptsEnabled && msg.Status == message.StatusRead {
+			msg.Status = message.StatusDelivered
+		}
+		if err != nil {
+			continue
+		}
+		receipts := counts[msg.ID]
+		if !receiptsEnabled {
+			receipts.Read = 0
+		}
+		msg.Receipts = &receipts
+	}
+}
+
+// GetMessageReceipts gets who received and read a message. Only the sender can see them,
+// and reads are left out for recipients or senders with read receipts turned off
+func (s *service) GetMessageReceipts(ctx context.Context, userID, messageID uuid.UUID) (*dto.GetMessageReceiptsResponse, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+	if msg.SenderID != userID {
+		return nil, message.ErrUnauthorized
+	}
+
+	participants, err := s.conversationRepo.FindParticipants(ctx, msg.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get participants: %w", err)
+	}
+	recipients := 0
+	for _, p := range participants {
+		if p.UserID != userID {
+			recipients++
+		}
+	}
+
+	receipts, err := s.messageRepo.GetReceipts(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receipts: %w", err)
+	}
+
+	// Users who turned read receipts off do not see other people's reads either
+	showReads := s.privacySettings(ctx, userID).ReadReceiptsEnabled
+
+	resp := &dto.GetMessageReceiptsResponse{
+		MessageID:  msg.ID.String(),
+		Recipients: recipients,
+		Receipts:   make([]dto.MessageReceiptDTO, len(receipts)),
+	}
+	for i, receipt := range receipts {
+		receiptDTO := dto.MessageReceiptDTO{
+			UserID:      receipt.UserID.String(),
+			DeliveredAt: receipt.DeliveredAt,
+		}
+		if showReads && !receipt.ReadHidden {
+			receiptDTO.ReadAt = receipt.ReadAt
+		}
+		if u, err := s.userRepo.FindByID(ctx, receipt.UserID); err == nil {
+			receiptDTO.User = &dto.UserDTO{
+				ID:            u.ID.String(),
+				WalletAddress: u.WalletAddress,
+				Username:      u.Username,
+				Avatar:        u.Avatar,
+				Status:        string(u.Status),
+			}
+		}
+		if receipt.DeliveredAt != nil {
+			resp.Delivered++
+		}
+		if receiptDTO.ReadAt != nil {
+			resp.Read++
+		}
+		resp.Receipts[i] = receiptDTO
+	}
+
+	return resp, nil
+}
+
+// Helper function to map domain message to DTO
+func toThreadSummaryDTO(summary message.ThreadSummary) dto.ThreadSummaryDTO {
+	return dto.ThreadSummaryDTO{
+		ReplyCount:    summary.ReplyCount,
+		LastReplyID:   summary.LastReplyID.String(),
+		LastReplyAt:   summary.LastReplyAt,
+		LastReplierID: summary.LastReplierID.String(),
+	}
+}
+
+func toMessageDTO(msg *message.Message, sender *user.User, recipient *user.User) dto.MessageDTO {
+	var replyToID *string
+	if msg.ReplyToID != nil {
+		id := msg.ReplyToID.String()
+		replyToID = &id
+	}
+
+	msgDTO := dto.MessageDTO{
+		ID:              msg.ID.String(),
+		ConversationID:  msg.ConversationID.String(),
+		SenderID:        msg.SenderID.String(),
+		Content:         msg.Content,
+		ContentType:     string(msg.ContentType),
+		Signature:       msg.Signature,
+		ReplyToID:       replyToID,
+		Status:          string(msg.Status),
+		CreatedAt:       msg.CreatedAt,
+		UpdatedAt:       msg.UpdatedAt,
+		Reactions:       msg.Reactions,
+		IsPinned:        msg.IsPinned,
+		PinnedBy:        msg.PinnedBy,
+		ExpiresAt:       msg.ExpiresAt,
+		EditedAt:        msg.EditedAt,
+		EditCount:       msg.EditCount,
+		DeletedAt:       msg.DeletedAt,
+		ClientMessageID: msg.ClientMessageID,
+	}
+
+	if msg.ThreadID != nil {
+		threadID := msg.ThreadID.String()
+		msgDTO.ThreadID = &threadID
+	}
+	if msg.Thread != nil {
+		thread := toThreadSummaryDTO(*msg.Thread)
+		msgDTO.Thread = &thread
+	}
+	if msg.Receipts != nil {
+		msgDTO.Receipts = &dto.ReceiptCountsDTO{
+			Delivered: msg.Receipts.Delivered,
+			Read:      msg.Receipts.Read,
+		}
+	}
+	if msg.DeletedBy != nil {
+		deletedBy := msg.DeletedBy.String()
+		msgDTO.DeletedBy = &deletedBy
+	}
+	if len(msg.LinkPreviews) > 0 {
+		msgDTO.LinkPreviews = toLinkPreviewDTOs(msg.LinkPreviews)
+	}
+	if msg.Poll != nil {
+		poll := toPollDTO(msg.Poll)
+		msgDTO.Poll = &poll
+	}
+
+	if sender != nil {
+		msgDTO.Sender = &dto.UserDTO{
+			ID:            sender.ID.String(),
+			WalletAddress: sender.WalletAddress,
+			Username:      sender.Username,
+			Avatar:        sender.Avatar,
+			Status:        string(sender.Status),
+		}
+	}
+
+	return msgDTO
+}
+
+func toLinkPreviewDTOs(previews []message.LinkPreview) []dto.LinkPreviewDTO {
+	previewDTOs := make([]dto.LinkPreviewDTO, len(previews))
+	for i, preview := range previews {
+		previewDTOs[i] = dto.LinkPreviewDTO{
+			URL:         preview.URL,
+			Title:       preview.Title,
+			Description: preview.Description,
+			ImageURL:    preview.ImageURL,
+			SiteName:    preview.SiteName,
+		}
+	}
+	return previewDTOs
+}
+
+func toPollDTO(poll *message.Poll) dto.PollDTO {
+	options := make([]dto.PollOptionDTO, len(poll.Options))
+	for i, option := range poll.Options {
+		options[i] = dto.PollOptionDTO{
+			Position:  option.Position,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+		}
+		if !poll.Anonymous && len(option.Voters) > 0 {
+			voters := make([]string, len(option.Voters))
+			for j, voterID := range option.Voters {
+				voters[j] = voterID.String()
+			}
+			options[i].Voters = voters
+		}
+	}
+
+	pollDTO := dto.PollDTO{
+		MessageID:      poll.MessageID.String(),
+		Question:       poll.Question,
+		Options:        options,
+		MultipleChoice: poll.MultipleChoice,
+		Anonymous:      poll.Anonymous,
+		Quiz:           poll.Quiz,
+		ClosesAt:       poll.ClosesAt,
+		IsClosed:       poll.IsClosed(),
+		TotalVoters:    poll.TotalVoters,
+		Voted:          poll.Voted,
+	}
+	if poll.RevealsAnswer() {
+		pollDTO.CorrectOption = poll.CorrectOption
+	}
+
+	return pollDTO
+}
+
+// Message Reactions (Day 13)
+
+func (s *service) AddReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error {
+	// Get message to find conversation ID
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return err
+	}
+	if msg.IsDeleted() {
+		return message.ErrMessageDeleted
+	}
+
+	if err := s.messageRepo.AddReaction(ctx, messageID, userID, emoji); err != nil {
+		return err
+	}
+	s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, messageID, changelog.KindReactionUpdated))
+
+	// Broadcast reaction added via WebSocket
+	if s.wsBroadcaster != nil {
+		if err := s.wsBroadcaster.BroadcastReactionAdded(
+			ctx,
+			msg.ConversationID,
+			messageID.String(),
+			userID.String(),
+			emoji,
+		); err != nil {
+			logger.Error("Failed to broadcast reaction added",
+				zap.String("message_id", messageID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) RemoveReaction(ctx context.Context, userID, messageID uuid.UUID, emoji string) error {
+	logger.Info("🗑️ RemoveReaction called",
+		zap.String("messageID", messageID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("emoji", emoji),
+	)
+
+	// Get message to find conversation ID
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		logger.Error("Failed to find message", zap.Error(err))
+		return err
+	}
+
+	logger.Info("📝 Message found, removing reaction from DB",
+		zap.String("conversationID", msg.ConversationID.String()),
+	)
+
+	if err := s.messageRepo.RemoveReaction(ctx, messageID, userID, emoji); err != nil {
+		logger.Error("Failed to remove reaction from DB", zap.Error(err))
+		return err
+	}
+
+	logger.Info("✅ Reaction removed from DB")
+	s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, messageID, changelog.KindReactionUpdated))
+
+	// Broadcast reaction removed via WebSocket
+	if s.wsBroadcaster != nil {
+		if err := s.wsBroadcaster.BroadcastReactionRemoved(
+			ctx,
+			msg.ConversationID,
+			messageID.String(),
+			userID.String(),
+			emoji,
+		); err != nil {
+			logger.Error("Failed to broadcast reaction removed",
+				zap.String("message_id", messageID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetMessageReactions(ctx context.Context, messageID uuid.UUID) (*dto.MessageReactionsResponse, error) {
+	reactions, err := s.messageRepo.GetMessageReactions(ctx, messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	reactionDTOs := make([]dto.ReactionResponse, len(reactions))
+	for i, r := range reactions {
+		reactionDTOs[i] = dto.ReactionResponse{
+			ID:        r.ID.String(),
+			MessageID: r.MessageID.String(),
+			UserID:    r.UserID.String(),
+			Emoji:     r.Emoji,
+			CreatedAt: r.CreatedAt,
+		}
+	}
+
+	return &dto.MessageReactionsResponse{
+		MessageID: messageID.String(),
+		Reactions: reactionDTOs,
+	}, nil
+}
+
+// Pinned Messages (Day 13)
+
+func (s *service) PinMessage(ctx context.Context, userID, conversationID, messageID uuid.UUID) error {
+	// Verify user is participant of conversation
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return conversation.ErrNotParticipant
+	}
+
+	// Verify message exists and belongs to conversation
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("message not found: %w", err)
+	}
+
+	if msg.ConversationID != conversationID {
+		return message.ErrMessageNotFound
+	}
+
+	if err := s.messageRepo.PinMessage(ctx, conversationID, messageID, userID); err != nil {
 		return fmt.Errorf("failed to pin message: %w", err)
 	}
+	s.recordChange(ctx, changelog.NewMessageChange(conversationID, messageID, changelog.KindPinUpdated))
+
+	// Broadcast message pinned via WebSocket
+	if s.wsBroadcaster != nil {
+		if err := s.wsBroadcaster.BroadcastMessagePinned(
+			ctx,
+			conversationID,
+			messageID.String(),
+			userID.String(),
+		); err != nil {
+			logger.Error("Failed to broadcast message pinned",
+				zap.String("message_id", messageID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) UnpinMessage(ctx context.Context, userID, conversationID, messageID uuid.UUID) error {
+	// Verify user is participant of conversation
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return conversation.ErrNotParticipant
+	}
+
+	// Verify message exists and belongs to conversation
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("message not found: %w", err)
+	}
+
+	if msg.ConversationID != conversationID {
+		return message.ErrMessageNotFound
+	}
+
+	if err := s.messageRepo.UnpinMessage(ctx, conversationID, messageID, userID); err != nil {
+		return fmt.Errorf("failed to unpin message: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewMessageChange(conversationID, messageID, changelog.KindPinUpdated))
+
+	// Broadcast message unpinned via WebSocket
+	if s.wsBroadcaster != nil {
+		if err := s.wsBroadcaster.BroadcastMessageUnpinned(
+			ctx,
+			conversationID,
+			messageID.String(),
+			userID.String(),
+		); err != nil {
+			logger.Error("Failed to broadcast message unpinned",
+				zap.String("message_id", messageID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (s *service) GetPinnedMessages(ctx context.Context, conversationID uuid.UUID) (*dto.PinnedMessagesResponse, error) {
+	pinned, err := s.messageRepo.GetPinnedMessages(ctx, conversationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedDTOs := make([]dto.PinnedMessageResponse, len(pinned))
+	for i, p := range pinned {
+		pinnedDTOs[i] = dto.PinnedMessageResponse{
+			ID:             p.ID.String(),
+			ConversationID: p.ConversationID.String(),
+			MessageID:      p.MessageID.String(),
+			PinnedBy:       p.PinnedBy.String(),
+			PinnedAt:       p.PinnedAt,
+		}
+	}
+
+	return &dto.PinnedMessagesResponse{
+		ConversationID: conversationID.String(),
+		PinnedMessages: pinnedDTOs,
+	}, nil
+}
+
+// Message Forwarding (Day 13)
+
+func (s *service) ForwardMessage(ctx context.Context, userID, messageID, targetConversationID uuid.UUID) (*dto.SendMessageResponse, error) {
+	// Get original message
+	originalMsg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("original message not found: %w", err)
+	}
+	if originalMsg.IsDeleted() {
+		return nil, message.ErrMessageDeleted
+	}
+	// Votes belong to the original poll, a copy would start without them
+	if originalMsg.ContentType == message.ContentTypePoll {
+		return nil, message.ErrPollUnsupported
+	}
+
+	// Verify user has access to the original message (is participant in source conversation)
+	isSourceParticipant, err := s.conversationRepo.IsParticipant(ctx, originalMsg.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check source participant: %w", err)
+	}
+	if !isSourceParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	// Verify user is participant of target conversation
+	isTargetParticipant, err := s.conversationRepo.IsParticipant(ctx, targetConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check target participant: %w", err)
+	}
+	if !isTargetParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	targetConv, err := s.conversationRepo.FindByID(ctx, targetConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find target conversation: %w", err)
+	}
+	if err := s.checkDirectNotBlocked(ctx, targetConv, userID); err != nil {
+		return nil, err
+	}
+
+	// Create new message with same content
+	newMsg := message.NewMessage(
+		targetConversationID,
+		userID,
+		originalMsg.Content,
+		originalMsg.ContentType,
+	)
+
+	// Mark as sent
+	newMsg.MarkAsSent()
+	s.applyDisappearingTimer(ctx, newMsg)
+
+	// Save new message
+	if err := s.messageRepo.Create(ctx, newMsg); err != nil {
+		return nil, fmt.Errorf("failed to create forwarded message: %w", err)
+	}
+
+	// Record forward relationship
+	if err := s.messageRepo.RecordForward(ctx, originalMsg.ID, newMsg.ID, userID, targetConversationID); err != nil {
+		logger.Warn("Failed to record forward", zap.Error(err))
+	}
+
+	// Update conversation's last message
+	conv, err := s.conversationRepo.FindByID(ctx, targetConversationID)
+	if err == nil {
+		conv.UpdateLastMessage(newMsg.ID)
+		if err := s.conversationRepo.Update(ctx, conv); err != nil {
+			logger.Warn("Failed to update conversation last message", zap.Error(err))
+		}
+	}
+	s.recordChange(ctx, changelog.NewMessageChange(targetConversationID, newMsg.ID, changelog.KindMessageNew))
+
+	// Get sender info for response
+	sender, _ := s.userRepo.FindByID(ctx, userID)
+	messageDTO := toMessageDTO(newMsg, sender, nil)
+
+	// Broadcast forwarded message via WebSocket
+	if s.wsBroadcaster != nil {
+		go func() {
+			if err := s.wsBroadcaster.BroadcastNewMessage(context.Background(), targetConversationID, messageDTO); err != nil {
+				logger.Error("Failed to broadcast forwarded message",
+					zap.String("message_id", newMsg.ID.String()),
+					zap.String("conversation_id", targetConversationID.String()),
+					zap.Error(err),
+				)
+			}
+		}()
+	}
+
+	s.incrementUnreadCounts(ctx, targetConv.ID, newMsg.SenderID)
+	s.generateLinkPreviews(newMsg, messageDTO)
+
+	logger.Info("Message forwarded",
+		zap.String("original_message_id", originalMsg.ID.String()),
+		zap.String("new_message_id", newMsg.ID.String()),
+		zap.String("user_id", userID.String()),
+		zap.String("target_conversation_id", targetConversationID.String()),
+	)
+
+	return &dto.SendMessageResponse{
+		Message: messageDTO,
+	}, nil
+}
+
+// Threads
+
+// GetThread gets the root message of a thread with its replies, oldest first
+func (s *service) GetThread(ctx context.Context, userID uuid.UUID, req *dto.GetThreadRequest) (*dto.GetThreadResponse, error) {
+	root, err := s.findThreadRoot(ctx, userID, req.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var after *message.Cursor
+	if req.After != "" {
+		cursor, err := s.resolveCursor(ctx, root.ConversationID, req.After)
+		if err != nil {
+			return nil, err
+		}
+		after = &cursor
+	}
+
+	replies, err := s.messageRepo.FindThreadReplies(ctx, root.ID, after, limit+1, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get thread replies: %w", err)
+	}
+
+	hasMore := len(replies) > limit
+	if hasMore {
+		replies = replies[:limit]
+	}
+
+	s.attachThreadSummaries(ctx, []*message.Message{root})
+	s.attachReceiptCounts(ctx, userID, append([]*message.Message{root}, replies...))
+	s.attachPolls(ctx, &userID, append([]*message.Message{root}, replies...))
+
+	isFollowing, err := s.messageRepo.IsFollowingThread(ctx, root.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check thread follow: %w", err)
+	}
+
+	userCache := make(map[uuid.UUID]*user.User)
+	senderOf := func(id uuid.UUID) *user.User {
+		sender, exists := userCache[id]
+		if !exists {
+			sender, _ = s.userRepo.FindByID(ctx, id)
+			userCache[id] = sender
+		}
+		return sender
+	}
+
+	replyDTOs := make([]dto.MessageDTO, len(replies))
+	for i, reply := range replies {
+		replyDTOs[i] = toMessageDTO(reply, senderOf(reply.SenderID), nil)
+	}
+
+	resp := &dto.GetThreadResponse{
+		Root:        toMessageDTO(root, senderOf(root.SenderID), nil),
+		Replies:     replyDTOs,
+		IsFollowing: isFollowing,
+		HasMore:     hasMore,
+	}
+	if len(replies) > 0 {
+		resp.NextCursor = message.CursorFor(replies[len(replies)-1]).Encode()
+	}
+
+	return resp, nil
+}
+
+// GetMessageRevisions gets the edit history of a message, oldest revision first
+func (s *service) GetMessageRevisions(ctx context.Context, userID, messageID uuid.UUID) (*dto.GetMessageRevisionsResponse, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	revisions, err := s.messageRepo.GetRevisions(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revisions: %w", err)
+	}
+
+	revisionDTOs := make([]dto.MessageRevisionDTO, len(revisions))
+	for i, rev := range revisions {
+		revisionDTOs[i] = dto.MessageRevisionDTO{
+			ID:          rev.ID.String(),
+			Revision:    rev.Revision,
+			Content:     rev.Content,
+			ContentType: string(rev.ContentType),
+			EditedBy:    rev.EditedBy.String(),
+			EditedAt:    rev.EditedAt,
+		}
+	}
+
+	return &dto.GetMessageRevisionsResponse{
+		MessageID:      msg.ID.String(),
+		CurrentContent: msg.Content,
+		EditCount:      msg.EditCount,
+		EditedAt:       msg.EditedAt,
+		Revisions:      revisionDTOs,
+	}, nil
+}
+
+// FollowThread subscribes a user to updates of the thread containing the message
+func (s *service) FollowThread(ctx context.Context, userID, messageID uuid.UUID) error {
+	root, err := s.findThreadRoot(ctx, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.FollowThread(ctx, root.ID, userID); err != nil {
+		return fmt.Errorf("failed to follow thread: %w", err)
+	}
+
+	return nil
+}
+
+// UnfollowThread unsubscribes a user from updates of the thread containing the message
+func (s *service) UnfollowThread(ctx context.Context, userID, messageID uuid.UUID) error {
+	root, err := s.findThreadRoot(ctx, userID, messageID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.UnfollowThread(ctx, root.ID, userID); err != nil {
+		return fmt.Errorf("failed to unfollow thread: %w", err)
+	}
+
+	return nil
+}
+
+// findThreadRoot resolves the root of the thread containing the message and
+// checks that the user can see it
+func (s *service) findThreadRoot(ctx context.Context, userID, messageID uuid.UUID) (*message.Message, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, fmt.Errorf("message not found: %w", err)
+	}
+
+	root := msg
+	if msg.ThreadID != nil {
+		root, err = s.messageRepo.FindByID(ctx, *msg.ThreadID)
+		if err != nil {
+			return nil, fmt.Errorf("thread root not found: %w", err)
+		}
+	}
+
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, root.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	return root, nil
+}
+
+// Mentions
+
+// GetMentions gets the mentions feed for a user
+func (s *service) GetMentions(ctx context.Context, userID uuid.UUID, req *dto.GetMentionsRequest) (*dto.GetMentionsResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	mentions, err := s.messageRepo.GetMentionsForUser(ctx, userID, req.ConversationID, req.UnreadOnly, limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	counts, err := s.messageRepo.CountUnreadMentions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread mentions: %w", err)
+	}
+
+	userCache := make(map[uuid.UUID]*user.User)
+	mentionDTOs := make([]dto.MentionDTO, 0, len(mentions))
+	for _, m := range mentions {
+		mentionDTO := dto.MentionDTO{
+			ID:             m.ID.String(),
+			MessageID:      m.MessageID.String(),
+			ConversationID: m.ConversationID.String(),
+			Position:       m.Position,
+			Length:         m.Length,
+			Read:           m.IsRead(),
+			CreatedAt:      m.CreatedAt,
+		}
+
+		msg, err := s.messageRepo.FindByID(ctx, m.MessageID)
+		if err == nil {
+			sender, exists := userCache[msg.SenderID]
+			if !exists {
+				sender, _ = s.userRepo.FindByID(ctx, msg.SenderID)
+				userCache[msg.SenderID] = sender
+			}
+			msgDTO := toMessageDTO(msg, sender, nil)
+			mentionDTO.Message = &msgDTO
+		}
+
+		mentionDTOs = append(mentionDTOs, mentionDTO)
+	}
+
+	unreadCounts := make(map[string]int64, len(counts))
+	var totalUnread int64
+	for conversationID, count := range counts {
+		unreadCounts[conversationID.String()] = count
+		totalUnread += count
+	}
+
+	return &dto.GetMentionsResponse{
+		Mentions:     mentionDTOs,
+		UnreadCounts: unreadCounts,
+		TotalUnread:  totalUnread,
+	}, nil
+}
+
+// Message Search (Day 13)
+
+func (s *service) SearchMessages(ctx context.Context, userID uuid.UUID, req *dto.SearchMessagesRequest) (*dto.SearchMessagesResponse, error) {
+	query := message.SearchQuery{
+		UserID:           userID,
+		Text:             req.Query,
+		ConversationType: req.ConversationType,
+		From:             req.From,
+		To:               req.To,
+		HasReactions:     req.HasReactions,
+		Pinned:           req.Pinned,
+		Offset:           req.Offset,
+	}
+
+	if req.ConversationID != nil {
+		id, err := uuid.Parse(*req.ConversationID)
+		if err != nil {
+			return nil, err
+		}
+		query.ConversationID = &id
+	}
+	if req.SenderID != nil {
+		id, err := uuid.Parse(*req.SenderID)
+		if err != nil {
+			return nil, err
+		}
+		query.SenderID = &id
+	}
+	for _, ct := range req.ContentTypes {
+		query.ContentTypes = append(query.ContentTypes, message.ContentType(ct))
+	}
+	if req.Cursor != "" {
+		cursor, err := message.ParseSearchCursor(req.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		query.Cursor = &cursor
+	}
+
+	query.Limit = req.Limit
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+
+	results, total, err := s.messageRepo.SearchMessages(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search messages: %w", err)
+	}
+
+	// Convert to DTOs
+	userCache := make(map[uuid.UUID]*user.User)
+	messageDTOs := make([]*dto.MessageDTO, len(results))
+	snippets := make(map[string]string, len(results))
+	for i, result := range results {
+		msg := result.Message
+		sender, exists := userCache[msg.SenderID]
+		if !exists {
+			sender, _ = s.userRepo.FindByID(ctx, msg.SenderID)
+			userCache[msg.SenderID] = sender
+		}
+		msgDTO := toMessageDTO(msg, sender, nil)
+		messageDTOs[i] = &msgDTO
+		snippets[msgDTO.ID] = result.Snippet
+	}
+
+	resp := &dto.SearchMessagesResponse{
+		Messages: messageDTOs,
+		Total:    total,
+		Snippets: snippets,
+	}
+
+	// A full page means there may be more results
+	if len(results) == query.Limit {
+		last := results[len(results)-1]
+		resp.NextCursor = message.SearchCursor{
+			Rank:      last.Rank,
+			CreatedAt: last.Message.CreatedAt,
+			ID:        last.Message.ID,
+		}.Encode()
+	}
+
+	return resp, nil
+}
+
+// resolveParticipantID resolves a participant identifier to a user UUID
+// Accepts either a UUID string or a Solana wallet address
+func (s *service) resolveParticipantID(ctx context.Context, participantID string) (uuid.UUID, error) {
+	// Try to parse as UUID first
+	userUUID, err := uuid.Parse(participantID)
+	if err == nil {
+		// It's a valid UUID, verify user exists
+		_, err := s.userRepo.FindByID(ctx, userUUID)
+		if err != nil {
+			return uuid.Nil, fmt.Errorf("user not found with ID: %s", participantID)
+		}
+		return userUUID, nil
+	}
+
+	// Not a UUID, treat as wallet address
+	user, err := s.userRepo.FindByWalletAddress(ctx, participantID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("user not found with wallet address: %s", participantID)
+	}
+
+	return user.ID, nil
+}
+
+// ArchiveConversation archives a conversation for a user
+func (s *service) ArchiveConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	// Verify user is a participant
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil {
+		logger.Error("Failed to check participant status", zap.Error(err))
+		return fmt.Errorf("failed to check participant status: %w", err)
+	}
+	if !isParticipant {
+		return fmt.Errorf("user is not a participant of this conversation")
+	}
+
+	// Archive the conversation
+	if err := s.conversationRepo.ArchiveConversation(ctx, conversationID, userID); err != nil {
+		logger.Error("Failed to archive conversation", zap.Error(err))
+		return fmt.Errorf("failed to archive conversation: %w", err)
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	logger.Info("Conversation archived",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()))
+
+	return nil
+}
+
+// UnarchiveConversation unarchives a conversation for a user
+func (s *service) UnarchiveConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	// Verify user is a participant
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil {
+		logger.Error("Failed to check participant status", zap.Error(err))
+		return fmt.Errorf("failed to check participant status: %w", err)
+	}
+	if !isParticipant {
+		return fmt.Errorf("user is not a participant of this conversation")
+	}
+
+	// Unarchive the conversation
+	if err := s.conversationRepo.UnarchiveConversation(ctx, conversationID, userID); err != nil {
+		logger.Error("Failed to unarchive conversation", zap.Error(err))
+		return fmt.Errorf("failed to unarchive conversation: %w", err)
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	logger.Info("Conversation unarchived",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()))
+
+	return nil
+}
+
+// PinConversation pins a conversation on top of the user's conversation list
+func (s *service) PinConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	if err := s.conversationRepo.PinConversation(ctx, conversationID, userID, conversation.MaxPinnedConversations); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	logger.Info("Conversation pinned",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()))
+
+	return nil
+}
+
+// UnpinConversation unpins a conversation for a user
+func (s *service) UnpinConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	if err := s.conversationRepo.UnpinConversation(ctx, conversationID, userID); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	return nil
+}
+
+// ReorderPinnedConversations sets the order of the user's pinned conversations, top first
+func (s *service) ReorderPinnedConversations(ctx context.Context, userID uuid.UUID, req *dto.ReorderPinnedRequest) error {
+	if err := s.conversationRepo.ReorderPinned(ctx, userID, req.ConversationIDs); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, req.ConversationIDs...)
+
+	return nil
+}
+
+// MuteConversation stops notifications of a conversation for a user, until the given time or until unmuted
+func (s *service) MuteConversation(ctx context.Context, userID, conversationID uuid.UUID, req *dto.MuteConversationRequest) error {
+	until := conversation.MuteForever
+	if req.Until != nil {
+		if !req.Until.After(time.Now()) {
+			return conversation.ErrInvalidMuteUntil
+		}
+		until = *req.Until
+	}
+
+	if err := s.conversationRepo.SetMutedUntil(ctx, conversationID, userID, &until); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	logger.Info("Conversation muted",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()),
+		zap.Time("until", until))
+
+	return nil
+}
+
+// UnmuteConversation turns notifications of a conversation back on for a user
+func (s *service) UnmuteConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	if err := s.conversationRepo.SetMutedUntil(ctx, conversationID, userID, nil); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
 
-	// Broadcast message pinned via WebSocket
-	if s.wsBroadcaster != nil {
-		if err := s.wsBroadcaster.BroadcastMessagePinned(
-			ctx,
-			conversationID,
-			messageID.String(),
-			userID.String(),
-		); err != nil {
-			logger.Error("Failed to broadcast message pinned",
-				zap.String("message_id", messageID.String()),
-				zap.Error(err),
-			)
+	return nil
+}
+
+// GetFolders lists the user's conversation folders, oldest first
+func (s *service) GetFolders(ctx context.Context, userID uuid.UUID) (*dto.GetFoldersResponse, error) {
+	folders, err := s.conversationRepo.FindFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+
+	folderDTOs := make([]dto.FolderDTO, len(folders))
+	for i, folder := range folders {
+		folderDTOs[i] = toFolderDTO(folder)
+	}
+
+	return &dto.GetFoldersResponse{
+		Folders: folderDTOs,
+	}, nil
+}
+
+// CreateFolder creates a conversation folder with a name unique among the user's folders
+func (s *service) CreateFolder(ctx context.Context, userID uuid.UUID, name string) (*dto.FolderDTO, error) {
+	folder := conversation.NewFolder(userID, name)
+	if err := folder.Validate(); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.conversationRepo.FindFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+	if len(folders) >= conversation.MaxFolders {
+		return nil, conversation.ErrFolderLimitReached
+	}
+	if folderNameTaken(folders, folder) {
+		return nil, conversation.ErrFolderNameTaken
+	}
+
+	if err := s.conversationRepo.CreateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	result := toFolderDTO(folder)
+	return &result, nil
+}
+
+// RenameFolder renames one of the user's conversation folders
+func (s *service) RenameFolder(ctx context.Context, userID, folderID uuid.UUID, name string) (*dto.FolderDTO, error) {
+	folder, err := s.findOwnFolder(ctx, userID, folderID)
+	if err != nil {
+		return nil, err
+	}
+
+	folder.Rename(name)
+	if err := folder.Validate(); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.conversationRepo.FindFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get folders: %w", err)
+	}
+	if folderNameTaken(folders, folder) {
+		return nil, conversation.ErrFolderNameTaken
+	}
+	for _, f := range folders {
+		if f.ID == folder.ID {
+			folder.ConversationCount = f.ConversationCount
 		}
 	}
 
+	if err := s.conversationRepo.UpdateFolder(ctx, folder); err != nil {
+		return nil, err
+	}
+
+	result := toFolderDTO(folder)
+	return &result, nil
+}
+
+// DeleteFolder deletes one of the user's conversation folders, the conversations in it are kept
+func (s *service) DeleteFolder(ctx context.Context, userID, folderID uuid.UUID) error {
+	if _, err := s.findOwnFolder(ctx, userID, folderID); err != nil {
+		return err
+	}
+
+	// The conversations in the folder lose its ID
+	conversations, err := s.conversationRepo.FindByUserID(ctx, userID, conversation.ListFilter{FolderID: &folderID}, -1, 0)
+	if err != nil {
+		return fmt.Errorf("failed to get folder conversations: %w", err)
+	}
+
+	if err := s.conversationRepo.DeleteFolder(ctx, folderID); err != nil {
+		return err
+	}
+
+	conversationIDs := make([]uuid.UUID, len(conversations))
+	for i, conv := range conversations {
+		conversationIDs[i] = conv.ID
+	}
+	s.recordOwnChange(ctx, userID, conversationIDs...)
+
 	return nil
 }
 
-func (s *service) UnpinMessage(ctx context.Context, userID, conversationID, messageID uuid.UUID) error {
-	// Verify user is participant of conversation
+// AddConversationToFolder adds a conversation the user takes part in to one of their folders
+func (s *service) AddConversationToFolder(ctx context.Context, userID, folderID, conversationID uuid.UUID) error {
+	if _, err := s.findOwnFolder(ctx, userID, folderID); err != nil {
+		return err
+	}
+
 	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
 	if err != nil {
 		return fmt.Errorf("failed to check participant: %w", err)
@@ -743,307 +3225,623 @@ func (s *service) UnpinMessage(ctx context.Context, userID, conversationID, mess
 		return conversation.ErrNotParticipant
 	}
 
-	// Verify message exists and belongs to conversation
-	msg, err := s.messageRepo.FindByID(ctx, messageID)
+	if err := s.conversationRepo.AddToFolder(ctx, folderID, conversationID); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	return nil
+}
+
+// RemoveConversationFromFolder removes a conversation from one of the user's folders
+func (s *service) RemoveConversationFromFolder(ctx context.Context, userID, folderID, conversationID uuid.UUID) error {
+	if _, err := s.findOwnFolder(ctx, userID, folderID); err != nil {
+		return err
+	}
+	if err := s.conversationRepo.RemoveFromFolder(ctx, folderID, conversationID); err != nil {
+		return err
+	}
+	s.recordOwnChange(ctx, userID, conversationID)
+
+	return nil
+}
+
+// findOwnFolder loads a folder, reporting folders of other users as not found
+func (s *service) findOwnFolder(ctx context.Context, userID, folderID uuid.UUID) (*conversation.Folder, error) {
+	folder, err := s.conversationRepo.FindFolderByID(ctx, folderID)
 	if err != nil {
-		return fmt.Errorf("message not found: %w", err)
+		return nil, err
+	}
+	if folder.UserID != userID {
+		return nil, conversation.ErrFolderNotFound
 	}
+	return folder, nil
+}
 
-	if msg.ConversationID != conversationID {
-		return message.ErrMessageNotFound
+// folderNameTaken checks if another of the folders has the same name, ignoring case
+func folderNameTaken(folders []*conversation.Folder, folder *conversation.Folder) bool {
+	for _, f := range folders {
+		if f.ID != folder.ID && strings.EqualFold(f.Name, folder.Name) {
+			return true
+		}
 	}
+	return false
+}
 
-	if err := s.messageRepo.UnpinMessage(ctx, conversationID, messageID, userID); err != nil {
-		return fmt.Errorf("failed to unpin message: %w", err)
+func toFolderDTO(folder *conversation.Folder) dto.FolderDTO {
+	return dto.FolderDTO{
+		ID:                folder.ID.String(),
+		Name:              folder.Name,
+		ConversationCount: folder.ConversationCount,
+		CreatedAt:         folder.CreatedAt,
+		UpdatedAt:         folder.UpdatedAt,
 	}
+}
 
-	// Broadcast message unpinned via WebSocket
-	if s.wsBroadcaster != nil {
-		if err := s.wsBroadcaster.BroadcastMessageUnpinned(
-			ctx,
-			conversationID,
-			messageID.String(),
-			userID.String(),
-		); err != nil {
-			logger.Error("Failed to broadcast message unpinned",
-				zap.String("message_id", messageID.String()),
-				zap.Error(err),
-			)
+// DeleteConversation permanently deletes a conversation (only if user is owner/admin)
+func (s *service) DeleteConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
+	// Get conversation details
+	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+	if err != nil {
+		logger.Error("Failed to find conversation", zap.Error(err))
+		return fmt.Errorf("conversation not found: %w", err)
+	}
+
+	// Verify user is a participant
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	if err != nil {
+		logger.Error("Failed to check participant status", zap.Error(err))
+		return fmt.Errorf("failed to check participant status: %w", err)
+	}
+	if !isParticipant {
+		return fmt.Errorf("user is not a participant of this conversation")
+	}
+
+	participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
+	if err != nil {
+		logger.Error("Failed to find participants", zap.Error(err))
+		return fmt.Errorf("failed to check permissions: %w", err)
+	}
+
+	// For direct conversations, allow any participant to delete
+	// For group/channel conversations, only allow owner/admin to delete
+	if conv.Type != conversation.TypeDirect {
+		// Check if user is owner or admin
+		hasPermission := false
+		for _, p := range participants {
+			if p.UserID == userID && (p.Role == conversation.RoleOwner || p.Role == conversation.RoleAdmin) {
+				hasPermission = true
+				break
+			}
+		}
+
+		if !hasPermission {
+			return fmt.Errorf("only owner or admin can delete group/channel conversations")
+		}
+	}
+
+	// Delete the conversation
+	if err := s.conversationRepo.Delete(ctx, conversationID); err != nil {
+		logger.Error("Failed to delete conversation", zap.Error(err))
+		return fmt.Errorf("failed to delete conversation: %w", err)
+	}
+	s.recordConversationRemoved(ctx, conversationID, participants)
+
+	logger.Info("Conversation deleted",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()))
+
+	return nil
+}
+
+// AcceptMessageRequest moves a message request into the user's conversations.
+// From then on the requester sees the user's read receipts, typing and presence
+func (s *service) AcceptMessageRequest(ctx context.Context, userID, conversationID uuid.UUID) error {
+	if err := s.conversationRepo.AcceptRequest(ctx, conversationID, userID); err != nil {
+		if errors.Is(err, conversation.ErrMessageRequestNotFound) {
+			return err
+		}
+		return fmt.Errorf("failed to accept message request: %w", err)
+	}
+	s.recordChange(ctx, changelog.NewConversationChange(conversationID, changelog.KindConversationUpdated))
+
+	logger.Info("Message request accepted",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()),
+	)
+
+	return nil
+}
+
+// DeclineMessageRequest deletes a message request, blocking the requester if asked to
+func (s *service) DeclineMessageRequest(ctx context.Context, userID, conversationID uuid.UUID, block bool) error {
+	participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
+	if err != nil {
+		return fmt.Errorf("failed to find participants: %w", err)
+	}
+
+	pending := conversation.FindPendingRequest(participants)
+	if pending == nil || pending.UserID != userID {
+		return conversation.ErrMessageRequestNotFound
+	}
+
+	if block {
+		for _, p := range participants {
+			if p.UserID == userID {
+				continue
+			}
+
+			// The requester is not one of the user's contacts, so there is no contact entry to keep in sync
+			err := s.privacyRepo.BlockUser(ctx, &privacy.BlockedUser{
+				UserID:        userID,
+				BlockedUserID: p.UserID,
+				Reason:        "message request",
+			})
+			if err != nil && !errors.Is(err, privacy.ErrUserAlreadyBlocked) {
+				return fmt.Errorf("failed to block requester: %w", err)
+			}
+		}
+	}
+
+	if err := s.conversationRepo.Delete(ctx, conversationID); err != nil {
+		return fmt.Errorf("failed to delete message request: %w", err)
+	}
+	s.recordConversationRemoved(ctx, conversationID, participants)
+
+	logger.Info("Message request declined",
+		zap.String("user_id", userID.String()),
+		zap.String("conversation_id", conversationID.String()),
+		zap.Bool("blocked", block),
+	)
+
+	return nil
+}
+
+// ScheduleMessage stores a message to be delivered into a conversation at a later time
+func (s *service) ScheduleMessage(ctx context.Context, userID uuid.UUID, req *dto.ScheduleMessageRequest) (*dto.ScheduledMessageDTO, error) {
+	if !req.SendAt.After(time.Now()) {
+		return nil, message.ErrInvalidSendTime
+	}
+	if message.ContentType(req.ContentType) == message.ContentTypePoll {
+		return nil, message.ErrPollUnsupported
+	}
+
+	conv, err := s.conversationRepo.FindByID(ctx, req.ConversationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find conversation: %w", err)
+	}
+
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conv.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
+	}
+
+	// Reject early if the user cannot post here; checked again when the message is sent
+	if err := s.checkSendPermission(ctx, conv, userID); err != nil {
+		return nil, err
+	}
+
+	scheduled := message.NewScheduledMessage(conv.ID, userID, req.Content, message.ContentType(req.ContentType), req.SendAt)
+	scheduled.ReplyToID = req.ReplyToID
+	if err := s.messageRepo.CreateScheduled(ctx, scheduled); err != nil {
+		return nil, fmt.Errorf("failed to schedule message: %w", err)
+	}
+
+	logger.Info("Message scheduled",
+		zap.String("scheduled_id", scheduled.ID.String()),
+		zap.String("conversation_id", conv.ID.String()),
+		zap.Time("send_at", scheduled.SendAt),
+	)
+
+	result := toScheduledMessageDTO(scheduled)
+	return &result, nil
+}
+
+// GetScheduledMessages lists the user's scheduled messages, soonest first
+func (s *service) GetScheduledMessages(ctx context.Context, userID uuid.UUID, req *dto.GetScheduledMessagesRequest) (*dto.GetScheduledMessagesResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	var statuses []message.ScheduledStatus
+	switch req.Status {
+	case "":
+		statuses = []message.ScheduledStatus{message.ScheduledPending}
+	case "all":
+	default:
+		statuses = []message.ScheduledStatus{message.ScheduledStatus(req.Status)}
+	}
+
+	scheduled, err := s.messageRepo.FindScheduledBySender(ctx, userID, req.ConversationID, statuses, limit, req.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled messages: %w", err)
+	}
+
+	result := make([]dto.ScheduledMessageDTO, len(scheduled))
+	for i, sm := range scheduled {
+		result[i] = toScheduledMessageDTO(sm)
+	}
+
+	return &dto.GetScheduledMessagesResponse{
+		ScheduledMessages: result,
+	}, nil
+}
+
+// UpdateScheduledMessage edits the content or send time of a pending scheduled message
+func (s *service) UpdateScheduledMessage(ctx context.Context, userID, scheduledID uuid.UUID, req *dto.UpdateScheduledMessageRequest) (*dto.ScheduledMessageDTO, error) {
+	scheduled, err := s.findOwnScheduled(ctx, userID, scheduledID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Content != nil {
+		scheduled.Content = *req.Content
+	}
+	if req.ContentType != nil {
+		if message.ContentType(*req.ContentType) == message.ContentTypePoll {
+			return nil, message.ErrPollUnsupported
+		}
+		scheduled.ContentType = message.ContentType(*req.ContentType)
+	}
+	if req.SendAt != nil {
+		if !req.SendAt.After(time.Now()) {
+			return nil, message.ErrInvalidSendTime
 		}
+		scheduled.SendAt = *req.SendAt
+	}
+	scheduled.UpdatedAt = time.Now()
+
+	// Fails if the dispatcher claimed the message meanwhile
+	if err := s.messageRepo.UpdateScheduled(ctx, scheduled); err != nil {
+		return nil, err
+	}
+
+	result := toScheduledMessageDTO(scheduled)
+	return &result, nil
+}
+
+// CancelScheduledMessage cancels a pending scheduled message
+func (s *service) CancelScheduledMessage(ctx context.Context, userID, scheduledID uuid.UUID) error {
+	if _, err := s.findOwnScheduled(ctx, userID, scheduledID); err != nil {
+		return err
+	}
+
+	if err := s.messageRepo.CancelScheduled(ctx, scheduledID); err != nil {
+		return err
 	}
 
+	logger.Info("Scheduled message canceled",
+		zap.String("scheduled_id", scheduledID.String()),
+		zap.String("user_id", userID.String()),
+	)
+
 	return nil
 }
 
-func (s *service) GetPinnedMessages(ctx context.Context, conversationID uuid.UUID) (*dto.PinnedMessagesResponse, error) {
-	pinned, err := s.messageRepo.GetPinnedMessages(ctx, conversationID)
+// findOwnScheduled loads a pending scheduled message of the user
+func (s *service) findOwnScheduled(ctx context.Context, userID, scheduledID uuid.UUID) (*message.ScheduledMessage, error) {
+	scheduled, err := s.messageRepo.FindScheduledByID(ctx, scheduledID)
 	if err != nil {
 		return nil, err
 	}
-
-	pinnedDTOs := make([]dto.PinnedMessageResponse, len(pinned))
-	for i, p := range pinned {
-		pinnedDTOs[i] = dto.PinnedMessageResponse{
-			ID:             p.ID.String(),
-			ConversationID: p.ConversationID.String(),
-			MessageID:      p.MessageID.String(),
-			PinnedBy:       p.PinnedBy.String(),
-			PinnedAt:       p.PinnedAt,
-		}
+	// Don't reveal other users' scheduled messages
+	if scheduled.SenderID != userID {
+		return nil, message.ErrScheduledMessageNotFound
+	}
+	if !scheduled.IsPending() {
+		return nil, message.ErrScheduledNotPending
 	}
 
-	return &dto.PinnedMessagesResponse{
-		ConversationID: conversationID.String(),
-		PinnedMessages: pinnedDTOs,
-	}, nil
+	return scheduled, nil
 }
 
-// Message Forwarding (Day 13)
+// DispatchScheduledMessage sends a scheduled message claimed by the dispatcher through the normal send pipeline
+func (s *service) DispatchScheduledMessage(ctx context.Context, scheduled *message.ScheduledMessage) (*dto.MessageDTO, error) {
+	// A previous attempt may have delivered it before its dispatcher went away
+	if scheduled.Attempts > 1 {
+		if _, err := s.messageRepo.FindByID(ctx, scheduled.ID); err == nil {
+			if err := s.messageRepo.MarkScheduledSent(ctx, scheduled.ID, time.Now()); err != nil {
+				return nil, fmt.Errorf("failed to mark scheduled message sent: %w", err)
+			}
+			return nil, nil
+		}
+	}
 
-func (s *service) ForwardMessage(ctx context.Context, userID, messageID, targetConversationID uuid.UUID) (*dto.SendMessageResponse, error) {
-	// Get original message
-	originalMsg, err := s.messageRepo.FindByID(ctx, messageID)
+	sender, err := s.userRepo.FindByID(ctx, scheduled.SenderID)
 	if err != nil {
-		return nil, fmt.Errorf("original message not found: %w", err)
+		return nil, fmt.Errorf("sender not found: %w", err)
 	}
 
-	// Verify user has access to the original message (is participant in source conversation)
-	isSourceParticipant, err := s.conversationRepo.IsParticipant(ctx, originalMsg.ConversationID, userID)
+	conv, err := s.conversationRepo.FindByID(ctx, scheduled.ConversationID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check source participant: %w", err)
+		return nil, fmt.Errorf("failed to find conversation: %w", err)
 	}
-	if !isSourceParticipant {
+
+	// Membership and posting rights may have changed since the message was scheduled
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conv.ID, scheduled.SenderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
 		return nil, conversation.ErrNotParticipant
 	}
+	if err := s.checkSendPermission(ctx, conv, scheduled.SenderID); err != nil {
+		return nil, err
+	}
 
-	// Verify user is participant of target conversation
-	isTargetParticipant, err := s.conversationRepo.IsParticipant(ctx, targetConversationID, userID)
+	messageDTO, err := s.deliverToConversation(ctx, conv, scheduled.ToMessage(), sender)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check target participant: %w", err)
+		return nil, err
 	}
-	if !isTargetParticipant {
-		return nil, conversation.ErrNotParticipant
+
+	if err := s.messageRepo.MarkScheduledSent(ctx, scheduled.ID, time.Now()); err != nil {
+		// The message is out; a retry finds it and only marks the schedule as sent
+		return nil, fmt.Errorf("failed to mark scheduled message sent: %w", err)
 	}
 
-	// Create new message with same content
-	newMsg := message.NewMessage(
-		targetConversationID,
-		userID,
-		originalMsg.Content,
-		originalMsg.ContentType,
-	)
+	return &messageDTO, nil
+}
 
-	// Mark as sent
-	newMsg.MarkAsSent()
+// toScheduledMessageDTO converts a domain scheduled message to DTO
+func toScheduledMessageDTO(scheduled *message.ScheduledMessage) dto.ScheduledMessageDTO {
+	result := dto.ScheduledMessageDTO{
+		ID:             scheduled.ID.String(),
+		ConversationID: scheduled.ConversationID.String(),
+		SenderID:       scheduled.SenderID.String(),
+		Content:        scheduled.Content,
+		ContentType:    string(scheduled.ContentType),
+		SendAt:         scheduled.SendAt,
+		Status:         string(scheduled.Status),
+		SentAt:         scheduled.SentAt,
+		LastError:      scheduled.LastError,
+		CreatedAt:      scheduled.CreatedAt,
+		UpdatedAt:      scheduled.UpdatedAt,
+	}
+	if scheduled.ReplyToID != nil {
+		replyToID := scheduled.ReplyToID.String()
+		result.ReplyToID = &replyToID
+	}
+
+	return result
+}
 
-	// Save new message
-	if err := s.messageRepo.Create(ctx, newMsg); err != nil {
-		return nil, fmt.Errorf("failed to create forwarded message: %w", err)
-	}
+// Polls
 
-	// Record forward relationship
-	if err := s.messageRepo.RecordForward(ctx, originalMsg.ID, newMsg.ID, userID, targetConversationID); err != nil {
-		logger.Warn("Failed to record forward", zap.Error(err))
+// applyPoll builds the poll of a poll message from the request, the message content is the question
+func applyPoll(msg *message.Message, req *dto.CreatePollRequest) error {
+	if msg.ContentType != message.ContentTypePoll {
+		if req != nil {
+			return message.ErrInvalidPoll
+		}
+		return nil
+	}
+	if req == nil {
+		return message.ErrInvalidPoll
 	}
 
-	// Update conversation's last message
-	conv, err := s.conversationRepo.FindByID(ctx, targetConversationID)
-	if err == nil {
-		conv.UpdateLastMessage(newMsg.ID)
-		if err := s.conversationRepo.Update(ctx, conv); err != nil {
-			logger.Warn("Failed to update conversation last message", zap.Error(err))
+	poll := message.NewPoll(msg.ID, msg.Content, req.Options, req.MultipleChoice, req.Anonymous)
+	if req.Quiz {
+		if req.CorrectOption == nil {
+			return message.ErrInvalidPoll
 		}
+		poll.SetQuiz(*req.CorrectOption)
+	} else if req.CorrectOption != nil {
+		return message.ErrInvalidPoll
 	}
-
-	// Get sender info for response
-	sender, _ := s.userRepo.FindByID(ctx, userID)
-	messageDTO := toMessageDTO(newMsg, sender, nil)
-
-	// Broadcast forwarded message via WebSocket
-	if s.wsBroadcaster != nil {
-		go func() {
-			if err := s.wsBroadcaster.BroadcastNewMessage(context.Background(), targetConversationID, messageDTO); err != nil {
-				logger.Error("Failed to broadcast forwarded message",
-					zap.String("message_id", newMsg.ID.String()),
-					zap.String("conversation_id", targetConversationID.String()),
-					zap.Error(err),
-				)
-			}
-		}()
+	if req.ClosesAt != nil {
+		poll.SetClosesAt(*req.ClosesAt)
+	}
+	if err := poll.Validate(); err != nil {
+		return err
 	}
 
-	logger.Info("Message forwarded",
-		zap.String("original_message_id", originalMsg.ID.String()),
-		zap.String("new_message_id", newMsg.ID.String()),
-		zap.String("user_id", userID.String()),
-		zap.String("target_conversation_id", targetConversationID.String()),
-	)
-
-	return &dto.SendMessageResponse{
-		Message: messageDTO,
-	}, nil
+	msg.Poll = poll
+	return nil
 }
 
-// Message Search (Day 13)
+// attachPolls loads the polls of the poll messages. When userID is set, each poll includes that user's votes
+func (s *service) attachPolls(ctx context.Context, userID *uuid.UUID, messages []*message.Message) {
+	pollIDs := make([]uuid.UUID, 0, len(messages))
+	for _, msg := range messages {
+		if msg.ContentType == message.ContentTypePoll && !msg.IsDeleted() {
+			pollIDs = append(pollIDs, msg.ID)
+		}
+	}
+	if len(pollIDs) == 0 {
+		return
+	}
 
-func (s *service) SearchMessages(ctx context.Context, userID uuid.UUID, req *dto.SearchMessagesRequest) (*dto.SearchMessagesResponse, error) {
-	var conversationID *uuid.UUID
-	if req.ConversationID != nil {
-		id, err := uuid.Parse(*req.ConversationID)
-		if err != nil {
-			return nil, err
+	polls, err := s.messageRepo.GetPolls(ctx, pollIDs, userID)
+	if err != nil {
+		logger.Warn("Failed to get polls", zap.Error(err))
+		return
+	}
+
+	for _, msg := range messages {
+		if poll, ok := polls[msg.ID]; ok {
+			msg.Poll = poll
 		}
-		conversationID = &id
 	}
+}
 
-	limit := req.Limit
-	if limit == 0 {
-		limit = 20
+// VotePoll casts the user's vote, replacing an earlier one. Quiz answers are final
+func (s *service) VotePoll(ctx context.Context, userID, messageID uuid.UUID, options []int) (*dto.PollDTO, error) {
+	msg, err := s.findVotablePoll(ctx, userID, messageID)
+	if err != nil {
+		return nil, err
 	}
 
-	messages, err := s.messageRepo.SearchMessages(ctx, userID, req.Query, conversationID, limit, req.Offset)
+	if msg.Poll.IsClosed() {
+		return nil, message.ErrPollClosed
+	}
+	if msg.Poll.Quiz && msg.Poll.HasVoted() {
+		return nil, message.ErrPollVoteFinal
+	}
+	positions, err := msg.Poll.NormalizeVote(options)
 	if err != nil {
 		return nil, err
 	}
 
-	// Convert to DTOs
-	messageDTOs := make([]*dto.MessageDTO, len(messages))
-	for i, msg := range messages {
-		sender, _ := s.userRepo.FindByID(ctx, msg.SenderID)
-		msgDTO := toMessageDTO(msg, sender, nil)
-		messageDTOs[i] = &msgDTO
+	if err := s.messageRepo.SetPollVotes(ctx, messageID, userID, positions); err != nil {
+		return nil, err
 	}
 
-	return &dto.SearchMessagesResponse{
-		Messages: messageDTOs,
-		Total:    len(messageDTOs),
-	}, nil
+	return s.pollResults(ctx, msg, userID)
 }
 
-// resolveParticipantID resolves a participant identifier to a user UUID
-// Accepts either a UUID string or a Solana wallet address
-func (s *service) resolveParticipantID(ctx context.Context, participantID string) (uuid.UUID, error) {
-	// Try to parse as UUID first
-	userUUID, err := uuid.Parse(participantID)
-	if err == nil {
-		// It's a valid UUID, verify user exists
-		_, err := s.userRepo.FindByID(ctx, userUUID)
-		if err != nil {
-			return uuid.Nil, fmt.Errorf("user not found with ID: %s", participantID)
-		}
-		return userUUID, nil
+// RetractPollVote removes the user's vote from a poll that is still open
+func (s *service) RetractPollVote(ctx context.Context, userID, messageID uuid.UUID) (*dto.PollDTO, error) {
+	msg, err := s.findVotablePoll(ctx, userID, messageID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Not a UUID, treat as wallet address
-	user, err := s.userRepo.FindByWalletAddress(ctx, participantID)
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("user not found with wallet address: %s", participantID)
+	if err := s.messageRepo.RetractPollVotes(ctx, messageID, userID); err != nil {
+		return nil, err
 	}
 
-	return user.ID, nil
+	return s.pollResults(ctx, msg, userID)
 }
 
-// ArchiveConversation archives a conversation for a user
-func (s *service) ArchiveConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
-	// Verify user is a participant
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+// ClosePoll ends voting early, only the sender of the poll can close it
+func (s *service) ClosePoll(ctx context.Context, userID, messageID uuid.UUID) (*dto.PollDTO, error) {
+	msg, err := s.findPoll(ctx, userID, messageID)
 	if err != nil {
-		logger.Error("Failed to check participant status", zap.Error(err))
-		return fmt.Errorf("failed to check participant status: %w", err)
+		return nil, err
 	}
-	if !isParticipant {
-		return fmt.Errorf("user is not a participant of this conversation")
+	if msg.SenderID != userID {
+		return nil, message.ErrUnauthorized
 	}
 
-	// Archive the conversation
-	if err := s.conversationRepo.ArchiveConversation(ctx, conversationID, userID); err != nil {
-		logger.Error("Failed to archive conversation", zap.Error(err))
-		return fmt.Errorf("failed to archive conversation: %w", err)
+	if err := s.messageRepo.ClosePoll(ctx, messageID, time.Now()); err != nil {
+		return nil, err
 	}
 
-	logger.Info("Conversation archived",
-		zap.String("user_id", userID.String()),
-		zap.String("conversation_id", conversationID.String()))
-
-	return nil
+	return s.pollResults(ctx, msg, userID)
 }
 
-// UnarchiveConversation unarchives a conversation for a user
-func (s *service) UnarchiveConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
-	// Verify user is a participant
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+// findPoll loads a poll message with the user's votes, checking the user can see it
+func (s *service) findPoll(ctx context.Context, userID, messageID uuid.UUID) (*message.Message, error) {
+	msg, err := s.messageRepo.FindByID(ctx, messageID)
 	if err != nil {
-		logger.Error("Failed to check participant status", zap.Error(err))
-		return fmt.Errorf("failed to check participant status: %w", err)
+		return nil, err
 	}
-	if !isParticipant {
-		return fmt.Errorf("user is not a participant of this conversation")
+	if msg.IsDeleted() {
+		return nil, message.ErrMessageDeleted
+	}
+	if msg.ContentType != message.ContentTypePoll {
+		return nil, message.ErrNotAPoll
 	}
 
-	// Unarchive the conversation
-	if err := s.conversationRepo.UnarchiveConversation(ctx, conversationID, userID); err != nil {
-		logger.Error("Failed to unarchive conversation", zap.Error(err))
-		return fmt.Errorf("failed to unarchive conversation: %w", err)
+	isParticipant, err := s.conversationRepo.IsParticipant(ctx, msg.ConversationID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check participant: %w", err)
+	}
+	if !isParticipant {
+		return nil, conversation.ErrNotParticipant
 	}
 
-	logger.Info("Conversation unarchived",
-		zap.String("user_id", userID.String()),
-		zap.String("conversation_id", conversationID.String()))
+	polls, err := s.messageRepo.GetPolls(ctx, []uuid.UUID{messageID}, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	poll, ok := polls[messageID]
+	if !ok {
+		return nil, message.ErrNotAPoll
+	}
+	msg.Poll = poll
 
-	return nil
+	return msg, nil
 }
 
-// DeleteConversation permanently deletes a conversation (only if user is owner/admin)
-func (s *service) DeleteConversation(ctx context.Context, userID, conversationID uuid.UUID) error {
-	// Get conversation details
-	conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+// findVotablePoll loads a poll the user may vote in. Blocks apply in direct chats, and in channels
+// only the owner and admins vote unless the channel lets subscribers vote
+func (s *service) findVotablePoll(ctx context.Context, userID, messageID uuid.UUID) (*message.Message, error) {
+	msg, err := s.findPoll(ctx, userID, messageID)
 	if err != nil {
-		logger.Error("Failed to find conversation", zap.Error(err))
-		return fmt.Errorf("conversation not found: %w", err)
+		return nil, err
 	}
 
-	// Verify user is a participant
-	isParticipant, err := s.conversationRepo.IsParticipant(ctx, conversationID, userID)
+	conv, err := s.conversationRepo.FindByID(ctx, msg.ConversationID)
 	if err != nil {
-		logger.Error("Failed to check participant status", zap.Error(err))
-		return fmt.Errorf("failed to check participant status: %w", err)
-	}
-	if !isParticipant {
-		return fmt.Errorf("user is not a participant of this conversation")
+		return nil, fmt.Errorf("failed to find conversation: %w", err)
 	}
 
-	// For direct conversations, allow any participant to delete
-	// For group/channel conversations, only allow owner/admin to delete
-	if conv.Type != conversation.TypeDirect {
-		participants, err := s.conversationRepo.FindParticipants(ctx, conversationID)
-		if err != nil {
-			logger.Error("Failed to find participants", zap.Error(err))
-			return fmt.Errorf("failed to check permissions: %w", err)
+	switch conv.Type {
+	case conversation.TypeDirect:
+		if err := s.checkDirectNotBlocked(ctx, conv, userID); err != nil {
+			return nil, err
 		}
 
-		// Check if user is owner or admin
-		hasPermission := false
-		for _, p := range participants {
-			if p.UserID == userID && (p.Role == conversation.RoleOwner || p.Role == conversation.RoleAdmin) {
-				hasPermission = true
-				break
-			}
+	case conversation.TypeChannel:
+		if s.channelRepo == nil {
+			return msg, nil
 		}
 
-		if !hasPermission {
-			return fmt.Errorf("only owner or admin can delete group/channel conversations")
+		ch, err := s.channelRepo.FindByConversationID(ctx, conv.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find channel: %w", err)
+		}
+		if ch.OwnerID == userID || (ch.Settings != nil && ch.Settings.SubscriberVotes) {
+			return msg, nil
+		}
+
+		isAdmin, err := s.channelRepo.IsAdmin(ctx, ch.ID, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check channel admin: %w", err)
+		}
+		if !isAdmin {
+			return nil, message.ErrPollVoteNotAllowed
 		}
 	}
 
-	// Delete the conversation
-	if err := s.conversationRepo.Delete(ctx, conversationID); err != nil {
-		logger.Error("Failed to delete conversation", zap.Error(err))
-		return fmt.Errorf("failed to delete conversation: %w", err)
+	return msg, nil
+}
+
+// pollResults reloads a poll after a change, broadcasts the new counts to the conversation
+// and returns the poll as seen by the user
+func (s *service) pollResults(ctx context.Context, msg *message.Message, userID uuid.UUID) (*dto.PollDTO, error) {
+	polls, err := s.messageRepo.GetPolls(ctx, []uuid.UUID{msg.ID}, &userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get poll: %w", err)
+	}
+	poll, ok := polls[msg.ID]
+	if !ok {
+		return nil, message.ErrNotAPoll
 	}
 
-	logger.Info("Conversation deleted",
-		zap.String("user_id", userID.String()),
-		zap.String("conversation_id", conversationID.String()))
+	s.recordChange(ctx, changelog.NewMessageChange(msg.ConversationID, msg.ID, changelog.KindMessageUpdated))
+	s.publishPollUpdate(msg.ConversationID, poll)
 
-	return nil
+	result := toPollDTO(poll)
+	return &result, nil
+}
+
+// publishPollUpdate broadcasts poll.updated without the user's own votes,
+// so a quiz answer is not revealed to participants who did not answer yet
+func (s *service) publishPollUpdate(conversationID uuid.UUID, poll *message.Poll) {
+	if s.wsBroadcaster == nil {
+		return
+	}
+
+	shared := *poll
+	shared.Voted = nil
+	pollDTO := toPollDTO(&shared)
+
+	go func() {
+		if err := s.wsBroadcaster.BroadcastPollUpdated(context.Background(), conversationID, pollDTO); err != nil {
+			logger.Error("Failed to broadcast poll updated",
+				zap.String("message_id", pollDTO.MessageID),
+				zap.String("conversation_id", conversationID.String()),
+				zap.Error(err),
+			)
+		}
+	}()
 }
@@ -0,0 +1,212 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/user"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+	"github.com/yourusername/sotalk/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+
+	// syncSettleDelay keeps the newest changes out of a sync, so a write that got its
+	// sequence number earlier but committed later is not skipped
+	syncSettleDelay = 2 * time.Second
+)
+
+// Sync returns the current state of the conversations and messages that changed after req.Since.
+// A client that has never synced, or whose position was pruned from the change log, is told to do a full resync
+func (s *service) Sync(ctx context.Context, userID uuid.UUID, req *dto.SyncRequest) (*dto.SyncResponse, error) {
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	if limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+
+	if req.Since <= 0 {
+		return s.fullResync(ctx)
+	}
+	known, err := s.changeLogRepo.Exists(ctx, req.Since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check sync position: %w", err)
+	}
+	if !known {
+		return s.fullResync(ctx)
+	}
+
+	// One extra change tells if there are more
+	changes, err := s.changeLogRepo.FindSince(ctx, userID, req.Since, syncSettleDelay, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get changes: %w", err)
+	}
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+
+	resp := &dto.SyncResponse{
+		Seq:                    req.Since,
+		HasMore:                hasMore,
+		Conversations:          []dto.ConversationDTO{},
+		RemovedConversationIDs: []string{},
+		Messages:               []dto.MessageDTO{},
+		DeletedMessages:        []dto.DeletedMessageDTO{},
+	}
+	if len(changes) == 0 {
+		return resp, nil
+	}
+	resp.Seq = changes[len(changes)-1].Seq
+
+	// Compact the changes into the conversations and messages they touched
+	conversationIDs := make([]uuid.UUID, 0, len(changes))
+	seenConversations := make(map[uuid.UUID]bool)
+	messageIDs := make([]uuid.UUID, 0, len(changes))
+	messageConversations := make(map[uuid.UUID]uuid.UUID)
+	for _, change := range changes {
+		if !seenConversations[change.ConversationID] {
+			seenConversations[change.ConversationID] = true
+			conversationIDs = append(conversationIDs, change.ConversationID)
+		}
+		if change.MessageID != nil {
+			if _, seen := messageConversations[*change.MessageID]; !seen {
+				messageConversations[*change.MessageID] = change.ConversationID
+				messageIDs = append(messageIDs, *change.MessageID)
+			}
+		}
+	}
+
+	if err := s.syncConversations(ctx, userID, conversationIDs, resp); err != nil {
+		return nil, err
+	}
+	if err := s.syncMessages(ctx, userID, messageIDs, messageConversations, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// fullResync tells the client to reload everything and continue syncing from the newest change
+func (s *service) fullResync(ctx context.Context) (*dto.SyncResponse, error) {
+	seq, err := s.changeLogRepo.LatestSeq(ctx, syncSettleDelay)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest change: %w", err)
+	}
+
+	return &dto.SyncResponse{
+		Seq:                    seq,
+		FullResync:             true,
+		Conversations:          []dto.ConversationDTO{},
+		RemovedConversationIDs: []string{},
+		Messages:               []dto.MessageDTO{},
+		DeletedMessages:        []dto.DeletedMessageDTO{},
+	}, nil
+}
+
+// syncConversations adds the current state of changed conversations, or their removal if the user is no longer in them
+func (s *service) syncConversations(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID, resp *dto.SyncResponse) error {
+	conversations := make([]*conversation.Conversation, 0, len(conversationIDs))
+	for _, conversationID := range conversationIDs {
+		_, err := s.conversationRepo.FindParticipant(ctx, conversationID, userID)
+		if errors.Is(err, conversation.ErrNotParticipant) {
+			resp.RemovedConversationIDs = append(resp.RemovedConversationIDs, conversationID.String())
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find participant: %w", err)
+		}
+
+		conv, err := s.conversationRepo.FindByID(ctx, conversationID)
+		if errors.Is(err, conversation.ErrConversationNotFound) {
+			resp.RemovedConversationIDs = append(resp.RemovedConversationIDs, conversationID.String())
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to find conversation: %w", err)
+		}
+		conversations = append(conversations, conv)
+	}
+
+	resp.Conversations = append(resp.Conversations, s.toConversationDTOs(ctx, userID, conversations)...)
+	return nil
+}
+
+// syncMessages adds the current state of changed messages. Messages deleted for everyone
+// are returned as tombstones, the ones expired or hidden by the user as deleted
+func (s *service) syncMessages(ctx context.Context, userID uuid.UUID, messageIDs []uuid.UUID, messageConversations map[uuid.UUID]uuid.UUID, resp *dto.SyncResponse) error {
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	messages, err := s.messageRepo.FindByIDs(ctx, messageIDs, &userID)
+	if err != nil {
+		return fmt.Errorf("failed to get messages: %w", err)
+	}
+
+	found := make(map[uuid.UUID]bool, len(messages))
+	for _, msg := range messages {
+		found[msg.ID] = true
+	}
+	for _, messageID := range messageIDs {
+		if !found[messageID] {
+			resp.DeletedMessages = append(resp.DeletedMessages, dto.DeletedMessageDTO{
+				ID:             messageID.String(),
+				ConversationID: messageConversations[messageID].String(),
+			})
+		}
+	}
+
+	s.attachThreadSummaries(ctx, messages)
+	s.attachReceiptCounts(ctx, userID, messages)
+	s.attachPolls(ctx, &userID, messages)
+
+	userCache := make(map[uuid.UUID]*user.User)
+	for _, msg := range messages {
+		sender, exists := userCache[msg.SenderID]
+		if !exists {
+			sender, _ = s.userRepo.FindByID(ctx, msg.SenderID)
+			userCache[msg.SenderID] = sender
+		}
+		resp.Messages = append(resp.Messages, toMessageDTO(msg, sender, nil))
+	}
+
+	return nil
+}
+
+// recordChange appends changes to the change log clients sync from.
+// The change itself already happened, so a failure is only logged
+func (s *service) recordChange(ctx context.Context, changes ...*changelog.Change) {
+	if err := s.changeLogRepo.Record(ctx, changes...); err != nil {
+		logger.Warn("Failed to record change", zap.Error(err))
+	}
+}
+
+// recordOwnChange records a change of the user's own state of conversations, such as their read marker, pins or folders
+func (s *service) recordOwnChange(ctx context.Context, userID uuid.UUID, conversationIDs ...uuid.UUID) {
+	changes := make([]*changelog.Change, len(conversationIDs))
+	for i, conversationID := range conversationIDs {
+		changes[i] = changelog.NewConversationChange(conversationID, changelog.KindConversationUpdated).ForUser(userID)
+	}
+	s.recordChange(ctx, changes...)
+}
+
+// recordConversationRemoved records a deleted conversation for each former participant,
+// who no longer see the changes of the conversation itself
+func (s *service) recordConversationRemoved(ctx context.Context, conversationID uuid.UUID, participants []*conversation.Participant) {
+	changes := make([]*changelog.Change, len(participants))
+	for i, p := range participants {
+		changes[i] = changelog.NewConversationChange(conversationID, changelog.KindMembershipUpdated).ForUser(p.UserID)
+	}
+	s.recordChange(ctx, changes...)
+}
@@ -0,0 +1,295 @@
+package message
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/changelog"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// syncLog numbers the changes it keeps, the oldest ones can be pruned
+type syncLog struct {
+	changelog.Repository
+	changes []*changelog.Change
+	seq     int64
+}
+
+func (l *syncLog) Record(ctx context.Context, changes ...*changelog.Change) error {
+	for _, change := range changes {
+		l.seq++
+		change.Seq = l.seq
+		l.changes = append(l.changes, change)
+	}
+	return nil
+}
+
+func (l *syncLog) Exists(ctx context.Context, seq int64) (bool, error) {
+	for _, change := range l.changes {
+		if change.Seq == seq {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (l *syncLog) FindSince(ctx context.Context, userID uuid.UUID, since int64, settleDelay time.Duration, limit int) ([]*changelog.Change, error) {
+	var changes []*changelog.Change
+	for _, change := range l.changes {
+		if change.Seq > since && (change.UserID == nil || *change.UserID == userID) && len(changes) < limit {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (l *syncLog) LatestSeq(ctx context.Context, settleDelay time.Duration) (int64, error) {
+	return l.seq, nil
+}
+
+// prune drops the changes up to seq
+func (l *syncLog) prune(seq int64) {
+	var kept []*changelog.Change
+	for _, change := range l.changes {
+		if change.Seq > seq {
+			kept = append(kept, change)
+		}
+	}
+	l.changes = kept
+}
+
+// syncMessagesRepo finds the messages that still exist
+type syncMessagesRepo struct {
+	message.Repository
+	messages map[uuid.UUID]*message.Message
+}
+
+func (r *syncMessagesRepo) FindByIDs(ctx context.Context, ids []uuid.UUID, userID *uuid.UUID) ([]*message.Message, error) {
+	var messages []*message.Message
+	for _, id := range ids {
+		if msg, ok := r.messages[id]; ok {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *syncMessagesRepo) CountUnreadMentions(ctx context.Context, userID uuid.UUID) (map[uuid.UUID]int64, error) {
+	return map[uuid.UUID]int64{}, nil
+}
+
+func (r *syncMessagesRepo) CountUnreadByConversationID(ctx context.Context, conversationID, userID uuid.UUID, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *syncMessagesRepo) GetThreadSummaries(ctx context.Context, rootIDs []uuid.UUID) (map[uuid.UUID]message.ThreadSummary, error) {
+	return map[uuid.UUID]message.ThreadSummary{}, nil
+}
+
+// syncConversationsRepo has no folders
+type syncConversationsRepo struct {
+	*conversationsRepo
+}
+
+func (r *syncConversationsRepo) FindFolderIDs(ctx context.Context, userID uuid.UUID, conversationIDs []uuid.UUID) (map[uuid.UUID][]uuid.UUID, error) {
+	return map[uuid.UUID][]uuid.UUID{}, nil
+}
+
+type syncFixture struct {
+	s        *service
+	log      *syncLog
+	convs    *conversationsRepo
+	messages *syncMessagesRepo
+	userID   uuid.UUID
+}
+
+func newSyncFixture() *syncFixture {
+	f := &syncFixture{
+		log:      &syncLog{},
+		convs:    newConversationsRepo(),
+		messages: &syncMessagesRepo{messages: make(map[uuid.UUID]*message.Message)},
+	}
+	users := &usersRepo{}
+	f.userID = users.add("me").ID
+	f.s = &service{
+		messageRepo:      f.messages,
+		conversationRepo: &syncConversationsRepo{f.convs},
+		userRepo:         users,
+		changeLogRepo:    f.log,
+	}
+	return f
+}
+
+// addMessage stores a message sent by someone else
+func (f *syncFixture) addMessage(conversationID uuid.UUID) *message.Message {
+	msg := &message.Message{ID: uuid.New(), ConversationID: conversationID, SenderID: uuid.New(), ContentType: message.ContentTypeText, CreatedAt: time.Now()}
+	f.messages.messages[msg.ID] = msg
+	return msg
+}
+
+// record logs a change and returns its Seq
+func (f *syncFixture) record(change *changelog.Change) int64 {
+	_ = f.log.Record(context.Background(), change)
+	return change.Seq
+}
+
+func TestSyncCompactsChanges(t *testing.T) {
+	f := newSyncFixture()
+	ctx := context.Background()
+	conv := f.convs.add(conversation.TypeGroup, f.userID, uuid.New())
+	msg := f.addMessage(conv.ID)
+
+	since := f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	f.record(changelog.NewMessageChange(conv.ID, msg.ID, changelog.KindMessageNew))
+	f.record(changelog.NewMessageChange(conv.ID, msg.ID, changelog.KindMessageUpdated))
+	f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated).ForUser(uuid.New()))
+	last := f.record(changelog.NewMessageChange(conv.ID, msg.ID, changelog.KindReactionUpdated))
+
+	resp, err := f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: since})
+	require.NoError(t, err)
+
+	assert.Equal(t, last, resp.Seq)
+	assert.False(t, resp.HasMore)
+	assert.False(t, resp.FullResync)
+	require.Len(t, resp.Conversations, 1, "one item however often it changed")
+	assert.Equal(t, conv.ID.String(), resp.Conversations[0].ID)
+	require.Len(t, resp.Messages, 1)
+	assert.Equal(t, msg.ID.String(), resp.Messages[0].ID)
+	assert.Empty(t, resp.RemovedConversationIDs)
+	assert.Empty(t, resp.DeletedMessages)
+}
+
+func TestSyncReportsWhatIsGone(t *testing.T) {
+	f := newSyncFixture()
+	ctx := context.Background()
+	conv := f.convs.add(conversation.TypeGroup, f.userID)
+	left := f.convs.add(conversation.TypeGroup, uuid.New())
+	gone := uuid.New()
+
+	since := f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	f.record(changelog.NewConversationChange(left.ID, changelog.KindMembershipUpdated).ForUser(f.userID))
+	f.record(changelog.NewMessageChange(conv.ID, gone, changelog.KindMessageDeleted))
+
+	resp, err := f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: since})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{left.ID.String()}, resp.RemovedConversationIDs)
+	assert.Equal(t, []dto.DeletedMessageDTO{{ID: gone.String(), ConversationID: conv.ID.String()}}, resp.DeletedMessages)
+	assert.Empty(t, resp.Messages)
+}
+
+func TestSyncPages(t *testing.T) {
+	f := newSyncFixture()
+	ctx := context.Background()
+	conv := f.convs.add(conversation.TypeDirect, f.userID, uuid.New())
+
+	since := f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	var seqs []int64
+	for i := 0; i < 3; i++ {
+		seqs = append(seqs, f.record(changelog.NewMessageChange(conv.ID, f.addMessage(conv.ID).ID, changelog.KindMessageNew)))
+	}
+
+	resp, err := f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: since, Limit: 2})
+	require.NoError(t, err)
+	assert.True(t, resp.HasMore)
+	assert.Equal(t, seqs[1], resp.Seq)
+	assert.Len(t, resp.Messages, 2)
+
+	resp, err = f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: resp.Seq, Limit: 2})
+	require.NoError(t, err)
+	assert.False(t, resp.HasMore)
+	assert.Equal(t, seqs[2], resp.Seq)
+	assert.Len(t, resp.Messages, 1)
+
+	resp, err = f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: resp.Seq})
+	require.NoError(t, err)
+	assert.Equal(t, seqs[2], resp.Seq, "nothing new keeps the position")
+	assert.Empty(t, resp.Messages)
+	assert.NotNil(t, resp.Conversations)
+}
+
+func TestSyncAsksForAFullResync(t *testing.T) {
+	f := newSyncFixture()
+	ctx := context.Background()
+	conv := f.convs.add(conversation.TypeGroup, f.userID)
+
+	first := f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	latest := f.record(changelog.NewConversationChange(conv.ID, changelog.KindConversationUpdated))
+	f.log.prune(first)
+
+	tests := []struct {
+		name  string
+		since int64
+	}{
+		{name: "never synced", since: 0},
+		{name: "position was pruned", since: first},
+		{name: "unknown position", since: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := f.s.Sync(ctx, f.userID, &dto.SyncRequest{Since: tt.since})
+			require.NoError(t, err)
+			assert.True(t, resp.FullResync)
+			assert.Equal(t, latest, resp.Seq, "continues from the newest change")
+			assert.Empty(t, resp.Conversations)
+		})
+	}
+}
+
+func TestRecordOwnChangeIsForTheUser(t *testing.T) {
+	log := &changeLog{}
+	s := &service{changeLogRepo: log}
+	userID, first, second := uuid.New(), uuid.New(), uuid.New()
+
+	s.recordOwnChange(context.Background(), userID, first, second)
+
+	require.Len(t, log.changes, 2)
+	for i, conversationID := range []uuid.UUID{first, second} {
+		assert.Equal(t, conversationID, log.changes[i].ConversationID)
+		assert.Equal(t, changelog.KindConversationUpdated, log.changes[i].Kind)
+		assert.Equal(t, &userID, log.changes[i].UserID)
+	}
+}
+
+// pruningLog deletes up to the batch size of the pending entries on each call
+type pruningLog struct {
+	changelog.Repository
+	pending int64
+	calls   int
+	err     error
+}
+
+func (l *pruningLog) DeleteOlderThan(ctx context.Context, age time.Duration, limit int) (int64, error) {
+	l.calls++
+	if l.err != nil {
+		return 0, l.err
+	}
+	deleted := min(l.pending, int64(limit))
+	l.pending -= deleted
+	return deleted, nil
+}
+
+func TestChangeLogWorkerPrunesBatchesUntilShort(t *testing.T) {
+	log := &pruningLog{pending: 5}
+	NewChangeLogWorker(log, time.Hour, time.Minute, 2).prune(context.Background())
+
+	assert.Equal(t, int64(0), log.pending)
+	assert.Equal(t, 3, log.calls)
+
+	log = &pruningLog{pending: 4}
+	NewChangeLogWorker(log, time.Hour, time.Minute, 2).prune(context.Background())
+	assert.Equal(t, 3, log.calls, "an empty batch ends a run that divides evenly")
+
+	log = &pruningLog{pending: 4, err: errors.New("database unavailable")}
+	NewChangeLogWorker(log, time.Hour, time.Minute, 2).prune(context.Background())
+	assert.Equal(t, 1, log.calls)
+}
@@ -0,0 +1,127 @@
+package message
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/conversation"
+	"github.com/yourusername/sotalk/internal/domain/message"
+)
+
+// threadsRepo keeps messages and the reply info of thread roots
+type threadsRepo struct {
+	message.Repository
+	messages  map[uuid.UUID]*message.Message
+	summaries map[uuid.UUID]message.ThreadSummary
+}
+
+func newThreadsRepo() *threadsRepo {
+	return &threadsRepo{
+		messages:  make(map[uuid.UUID]*message.Message),
+		summaries: make(map[uuid.UUID]message.ThreadSummary),
+	}
+}
+
+func (r *threadsRepo) add(conversationID uuid.UUID, replyTo *message.Message) *message.Message {
+	msg := &message.Message{ID: uuid.New(), ConversationID: conversationID, SenderID: uuid.New(), CreatedAt: time.Now()}
+	if replyTo != nil {
+		msg.SetReplyTo(replyTo.ID)
+		msg.SetThread(replyTo.ThreadRootID())
+	}
+	r.messages[msg.ID] = msg
+	return msg
+}
+
+func (r *threadsRepo) FindByID(ctx context.Context, id uuid.UUID) (*message.Message, error) {
+	if msg, ok := r.messages[id]; ok {
+		return msg, nil
+	}
+	return nil, message.ErrMessageNotFound
+}
+
+func (r *threadsRepo) GetThreadSummaries(ctx context.Context, rootIDs []uuid.UUID) (map[uuid.UUID]message.ThreadSummary, error) {
+	summaries := make(map[uuid.UUID]message.ThreadSummary)
+	for _, id := range rootIDs {
+		if summary, ok := r.summaries[id]; ok {
+			summaries[id] = summary
+		}
+	}
+	return summaries, nil
+}
+
+func TestApplyThread(t *testing.T) {
+	repo := newThreadsRepo()
+	s := &service{messageRepo: repo}
+	conversationID := uuid.New()
+	root := repo.add(conversationID, nil)
+	reply := repo.add(conversationID, root)
+	elsewhere := repo.add(uuid.New(), nil)
+
+	tests := []struct {
+		name    string
+		replyTo *uuid.UUID
+		want    *uuid.UUID
+	}{
+		{name: "not a reply"},
+		{name: "reply to a root starts its thread", replyTo: &root.ID, want: &root.ID},
+		{name: "reply to a reply joins the same thread", replyTo: &reply.ID, want: &root.ID},
+		{name: "reply to another conversation", replyTo: &elsewhere.ID},
+		{name: "reply to an unknown message", replyTo: ptr(uuid.New())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &message.Message{ID: uuid.New(), ConversationID: conversationID, ReplyToID: tt.replyTo}
+			s.applyThread(context.Background(), msg)
+			assert.Equal(t, tt.want, msg.ThreadID)
+		})
+	}
+}
+
+func TestFindThreadRoot(t *testing.T) {
+	repo := newThreadsRepo()
+	conversations := newConversationsRepo()
+	member, outsider := uuid.New(), uuid.New()
+	conv := conversations.add(conversation.TypeGroup, member)
+	s := &service{messageRepo: repo, conversationRepo: conversations}
+
+	root := repo.add(conv.ID, nil)
+	reply := repo.add(conv.ID, root)
+
+	found, err := s.findThreadRoot(context.Background(), member, reply.ID)
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, found.ID)
+
+	found, err = s.findThreadRoot(context.Background(), member, root.ID)
+	require.NoError(t, err)
+	assert.Equal(t, root.ID, found.ID)
+
+	_, err = s.findThreadRoot(context.Background(), outsider, reply.ID)
+	assert.Equal(t, conversation.ErrNotParticipant, err)
+}
+
+func TestAttachThreadSummariesToRootsOnly(t *testing.T) {
+	repo := newThreadsRepo()
+	s := &service{messageRepo: repo}
+	conversationID := uuid.New()
+
+	root := repo.add(conversationID, nil)
+	quiet := repo.add(conversationID, nil)
+	reply := repo.add(conversationID, root)
+	repo.summaries[root.ID] = message.ThreadSummary{RootMessageID: root.ID, ReplyCount: 1, LastReplyID: reply.ID}
+
+	s.attachThreadSummaries(context.Background(), []*message.Message{root, quiet, reply})
+
+	require.NotNil(t, root.Thread)
+	assert.Equal(t, 1, root.Thread.ReplyCount)
+	assert.Nil(t, quiet.Thread)
+	assert.Nil(t, reply.Thread)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
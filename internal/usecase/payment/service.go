@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/domain/payment"
+	"github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/domain/user"
 	"github.com/yourusername/sotalk/internal/domain/wallet"
 	"github.com/yourusername/sotalk/internal/infrastructure/solana"
@@ -26,6 +27,7 @@ type service struct {
 	paymentRepo   payment.Repository
 	userRepo      user.Repository
 	walletRepo    wallet.Repository
+	privacyRepo   privacy.Repository
 	solanaClient  *solana.Client
 	wsBroadcaster WSBroadcaster
 }
@@ -35,6 +37,7 @@ func NewService(
 	paymentRepo payment.Repository,
 	userRepo user.Repository,
 	walletRepo wallet.Repository,
+	privacyRepo privacy.Repository,
 	solanaClient *solana.Client,
 	wsBroadcaster WSBroadcaster,
 ) Service {
@@ -42,6 +45,7 @@ func NewService(
 		paymentRepo:   paymentRepo,
 		userRepo:      userRepo,
 		walletRepo:    walletRepo,
+		privacyRepo:   privacyRepo,
 		solanaClient:  solanaClient,
 		wsBroadcaster: wsBroadcaster,
 	}
@@ -76,6 +80,10 @@ func (s *service) CreatePaymentRequest(ctx context.Context, fromUserID uuid.UUID
 		return nil, fmt.Errorf("recipient not found: %w", err)
 	}
 
+	if err := s.checkNotBlocked(ctx, fromUserID, toUserID); err != nil {
+		return nil, err
+	}
+
 	// Set expiry time (default 15 minutes)
 	expiryMinutes := req.ExpiryMinutes
 	if expiryMinutes <= 0 {
@@ -125,6 +133,18 @@ func (s *service) CreatePaymentRequest(ctx context.Context, fromUserID uuid.UUID
 	}, nil
 }
 
+// checkNotBlocked returns privacy.ErrUserUnavailable if either user blocked the other
+func (s *service) checkNotBlocked(ctx context.Context, userID, otherUserID uuid.UUID) error {
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, userID, otherUserID)
+	if err != nil {
+		return fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return privacy.ErrUserUnavailable
+	}
+	return nil
+}
+
 // GetPaymentRequest retrieves a payment request by ID
 func (s *service) GetPaymentRequest(ctx context.Context, userID, paymentID uuid.UUID) (*dto.PaymentRequestResponse, error) {
 	paymentReq, err := s.paymentRepo.FindPaymentRequestByID(ctx, paymentID)
@@ -358,6 +378,10 @@ func (s *service) SendDirectPayment(ctx context.Context, fromUserID uuid.UUID, r
 		return nil, payment.ErrInvalidPaymentAmount
 	}
 
+	if err := s.checkNotBlocked(ctx, fromUserID, toUserID); err != nil {
+		return nil, err
+	}
+
 	// Step 1: Get sender's wallet by walletID
 	walletID, err := uuid.Parse(req.WalletID)
 	if err != nil {
@@ -19,6 +19,12 @@ type Service interface {
 	IsUserBlocked(ctx context.Context, userID, targetUserID uuid.UUID) (bool, error)
 	GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]*dto.BlockedUserResponse, error)
 
+	// Presence
+	// PresenceAudience returns the users who are sent a user's presence updates
+	PresenceAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+	// CanSeePresence checks if viewerID may see targetID's presence, also from outside their audience
+	CanSeePresence(ctx context.Context, viewerID, targetID uuid.UUID) (bool, error)
+
 	// Disappearing Messages
 	SetDisappearingMessages(ctx context.Context, userID uuid.UUID, req *dto.DisappearingMessagesRequest) (*dto.DisappearingMessagesResponse, error)
 	GetDisappearingMessagesConfig(ctx context.Context, conversationID uuid.UUID) (*dto.DisappearingMessagesResponse, error)
@@ -0,0 +1,106 @@
+package privacy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	domainPrivacy "github.com/yourusername/sotalk/internal/domain/privacy"
+)
+
+// PresenceAudience returns the users who are sent a user's presence updates: their conversation
+// peers and the users who have them in their contacts, as far as their last seen visibility allows.
+// Blocked users are left out either way.
+func (s *service) PresenceAudience(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	settings, err := s.lastSeenSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []uuid.UUID
+	switch settings.LastSeenVisibility {
+	case domainPrivacy.VisibilityEveryone:
+		peerIDs, err := s.conversationRepo.FindPeerIDs(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get conversation peers: %w", err)
+		}
+		candidates = append(candidates, peerIDs...)
+		fallthrough
+	case domainPrivacy.VisibilityContacts:
+		ownerIDs, err := s.contactRepo.GetContactOwnerIDs(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contact owners: %w", err)
+		}
+		candidates = append(candidates, ownerIDs...)
+	default:
+		return nil, nil
+	}
+
+	blocked, err := s.privacyRepo.GetBlockRelations(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block relations: %w", err)
+	}
+
+	excluded := make(map[uuid.UUID]bool, len(blocked)+1)
+	excluded[userID] = true
+	for _, id := range blocked {
+		excluded[id] = true
+	}
+
+	audience := make([]uuid.UUID, 0, len(candidates))
+	for _, id := range candidates {
+		if excluded[id] {
+			continue
+		}
+		excluded[id] = true
+		audience = append(audience, id)
+	}
+
+	return audience, nil
+}
+
+// CanSeePresence checks if viewerID may see targetID's presence, also from outside their audience
+func (s *service) CanSeePresence(ctx context.Context, viewerID, targetID uuid.UUID) (bool, error) {
+	if viewerID == targetID {
+		return true, nil
+	}
+
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, viewerID, targetID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return false, nil
+	}
+
+	settings, err := s.lastSeenSettings(ctx, targetID)
+	if err != nil {
+		return false, err
+	}
+
+	relationship := "stranger"
+	if settings.LastSeenVisibility == domainPrivacy.VisibilityContacts {
+		isContact, err := s.contactRepo.IsContact(ctx, viewerID, targetID)
+		if err != nil {
+			return false, fmt.Errorf("failed to check contact: %w", err)
+		}
+		if isContact {
+			relationship = "contact"
+		}
+	}
+
+	return settings.CanSeeLastSeen(relationship), nil
+}
+
+// lastSeenSettings returns a user's privacy settings, the defaults when they never saved any
+func (s *service) lastSeenSettings(ctx context.Context, userID uuid.UUID) (*domainPrivacy.PrivacySettings, error) {
+	settings, err := s.privacyRepo.GetPrivacySettings(ctx, userID)
+	if errors.Is(err, domainPrivacy.ErrPrivacySettingsNotFound) {
+		return domainPrivacy.DefaultPrivacySettings(userID), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get privacy settings: %w", err)
+	}
+	return settings, nil
+}
@@ -4,11 +4,13 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base32"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pquerna/otp/totp"
+	"github.com/yourusername/sotalk/internal/domain/contact"
 	"github.com/yourusername/sotalk/internal/domain/conversation"
 	domainPrivacy "github.com/yourusername/sotalk/internal/domain/privacy"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
@@ -19,13 +21,15 @@ import (
 type service struct {
 	privacyRepo      domainPrivacy.Repository
 	conversationRepo conversation.Repository
+	contactRepo      contact.Repository
 }
 
 // NewService creates a new privacy service
-func NewService(privacyRepo domainPrivacy.Repository, conversationRepo conversation.Repository) Service {
+func NewService(privacyRepo domainPrivacy.Repository, conversationRepo conversation.Repository, contactRepo contact.Repository) Service {
 	return &service{
 		privacyRepo:      privacyRepo,
 		conversationRepo: conversationRepo,
+		contactRepo:      contactRepo,
 	}
 }
 
@@ -36,14 +40,7 @@ func (s *service) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*dt
 	if err != nil {
 		if err == domainPrivacy.ErrPrivacySettingsNotFound {
 			// Create default settings
-			settings = &domainPrivacy.PrivacySettings{
-				UserID:                 userID,
-				ProfilePhotoVisibility: domainPrivacy.VisibilityEveryone,
-				LastSeenVisibility:     domainPrivacy.VisibilityEveryone,
-				StatusVisibility:       domainPrivacy.VisibilityEveryone,
-				ReadReceiptsEnabled:    true,
-				TypingIndicatorEnabled: true,
-			}
+			settings = domainPrivacy.DefaultPrivacySettings(userID)
 
 			if err := s.privacyRepo.CreatePrivacySettings(ctx, settings); err != nil {
 				return nil, fmt.Errorf("failed to create default privacy settings: %w", err)
@@ -60,6 +57,7 @@ func (s *service) GetPrivacySettings(ctx context.Context, userID uuid.UUID) (*dt
 		StatusVisibility:       string(settings.StatusVisibility),
 		ReadReceiptsEnabled:    settings.ReadReceiptsEnabled,
 		TypingIndicatorEnabled: settings.TypingIndicatorEnabled,
+		WhoCanMessage:          string(settings.WhoCanMessage),
 		UpdatedAt:              settings.UpdatedAt,
 	}, nil
 }
@@ -70,14 +68,7 @@ func (s *service) UpdatePrivacySettings(ctx context.Context, userID uuid.UUID, r
 	if err != nil {
 		if err == domainPrivacy.ErrPrivacySettingsNotFound {
 			// Create default settings first
-			settings = &domainPrivacy.PrivacySettings{
-				UserID:                 userID,
-				ProfilePhotoVisibility: domainPrivacy.VisibilityEveryone,
-				LastSeenVisibility:     domainPrivacy.VisibilityEveryone,
-				StatusVisibility:       domainPrivacy.VisibilityEveryone,
-				ReadReceiptsEnabled:    true,
-				TypingIndicatorEnabled: true,
-			}
+			settings = domainPrivacy.DefaultPrivacySettings(userID)
 		} else {
 			return nil, err
 		}
@@ -99,6 +90,14 @@ func (s *service) UpdatePrivacySettings(ctx context.Context, userID uuid.UUID, r
 	if req.TypingIndicatorEnabled != nil {
 		settings.TypingIndicatorEnabled = *req.TypingIndicatorEnabled
 	}
+	if req.WhoCanMessage != nil {
+		switch who := domainPrivacy.Visibility(*req.WhoCanMessage); who {
+		case domainPrivacy.VisibilityEveryone, domainPrivacy.VisibilityContacts, domainPrivacy.VisibilityNobody:
+			settings.WhoCanMessage = who
+		default:
+			return nil, domainPrivacy.ErrInvalidVisibility
+		}
+	}
 
 	if err := s.privacyRepo.UpdatePrivacySettings(ctx, settings); err != nil {
 		return nil, err
@@ -138,6 +137,7 @@ func (s *service) BlockUser(ctx context.Context, userID uuid.UUID, req *dto.Bloc
 	if err := s.privacyRepo.BlockUser(ctx, blocked); err != nil {
 		return err
 	}
+	s.syncContactBlocked(ctx, userID, blockedUserID, true)
 
 	logger.Info("User blocked",
 		zap.String("user_id", userID.String()),
@@ -151,6 +151,7 @@ func (s *service) UnblockUser(ctx context.Context, userID, blockedUserID uuid.UU
 	if err := s.privacyRepo.UnblockUser(ctx, userID, blockedUserID); err != nil {
 		return err
 	}
+	s.syncContactBlocked(ctx, userID, blockedUserID, false)
 
 	logger.Info("User unblocked",
 		zap.String("user_id", userID.String()),
@@ -162,17 +163,31 @@ func (s *service) UnblockUser(ctx context.Context, userID, blockedUserID uuid.UU
 
 func (s *service) IsUserBlocked(ctx context.Context, userID, targetUserID uuid.UUID) (bool, error) {
 	// Check both directions: is userID blocking targetUserID, or is targetUserID blocking userID
-	blocked1, err := s.privacyRepo.IsUserBlocked(ctx, userID, targetUserID)
-	if err != nil {
-		return false, err
+	return s.privacyRepo.IsBlockedBetween(ctx, userID, targetUserID)
+}
+
+// syncContactBlocked mirrors a block onto the blocker's contact entry, if they have one
+func (s *service) syncContactBlocked(ctx context.Context, userID, blockedUserID uuid.UUID, blocked bool) {
+	if s.contactRepo == nil {
+		return
 	}
 
-	blocked2, err := s.privacyRepo.IsUserBlocked(ctx, targetUserID, userID)
+	c, err := s.contactRepo.GetContact(ctx, userID, blockedUserID)
 	if err != nil {
-		return false, err
+		if !errors.Is(err, contact.ErrContactNotFound) {
+			logger.Warn("Failed to get contact for block sync", zap.Error(err))
+		}
+		return
 	}
 
-	return blocked1 || blocked2, nil
+	c.IsBlocked = blocked
+	if err := s.contactRepo.UpdateContact(ctx, c); err != nil {
+		logger.Warn("Failed to sync contact block status",
+			zap.String("user_id", userID.String()),
+			zap.String("contact_id", blockedUserID.String()),
+			zap.Error(err),
+		)
+	}
 }
 
 func (s *service) GetBlockedUsers(ctx context.Context, userID uuid.UUID) ([]*dto.BlockedUserResponse, error) {
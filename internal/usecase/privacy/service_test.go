@@ -0,0 +1,71 @@
+package privacy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yourusername/sotalk/internal/domain/contact"
+	domainPrivacy "github.com/yourusername/sotalk/internal/domain/privacy"
+	"github.com/yourusername/sotalk/internal/usecase/dto"
+)
+
+// privacyRepo accepts every block and unblock
+type privacyRepo struct {
+	domainPrivacy.Repository
+}
+
+func (r *privacyRepo) BlockUser(ctx context.Context, blocked *domainPrivacy.BlockedUser) error {
+	return nil
+}
+
+func (r *privacyRepo) UnblockUser(ctx context.Context, userID, blockedUserID uuid.UUID) error {
+	return nil
+}
+
+// contactRepo holds one user's contacts
+type contactRepo struct {
+	contact.Repository
+	contacts map[uuid.UUID]*contact.Contact
+}
+
+func (r *contactRepo) GetContact(ctx context.Context, userID, contactID uuid.UUID) (*contact.Contact, error) {
+	if c, ok := r.contacts[contactID]; ok && c.UserID == userID {
+		return c, nil
+	}
+	return nil, contact.ErrContactNotFound
+}
+
+func (r *contactRepo) UpdateContact(ctx context.Context, c *contact.Contact) error {
+	r.contacts[c.ContactID] = c
+	return nil
+}
+
+func TestBlockKeepsTheContactInSync(t *testing.T) {
+	userID, friend, stranger := uuid.New(), uuid.New(), uuid.New()
+	contacts := &contactRepo{contacts: map[uuid.UUID]*contact.Contact{
+		friend: {UserID: userID, ContactID: friend},
+	}}
+	s := &service{privacyRepo: &privacyRepo{}, contactRepo: contacts}
+	ctx := context.Background()
+
+	require.NoError(t, s.BlockUser(ctx, userID, &dto.BlockUserRequest{BlockedUserID: friend.String()}))
+	assert.True(t, contacts.contacts[friend].IsBlocked)
+
+	require.NoError(t, s.UnblockUser(ctx, userID, friend))
+	assert.False(t, contacts.contacts[friend].IsBlocked)
+
+	// Users who are not contacts are blocked without one
+	require.NoError(t, s.BlockUser(ctx, userID, &dto.BlockUserRequest{BlockedUserID: stranger.String()}))
+	assert.Len(t, contacts.contacts, 1)
+}
+
+func TestBlockingYourself(t *testing.T) {
+	userID := uuid.New()
+	s := &service{privacyRepo: &privacyRepo{}}
+
+	err := s.BlockUser(context.Background(), userID, &dto.BlockUserRequest{BlockedUserID: userID.String()})
+	assert.Equal(t, domainPrivacy.ErrCannotBlockSelf, err)
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/yourusername/sotalk/internal/domain/contact"
+	domainPrivacy "github.com/yourusername/sotalk/internal/domain/privacy"
 	domainStatus "github.com/yourusername/sotalk/internal/domain/status"
 	"github.com/yourusername/sotalk/internal/domain/user"
 	"github.com/yourusername/sotalk/internal/usecase/dto"
@@ -18,14 +19,16 @@ type service struct {
 	statusRepo  domainStatus.Repository
 	contactRepo contact.Repository
 	userRepo    user.Repository
+	privacyRepo domainPrivacy.Repository
 }
 
 // NewService creates a new status service
-func NewService(statusRepo domainStatus.Repository, contactRepo contact.Repository, userRepo user.Repository) Service {
+func NewService(statusRepo domainStatus.Repository, contactRepo contact.Repository, userRepo user.Repository, privacyRepo domainPrivacy.Repository) Service {
 	return &service{
 		statusRepo:  statusRepo,
 		contactRepo: contactRepo,
 		userRepo:    userRepo,
+		privacyRepo: privacyRepo,
 	}
 }
 
@@ -126,6 +129,15 @@ func (s *service) canViewStatus(ctx context.Context, viewerID, statusOwnerID uui
 		return true, nil
 	}
 
+	// Nobody sees statuses across a block, whichever side blocked
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, viewerID, statusOwnerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return false, nil
+	}
+
 	switch privacy {
 	case domainStatus.StatusPrivacyEveryone:
 		// Anyone can view
@@ -163,16 +175,17 @@ func (s *service) GetStatusFeed(ctx context.Context, userID uuid.UUID, limit, of
 		limit = 20
 	}
 
+	// Statuses of users the viewer blocked or was blocked by are already left out
 	statuses, err := s.statusRepo.GetStatusFeed(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	result := make([]*dto.StatusResponse, len(statuses))
-	for i, st := range statuses {
+	result := make([]*dto.StatusResponse, 0, len(statuses))
+	for _, st := range statuses {
 		hasViewed, _ := s.statusRepo.HasViewed(ctx, st.ID, userID)
 
-		result[i] = &dto.StatusResponse{
+		result = append(result, &dto.StatusResponse{
 			ID:        st.ID.String(),
 			UserID:    st.UserID.String(),
 			MediaID:   st.MediaID.String(),
@@ -182,7 +195,7 @@ func (s *service) GetStatusFeed(ctx context.Context, userID uuid.UUID, limit, of
 			HasViewed: hasViewed,
 			ExpiresAt: st.ExpiresAt,
 			CreatedAt: st.CreatedAt,
-		}
+		})
 	}
 
 	return result, nil
@@ -199,6 +212,15 @@ func (s *service) ViewStatus(ctx context.Context, statusID, viewerID uuid.UUID)
 		return domainStatus.ErrStatusExpired
 	}
 
+	// Statuses across a block look like they do not exist
+	blocked, err := s.privacyRepo.IsBlockedBetween(ctx, viewerID, status.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check block: %w", err)
+	}
+	if blocked {
+		return domainStatus.ErrStatusNotFound
+	}
+
 	// Add view
 	view := &domainStatus.StatusView{
 		StatusID: statusID,
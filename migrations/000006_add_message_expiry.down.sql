@@ -0,0 +1,5 @@
+-- Rollback: Remove message expiry column
+
+DROP INDEX IF EXISTS idx_messages_expires_at;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS expires_at;
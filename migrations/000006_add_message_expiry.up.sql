@@ -0,0 +1,7 @@
+-- Add expiry timestamp to messages for disappearing messages
+-- Set when the conversation has disappearing messages enabled at send time
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP;
+
+-- Partial index so the expiry worker only scans messages that can expire
+CREATE INDEX IF NOT EXISTS idx_messages_expires_at ON messages(expires_at) WHERE expires_at IS NOT NULL;
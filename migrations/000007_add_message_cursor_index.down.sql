@@ -0,0 +1,3 @@
+-- Rollback: Remove keyset pagination index
+
+DROP INDEX IF EXISTS idx_messages_conversation_cursor;
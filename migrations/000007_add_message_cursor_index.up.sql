@@ -0,0 +1,4 @@
+-- Composite index backing keyset pagination of message history
+-- Queries compare (created_at, id) within a conversation for before/after/around cursors
+
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_cursor ON messages(conversation_id, created_at, id);
@@ -0,0 +1,3 @@
+-- Rollback: Remove full-text search index
+
+DROP INDEX IF EXISTS idx_messages_search;
@@ -0,0 +1,6 @@
+-- Full-text search index on message content
+-- The expression must match searchVector in messageRepository.
+-- 'simple' lowercases words without language-specific stemming or stop words,
+-- so messages in any language are searchable the same way.
+
+CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (to_tsvector('simple'::regconfig, content));
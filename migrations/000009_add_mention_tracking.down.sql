@@ -0,0 +1,6 @@
+-- Rollback: Remove mention tracking columns
+
+DROP INDEX IF EXISTS idx_mention_user_conversation;
+
+ALTER TABLE message_mentions DROP COLUMN IF EXISTS read_at;
+ALTER TABLE message_mentions DROP COLUMN IF EXISTS conversation_id;
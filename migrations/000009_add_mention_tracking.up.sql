@@ -0,0 +1,16 @@
+-- Track which conversation a mention belongs to and whether it has been read
+-- Backs the per-conversation unread mention counter and the mentions feed
+
+ALTER TABLE message_mentions ADD COLUMN IF NOT EXISTS conversation_id UUID;
+ALTER TABLE message_mentions ADD COLUMN IF NOT EXISTS read_at TIMESTAMP;
+
+UPDATE message_mentions mm
+SET conversation_id = m.conversation_id
+FROM messages m
+WHERE m.id = mm.message_id AND mm.conversation_id IS NULL;
+
+DELETE FROM message_mentions WHERE conversation_id IS NULL;
+
+ALTER TABLE message_mentions ALTER COLUMN conversation_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_mention_user_conversation ON message_mentions(user_id, conversation_id);
@@ -0,0 +1,7 @@
+-- Rollback: Remove threaded replies
+
+DROP TABLE IF EXISTS thread_followers;
+
+DROP INDEX IF EXISTS idx_messages_thread;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS thread_id;
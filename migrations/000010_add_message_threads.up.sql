@@ -0,0 +1,16 @@
+-- Threaded replies: every reply records the root message of its thread
+-- Replies to replies point at the same root so a thread is one flat list
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS thread_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_messages_thread ON messages(thread_id, created_at, id);
+
+-- Users following a thread receive thread.updated events
+CREATE TABLE IF NOT EXISTS thread_followers (
+    root_message_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (root_message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_thread_followers_user_id ON thread_followers(user_id);
@@ -0,0 +1,6 @@
+-- Rollback: Remove message edit history
+
+DROP TABLE IF EXISTS message_revisions;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS edit_count;
+ALTER TABLE messages DROP COLUMN IF EXISTS edited_at;
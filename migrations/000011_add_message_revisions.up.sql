@@ -0,0 +1,16 @@
+-- Message edit history: every edit keeps the content it replaced
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS edit_count INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS message_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL,
+    revision INT NOT NULL,
+    content TEXT NOT NULL,
+    content_type VARCHAR(20) NOT NULL,
+    edited_by UUID NOT NULL,
+    edited_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_revision_message ON message_revisions(message_id, revision);
@@ -0,0 +1,6 @@
+-- Rollback: Remove message tombstones and per-user hides
+
+DROP TABLE IF EXISTS message_hides;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_at;
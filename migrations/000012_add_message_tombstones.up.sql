@@ -0,0 +1,14 @@
+-- Delete for everyone keeps the row as a tombstone so replies, threads and pins still resolve
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_by UUID;
+
+-- Delete for me hides a message from a single user's history
+CREATE TABLE IF NOT EXISTS message_hides (
+    message_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    hidden_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_hides_user_id ON message_hides(user_id);
@@ -0,0 +1,3 @@
+-- Rollback: Remove scheduled messages
+
+DROP TABLE IF EXISTS scheduled_messages;
@@ -0,0 +1,5 @@
+-- Rollback: Remove client-generated message IDs
+
+DROP INDEX IF EXISTS idx_messages_sender_client_id;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS client_message_id;
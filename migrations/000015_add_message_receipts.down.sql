@@ -0,0 +1,3 @@
+-- Rollback: Remove per-recipient message receipts
+
+DROP TABLE IF EXISTS message_receipts;
@@ -0,0 +1,11 @@
+-- Per-recipient delivery and read receipts, so group messages track each member separately
+
+CREATE TABLE IF NOT EXISTS message_receipts (
+    message_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    delivered_at TIMESTAMP,
+    read_at TIMESTAMP,
+    PRIMARY KEY (message_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_receipts_user_id ON message_receipts(user_id);
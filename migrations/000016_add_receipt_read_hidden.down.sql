@@ -0,0 +1,3 @@
+-- Rollback: Remove hidden read receipts flag
+
+ALTER TABLE message_receipts DROP COLUMN IF EXISTS read_hidden;
@@ -0,0 +1,3 @@
+-- Reads made with read receipts turned off clear unread counts but are never shown to others
+
+ALTER TABLE message_receipts ADD COLUMN IF NOT EXISTS read_hidden BOOLEAN NOT NULL DEFAULT false;
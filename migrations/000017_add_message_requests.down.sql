@@ -0,0 +1,7 @@
+-- Rollback: Remove message requests
+
+DROP INDEX IF EXISTS idx_conversation_participants_requests;
+
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS requested_at;
+
+ALTER TABLE privacy_settings DROP COLUMN IF EXISTS who_can_message;
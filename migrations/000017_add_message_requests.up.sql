@@ -0,0 +1,7 @@
+-- Message requests: direct chats from people a user does not accept chats from wait in a separate inbox
+
+ALTER TABLE privacy_settings ADD COLUMN IF NOT EXISTS who_can_message VARCHAR(20) NOT NULL DEFAULT 'everyone';
+
+ALTER TABLE conversation_participants ADD COLUMN IF NOT EXISTS requested_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_conversation_participants_requests ON conversation_participants(user_id, requested_at) WHERE requested_at IS NOT NULL;
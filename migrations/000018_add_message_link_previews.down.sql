@@ -0,0 +1,3 @@
+-- Rollback: Remove message link previews
+
+ALTER TABLE messages DROP COLUMN IF EXISTS link_previews;
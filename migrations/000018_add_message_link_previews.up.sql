@@ -0,0 +1,3 @@
+-- Link previews: metadata of links in a message, generated after the message is sent
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS link_previews JSONB;
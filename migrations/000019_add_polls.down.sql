@@ -0,0 +1,5 @@
+-- Rollback: Remove polls
+
+DROP TABLE IF EXISTS poll_votes;
+DROP TABLE IF EXISTS poll_options;
+DROP TABLE IF EXISTS polls;
@@ -0,0 +1,32 @@
+-- Polls: poll messages with their options, per-option vote counts and votes
+
+CREATE TABLE IF NOT EXISTS polls (
+    message_id UUID PRIMARY KEY,
+    question TEXT NOT NULL,
+    multiple_choice BOOLEAN NOT NULL DEFAULT FALSE,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    quiz BOOLEAN NOT NULL DEFAULT FALSE,
+    correct_option INT,
+    closes_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    total_voters INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS poll_options (
+    message_id UUID NOT NULL,
+    position INT NOT NULL,
+    text VARCHAR(255) NOT NULL,
+    vote_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (message_id, position)
+);
+
+CREATE TABLE IF NOT EXISTS poll_votes (
+    message_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, user_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_votes_user_id ON poll_votes(user_id);
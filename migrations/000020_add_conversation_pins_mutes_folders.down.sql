@@ -0,0 +1,8 @@
+-- Rollback: Remove conversation pins, mutes and folders
+
+DROP TABLE IF EXISTS conversation_folder_items;
+DROP TABLE IF EXISTS conversation_folders;
+
+DROP INDEX IF EXISTS idx_conversation_participants_pinned;
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS muted_until;
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS pin_order;
@@ -0,0 +1,27 @@
+-- Per-user conversation state: pin order, mute end and user-defined folders
+
+ALTER TABLE conversation_participants ADD COLUMN IF NOT EXISTS pin_order INT;
+ALTER TABLE conversation_participants ADD COLUMN IF NOT EXISTS muted_until TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_conversation_participants_pinned
+    ON conversation_participants(user_id, pin_order)
+    WHERE pin_order IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS conversation_folders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_conversation_folders_user_name ON conversation_folders(user_id, name);
+
+CREATE TABLE IF NOT EXISTS conversation_folder_items (
+    folder_id UUID NOT NULL,
+    conversation_id UUID NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (folder_id, conversation_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conversation_folder_items_conversation_id ON conversation_folder_items(conversation_id);
@@ -0,0 +1,3 @@
+-- Rollback: Remove conversation read markers
+
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS last_read_message_id;
@@ -0,0 +1,3 @@
+-- Read markers: the message each participant has read up to
+
+ALTER TABLE conversation_participants ADD COLUMN IF NOT EXISTS last_read_message_id UUID;
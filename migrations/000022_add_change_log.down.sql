@@ -0,0 +1,3 @@
+-- Rollback: Remove change log
+
+DROP TABLE IF EXISTS change_log;
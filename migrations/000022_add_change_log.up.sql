@@ -0,0 +1,14 @@
+-- Change log: per user change sequence numbers for delta sync
+
+CREATE TABLE IF NOT EXISTS change_log (
+    seq BIGSERIAL PRIMARY KEY,
+    user_id UUID,
+    conversation_id UUID NOT NULL,
+    message_id UUID,
+    kind VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_change_log_user ON change_log(user_id, seq);
+CREATE INDEX IF NOT EXISTS idx_change_log_conversation ON change_log(conversation_id, seq);
+CREATE INDEX IF NOT EXISTS idx_change_log_created_at ON change_log(created_at);
@@ -0,0 +1,2 @@
+-- Rollback: Nothing to undo
+-- Backfilled participants can't be told apart from ones added since, and channels need them to post
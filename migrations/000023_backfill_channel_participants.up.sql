@@ -0,0 +1,19 @@
+-- Channel participants: owners, admins and subscribers of channels created before posting
+-- went through the conversation become participants of the channel's conversation
+
+INSERT INTO conversation_participants (conversation_id, user_id, role, joined_at)
+SELECT c.conversation_id, c.owner_id, 'owner', c.created_at
+FROM channels c
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+INSERT INTO conversation_participants (conversation_id, user_id, role, joined_at)
+SELECT c.conversation_id, ca.user_id, 'admin', ca.added_at
+FROM channel_admins ca
+JOIN channels c ON c.id = ca.channel_id
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
+
+INSERT INTO conversation_participants (conversation_id, user_id, role, joined_at)
+SELECT c.conversation_id, cs.user_id, 'member', cs.subscribed_at
+FROM channel_subscribers cs
+JOIN channels c ON c.id = cs.channel_id
+ON CONFLICT (conversation_id, user_id) DO NOTHING;
@@ -0,0 +1,5 @@
+-- Rollback: Search with the simple configuration again
+
+DROP INDEX IF EXISTS idx_messages_search;
+
+CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (to_tsvector('simple'::regconfig, content));
@@ -0,0 +1,6 @@
+-- Language-aware message search: the search index stems English words and drops stop words
+-- The expression must match searchVector in messageRepository.
+
+DROP INDEX IF EXISTS idx_messages_search;
+
+CREATE INDEX IF NOT EXISTS idx_messages_search ON messages USING GIN (to_tsvector('english'::regconfig, content));
@@ -10,13 +10,16 @@ import (
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Solana   SolanaConfig
-	JWT      JWTConfig
-	Storage  StorageConfig
-	SMTP     SMTPConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	Redis       RedisConfig
+	Solana      SolanaConfig
+	JWT         JWTConfig
+	Storage     StorageConfig
+	SMTP        SMTPConfig
+	Workers     WorkersConfig
+	LinkPreview LinkPreviewConfig
+	WebSocket   WebSocketConfig
 }
 
 type ServerConfig struct {
@@ -74,6 +77,33 @@ type SMTPConfig struct {
 	FromName string
 }
 
+type WorkersConfig struct {
+	MessageExpiryInterval     time.Duration
+	MessageExpiryBatchSize    int
+	ScheduledMessageInterval  time.Duration
+	ScheduledMessageBatchSize int
+	ChangeLogRetention        time.Duration // How long delta sync can catch up before a full resync is needed
+	ChangeLogPruneInterval    time.Duration
+	ChangeLogPruneBatchSize   int
+}
+
+type LinkPreviewConfig struct {
+	Enabled              bool
+	Timeout              time.Duration // Per link, redirects included
+	MaxBodyBytes         int
+	CacheTTL             time.Duration
+	AllowPrivateNetworks bool // Development only, lets previews be tested against a local server
+}
+
+type WebSocketConfig struct {
+	ClusterEnabled bool          // Relay events through Redis so several API nodes can run side by side
+	NodeID         string        // Unique per node, generated when empty
+	PresenceTTL    time.Duration // How long a crashed node's users still count as online
+	ReplaySize     int           // Events kept per user for resuming sessions
+	ReplayTTL      time.Duration // How long after their last event a user's replay buffer is kept
+	AwayAfter      time.Duration // Inactivity after which a connected user shows as away
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if exists (for local development)
@@ -128,6 +158,30 @@ func Load() (*Config, error) {
 			From:     getEnv("SMTP_FROM", "noreply@sotalk.com"),
 			FromName: getEnv("SMTP_FROM_NAME", "SoTalk"),
 		},
+		Workers: WorkersConfig{
+			MessageExpiryInterval:     getEnvAsDuration("MESSAGE_EXPIRY_INTERVAL", 30*time.Second),
+			MessageExpiryBatchSize:    getEnvAsInt("MESSAGE_EXPIRY_BATCH_SIZE", 100),
+			ScheduledMessageInterval:  getEnvAsDuration("SCHEDULED_MESSAGE_INTERVAL", 10*time.Second),
+			ScheduledMessageBatchSize: getEnvAsInt("SCHEDULED_MESSAGE_BATCH_SIZE", 100),
+			ChangeLogRetention:        getEnvAsDuration("CHANGE_LOG_RETENTION", 30*24*time.Hour),
+			ChangeLogPruneInterval:    getEnvAsDuration("CHANGE_LOG_PRUNE_INTERVAL", 10*time.Minute),
+			ChangeLogPruneBatchSize:   getEnvAsInt("CHANGE_LOG_PRUNE_BATCH_SIZE", 5000),
+		},
+		LinkPreview: LinkPreviewConfig{
+			Enabled:              getEnvAsBool("LINK_PREVIEW_ENABLED", true),
+			Timeout:              getEnvAsDuration("LINK_PREVIEW_TIMEOUT", 5*time.Second),
+			MaxBodyBytes:         getEnvAsInt("LINK_PREVIEW_MAX_BODY_BYTES", 512*1024),
+			CacheTTL:             getEnvAsDuration("LINK_PREVIEW_CACHE_TTL", 24*time.Hour),
+			AllowPrivateNetworks: getEnvAsBool("LINK_PREVIEW_ALLOW_PRIVATE_NETWORKS", false),
+		},
+		WebSocket: WebSocketConfig{
+			ClusterEnabled: getEnvAsBool("WS_CLUSTER_ENABLED", false),
+			NodeID:         getEnv("WS_NODE_ID", ""),
+			PresenceTTL:    getEnvAsDuration("WS_PRESENCE_TTL", 30*time.Second),
+			ReplaySize:     getEnvAsInt("WS_REPLAY_SIZE", 500),
+			ReplayTTL:      getEnvAsDuration("WS_REPLAY_TTL", 5*time.Minute),
+			AwayAfter:      getEnvAsDuration("WS_AWAY_AFTER", 5*time.Minute),
+		},
 	}
 
 	// Validate critical configuration
@@ -152,6 +206,9 @@ func (c *Config) Validate() error {
 	if c.Solana.RPCEndpoint == "" {
 		return fmt.Errorf("Solana RPC endpoint is required")
 	}
+	if c.LinkPreview.AllowPrivateNetworks && c.Server.Environment == "production" {
+		return fmt.Errorf("link previews cannot reach private networks in production")
+	}
 	if c.Storage.Provider == "azure" {
 		if c.Storage.AzureAccountName == "" {
 			return fmt.Errorf("Azure storage account name is required when using Azure provider")
@@ -188,6 +245,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {